@@ -1,19 +1,109 @@
 package gokzg4844
 
 import (
-	"encoding/json"
+	"bufio"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
 // Context holds the necessary configuration needed to create and verify proofs.
 //
+// A Context is safe for concurrent use by multiple go routines: its state is only written by the constructors,
+// except for the fingerprint and the precomputations of the EIP-7594 methods which are computed lazily under a
+// [sync.Once], and by [Context.Close], which waits for the calls in flight to return before releasing the trusted
+// setup. No other method modifies it. The only way to break this guarantee is to modify the points returned by
+// [Context.CommitKeyPointsUnsafe].
+//
+// No method modifies its inputs: the blobs, commitments, proofs, cells and other slices passed by the caller are only
+// read, never sorted, permuted or appended to in place, so they can be reused after the call and read by other go
+// routines during it. Methods which need to reorder their inputs work on a copy.
+//
 // Note: We could marshall this object so that clients won't need to process the SRS each time. The time to process is
 // about 2-5 seconds.
 type Context struct {
-	domain    *kzg.Domain
+	domain *kzg.Domain
+	// commitKey is nil if the context was created using [ModeVerifyOnly].
 	commitKey *kzg.CommitKey
 	openKey   *kzg.OpeningKey
+
+	// monomialCommitKey holds the G1 points of the trusted setup in monomial form.
+	// This is nil if the trusted setup did not contain them or if the context was
+	// created using [WithoutMonomialSRS] or [ModeVerifyOnly].
+	monomialCommitKey *kzg.CommitKey
+
+	// mode is set using [WithMode].
+	mode ContextMode
+
+	// numGoRoutines is the number of go routines used by methods which do not
+	// take it as a parameter. See [WithNumGoRoutines].
+	numGoRoutines int
+
+	// precomputedSRSWindowBits is the window size of the fixed base table computed
+	// using [WithPrecomputedSRS], and 0 if there is none.
+	precomputedSRSWindowBits int
+
+	// randomSource is the source of the random scalars used to batch verifications.
+	// See [WithRandomSource].
+	randomSource io.Reader
+
+	// observer is set using [WithObserver], and is nil otherwise.
+	observer Observer
+
+	// commitmentCache is set using [WithCommitmentCache], and is nil otherwise.
+	commitmentCache *commitmentCache
+
+	// rejectInfinityCommitments and rejectInfinityProofs are set using
+	// [WithRejectInfinityCommitments] and [WithRejectInfinityProofs].
+	rejectInfinityCommitments bool
+	rejectInfinityProofs      bool
+
+	// trustedCommitments is set using [WithTrustedCommitments].
+	trustedCommitments bool
+
+	// newChallengeHasher and challengeDomainSeparator are set using [WithChallengeHasher]
+	// and [WithChallengeDomainSeparator]. newChallengeHasher is nil for SHA-256.
+	newChallengeHasher       func() hash.Hash
+	challengeDomainSeparator string
+
+	// challengeScratchPool holds the hashers of [Context.computeChallenge] when the
+	// challenges do not use the defaults, which share a global pool.
+	challengeScratchPool sync.Pool
+
+	// setupDigest is the SHA-256 digest of the points of the trusted setup held by the
+	// context. See [Context.SaveSetupCache].
+	setupDigest [32]byte
+
+	// setupFingerprint is computed lazily by [Context.SetupFingerprint].
+	setupFingerprintOnce sync.Once
+	setupFingerprint     [32]byte
+
+	// The precomputations for the EIP-7594 methods are computed lazily by
+	// [Context.extendedDomain], [Context.cellCosetDomain] and [Context.cellProofKey].
+	extendedDomainOnce   sync.Once
+	extendedDomainCache  *kzg.Domain
+	cellCosetDomainOnce  sync.Once
+	cellCosetDomainCache *kzg.Domain
+	cellProofKeyOnce     sync.Once
+	cellProofKeyCache    *kzg.FK20Key
+	cellProofKeyErr      error
+
+	// extendedDomainSize and cellProofKeySize hold the number of bytes of the lazily
+	// computed precomputations, so that [Context.MemoryFootprint] can read them while
+	// they are being computed. extendedDomainSize includes the cell coset domain.
+	extendedDomainSize atomic.Int64
+	cellProofKeySize   atomic.Int64
+
+	// calls tracks the calls in flight and whether [Context.Close] was called.
+	calls callTracker
 }
 
 // BlsModulus is the bytes representation of the bls12-381 scalar field modulus.
@@ -39,24 +129,96 @@ var PointAtInfinity = [48]byte{0xc0}
 // methods. "4096" denotes that we will only be able to commit to polynomials with at most 4096 evaluations. "Secure"
 // denotes that this method is using a trusted setup file that was generated in an official
 // ceremony. In particular, the trusted file being used was taken from the ethereum KZG ceremony.
-func NewContext4096Secure() (*Context, error) {
+//
+// Options can be passed to modify the behavior of the [Context], see [ContextOption].
+func NewContext4096Secure(opts ...ContextOption) (*Context, error) {
 	if ScalarsPerBlob != 4096 {
 		// This is a library bug and so we panic.
-		panic("this method is named `NewContext4096Insecure1337` we expect SCALARS_PER_BLOB to be 4096")
+		panic("this method is named `NewContext4096Secure` we expect SCALARS_PER_BLOB to be 4096")
+	}
+
+	parsedSetup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewContext4096(parsedSetup, opts...)
+}
+
+// NewContext4096Insecure1337 creates a new context object using a trusted setup generated from the known secret 1337.
+// It has the same number of points as the trusted setup used by [NewContext4096Secure], so that commitments and proofs
+// have the same format, but they are different.
+//
+// This SHOULD NOT BE USED IN PRODUCTION, since anyone can create proofs for false statements using the secret. It is
+// intended for tests and development only.
+//
+// Options can be passed to modify the behavior of the [Context], see [ContextOption].
+func NewContext4096Insecure1337(opts ...ContextOption) (*Context, error) {
+	return NewContextFromSetup(insecureTrustedSetup(1337, ScalarsPerBlob), ScalarsPerBlob, opts...)
+}
+
+// NewContext4096FromReader creates a new context object from a trusted setup read from `r` in the JSON format of
+// [JSONTrustedSetup], as published by the Ethereum KZG ceremony.
+//
+// It returns an error wrapping [ErrInvalidTrustedSetupJSON] if the JSON is malformed, [ErrInvalidTrustedSetupSize]
+// if the setup has the wrong number of points and [ErrInvalidTrustedSetupPoint] if a point is not a
+// hex-string of the right length. The 0x prefix of the points is optional.
+//
+// The JSON is decoded one point at a time, so that the memory allocated to decode it is bounded by about twice the
+// size of the document, instead of also holding the whole document.
+//
+// Since the trusted setup is not embedded in the library, the points are checked to be in the correct subgroup
+// unless [WithSubgroupChecks] is used to disable the checks.
+func NewContext4096FromReader(r io.Reader, opts ...ContextOption) (*Context, error) {
+	trustedSetup, err := decodeTrustedSetup(r)
+	if err != nil {
+		return nil, err
 	}
+	return NewContext4096(trustedSetup, withDefaultSubgroupChecks(opts)...)
+}
 
-	parsedSetup := JSONTrustedSetup{}
+// NewContextFromJSONChecked creates a new context object from a trusted setup read from `r` in the JSON format of
+// [JSONTrustedSetup], like [NewContext4096FromReader]. Before the context is created, the trusted setup is checked
+// using [CheckTrustedSetupStructure], or with an exhaustive check of the lagrange G1 points if
+// [WithExhaustiveSetupCheck] is passed.
+//
+// This should be used when the trusted setup comes from an untrusted source. It returns an error wrapping
+// [ErrInvalidTrustedSetupStructure] if the points are not successive powers of the same secret.
+func NewContextFromJSONChecked(r io.Reader, opts ...ContextOption) (*Context, error) {
+	trustedSetup, err := decodeTrustedSetup(r)
+	if err != nil {
+		return nil, err
+	}
+	config := newContextConfig(opts)
+	if err := checkTrustedSetupStructure(trustedSetup, config.exhaustiveSetupCheck, config.numGoRoutines, config.randomSource); err != nil {
+		return nil, err
+	}
+	return NewContext4096(trustedSetup, opts...)
+}
 
-	err := json.Unmarshal([]byte(testKzgSetupStr), &parsedSetup)
+// NewContext4096FromTextReader creates a new context object from a trusted setup read from `r` in the plain-text
+// format used by c-kzg-4844. See [ParseTrustedSetupText].
+//
+// Like [NewContext4096FromReader], the points are checked to be in the correct subgroup unless
+// [WithSubgroupChecks] is used to disable the checks.
+func NewContext4096FromTextReader(r io.Reader, opts ...ContextOption) (*Context, error) {
+	trustedSetup, err := ParseTrustedSetupText(r)
 	if err != nil {
 		return nil, err
 	}
+	return NewContext4096(trustedSetup, withDefaultSubgroupChecks(opts)...)
+}
 
-	if ScalarsPerBlob != len(parsedSetup.SetupG1Lagrange) {
-		// This is a library method and so we panic
-		panic("this method is named `NewContext4096Insecure1337` we expect the number of G1 elements in the trusted setup to be 4096")
+// NewContext4096FromFile creates a new context object from the trusted setup stored in the JSON file at `path`.
+// See [NewContext4096FromReader].
+func NewContext4096FromFile(path string, opts ...ContextOption) (*Context, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
-	return NewContext4096(&parsedSetup)
+	defer file.Close()
+
+	return NewContext4096FromReader(bufio.NewReader(file), opts...)
 }
 
 // NewContext4096 creates a new context object which will hold the state needed for one to use the EIP-4844 methods. The
@@ -75,22 +237,122 @@ func NewContext4096Secure() (*Context, error) {
 //   - G2points = {H, alpha * H, alpha^2 * H, ..., alpha^n * H}
 //   - Lagrange G1Points = {L_0(alpha^0) * G, L_1(alpha) * G, L_2(alpha^2) * G, ..., L_n(alpha^n) * G}
 //
+// The monomial G1 points are optional. If they are present, they are retained so that one can commit to polynomials
+// in monomial form using [Context.CommitToMonomialPolynomial], unless [WithoutMonomialSRS] is passed.
+//
 // [Full Danksharding]: https://notes.ethereum.org/@dankrad/new_sharding
-func NewContext4096(trustedSetup *JSONTrustedSetup) (*Context, error) {
+func NewContext4096(trustedSetup *JSONTrustedSetup, opts ...ContextOption) (*Context, error) {
+	if len(trustedSetup.SetupG1Monomial) != 0 && len(trustedSetup.SetupG1Monomial) != len(trustedSetup.SetupG1Lagrange) {
+		return nil, ErrInvalidMonomialSRSSize
+	}
+
+	return NewContextFromSetup(trustedSetup.flexible(), ScalarsPerBlob, opts...)
+}
+
+// NewContextFromSetup creates a new context object for polynomials with `size` evaluations, which must be a power of
+// two. See [NewContext4096] for the expected order of the points in the trusted setup.
+//
+// The lagrange G1 points depend on the size of the domain, so the trusted setup must have exactly `size` of them. If
+// present, the monomial G1 points are truncated to the first `size` points, so a larger setup can be reused.
+//
+// The methods which take a [Blob] can only be used if `size` is [ScalarsPerBlob]; otherwise they return
+// [ErrContextSizeMismatch]. For other sizes, polynomials can be committed to using [Context.CommitToPolynomial] and
+// opened using [Context.ComputePolynomialKZGProof]; the proofs are verified using [Context.VerifyKZGProof].
+func NewContextFromSetup(trustedSetup *JSONTrustedSetupFlexible, size uint64, opts ...ContextOption) (*Context, error) {
+	config := newContextConfig(opts)
+
+	if err := checkContextSize(size); err != nil {
+		return nil, err
+	}
 	// This should not happen for the ETH protocol
 	// However since it's a public method, we add the check.
 	if len(trustedSetup.SetupG2) < 2 {
 		return nil, kzg.ErrMinSRSSize
 	}
+	if uint64(len(trustedSetup.SetupG1Lagrange)) != size {
+		return nil, fmt.Errorf("%w: expected %d lagrange G1 points, got %d", ErrInvalidTrustedSetupSize, size, len(trustedSetup.SetupG1Lagrange))
+	}
+	if len(trustedSetup.SetupG1Monomial) != 0 && uint64(len(trustedSetup.SetupG1Monomial)) < size {
+		return nil, ErrInvalidMonomialSRSSize
+	}
+
+	truncatedSetup := *trustedSetup
+	if len(truncatedSetup.SetupG1Monomial) != 0 {
+		truncatedSetup.SetupG1Monomial = truncatedSetup.SetupG1Monomial[:size]
+	}
 
 	// Parse the trusted setup from hex strings to G1 and G2 points
-	genG1, setupLagrangeG1Points, setupG2Points := parseTrustedSetup(trustedSetup)
+	genG1, setupMonomialG1Points, setupLagrangeG1Points, setupG2Points, err := parseTrustedSetup(&truncatedSetup, config.retainMonomialSRS(), config.subgroupChecks, config.numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
+	return newContextFromPoints(config, genG1, setupMonomialG1Points, setupLagrangeG1Points, setupG2Points)
+}
+
+// NewContextFromLagrangeSetup creates a new context object from a trusted setup which only holds the lagrange G1
+// points and the G2 points, as shipped by some distributions. The size of the context is the number of lagrange G1
+// points, which must be a power of two; see [NewContextFromSetup].
+//
+// The G1 generator, which would otherwise be the first monomial G1 point, is the standard BLS12-381 generator used by
+// the Ethereum KZG ceremony. All the methods needed by EIP-4844 are available, but [Context.CommitToMonomialPolynomial]
+// and [Context.ComputeDegreeBoundProof] return [ErrMonomialSRSUnavailable].
+func NewContextFromLagrangeSetup(lagrangeG1 []G1Hex, g2 []G2Hex, opts ...ContextOption) (*Context, error) {
+	trustedSetup := &JSONTrustedSetupFlexible{
+		SetupG2:         g2,
+		SetupG1Lagrange: lagrangeG1,
+	}
+	return NewContextFromSetup(trustedSetup, uint64(len(lagrangeG1)), opts...)
+}
+
+// NewInsecureContextWithSecret creates a new context object for polynomials with `size` evaluations using a trusted
+// setup generated in memory from the known secret `tau`. The trusted setup has `size` monomial and lagrange G1 points
+// and 65 G2 points, like the trusted setup used by [NewContext4096Secure]. [NewContext4096Insecure1337] is the same
+// as passing 1337 and [ScalarsPerBlob].
+//
+// This SHOULD NOT BE USED IN PRODUCTION, since anyone knowing the secret can create proofs for false statements. It
+// is intended for tests and local development networks, where knowing the secret allows checking evaluations
+// directly.
+//
+// It returns [ErrInvalidContextSize] if `size` is not a power of two between 2 and 2^32, the largest domain the
+// scalar field supports, or 2^30 on 32-bit platforms.
+func NewInsecureContextWithSecret(tau fr.Element, size uint64, opts ...ContextOption) (*Context, error) {
+	config := newContextConfig(opts)
+
+	if err := checkContextSize(size); err != nil {
+		return nil, err
+	}
+
+	monomialG1, lagrangeG1, g2 := insecureSetupPoints(tau, size)
+	genG1 := monomialG1[0]
+	if !config.retainMonomialSRS() {
+		monomialG1 = nil
+	}
+
+	return newContextFromPoints(config, genG1, monomialG1, lagrangeG1, g2)
+}
+
+// checkContextSize returns [ErrInvalidContextSize] if a [Context] cannot be created for polynomials
+// with `size` evaluations.
+func checkContextSize(size uint64) error {
+	if size < 2 || kzg.CheckDomainSize(size) != nil {
+		return ErrInvalidContextSize
+	}
+	return nil
+}
+
+// newContextFromPoints creates a new context object from the parsed points of the trusted setup.
+// The size of the context is the number of lagrange G1 points, which are in the order of the
+// trusted setup, i.e. not bit-reversed. The monomial G1 points may be nil.
+func newContextFromPoints(config *contextConfig, genG1 bls12381.G1Affine, setupMonomialG1Points, setupLagrangeG1Points []bls12381.G1Affine, setupG2Points []bls12381.G2Affine) (*Context, error) {
+	// The digest identifies the trusted setup in the cache written by [Context.SaveSetupCache]
+	// so it is computed before the points are reversed.
+	setupDigest := computeSetupDigest(setupMonomialG1Points, setupLagrangeG1Points, setupG2Points)
 
 	// Get the generator points and the degree-1 element for G2 points
 	// The generators are the degree-0 elements in the trusted setup
 	//
 	// This will never panic as we checked the minimum SRS size is >= 2
-	// and `ScalarsPerBlob` is 4096
 	genG2 := setupG2Points[0]
 	alphaGenG2 := setupG2Points[1]
 
@@ -101,18 +363,71 @@ func NewContext4096(trustedSetup *JSONTrustedSetup) (*Context, error) {
 		GenG1:   genG1,
 		GenG2:   genG2,
 		AlphaG2: alphaGenG2,
+		G2:      setupG2Points,
 	}
+	// The G₂ points used to verify opening proofs are fixed, so we precompute their pairing lines
+	openingKey.PrecomputePairingLines()
 
-	domain := kzg.NewDomain(ScalarsPerBlob)
+	domain := kzg.NewDomain(uint64(len(setupLagrangeG1Points)))
 	// Bit-Reverse the roots and the trusted setup according to the specs
 	// The bit reversal is not needed for simple KZG however it was
 	// implemented to make the step for full dank-sharding easier.
 	commitKey.ReversePoints()
 	domain.ReverseRoots()
 
-	return &Context{
-		domain:    domain,
-		commitKey: &commitKey,
-		openKey:   &openingKey,
-	}, nil
+	// The table needs to be computed after the points have been reversed
+	var precomputedSRSWindowBits int
+	if config.precomputeSRS && config.mode != ModeVerifyOnly {
+		err := commitKey.PrecomputeFixedBaseTable(config.precomputedSRSWindowBits, config.numGoRoutines)
+		if err != nil {
+			return nil, err
+		}
+		precomputedSRSWindowBits = config.precomputedSRSWindowBits
+	}
+
+	var monomialCommitKey *kzg.CommitKey
+	if setupMonomialG1Points != nil {
+		monomialCommitKey = &kzg.CommitKey{
+			G1: setupMonomialG1Points,
+		}
+	}
+
+	var cache *commitmentCache
+	if config.commitmentCacheSize > 0 {
+		cache = newCommitmentCache(config.commitmentCacheSize)
+	}
+
+	c := &Context{
+		domain:            domain,
+		commitKey:         &commitKey,
+		openKey:           &openingKey,
+		monomialCommitKey: monomialCommitKey,
+		mode:              config.mode,
+		numGoRoutines:     config.numGoRoutines,
+		randomSource:      config.randomSource,
+		observer:          config.observer,
+		commitmentCache:   cache,
+		setupDigest:       setupDigest,
+
+		precomputedSRSWindowBits:  precomputedSRSWindowBits,
+		rejectInfinityCommitments: config.rejectInfinityCommitments,
+		rejectInfinityProofs:      config.rejectInfinityProofs,
+		trustedCommitments:        config.trustedCommitments,
+		newChallengeHasher:        config.newChallengeHasher,
+		challengeDomainSeparator:  config.challengeDomainSeparator,
+	}
+
+	switch config.mode {
+	case ModeVerifyOnly:
+		// The fingerprint is computed from the lagrange G1 points, so it is computed before they are dropped
+		c.SetupFingerprint()
+		c.commitKey = nil
+	case ModeFull:
+		if _, err := c.cellProofKey(); err != nil {
+			return nil, err
+		}
+		c.extendedDomain()
+		c.cellCosetDomain()
+	}
+	return c, nil
 }
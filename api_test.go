@@ -1,13 +1,32 @@
 package gokzg4844_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
 	"math/big"
+	"math/bits"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/spectest"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/consensys/gnark-crypto/ecc"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
 )
 
 // Set the number of go routines to be 0
@@ -27,31 +46,29 @@ func TestPointAtInfinity(t *testing.T) {
 }
 
 func TestNonCanonicalScalar(t *testing.T) {
-	reducedScalar := GetRandFieldElement(13)
+	reducedScalar := testutil.GenerateScalar(13)
 	_, err := gokzg4844.DeserializeScalar(reducedScalar)
 	require.NoError(t, err)
 
-	unreducedScalar := createScalarNonCanonical(reducedScalar)
+	unreducedScalar := testutil.NonCanonicalScalar(reducedScalar)
 	_, err = gokzg4844.DeserializeScalar(unreducedScalar)
 	require.Error(t, err)
 }
 
 func TestNonCanonicalSmoke(t *testing.T) {
-	blobGood := GetRandBlob(123456789)
-	blobBad := GetRandBlob(123456789)
-	unreducedScalar := nonCanonicalScalar(123445)
-	modifyBlob(blobBad, unreducedScalar, 0)
+	blobGood := testutil.GenerateBlob(123456789)
+	blobBad := testutil.GenerateInvalidBlob(123456789, 0)
 
 	commitment, err := ctx.BlobToKZGCommitment(blobGood, NumGoRoutines)
 	require.NoError(t, err)
 	_, err = ctx.BlobToKZGCommitment(blobBad, NumGoRoutines)
 	require.Error(t, err, "expected an error as we gave a non-canonical blob")
 
-	inputPointGood := GetRandFieldElement(123)
-	inputPointBad := createScalarNonCanonical(inputPointGood)
+	inputPointGood := testutil.GenerateScalar(123)
+	inputPointBad := testutil.NonCanonicalScalar(inputPointGood)
 	proof, claimedValueGood, err := ctx.ComputeKZGProof(blobGood, inputPointGood, NumGoRoutines)
 	require.NoError(t, err)
-	claimedValueBad := createScalarNonCanonical(claimedValueGood)
+	claimedValueBad := testutil.NonCanonicalScalar(claimedValueGood)
 
 	_, _, err = ctx.ComputeKZGProof(blobGood, inputPointBad, NumGoRoutines)
 	require.Error(t, err, "expected an error since input point was not canonical")
@@ -78,45 +95,2031 @@ func TestNonCanonicalSmoke(t *testing.T) {
 	require.Error(t, err, "expected an error since blob was not canonical")
 }
 
-// Below are helper methods which allow us to change a serialized element into
-// its non-canonical counterpart by adding the modulus
+// modifyBlob writes newValue at the byte offset index of the blob, which is used to
+// replace a scalar by a non-canonical one
 func modifyBlob(blob *gokzg4844.Blob, newValue gokzg4844.Scalar, index int) {
 	copy(blob[index:index+gokzg4844.SerializedScalarSize], newValue[:])
 }
 
-func nonCanonicalScalar(seed int64) gokzg4844.Scalar {
-	return createScalarNonCanonical(GetRandFieldElement(seed))
+func TestCommitToMonomialPolynomial(t *testing.T) {
+	// 1 + 2x + 3x^2 + ... + 8x^7
+	coeffs := make([]fr.Element, 8)
+	for i := 0; i < len(coeffs); i++ {
+		coeffs[i].SetUint64(uint64(i + 1))
+	}
+
+	// Evaluate the polynomial over the domain to get the same polynomial in lagrange form
+	poly := make([]fr.Element, gokzg4844.ScalarsPerBlob)
+	for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+		root, err := ctx.DomainByIndex(i)
+		require.NoError(t, err)
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			poly[i].Mul(&poly[i], root)
+			poly[i].Add(&poly[i], &coeffs[j])
+		}
+	}
+
+	monomialCommitment, err := ctx.CommitToMonomialPolynomial(coeffs)
+	require.NoError(t, err)
+	lagrangeCommitment, err := ctx.BlobToKZGCommitment(gokzg4844.SerializePoly(poly), NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, lagrangeCommitment, monomialCommitment)
+
+	_, err = ctx.CommitToMonomialPolynomial(make([]fr.Element, gokzg4844.ScalarsPerBlob+1))
+	require.Error(t, err, "expected an error since there are more coefficients than points in the setup")
+}
+
+func TestCommitToMonomialPolynomialWithoutMonomialSRS(t *testing.T) {
+	ctxNoMonomial, err := gokzg4844.NewContext4096Secure(gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+
+	_, err = ctxNoMonomial.CommitToMonomialPolynomial([]fr.Element{fr.One()})
+	require.ErrorIs(t, err, gokzg4844.ErrMonomialSRSUnavailable)
+}
+
+func TestDomainByIndex(t *testing.T) {
+	for _, index := range []int{-1, gokzg4844.ScalarsPerBlob} {
+		_, err := ctx.DomainByIndex(index)
+		require.ErrorIs(t, err, gokzg4844.ErrIndexOutOfRange)
+	}
+
+	// The roots are copies
+	root, err := ctx.DomainByIndex(gokzg4844.ScalarsPerBlob - 1)
+	require.NoError(t, err)
+	expected := *root
+	root.SetOne()
+	root, err = ctx.DomainByIndex(gokzg4844.ScalarsPerBlob - 1)
+	require.NoError(t, err)
+	require.Equal(t, expected, *root)
 }
 
-func createScalarNonCanonical(serScalar gokzg4844.Scalar) gokzg4844.Scalar {
-	scalar, err := gokzg4844.DeserializeScalar(serScalar)
+func TestCommitKeyPoints(t *testing.T) {
+	blob := testutil.GenerateBlob(7)
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	// The i'th scalar of the blob is multiplied by the i'th point
+	points := ctx.CommitKeyPoints()
+	require.Len(t, points, gokzg4844.ScalarsPerBlob)
+	require.Equal(t, ctx.CommitKeyPointsUnsafe(), points)
+	poly, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	var commitment bls12381.G1Affine
+	_, err = commitment.MultiExp(points, poly, ecc.MultiExpConfig{})
+	require.NoError(t, err)
+	require.Equal(t, expected, gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(commitment)))
+
+	pointsBytes := ctx.CommitKeyPointsBytes()
+	for i := range points {
+		require.Equal(t, gokzg4844.SerializeG1Point(points[i]), pointsBytes[i])
+	}
+
+	// Modifying the copy does not change the commitments
+	for i := range points {
+		points[i].Double(&points[i])
+	}
+	got, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+}
+
+// The points are held in bit-reversed order, so committing to a blob gives the same commitment as copying its
+// polynomial into the order of the trusted setup for every commitment
+func TestBlobToKZGCommitmentStoredOrder(t *testing.T) {
+	setupKey := setupOrderCommitKey()
+	for _, seed := range []int64{1, 2, 3} {
+		blob := testutil.GenerateBlob(seed)
+		expected, err := commitInSetupOrder(blob, setupKey)
+		require.NoError(t, err)
+		got, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+	}
+}
+
+// setupOrderCommitKey returns the commit key of ctx in the order of the trusted setup.
+func setupOrderCommitKey() *kzg.CommitKey {
+	setupKey := &kzg.CommitKey{G1: ctx.CommitKeyPoints()}
+	setupKey.ReversePoints()
+	return setupKey
+}
+
+// commitInSetupOrder commits to the blob by copying its polynomial into the order of the trusted setup, using
+// bits.Reverse for the index mapping, and committing with setupKey.
+func commitInSetupOrder(blob *gokzg4844.Blob, setupKey *kzg.CommitKey) (gokzg4844.KZGCommitment, error) {
+	poly, err := gokzg4844.DeserializeBlob(blob)
+	if err != nil {
+		return gokzg4844.KZGCommitment{}, err
+	}
+	logSize := bits.Len(gokzg4844.ScalarsPerBlob) - 1
+	permuted := make(kzg.Polynomial, len(poly))
+	for i := range poly {
+		permuted[bits.Reverse64(uint64(i))>>(64-logSize)] = poly[i]
+	}
+	commitment, err := kzg.Commit(permuted, setupKey, NumGoRoutines)
 	if err != nil {
-		panic(err)
+		return gokzg4844.KZGCommitment{}, err
+	}
+	return gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(*commitment)), nil
+}
+
+func TestVerifKeyG2(t *testing.T) {
+	_, _, _, genG2 := bls12381.Generators()
+	g2 := ctx.VerifKeyG2()
+	require.True(t, g2[0].Equal(&genG2))
+	require.True(t, g2[1].IsInSubGroup())
+	require.False(t, g2[1].Equal(&genG2))
+
+	g2Bytes := ctx.VerifKeyG2Bytes()
+	require.Equal(t, gokzg4844.G2Point(g2[0].Bytes()), g2Bytes[0])
+	require.Equal(t, gokzg4844.G2Point(g2[1].Bytes()), g2Bytes[1])
+}
+
+func TestContextWithPrecomputedSRS(t *testing.T) {
+	_, err := gokzg4844.NewContext4096Secure(gokzg4844.WithPrecomputedSRS(3))
+	require.Error(t, err, "expected an error since the window size is too small")
+
+	ctxPrecomputed, err := gokzg4844.NewContext4096Secure(gokzg4844.WithPrecomputedSRS(8))
+	require.NoError(t, err)
+
+	for i := int64(0); i < 4; i++ {
+		blob := testutil.GenerateBlob(i)
+		expectedCommitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		gotCommitment, err := ctxPrecomputed.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expectedCommitment, gotCommitment)
+
+		expectedProof, err := ctx.ComputeBlobKZGProof(blob, expectedCommitment, NumGoRoutines)
+		require.NoError(t, err)
+		gotProof, err := ctxPrecomputed.ComputeBlobKZGProof(blob, gotCommitment, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expectedProof, gotProof)
+	}
+}
+
+func TestNumGoRoutines(t *testing.T) {
+	ctxSerial, err := gokzg4844.NewContext4096Secure(gokzg4844.WithSerialMode(), gokzg4844.WithPrecomputedSRS(8))
+	require.NoError(t, err)
+	ctxFour, err := gokzg4844.NewContext4096Secure(gokzg4844.WithNumGoRoutines(4))
+	require.NoError(t, err)
+
+	blobs := make([]gokzg4844.Blob, 4)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+	}
+	inputPoint := testutil.GenerateScalar(42)
+
+	created, ok := goroutinesCreated()
+	serialCommitments, err := ctxSerial.BlobsToKZGCommitments(blobs)
+	require.NoError(t, err)
+	serialProofs, err := ctxSerial.ComputeBlobKZGProofBatch(blobs, serialCommitments)
+	require.NoError(t, err)
+	serialProof, serialOutput, err := ctxSerial.ComputeKZGProof(&blobs[0], inputPoint, 0)
+	require.NoError(t, err)
+	// The serial mode does everything from the calling go routine when the SRS is precomputed
+	if ok {
+		createdAfter, _ := goroutinesCreated()
+		require.Equal(t, created, createdAfter)
+	}
+
+	commitments, err := ctxFour.BlobsToKZGCommitments(blobs)
+	require.NoError(t, err)
+	require.Equal(t, serialCommitments, commitments)
+	proofs, err := ctxFour.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+	require.Equal(t, serialProofs, proofs)
+	proof, output, err := ctxFour.ComputeKZGProof(&blobs[0], inputPoint, 0)
+	require.NoError(t, err)
+	require.Equal(t, serialProof, proof)
+	require.Equal(t, serialOutput, output)
+
+	for _, c := range []*gokzg4844.Context{ctxSerial, ctxFour} {
+		require.NoError(t, c.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+		require.NoError(t, c.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs))
+		require.NoError(t, c.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs, gokzg4844.WithAllFailures()))
+	}
+}
+
+// goroutinesCreated returns the number of go routines created since the program started, and false if the runtime
+// does not report it.
+func goroutinesCreated() (uint64, bool) {
+	sample := []metrics.Sample{{Name: "/sched/goroutines-created:goroutines"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0, false
+	}
+	return sample[0].Value.Uint64(), true
+}
+
+func TestDegreeBoundProof(t *testing.T) {
+	const degreeBound = gokzg4844.ScalarsPerBlob - 56
+
+	// Polynomial with degree exactly degreeBound - 1, in lagrange form
+	coeffs := make([]fr.Element, degreeBound)
+	for i := 0; i < len(coeffs); i++ {
+		coeffs[i].SetUint64(uint64(i + 1))
+	}
+	poly := make([]fr.Element, gokzg4844.ScalarsPerBlob)
+	for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+		root, err := ctx.DomainByIndex(i)
+		require.NoError(t, err)
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			poly[i].Mul(&poly[i], root)
+			poly[i].Add(&poly[i], &coeffs[j])
+		}
+	}
+	blob := gokzg4844.SerializePoly(poly)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	proof, err := ctx.ComputeDegreeBoundProof(blob, degreeBound, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyDegreeBoundProof(commitment, degreeBound, proof))
+
+	// The proof does not hold for a tighter bound
+	err = ctx.VerifyDegreeBoundProof(commitment, degreeBound-1, proof)
+	require.ErrorIs(t, err, kzg.ErrVerifyOpeningProof)
+	_, err = ctx.ComputeDegreeBoundProof(blob, degreeBound-1, NumGoRoutines)
+	require.ErrorIs(t, err, kzg.ErrPolynomialExceedsDegreeBound)
+
+	// The trusted setup only contains 65 G2 points
+	err = ctx.VerifyDegreeBoundProof(commitment, gokzg4844.ScalarsPerBlob-65, proof)
+	require.ErrorIs(t, err, kzg.ErrDegreeBoundShiftUnavailable)
+
+	ctxNoMonomial, err := gokzg4844.NewContext4096Secure(gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+	_, err = ctxNoMonomial.ComputeDegreeBoundProof(blob, degreeBound, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrMonomialSRSUnavailable)
+}
+
+// merkleRoot computes a SHA-256 Merkle root over the field elements of the blob.
+// It is used as a toy external commitment scheme.
+func merkleRoot(blob *gokzg4844.Blob) []byte {
+	layer := make([][]byte, gokzg4844.ScalarsPerBlob)
+	for i := range layer {
+		leaf := sha256.Sum256(blob[i*gokzg4844.SerializedScalarSize : (i+1)*gokzg4844.SerializedScalarSize])
+		layer[i] = leaf[:]
+	}
+	for len(layer) > 1 {
+		next := make([][]byte, len(layer)/2)
+		for i := range next {
+			node := sha256.Sum256(append(append([]byte{}, layer[2*i]...), layer[2*i+1]...))
+			next[i] = node[:]
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+// evaluateBlob evaluates the polynomial represented by the blob at `z` using the barycentric formula.
+// It is used as the evaluation oracle of the toy external commitment scheme.
+func evaluateBlob(t *testing.T, blob *gokzg4844.Blob, z gokzg4844.Scalar) gokzg4844.Scalar {
+	poly, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	point, err := gokzg4844.DeserializeScalar(z)
+	require.NoError(t, err)
+
+	roots := make([]fr.Element, gokzg4844.ScalarsPerBlob)
+	denominators := make([]fr.Element, gokzg4844.ScalarsPerBlob)
+	for i := range roots {
+		root, err := ctx.DomainByIndex(i)
+		require.NoError(t, err)
+		roots[i] = *root
+		denominators[i].Sub(&point, root)
+	}
+	invDenominators := fr.BatchInvert(denominators)
+
+	// f(z) = (z^n - 1) / n * Σ f_i * w_i / (z - w_i)
+	var result fr.Element
+	for i := range roots {
+		var term fr.Element
+		term.Mul(&poly[i], &roots[i])
+		term.Mul(&term, &invDenominators[i])
+		result.Add(&result, &term)
+	}
+	var zn, n fr.Element
+	zn.Exp(point, big.NewInt(gokzg4844.ScalarsPerBlob))
+	zn.Sub(&zn, new(fr.Element).SetOne())
+	n.SetUint64(gokzg4844.ScalarsPerBlob)
+	n.Inverse(&n)
+	result.Mul(&result, &zn)
+	result.Mul(&result, &n)
+
+	return gokzg4844.SerializeScalar(result)
+}
+
+func TestEquivalenceProof(t *testing.T) {
+	blob := testutil.GenerateBlob(1)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	externalCommitment := merkleRoot(blob)
+
+	oracle := func(challenge gokzg4844.Scalar) (gokzg4844.Scalar, error) {
+		return evaluateBlob(t, blob, challenge), nil
+	}
+	challenge, claimedValue, proof, err := ctx.ComputeEquivalenceProof(blob, commitment, externalCommitment, oracle, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.ComputeEquivalenceChallenge(commitment, externalCommitment), challenge)
+
+	// The verifier checks the KZG side, and the external evaluation at the same challenge
+	require.NoError(t, ctx.VerifyEquivalenceProof(commitment, externalCommitment, claimedValue, proof))
+	require.Equal(t, evaluateBlob(t, blob, challenge), claimedValue)
+
+	// A wrong claimed value or a different external commitment is rejected
+	wrongValue := evaluateBlob(t, blob, gokzg4844.Scalar{})
+	require.Error(t, ctx.VerifyEquivalenceProof(commitment, externalCommitment, wrongValue, proof))
+	require.Error(t, ctx.VerifyEquivalenceProof(commitment, merkleRoot(testutil.GenerateBlob(2)), claimedValue, proof))
+
+	// An external commitment to different data evaluates differently at the challenge
+	otherBlob := testutil.GenerateBlob(2)
+	otherOracle := func(challenge gokzg4844.Scalar) (gokzg4844.Scalar, error) {
+		return evaluateBlob(t, otherBlob, challenge), nil
+	}
+	_, _, _, err = ctx.ComputeEquivalenceProof(blob, commitment, merkleRoot(otherBlob), otherOracle, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrEquivalenceMismatch)
+}
+
+func TestComputeKZGProofFr(t *testing.T) {
+	blob := testutil.GenerateBlob(3)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	inDomain, err := ctx.DomainByIndex(5)
+	require.NoError(t, err)
+	outOfDomain, err := gokzg4844.DeserializeScalar(testutil.GenerateScalar(3))
+	require.NoError(t, err)
+
+	for _, point := range []fr.Element{*inDomain, outOfDomain} {
+		proof, claimedValue, err := ctx.ComputeKZGProofFr(blob, point)
+		require.NoError(t, err)
+
+		// The byte and field element variants agree
+		pointBytes := gokzg4844.SerializeScalar(point)
+		proofBytes, claimedValueBytes, err := ctx.ComputeKZGProof(blob, pointBytes, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, proofBytes, proof)
+		require.Equal(t, claimedValueBytes, gokzg4844.SerializeScalar(claimedValue))
+
+		require.NoError(t, ctx.VerifyKZGProofFr(commitment, point, claimedValue, proof))
+		require.NoError(t, ctx.VerifyKZGProof(commitment, pointBytes, claimedValueBytes, proofBytes))
+
+		var wrongValue fr.Element
+		wrongValue.Add(&claimedValue, new(fr.Element).SetOne())
+		require.Error(t, ctx.VerifyKZGProofFr(commitment, point, wrongValue, proof))
+	}
+}
+
+// FuzzVerifyKZGProof checks that verifying a proof for arbitrary inputs never panics, that every failure is either an
+// invalid input or a proof which does not verify, and that the byte and field element variants agree. The seeds are
+// derived from a valid proof, so that the fuzzer starts from inputs which pass the deserialization.
+func FuzzVerifyKZGProof(f *testing.F) {
+	blob := testutil.GenerateBlob(4)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(f, err)
+	z := testutil.GenerateScalar(4)
+	proof, y, err := ctx.ComputeKZGProof(blob, z, NumGoRoutines)
+	require.NoError(f, err)
+	f.Add(commitment[:], z[:], y[:], proof[:])
+	f.Add(commitment[:], z[:], z[:], proof[:])
+	f.Add(gokzg4844.PointAtInfinity[:], z[:], []byte{}, gokzg4844.PointAtInfinity[:])
+	nonCanonical := testutil.NonCanonicalScalar(z)
+	f.Add(commitment[:], nonCanonical[:], y[:], proof[:])
+
+	f.Fuzz(func(t *testing.T, commitmentBytes, zBytes, yBytes, proofBytes []byte) {
+		var (
+			commitment gokzg4844.KZGCommitment
+			z, y       gokzg4844.Scalar
+			proof      gokzg4844.KZGProof
+		)
+		copy(commitment[:], commitmentBytes)
+		copy(z[:], zBytes)
+		copy(y[:], yBytes)
+		copy(proof[:], proofBytes)
+
+		err := ctx.VerifyKZGProof(commitment, z, y, proof)
+		if err != nil {
+			require.True(t, errors.Is(err, gokzg4844.ErrInvalidInput) != errors.Is(err, gokzg4844.ErrProofInvalid), "unclassified error: %v", err)
+		}
+
+		zElement, zErr := gokzg4844.DeserializeScalar(z)
+		yElement, yErr := gokzg4844.DeserializeScalar(y)
+		if zErr == nil && yErr == nil {
+			require.Equal(t, err, ctx.VerifyKZGProofFr(commitment, zElement, yElement, proof))
+		}
+	})
+}
+
+func TestEvaluateBlobAt(t *testing.T) {
+	blob := testutil.GenerateBlob(4)
+
+	// The context holds the domain in bit-reversed order, so the k-th root of unity
+	// evaluates to the scalar of the blob at the bit-reversed index of k
+	domain := kzg.NewDomain(gokzg4844.ScalarsPerBlob)
+	logSize := bits.Len(gokzg4844.ScalarsPerBlob) - 1
+	for _, k := range []uint64{0, 1, 5, 2049, gokzg4844.ScalarsPerBlob - 1} {
+		index := bits.Reverse64(k) >> (64 - logSize)
+		value, err := ctx.EvaluateBlobAt(blob, gokzg4844.SerializeScalar(domain.Roots[k]))
+		require.NoError(t, err)
+		require.Equal(t, blob[index*gokzg4844.SerializedScalarSize:(index+1)*gokzg4844.SerializedScalarSize], value[:])
+	}
+
+	// Outside of the domain, the value is the claimed value of the opening proof
+	inputPoints := []gokzg4844.Scalar{testutil.GenerateScalar(1), testutil.GenerateScalar(2), gokzg4844.SerializeScalar(domain.Roots[7])}
+	for _, inputPoint := range inputPoints {
+		value, err := ctx.EvaluateBlobAt(blob, inputPoint)
+		require.NoError(t, err)
+		_, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, claimedValue, value)
+	}
+
+	values, err := ctx.EvaluateBlobAtPoints(blob, inputPoints)
+	require.NoError(t, err)
+	require.Len(t, values, len(inputPoints))
+	for i, inputPoint := range inputPoints {
+		value, err := ctx.EvaluateBlobAt(blob, inputPoint)
+		require.NoError(t, err)
+		require.Equal(t, value, values[i])
+	}
+
+	// Non-canonical points are rejected
+	_, err = ctx.EvaluateBlobAt(blob, testutil.GenerateNonCanonicalScalar(1))
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	_, err = ctx.EvaluateBlobAtPoints(blob, append(inputPoints, testutil.GenerateNonCanonicalScalar(1)))
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "index 3")
+
+	// So are invalid blobs
+	modifyBlob(blob, testutil.GenerateNonCanonicalScalar(2), 0)
+	_, err = ctx.EvaluateBlobAt(blob, inputPoints[0])
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestBlobsToKZGCommitments(t *testing.T) {
+	const numBlobs = 9
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+	}
+
+	// Check the ordering with fewer and more go routines than blobs
+	for _, numGoRoutines := range []int{2, 4 * numBlobs} {
+		ctxWithGoRoutines, err := gokzg4844.NewContext4096Secure(gokzg4844.WithNumGoRoutines(numGoRoutines))
+		require.NoError(t, err)
+
+		commitments, err := ctxWithGoRoutines.BlobsToKZGCommitments(blobs)
+		require.NoError(t, err)
+		require.Len(t, commitments, numBlobs)
+		for i := range blobs {
+			expected, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+			require.NoError(t, err)
+			require.Equal(t, expected, commitments[i])
+		}
+	}
+
+	commitments, err := ctx.BlobsToKZGCommitments(nil)
+	require.NoError(t, err)
+	require.Empty(t, commitments)
+}
+
+func TestBlobsToKZGCommitmentsInvalidBlob(t *testing.T) {
+	blobs := make([]gokzg4844.Blob, 6)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+	}
+	modifyBlob(&blobs[4], testutil.GenerateNonCanonicalScalar(4), 7)
+	modifyBlob(&blobs[2], testutil.GenerateNonCanonicalScalar(2), 0)
+
+	_, err := ctx.BlobsToKZGCommitments(blobs)
+	var blobErr *gokzg4844.BlobError
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 2, blobErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestComputeBlobKZGProofBatch(t *testing.T) {
+	const numBlobs = 9
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	commitments := make([]gokzg4844.KZGCommitment, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+	}
+
+	// Check the ordering with fewer and more go routines than blobs
+	for _, numGoRoutines := range []int{2, 4 * numBlobs} {
+		ctxWithGoRoutines, err := gokzg4844.NewContext4096Secure(gokzg4844.WithNumGoRoutines(numGoRoutines))
+		require.NoError(t, err)
+
+		proofs, err := ctxWithGoRoutines.ComputeBlobKZGProofBatch(blobs, commitments)
+		require.NoError(t, err)
+		require.Len(t, proofs, numBlobs)
+		for i := range blobs {
+			expected, err := ctx.ComputeBlobKZGProof(&blobs[i], commitments[i], NumGoRoutines)
+			require.NoError(t, err)
+			require.Equal(t, expected, proofs[i])
+		}
+		require.NoError(t, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	}
+
+	proofs, err := ctx.ComputeBlobKZGProofBatch(nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, proofs)
+
+	_, err = ctx.ComputeBlobKZGProofBatch(blobs, commitments[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+
+	// The first invalid blob or commitment is reported
+	invalidBlobs := append([]gokzg4844.Blob{}, blobs...)
+	invalidCommitments := append([]gokzg4844.KZGCommitment{}, commitments...)
+	modifyBlob(&invalidBlobs[6], testutil.GenerateNonCanonicalScalar(6), 0)
+	invalidCommitments[5] = gokzg4844.KZGCommitment{0xff}
+	_, err = ctx.ComputeBlobKZGProofBatch(invalidBlobs, invalidCommitments)
+	var blobErr *gokzg4844.BlobError
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 5, blobErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPointEncoding)
+}
+
+func TestVerifyBlobKZGProofBatchInvalidBlob(t *testing.T) {
+	blobs := make([]gokzg4844.Blob, 16)
+	commitments := make([]gokzg4844.KZGCommitment, len(blobs))
+	proofs := make([]gokzg4844.KZGProof, len(blobs))
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		proof, err := ctx.ComputeBlobKZGProof(&blobs[i], commitment, NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+		proofs[i] = proof
+	}
+	require.NoError(t, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+
+	modifyBlob(&blobs[15], testutil.GenerateNonCanonicalScalar(15), 4095)
+	modifyBlob(&blobs[9], testutil.GenerateNonCanonicalScalar(9), 0)
+	modifyBlob(&blobs[5], testutil.GenerateNonCanonicalScalar(5), 100)
+
+	// The first invalid blob is reported whichever go routine processes it
+	for _, numGoRoutines := range []int{1, 3, 16, 0} {
+		ctxGoRoutines, err := gokzg4844.NewContext4096Secure(gokzg4844.WithNumGoRoutines(numGoRoutines))
+		require.NoError(t, err)
+		for n := 0; n < 10; n++ {
+			err := ctxGoRoutines.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+			var blobErr *gokzg4844.BlobError
+			require.ErrorAs(t, err, &blobErr)
+			require.Equal(t, 5, blobErr.Index)
+			require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+		}
+	}
+}
+
+// recordingReader records the bytes read from r.
+type recordingReader struct {
+	r    io.Reader
+	read []byte
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read = append(r.read, p[:n]...)
+	return n, err
+}
+
+func TestWithRandomSource(t *testing.T) {
+	const numBlobs = 4
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	commitments := make([]gokzg4844.KZGCommitment, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+	}
+	proofs, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+
+	// The same seed gives the same scalar to combine the proofs
+	var recorded [][]byte
+	for run := 0; run < 2; run++ {
+		seed := testutil.GenerateScalar(42)
+		randomSource := &recordingReader{r: bytes.NewReader(bytes.Repeat(seed[:], 4))}
+		seededCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithRandomSource(randomSource))
+		require.NoError(t, err)
+		require.NoError(t, seededCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+		require.NotEmpty(t, randomSource.read)
+		recorded = append(recorded, randomSource.read)
+	}
+	require.Equal(t, recorded[0], recorded[1])
+
+	// Failing to read randomness is an error rather than a fallback to another source
+	failingCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithRandomSource(iotest.ErrReader(errors.New("no randomness"))))
+	require.NoError(t, err)
+	err = failingCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrRandomSource)
+	err = failingCtx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrRandomSource)
+
+	// A single proof is verified without randomness
+	require.NoError(t, failingCtx.VerifyBlobKZGProofBatch(blobs[:1], commitments[:1], proofs[:1]))
+}
+
+func TestVerifyBlobKZGProofBatchParPolicies(t *testing.T) {
+	const numBlobs = 32
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	commitments := make([]gokzg4844.KZGCommitment, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+	}
+	proofs, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+
+	for _, opts := range [][]gokzg4844.BatchOption{nil, {gokzg4844.WithFailFast()}, {gokzg4844.WithAllFailures()}, {gokzg4844.WithBisection()}} {
+		require.NoError(t, ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs, opts...))
+		require.ErrorIs(t, ctx.VerifyBlobKZGProofBatchPar(blobs, commitments[1:], proofs, opts...), gokzg4844.ErrBatchLengthCheck)
+	}
+
+	// Swap in the proof of another blob in the middle of the batch
+	invalidProofs := append([]gokzg4844.KZGProof{}, proofs...)
+	invalidProofs[numBlobs/2] = proofs[0]
+
+	// The random linear combination does not tell which proof is invalid
+	err = ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, invalidProofs)
+	require.ErrorIs(t, err, kzg.ErrVerifyOpeningProof)
+	var blobErr *gokzg4844.BlobError
+	require.False(t, errors.As(err, &blobErr))
+
+	err = ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, invalidProofs, gokzg4844.WithFailFast())
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, numBlobs/2, blobErr.Index)
+	require.ErrorIs(t, err, kzg.ErrVerifyOpeningProof)
+
+	err = ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, invalidProofs, gokzg4844.WithAllFailures())
+	var batchErr *gokzg4844.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{numBlobs / 2}, batchErr.Indices())
+	require.ErrorIs(t, err, kzg.ErrVerifyOpeningProof)
+
+	// Every failure is reported, whatever its reason
+	invalidBlobs := append([]gokzg4844.Blob{}, blobs...)
+	modifyBlob(&invalidBlobs[3], testutil.GenerateNonCanonicalScalar(3), 0)
+	invalidCommitments := append([]gokzg4844.KZGCommitment{}, commitments...)
+	invalidCommitments[30] = gokzg4844.KZGCommitment{0xff}
+	err = ctx.VerifyBlobKZGProofBatchPar(invalidBlobs, invalidCommitments, invalidProofs, gokzg4844.WithAllFailures())
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{3, numBlobs / 2, 30}, batchErr.Indices())
+	require.ErrorIs(t, batchErr.Errs[0], gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorIs(t, batchErr.Errs[1], kzg.ErrVerifyOpeningProof)
+	require.ErrorIs(t, batchErr.Errs[2], gokzg4844.ErrInvalidPointEncoding)
+
+	// With the default policy, the first invalid blob, commitment or proof is reported before any proof is checked
+	err = ctx.VerifyBlobKZGProofBatchPar(invalidBlobs, invalidCommitments, invalidProofs)
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 3, blobErr.Index)
+
+	// The last option wins
+	err = ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, invalidProofs, gokzg4844.WithAllFailures(), gokzg4844.WithFailFast())
+	require.False(t, errors.As(err, &batchErr))
+	require.ErrorAs(t, err, &blobErr)
+}
+
+// g1PointNotInSubgroup returns a point on the G1 curve y^2 = x^3 + 4 which is not in the prime order subgroup.
+func g1PointNotInSubgroup(t *testing.T) bls12381.G1Affine {
+	var point bls12381.G1Affine
+	for i := uint64(1); ; i++ {
+		point.X.SetUint64(i)
+		var rhs, b fp.Element
+		b.SetUint64(4)
+		rhs.Square(&point.X).Mul(&rhs, &point.X).Add(&rhs, &b)
+		if rhs.Legendre() == 1 {
+			point.Y.Sqrt(&rhs)
+			if !point.IsInSubGroup() {
+				break
+			}
+		}
+	}
+	require.True(t, point.IsOnCurve())
+	return point
+}
+
+// countingReader returns an endless stream of ones and counts the calls to Read.
+type countingReader struct {
+	calls int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.calls++
+	for i := range p {
+		p[i] = 1
+	}
+	return len(p), nil
+}
+
+func TestVerifyBlobKZGProofBatchParBisection(t *testing.T) {
+	const numBlobs = 6
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(60 + i))
+	}
+	commitments, err := ctx.BlobsToKZGCommitments(blobs)
+	require.NoError(t, err)
+	proofs, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+
+	// Each combined check samples one random scalar, so the calls to the random source count the checks
+	randomSource := &countingReader{}
+	bisectCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithRandomSource(randomSource))
+	require.NoError(t, err)
+
+	require.NoError(t, bisectCtx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs, gokzg4844.WithBisection()))
+	require.Equal(t, 1, randomSource.calls)
+
+	for _, invalidIndices := range [][]int{{0}, {5}, {2, 3}, {0, 1, 2, 3, 4, 5}} {
+		invalidProofs := append([]gokzg4844.KZGProof{}, proofs...)
+		for _, i := range invalidIndices {
+			invalidProofs[i] = proofs[(i+1)%numBlobs]
+		}
+
+		err := bisectCtx.VerifyBlobKZGProofBatchPar(blobs, commitments, invalidProofs, gokzg4844.WithBisection())
+		var batchErr *gokzg4844.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, invalidIndices, batchErr.Indices())
+		require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+
+		// The same failures are found by verifying the proofs one by one
+		err = ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, invalidProofs, gokzg4844.WithAllFailures())
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, invalidIndices, batchErr.Indices())
+	}
+
+	// Invalid blobs, commitments and proofs are reported with the proofs which fail
+	invalidBlobs := append([]gokzg4844.Blob{}, blobs...)
+	modifyBlob(&invalidBlobs[1], testutil.GenerateNonCanonicalScalar(61), 0)
+	invalidCommitments := append([]gokzg4844.KZGCommitment{}, commitments...)
+	invalidCommitments[4] = gokzg4844.KZGCommitment{0xff}
+	invalidProofs := append([]gokzg4844.KZGProof{}, proofs...)
+	invalidProofs[2] = proofs[3]
+	invalidProofs[5] = gokzg4844.KZGProof{0xff}
+	err = bisectCtx.VerifyBlobKZGProofBatchPar(invalidBlobs, invalidCommitments, invalidProofs, gokzg4844.WithBisection())
+	var batchErr *gokzg4844.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{1, 2, 4, 5}, batchErr.Indices())
+	require.ErrorIs(t, batchErr.Errs[0], gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorIs(t, batchErr.Errs[1], gokzg4844.ErrProofInvalid)
+	require.ErrorIs(t, batchErr.Errs[2], gokzg4844.ErrInvalidPointEncoding)
+	require.ErrorIs(t, batchErr.Errs[3], gokzg4844.ErrInvalidPointEncoding)
+
+	require.NoError(t, bisectCtx.VerifyBlobKZGProofBatchPar(nil, nil, nil, gokzg4844.WithBisection()))
+}
+
+func TestVerifyBlobKZGProofBatchInvalidCommitment(t *testing.T) {
+	blobs := make([]gokzg4844.Blob, 8)
+	commitments := make([]gokzg4844.KZGCommitment, len(blobs))
+	proofs := make([]gokzg4844.KZGProof, len(blobs))
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		proof, err := ctx.ComputeBlobKZGProof(&blobs[i], commitment, NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+		proofs[i] = proof
+	}
+
+	notInSubgroup := gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(g1PointNotInSubgroup(t)))
+	invalidEncoding := gokzg4844.KZGCommitment{0x9f}
+	for i := 1; i < len(invalidEncoding); i++ {
+		invalidEncoding[i] = 0xff
+	}
+
+	ctxTrusted, err := gokzg4844.NewContext4096Secure(gokzg4844.WithTrustedCommitments())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		modify        func(commitments []gokzg4844.KZGCommitment)
+		expectedIndex int
+		expectedErr   error
+	}{
+		{"not in subgroup", func(commitments []gokzg4844.KZGCommitment) {
+			commitments[3] = notInSubgroup
+		}, 3, gokzg4844.ErrPointNotInSubgroup},
+		{"invalid encoding", func(commitments []gokzg4844.KZGCommitment) {
+			commitments[3] = invalidEncoding
+		}, 3, gokzg4844.ErrInvalidPointEncoding},
+		{"first of several invalid commitments", func(commitments []gokzg4844.KZGCommitment) {
+			commitments[6] = invalidEncoding
+			commitments[3] = notInSubgroup
+			commitments[5] = notInSubgroup
+		}, 3, gokzg4844.ErrPointNotInSubgroup},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modified := make([]gokzg4844.KZGCommitment, len(commitments))
+			copy(modified, commitments)
+			test.modify(modified)
+
+			for _, verify := range []func([]gokzg4844.Blob, []gokzg4844.KZGCommitment, []gokzg4844.KZGProof) error{
+				ctx.VerifyBlobKZGProofBatch,
+				func(blobs []gokzg4844.Blob, commitments []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof) error {
+					return ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs)
+				},
+			} {
+				err := verify(blobs, modified, proofs)
+				var blobErr *gokzg4844.BlobError
+				require.ErrorAs(t, err, &blobErr)
+				require.Equal(t, test.expectedIndex, blobErr.Index)
+				require.ErrorIs(t, err, test.expectedErr)
+			}
+		})
+	}
+
+	// Trusted commitments are not subgroup checked, so the proofs are rejected by the pairing check instead.
+	// Invalid encodings are still rejected.
+	modified := make([]gokzg4844.KZGCommitment, len(commitments))
+	copy(modified, commitments)
+	require.NoError(t, ctxTrusted.VerifyBlobKZGProofBatch(blobs, modified, proofs))
+	modified[3] = notInSubgroup
+	require.ErrorIs(t, ctxTrusted.VerifyBlobKZGProofBatch(blobs, modified, proofs), kzg.ErrVerifyOpeningProof)
+	modified[3] = invalidEncoding
+	require.ErrorIs(t, ctxTrusted.VerifyBlobKZGProofBatch(blobs, modified, proofs), gokzg4844.ErrInvalidPointEncoding)
+
+	_, err = gokzg4844.DeserializeKZGCommitment(notInSubgroup)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+}
+
+// TestVerifyBlobKZGProofBatchRepeatedCommitments checks that batches in which each commitment appears several times,
+// and is only deserialized once, are accepted or rejected in the same way as when verifying each triple on its own.
+func TestVerifyBlobKZGProofBatchRepeatedCommitments(t *testing.T) {
+	const (
+		batchSize      = 8
+		numCommitments = 2
+	)
+	blobs := make([]gokzg4844.Blob, batchSize)
+	commitments := make([]gokzg4844.KZGCommitment, batchSize)
+	proofs := make([]gokzg4844.KZGProof, batchSize)
+	for i := 0; i < numCommitments; i++ {
+		fixture, err := testutil.GenerateProofFixture(ctx, int64(30+i))
+		require.NoError(t, err)
+		for j := i; j < batchSize; j += numCommitments {
+			blobs[j], commitments[j], proofs[j] = *fixture.Blob, fixture.Commitment, fixture.Proof
+		}
+	}
+	notInSubgroup := gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(g1PointNotInSubgroup(t)))
+
+	tests := []struct {
+		name   string
+		modify func(commitments []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof)
+	}{
+		{"valid", func([]gokzg4844.KZGCommitment, []gokzg4844.KZGProof) {}},
+		{"wrong proof for a repeated commitment", func(_ []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof) {
+			proofs[5] = proofs[4]
+		}},
+		{"commitment of the other blob", func(commitments []gokzg4844.KZGCommitment, _ []gokzg4844.KZGProof) {
+			commitments[5] = commitments[4]
+		}},
+		{"repeated invalid commitment", func(commitments []gokzg4844.KZGCommitment, _ []gokzg4844.KZGProof) {
+			commitments[6] = notInSubgroup
+			commitments[2] = notInSubgroup
+		}},
+		{"invalid commitments after a repeated one", func(commitments []gokzg4844.KZGCommitment, _ []gokzg4844.KZGProof) {
+			commitments[7] = notInSubgroup
+			commitments[3] = gokzg4844.KZGCommitment{0x9f}
+			commitments[5] = gokzg4844.KZGCommitment{0x9f}
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modifiedCommitments := append([]gokzg4844.KZGCommitment{}, commitments...)
+			modifiedProofs := append([]gokzg4844.KZGProof{}, proofs...)
+			test.modify(modifiedCommitments, modifiedProofs)
+
+			// The result expected from verifying the triples one by one
+			var expectedErr error
+			expectedIndex := -1
+			for i := range blobs {
+				if err := ctx.VerifyBlobKZGProof(&blobs[i], modifiedCommitments[i], modifiedProofs[i]); err != nil {
+					expectedErr, expectedIndex = err, i
+					break
+				}
+			}
+
+			for _, verify := range []func([]gokzg4844.Blob, []gokzg4844.KZGCommitment, []gokzg4844.KZGProof) error{
+				ctx.VerifyBlobKZGProofBatch,
+				func(blobs []gokzg4844.Blob, commitments []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof) error {
+					return ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs)
+				},
+				func(blobs []gokzg4844.Blob, commitments []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof) error {
+					return ctx.VerifyBlobSidecarBatch(blobs, commitments, versionedHashes(commitments), proofs)
+				},
+			} {
+				err := verify(blobs, modifiedCommitments, modifiedProofs)
+				switch {
+				case expectedErr == nil:
+					require.NoError(t, err)
+				case errors.Is(expectedErr, gokzg4844.ErrInvalidInput):
+					var blobErr *gokzg4844.BlobError
+					require.ErrorAs(t, err, &blobErr)
+					require.Equal(t, expectedIndex, blobErr.Index)
+					require.Equal(t, expectedErr, blobErr.Err)
+				default:
+					require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+					require.ErrorIs(t, expectedErr, gokzg4844.ErrProofInvalid)
+				}
+			}
+		})
+	}
+}
+
+// versionedHashes returns the versioned hashes of the commitments.
+func versionedHashes(commitments []gokzg4844.KZGCommitment) [][32]byte {
+	hashes := make([][32]byte, len(commitments))
+	for i := range commitments {
+		hashes[i] = gokzg4844.KZGToVersionedHash(commitments[i])
+	}
+	return hashes
+}
+
+func TestVerifyBlobKZGProofAllocs(t *testing.T) {
+	fixture, err := testutil.GenerateProofFixture(ctx, 1)
+	require.NoError(t, err)
+
+	// The polynomial, the point decoders and the challenge hasher are pooled, so the remaining allocations are made by
+	// gnark-crypto's subgroup checks, pairing and scalar multiplications. The bound is the count measured with
+	// gnark-crypto v0.13.0, and should be lowered whenever an allocation is removed.
+	const maxAllocs = 49
+	allocs := testing.AllocsPerRun(20, func() {
+		require.NoError(t, ctx.VerifyBlobKZGProof(fixture.Blob, fixture.Commitment, fixture.Proof))
+	})
+	require.LessOrEqual(t, allocs, float64(maxAllocs))
+}
+
+func TestVerifyBlobKZGProofUncompressed(t *testing.T) {
+	blobs := make([]gokzg4844.Blob, 4)
+	commitments := make([]gokzg4844.KZGCommitmentUncompressed, len(blobs))
+	proofs := make([]gokzg4844.KZGProofUncompressed, len(blobs))
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		proof, err := ctx.ComputeBlobKZGProof(&blobs[i], commitment, NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i], err = gokzg4844.SerializeKZGCommitmentUncompressed(commitment)
+		require.NoError(t, err)
+		proofs[i], err = gokzg4844.SerializeKZGProofUncompressed(proof)
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.VerifyBlobKZGProofUncompressed(&blobs[i], commitments[i], proofs[i]))
+	}
+	require.NoError(t, ctx.VerifyBlobKZGProofBatchUncompressed(blobs, commitments, proofs))
+
+	// A proof for another blob is rejected
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofUncompressed(&blobs[0], commitments[0], proofs[1]), kzg.ErrVerifyOpeningProof)
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofBatchUncompressed(blobs, commitments, proofs), kzg.ErrVerifyOpeningProof)
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+
+	// Commitments are subgroup checked like in the compressed form
+	notInSubgroup := gokzg4844.KZGCommitmentUncompressed(gokzg4844.SerializeG1PointUncompressed(g1PointNotInSubgroup(t)))
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofUncompressed(&blobs[2], notInSubgroup, proofs[2]), gokzg4844.ErrPointNotInSubgroup)
+	commitments[2] = notInSubgroup
+	err := ctx.VerifyBlobKZGProofBatchUncompressed(blobs, commitments, proofs)
+	var blobErr *gokzg4844.BlobError
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 2, blobErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofBatchUncompressed(blobs, commitments[:3], proofs), gokzg4844.ErrBatchLengthCheck)
+}
+
+func TestVerifyBlobKZGProofAgainstVersionedHash(t *testing.T) {
+	blob := testutil.GenerateBlob(11)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	versionedHash := gokzg4844.KZGToVersionedHash(commitment)
+
+	require.NoError(t, ctx.VerifyBlobKZGProofAgainstVersionedHash(blob, versionedHash, proof))
+
+	// The versioned hash of another blob is rejected before checking the proof
+	otherCommitment, err := ctx.BlobToKZGCommitment(testutil.GenerateBlob(12), NumGoRoutines)
+	require.NoError(t, err)
+	err = ctx.VerifyBlobKZGProofAgainstVersionedHash(blob, gokzg4844.KZGToVersionedHash(otherCommitment), proof)
+	require.ErrorIs(t, err, gokzg4844.ErrVersionedHashMismatch)
+
+	// The hash must carry the version byte
+	unversionedHash := versionedHash
+	unversionedHash[0] = 0
+	err = ctx.VerifyBlobKZGProofAgainstVersionedHash(blob, unversionedHash, proof)
+	require.ErrorIs(t, err, gokzg4844.ErrVersionedHashMismatch)
+
+	otherProof, err := ctx.ComputeBlobKZGProof(testutil.GenerateBlob(12), otherCommitment, NumGoRoutines)
+	require.NoError(t, err)
+	err = ctx.VerifyBlobKZGProofAgainstVersionedHash(blob, versionedHash, otherProof)
+	require.ErrorIs(t, err, kzg.ErrVerifyOpeningProof)
+}
+
+func TestErrorClassification(t *testing.T) {
+	blobs := []gokzg4844.Blob{*testutil.GenerateBlob(1), *testutil.GenerateBlob(2)}
+	commitments := make([]gokzg4844.KZGCommitment, len(blobs))
+	for i := range blobs {
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+	}
+	proofs, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+	wrongProofs := []gokzg4844.KZGProof{proofs[0], proofs[0]}
+
+	invalidBlob := blobs[0]
+	modifyBlob(&invalidBlob, testutil.GenerateNonCanonicalScalar(1), 7*gokzg4844.SerializedScalarSize)
+	notInSubgroup := gokzg4844.SerializeG1Point(g1PointNotInSubgroup(t))
+	invalidEncoding := gokzg4844.KZGProof{0x9f}
+
+	tests := []struct {
+		name   string
+		verify func() error
+		want   error
+	}{
+		{"valid proof", func() error {
+			return ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[0])
+		}, nil},
+		{"valid batch", func() error {
+			return ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+		}, nil},
+		{"incorrect proof", func() error {
+			return ctx.VerifyBlobKZGProof(&blobs[1], commitments[1], proofs[0])
+		}, gokzg4844.ErrProofInvalid},
+		{"batch with an incorrect proof", func() error {
+			return ctx.VerifyBlobKZGProofBatch(blobs, commitments, wrongProofs)
+		}, gokzg4844.ErrProofInvalid},
+		{"parallel batch with an incorrect proof", func() error {
+			return ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, wrongProofs, gokzg4844.WithAllFailures())
+		}, gokzg4844.ErrProofInvalid},
+		{"versioned hash of another commitment", func() error {
+			return ctx.VerifyBlobKZGProofAgainstVersionedHash(&blobs[0], gokzg4844.KZGToVersionedHash(commitments[1]), proofs[0])
+		}, gokzg4844.ErrProofInvalid},
+		{"incorrect length blob", func() error {
+			return ctx.VerifyBlobKZGProofBytes(blobs[0][:len(blobs[0])-1], commitments[0][:], proofs[0][:])
+		}, gokzg4844.ErrInvalidInput},
+		{"incorrect length proof", func() error {
+			return ctx.VerifyBlobKZGProofBytes(blobs[0][:], commitments[0][:], append(proofs[0][:], 0))
+		}, gokzg4844.ErrInvalidInput},
+		{"non-canonical scalar in blob", func() error {
+			return ctx.VerifyBlobKZGProof(&invalidBlob, commitments[0], proofs[0])
+		}, gokzg4844.ErrInvalidInput},
+		{"invalid proof encoding", func() error {
+			return ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], invalidEncoding)
+		}, gokzg4844.ErrInvalidInput},
+		{"commitment not in subgroup", func() error {
+			return ctx.VerifyBlobKZGProof(&blobs[0], gokzg4844.KZGCommitment(notInSubgroup), proofs[0])
+		}, gokzg4844.ErrInvalidInput},
+		{"batch with an invalid proof encoding", func() error {
+			return ctx.VerifyBlobKZGProofBatch(blobs, commitments, []gokzg4844.KZGProof{proofs[0], invalidEncoding})
+		}, gokzg4844.ErrInvalidInput},
+		{"inconsistent array lengths", func() error {
+			return ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs[:1])
+		}, gokzg4844.ErrInvalidInput},
+		{"invalid hex string", func() error {
+			var proof gokzg4844.KZGProof
+			return proof.UnmarshalText([]byte("0x1234"))
+		}, gokzg4844.ErrInvalidInput},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.verify()
+			if test.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, test.want)
+			// The classes are exclusive
+			for _, class := range []error{gokzg4844.ErrInvalidInput, gokzg4844.ErrProofInvalid} {
+				if class != test.want {
+					require.NotErrorIs(t, err, class)
+				}
+			}
+		})
+	}
+}
+
+func TestByteSliceAPI(t *testing.T) {
+	blob := testutil.GenerateBlob(13)
+	commitment, err := ctx.BlobToKZGCommitmentBytes(blob[:], NumGoRoutines)
+	require.NoError(t, err)
+	expectedCommitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCommitment, commitment)
+
+	proof, err := ctx.ComputeBlobKZGProofBytes(blob[:], commitment[:], NumGoRoutines)
+	require.NoError(t, err)
+	expectedProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, proof)
+
+	require.NoError(t, ctx.VerifyBlobKZGProofBytes(blob[:], commitment[:], proof[:]))
+
+	requireLengthError := func(t *testing.T, err error, got, want int) {
+		var lengthErr *gokzg4844.LengthError
+		require.ErrorAs(t, err, &lengthErr)
+		require.Equal(t, gokzg4844.LengthError{Got: got, Want: want}, *lengthErr)
+		require.ErrorIs(t, err, gokzg4844.ErrWrongLength)
+	}
+	requirePointLengthError := func(t *testing.T, err error, kind string, got int) {
+		var pointErr *gokzg4844.PointError
+		require.ErrorAs(t, err, &pointErr)
+		require.Equal(t, kind, pointErr.Kind)
+		requireLengthError(t, err, got, gokzg4844.CompressedG1Size)
+	}
+
+	blobSize := len(blob)
+	longBlob := append(blob[:], 0)
+	for _, serBlob := range [][]byte{nil, {}, blob[:blobSize-1], longBlob} {
+		_, err := ctx.BlobToKZGCommitmentBytes(serBlob, NumGoRoutines)
+		requireLengthError(t, err, len(serBlob), blobSize)
+		_, err = ctx.ComputeBlobKZGProofBytes(serBlob, commitment[:], NumGoRoutines)
+		requireLengthError(t, err, len(serBlob), blobSize)
+		err = ctx.VerifyBlobKZGProofBytes(serBlob, commitment[:], proof[:])
+		requireLengthError(t, err, len(serBlob), blobSize)
+		_, err = gokzg4844.DeserializeBlobBytes(serBlob)
+		requireLengthError(t, err, len(serBlob), blobSize)
+	}
+
+	for _, serPoint := range [][]byte{nil, commitment[:gokzg4844.CompressedG1Size-1], append(commitment[:], 0)} {
+		_, err := ctx.ComputeBlobKZGProofBytes(blob[:], serPoint, NumGoRoutines)
+		requirePointLengthError(t, err, "commitment", len(serPoint))
+		err = ctx.VerifyBlobKZGProofBytes(blob[:], serPoint, proof[:])
+		requirePointLengthError(t, err, "commitment", len(serPoint))
+		err = ctx.VerifyBlobKZGProofBytes(blob[:], commitment[:], serPoint)
+		requirePointLengthError(t, err, "proof", len(serPoint))
+		_, err = gokzg4844.DeserializeKZGCommitmentBytes(serPoint)
+		requirePointLengthError(t, err, "commitment", len(serPoint))
+		_, err = gokzg4844.DeserializeKZGProofBytes(serPoint)
+		requirePointLengthError(t, err, "proof", len(serPoint))
+	}
+
+	// The lengths are checked before the blob is deserialized
+	invalidBlob := *blob
+	modifyBlob(&invalidBlob, testutil.GenerateNonCanonicalScalar(1), 0)
+	err = ctx.VerifyBlobKZGProofBytes(invalidBlob[:], commitment[:], nil)
+	requirePointLengthError(t, err, "proof", 0)
+	err = ctx.VerifyBlobKZGProofBytes(invalidBlob[:], commitment[:], proof[:])
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestDeserializationErrorIndices(t *testing.T) {
+	blobs := make([]gokzg4844.Blob, 6)
+	commitments := make([]gokzg4844.KZGCommitment, len(blobs))
+	proofs := make([]gokzg4844.KZGProof, len(blobs))
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		proof, err := ctx.ComputeBlobKZGProof(&blobs[i], commitment, NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+		proofs[i] = proof
+	}
+	notInSubgroup := gokzg4844.SerializeG1Point(g1PointNotInSubgroup(t))
+
+	// A single blob reports the index of the scalar
+	invalidBlob := blobs[2]
+	modifyBlob(&invalidBlob, testutil.GenerateNonCanonicalScalar(2), 77*gokzg4844.SerializedScalarSize)
+	_, err := ctx.BlobToKZGCommitment(&invalidBlob, NumGoRoutines)
+	var scalarErr *gokzg4844.ScalarError
+	require.ErrorAs(t, err, &scalarErr)
+	require.Equal(t, 77, scalarErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+
+	// A single proof reports the kind of point and the reason
+	var pointErr *gokzg4844.PointError
+	err = ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], gokzg4844.KZGProof(notInSubgroup))
+	require.ErrorAs(t, err, &pointErr)
+	require.Equal(t, "proof", pointErr.Kind)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+
+	tests := []struct {
+		name          string
+		modify        func(blobs []gokzg4844.Blob, commitments []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof)
+		expectedIndex int
+		check         func(t *testing.T, err error)
+	}{
+		{"scalar", func(blobs []gokzg4844.Blob, _ []gokzg4844.KZGCommitment, _ []gokzg4844.KZGProof) {
+			blobs[2] = invalidBlob
+		}, 2, func(t *testing.T, err error) {
+			var scalarErr *gokzg4844.ScalarError
+			require.ErrorAs(t, err, &scalarErr)
+			require.Equal(t, 77, scalarErr.Index)
+		}},
+		{"commitment", func(_ []gokzg4844.Blob, commitments []gokzg4844.KZGCommitment, _ []gokzg4844.KZGProof) {
+			commitments[3] = gokzg4844.KZGCommitment{0xff}
+		}, 3, func(t *testing.T, err error) {
+			var pointErr *gokzg4844.PointError
+			require.ErrorAs(t, err, &pointErr)
+			require.Equal(t, "commitment", pointErr.Kind)
+			require.ErrorIs(t, err, gokzg4844.ErrInvalidPointEncoding)
+		}},
+		{"proof", func(_ []gokzg4844.Blob, _ []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof) {
+			proofs[5] = gokzg4844.KZGProof(notInSubgroup)
+			proofs[4] = gokzg4844.KZGProof(notInSubgroup)
+		}, 4, func(t *testing.T, err error) {
+			var pointErr *gokzg4844.PointError
+			require.ErrorAs(t, err, &pointErr)
+			require.Equal(t, "proof", pointErr.Kind)
+			require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modifiedBlobs := append([]gokzg4844.Blob{}, blobs...)
+			modifiedCommitments := append([]gokzg4844.KZGCommitment{}, commitments...)
+			modifiedProofs := append([]gokzg4844.KZGProof{}, proofs...)
+			test.modify(modifiedBlobs, modifiedCommitments, modifiedProofs)
+
+			for _, err := range []error{
+				ctx.VerifyBlobKZGProofBatch(modifiedBlobs, modifiedCommitments, modifiedProofs),
+				ctx.VerifyBlobKZGProofBatchPar(modifiedBlobs, modifiedCommitments, modifiedProofs),
+			} {
+				var blobErr *gokzg4844.BlobError
+				require.ErrorAs(t, err, &blobErr)
+				require.Equal(t, test.expectedIndex, blobErr.Index)
+				test.check(t, err)
+			}
+		})
+	}
+}
+
+func TestRejectPointAtInfinity(t *testing.T) {
+	infinity := gokzg4844.PointAtInfinity
+
+	// The commitment and the proof for the zero blob are both the point at infinity
+	zeroBlob := &gokzg4844.Blob{}
+	zeroCommitment, err := ctx.BlobToKZGCommitment(zeroBlob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.KZGCommitment(infinity), zeroCommitment)
+	zeroProof, err := ctx.ComputeBlobKZGProof(zeroBlob, zeroCommitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.KZGProof(infinity), zeroProof)
+
+	// The proof for a constant blob is the point at infinity, but its commitment is not
+	constantPoly := make([]fr.Element, gokzg4844.ScalarsPerBlob)
+	for i := range constantPoly {
+		constantPoly[i].SetOne()
+	}
+	constantBlob := gokzg4844.SerializePoly(constantPoly)
+	constantCommitment, err := ctx.BlobToKZGCommitment(constantBlob, NumGoRoutines)
+	require.NoError(t, err)
+	require.NotEqual(t, gokzg4844.KZGCommitment(infinity), constantCommitment)
+	constantProof, err := ctx.ComputeBlobKZGProof(constantBlob, constantCommitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.KZGProof(infinity), constantProof)
+
+	inputPoint := testutil.GenerateScalar(1)
+	one := gokzg4844.SerializeScalar(fr.One())
+
+	// verifyAll checks the blob with each of the verification methods taking a commitment and a proof
+	verifyAll := func(c *gokzg4844.Context, blob *gokzg4844.Blob, claimedValue gokzg4844.Scalar, commitment gokzg4844.KZGCommitment, proof gokzg4844.KZGProof) []error {
+		return []error{
+			c.VerifyKZGProof(commitment, inputPoint, claimedValue, proof),
+			c.VerifyBlobKZGProof(blob, commitment, proof),
+			c.VerifyBlobKZGProofBatch([]gokzg4844.Blob{*blob, *blob}, []gokzg4844.KZGCommitment{commitment, commitment}, []gokzg4844.KZGProof{proof, proof}),
+			c.VerifyBlobKZGProofBatchPar([]gokzg4844.Blob{*blob}, []gokzg4844.KZGCommitment{commitment}, []gokzg4844.KZGProof{proof}),
+		}
 	}
-	// Convert scalar to big int to add modulus to it
-	var scalarBi big.Int
-	scalar.BigInt(&scalarBi)
 
-	nonCanonicalScalar := addModP(scalarBi)
+	// By default, the point at infinity is accepted as in the specs
+	for _, err := range verifyAll(ctx, zeroBlob, gokzg4844.Scalar{}, zeroCommitment, zeroProof) {
+		require.NoError(t, err)
+	}
+	for _, err := range verifyAll(ctx, constantBlob, one, constantCommitment, constantProof) {
+		require.NoError(t, err)
+	}
+
+	ctxRejectCommitments, err := gokzg4844.NewContext4096Secure(gokzg4844.WithRejectInfinityCommitments())
+	require.NoError(t, err)
+	for _, err := range verifyAll(ctxRejectCommitments, zeroBlob, gokzg4844.Scalar{}, zeroCommitment, zeroProof) {
+		require.ErrorIs(t, err, gokzg4844.ErrPointAtInfinity)
+	}
+	for _, err := range verifyAll(ctxRejectCommitments, constantBlob, one, constantCommitment, constantProof) {
+		require.NoError(t, err)
+	}
+	err = ctxRejectCommitments.VerifyDegreeBoundProof(zeroCommitment, gokzg4844.ScalarsPerBlob, zeroProof)
+	require.ErrorIs(t, err, gokzg4844.ErrPointAtInfinity)
+
+	ctxRejectProofs, err := gokzg4844.NewContext4096Secure(gokzg4844.WithRejectInfinityProofs())
+	require.NoError(t, err)
+	for _, err := range verifyAll(ctxRejectProofs, constantBlob, one, constantCommitment, constantProof) {
+		require.ErrorIs(t, err, gokzg4844.ErrPointAtInfinity)
+	}
+	err = ctxRejectProofs.VerifyEquivalenceProof(constantCommitment, []byte("external"), one, constantProof)
+	require.ErrorIs(t, err, gokzg4844.ErrPointAtInfinity)
+}
+
+type observedOperation struct {
+	name      string
+	itemCount int
+}
+
+// recordingObserver records the operations reported to it.
+type recordingObserver struct {
+	mu         sync.Mutex
+	operations []observedOperation
+}
+
+func (o *recordingObserver) OnOperation(name string, itemCount int, d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.operations = append(o.operations, observedOperation{name, itemCount})
+}
 
-	serBigIntNonCanonScalar := nonCanonicalScalar.Bytes()
+func (o *recordingObserver) reset() []observedOperation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	operations := o.operations
+	o.operations = nil
+	return operations
+}
 
-	if len(serBigIntNonCanonScalar) != fr.Bytes {
-		panic("unreduced scalar should fit into 32 bytes")
+func TestWithChallengeHasher(t *testing.T) {
+	newBLAKE2b := func() hash.Hash {
+		h, err := blake2b.New256(nil)
+		require.NoError(t, err)
+		return h
 	}
+	customCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithChallengeHasher(newBLAKE2b), gokzg4844.WithChallengeDomainSeparator("EXAMPLECHAIN_FS_V1"))
+	require.NoError(t, err)
+
+	blob := testutil.GenerateBlob(80)
+	commitment, err := customCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := customCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, customCtx.VerifyBlobKZGProof(blob, commitment, proof))
+	require.NoError(t, customCtx.VerifyBlobKZGProofBatch([]gokzg4844.Blob{*blob}, []gokzg4844.KZGCommitment{commitment}, []gokzg4844.KZGProof{proof}))
+
+	// The proofs of the two contexts open the blob at different points, so they are not interchangeable
+	defaultProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NotEqual(t, defaultProof, proof)
+	require.ErrorIs(t, ctx.VerifyBlobKZGProof(blob, commitment, proof), gokzg4844.ErrProofInvalid)
+	require.ErrorIs(t, customCtx.VerifyBlobKZGProof(blob, commitment, defaultProof), gokzg4844.ErrProofInvalid)
 
-	// Convert the serialized big integer scalar into
-	// a `gokzg4844.Scalar`
-	var serNonCanonScalar gokzg4844.Scalar
-	copy(serNonCanonScalar[:], serBigIntNonCanonScalar)
-	return serNonCanonScalar
+	// The same holds for the equivalence proofs
+	externalCommitment := merkleRoot(blob)
+	oracle := func(challenge gokzg4844.Scalar) (gokzg4844.Scalar, error) {
+		return evaluateBlob(t, blob, challenge), nil
+	}
+	challenge, claimedValue, equivalenceProof, err := customCtx.ComputeEquivalenceProof(blob, commitment, externalCommitment, oracle, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, customCtx.ComputeEquivalenceChallenge(commitment, externalCommitment), challenge)
+	require.NotEqual(t, gokzg4844.ComputeEquivalenceChallenge(commitment, externalCommitment), challenge)
+	require.NoError(t, customCtx.VerifyEquivalenceProof(commitment, externalCommitment, claimedValue, equivalenceProof))
+	require.ErrorIs(t, ctx.VerifyEquivalenceProof(commitment, externalCommitment, claimedValue, equivalenceProof), gokzg4844.ErrProofInvalid)
 }
 
-func addModP(x big.Int) big.Int {
-	modulus := fr.Modulus()
+func TestWithObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	observedCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithObserver(observer))
+	require.NoError(t, err)
+
+	const numBlobs = 3
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	commitments := make([]gokzg4844.KZGCommitment, numBlobs)
+	proofs := make([]gokzg4844.KZGProof, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(70 + i))
+		commitments[i], err = observedCtx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		proofs[i], err = observedCtx.ComputeBlobKZGProof(&blobs[i], commitments[i], NumGoRoutines)
+		require.NoError(t, err)
+	}
+	var expected []observedOperation
+	for range blobs {
+		expected = append(expected,
+			observedOperation{gokzg4844.OperationBlobToKZGCommitment, 1},
+			observedOperation{gokzg4844.OperationComputeBlobKZGProof, 1},
+		)
+	}
+	require.Equal(t, expected, observer.reset())
+
+	// The phases of the batch verification are reported before the verification itself
+	require.NoError(t, observedCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	require.Equal(t, []observedOperation{
+		{gokzg4844.OperationMultiExp, numBlobs},
+		{gokzg4844.OperationMultiExp, 2*numBlobs + 1},
+		{gokzg4844.OperationPairing, 2},
+		{gokzg4844.OperationVerifyBlobKZGProofBatch, numBlobs},
+	}, observer.reset())
 
-	var xPlusModulus big.Int
-	xPlusModulus.Add(&x, modulus)
+	// Failures are reported too
+	err = observedCtx.VerifyBlobKZGProofBatch(blobs, commitments[1:], proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+	require.Equal(t, []observedOperation{{gokzg4844.OperationVerifyBlobKZGProofBatch, numBlobs}}, observer.reset())
+
+	// The phases are also reported by the parallel batch verification
+	require.NoError(t, observedCtx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs))
+	require.Equal(t, []observedOperation{
+		{gokzg4844.OperationMultiExp, numBlobs},
+		{gokzg4844.OperationMultiExp, 2*numBlobs + 1},
+		{gokzg4844.OperationPairing, 2},
+	}, observer.reset())
+}
+
+func TestVerifyBlobSidecar(t *testing.T) {
+	const numSidecars = 3
+	blobs := make([]gokzg4844.Blob, numSidecars)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(80 + i))
+	}
+	commitments, err := ctx.BlobsToKZGCommitments(blobs)
+	require.NoError(t, err)
+	proofs, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+	versionedHashes := make([][32]byte, numSidecars)
+	for i := range commitments {
+		versionedHashes[i] = gokzg4844.KZGToVersionedHash(commitments[i])
+	}
+
+	for i := range blobs {
+		require.NoError(t, ctx.VerifyBlobSidecar(&blobs[i], commitments[i], versionedHashes[i], proofs[i]))
+	}
+	require.NoError(t, ctx.VerifyBlobSidecarBatch(blobs, commitments, versionedHashes, proofs))
+	require.NoError(t, ctx.VerifyBlobSidecarBatch(nil, nil, nil, nil))
+
+	// Perturb each component of the sidecar at index 1
+	invalidBlob := blobs[1]
+	modifyBlob(&invalidBlob, testutil.GenerateNonCanonicalScalar(81), 5*gokzg4844.SerializedScalarSize)
+	otherBlob := blobs[0]
+	invalidCommitment := gokzg4844.KZGCommitment{0xff}
+	invalidProof := gokzg4844.KZGProof{0xff}
+	otherVersionedHash := versionedHashes[1]
+	otherVersionedHash[31] ^= 1
+
+	var scalarErr *gokzg4844.ScalarError
+	var pointErr *gokzg4844.PointError
+	tests := []struct {
+		name          string
+		blob          gokzg4844.Blob
+		commitment    gokzg4844.KZGCommitment
+		versionedHash [32]byte
+		proof         gokzg4844.KZGProof
+		check         func(t *testing.T, err error)
+	}{
+		{"non-canonical blob", invalidBlob, commitments[1], versionedHashes[1], proofs[1], func(t *testing.T, err error) {
+			require.ErrorAs(t, err, &scalarErr)
+			require.Equal(t, 5, scalarErr.Index)
+		}},
+		{"invalid commitment", blobs[1], invalidCommitment, versionedHashes[1], proofs[1], func(t *testing.T, err error) {
+			require.ErrorAs(t, err, &pointErr)
+			require.Equal(t, "commitment", pointErr.Kind)
+		}},
+		{"other commitment", blobs[1], commitments[2], versionedHashes[1], proofs[1], func(t *testing.T, err error) {
+			require.ErrorIs(t, err, gokzg4844.ErrVersionedHashMismatch)
+		}},
+		{"other versioned hash", blobs[1], commitments[1], otherVersionedHash, proofs[1], func(t *testing.T, err error) {
+			require.ErrorIs(t, err, gokzg4844.ErrVersionedHashMismatch)
+		}},
+		{"invalid proof", blobs[1], commitments[1], versionedHashes[1], invalidProof, func(t *testing.T, err error) {
+			require.ErrorAs(t, err, &pointErr)
+			require.Equal(t, "proof", pointErr.Kind)
+		}},
+		{"other proof", blobs[1], commitments[1], versionedHashes[1], proofs[2], func(t *testing.T, err error) {
+			require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+		}},
+		{"other blob", otherBlob, commitments[1], versionedHashes[1], proofs[1], func(t *testing.T, err error) {
+			require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ctx.VerifyBlobSidecar(&test.blob, test.commitment, test.versionedHash, test.proof)
+			require.Error(t, err)
+			test.check(t, err)
+
+			// The batch reports the same error for the index of the sidecar
+			batchBlobs := append([]gokzg4844.Blob{}, blobs...)
+			batchCommitments := append([]gokzg4844.KZGCommitment{}, commitments...)
+			batchVersionedHashes := append([][32]byte{}, versionedHashes...)
+			batchProofs := append([]gokzg4844.KZGProof{}, proofs...)
+			batchBlobs[1], batchCommitments[1], batchVersionedHashes[1], batchProofs[1] = test.blob, test.commitment, test.versionedHash, test.proof
+
+			err = ctx.VerifyBlobSidecarBatch(batchBlobs, batchCommitments, batchVersionedHashes, batchProofs)
+			require.Error(t, err)
+			test.check(t, err)
+			var blobErr *gokzg4844.BlobError
+			if !errors.Is(err, gokzg4844.ErrProofInvalid) {
+				require.ErrorAs(t, err, &blobErr)
+				require.Equal(t, 1, blobErr.Index)
+			}
+		})
+	}
+
+	err = ctx.VerifyBlobSidecarBatch(blobs, commitments, versionedHashes[1:], proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+}
+
+func TestContextClose(t *testing.T) {
+	ctxClosed, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+
+	blob := testutil.GenerateBlob(90)
+	commitment, err := ctxClosed.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctxClosed.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+
+	require.NoError(t, ctxClosed.Close())
+	require.NoError(t, ctxClosed.Close())
+
+	_, err = ctxClosed.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	_, err = ctxClosed.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	_, _, err = ctxClosed.ComputeKZGProof(blob, gokzg4844.Scalar{}, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	_, err = ctxClosed.ComputeCells(blob)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	_, err = ctxClosed.DomainByIndex(0)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	require.ErrorIs(t, ctxClosed.VerifyBlobKZGProof(blob, commitment, proof), gokzg4844.ErrContextClosed)
+	require.ErrorIs(t, ctxClosed.VerifyBlobKZGProofBatch(nil, nil, nil), gokzg4844.ErrContextClosed)
+	require.ErrorIs(t, ctxClosed.SaveSetupCache(io.Discard), gokzg4844.ErrContextClosed)
+
+	// The methods which do not return an error return zero values
+	require.Nil(t, ctxClosed.CommitKeyPoints())
+	require.Equal(t, [32]byte{}, ctxClosed.SetupFingerprint())
+	require.Equal(t, gokzg4844.MemoryUsage{}, ctxClosed.MemoryFootprint())
+
+	// Other contexts are not affected
+	require.NoError(t, ctx.VerifyBlobKZGProof(blob, commitment, proof))
+}
+
+func TestMemoryFootprint(t *testing.T) {
+	// The global context is not used, since the EIP-7594 tests add lazily computed precomputations to it
+	ctxDefault, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+	usage := ctxDefault.MemoryFootprint()
+	require.Positive(t, usage.SRS)
+	require.Positive(t, usage.Domain)
+	require.Equal(t, usage.SRS+usage.Domain+usage.PrecomputedTables, usage.Total())
 
-	return xPlusModulus
+	ctxPrecomputed, err := gokzg4844.NewContext4096Secure(gokzg4844.WithPrecomputedSRS(8))
+	require.NoError(t, err)
+	precomputedUsage := ctxPrecomputed.MemoryFootprint()
+	require.Equal(t, usage.SRS, precomputedUsage.SRS)
+	require.Equal(t, usage.Domain, precomputedUsage.Domain)
+	require.Greater(t, precomputedUsage.PrecomputedTables, usage.PrecomputedTables)
+
+	_, _, err = ctxDefault.ComputeCellsAndKZGProofs(testutil.GenerateBlob(91))
+	require.NoError(t, err)
+	cellsUsage := ctxDefault.MemoryFootprint()
+	require.Greater(t, cellsUsage.PrecomputedTables, usage.PrecomputedTables)
+
+	ctxNoMonomial, err := gokzg4844.NewContext4096Secure(gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+	require.Less(t, ctxNoMonomial.MemoryFootprint().SRS, usage.SRS)
+}
+
+func TestDeserializeBlobGoRoutines(t *testing.T) {
+	fixture, err := testutil.GenerateProofFixture(ctx, 3)
+	require.NoError(t, err)
+	z := testutil.GenerateScalar(3)
+	expectedProof, expectedClaim, err := ctx.ComputeKZGProof(fixture.Blob, z, 1)
+	require.NoError(t, err)
+
+	// The blob is deserialized in chunks, so the first invalid scalar is reported even if a later chunk is checked first
+	invalidBlob := *fixture.Blob
+	modifyBlob(&invalidBlob, testutil.GenerateNonCanonicalScalar(3), 3000*gokzg4844.SerializedScalarSize)
+	modifyBlob(&invalidBlob, testutil.GenerateNonCanonicalScalar(4), 700*gokzg4844.SerializedScalarSize)
+
+	for _, numGoRoutines := range []int{1, 3, 8, 64} {
+		commitment, err := ctx.BlobToKZGCommitment(fixture.Blob, numGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, fixture.Commitment, commitment)
+		proof, err := ctx.ComputeBlobKZGProof(fixture.Blob, commitment, numGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, fixture.Proof, proof)
+		proof, claim, err := ctx.ComputeKZGProof(fixture.Blob, z, numGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expectedProof, proof)
+		require.Equal(t, expectedClaim, claim)
+
+		var scalarErr *gokzg4844.ScalarError
+		_, err = ctx.BlobToKZGCommitment(&invalidBlob, numGoRoutines)
+		require.ErrorAs(t, err, &scalarErr)
+		require.Equal(t, 700, scalarErr.Index)
+		_, err = ctx.ComputeBlobKZGProof(&invalidBlob, commitment, numGoRoutines)
+		require.ErrorAs(t, err, &scalarErr)
+		require.Equal(t, 700, scalarErr.Index)
+		_, _, err = ctx.ComputeKZGProof(&invalidBlob, z, numGoRoutines)
+		require.ErrorAs(t, err, &scalarErr)
+		require.Equal(t, 700, scalarErr.Index)
+	}
+}
+
+func TestVerifyOnlyContext(t *testing.T) {
+	ctxVerify, err := gokzg4844.NewContext4096Secure(gokzg4844.WithMode(gokzg4844.ModeVerifyOnly))
+	require.NoError(t, err)
+
+	for _, handler := range []string{"verify_kzg_proof", "verify_blob_kzg_proof", "verify_blob_kzg_proof_batch"} {
+		t.Run(handler, func(t *testing.T) {
+			spectest.RunAll(t, ctxVerify, filepath.Join(testDir, handler))
+		})
+	}
+
+	fixture, err := testutil.GenerateProofFixture(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, ctxVerify.VerifyBlobKZGProof(fixture.Blob, fixture.Commitment, fixture.Proof))
+	require.Equal(t, ctx.SetupFingerprint(), ctxVerify.SetupFingerprint())
+
+	_, err = ctxVerify.BlobToKZGCommitment(fixture.Blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOnlyContext)
+	_, err = ctxVerify.BlobsToKZGCommitments([]gokzg4844.Blob{*fixture.Blob})
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOnlyContext)
+	_, err = ctxVerify.ComputeBlobKZGProof(fixture.Blob, fixture.Commitment, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOnlyContext)
+	_, _, err = ctxVerify.ComputeKZGProof(fixture.Blob, gokzg4844.Scalar{}, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOnlyContext)
+	err = ctxVerify.VerifyBlobKZGProofAgainstVersionedHash(fixture.Blob, gokzg4844.KZGToVersionedHash(fixture.Commitment), fixture.Proof)
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOnlyContext)
+	require.ErrorIs(t, ctxVerify.SaveSetupCache(io.Discard), gokzg4844.ErrVerifyOnlyContext)
+	_, err = ctxVerify.PrecomputePartialCommitment(nil, nil)
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOnlyContext)
+	_, _, err = ctxVerify.ComputeCellsAndKZGProofs(fixture.Blob)
+	require.ErrorIs(t, err, gokzg4844.ErrMonomialSRSUnavailable)
+	require.Nil(t, ctxVerify.CommitKeyPoints())
+
+	// Only the G2 points are retained
+	ctxDefault, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+	usage, defaultUsage := ctxVerify.MemoryFootprint(), ctxDefault.MemoryFootprint()
+	require.Less(t, 50*usage.SRS, defaultUsage.SRS)
+	require.Equal(t, defaultUsage.Domain, usage.Domain)
+	require.Contains(t, ctxVerify.String(), "verify only")
+}
+
+func TestFullContext(t *testing.T) {
+	ctxFull, err := gokzg4844.NewContext4096Secure(gokzg4844.WithMode(gokzg4844.ModeFull))
+	require.NoError(t, err)
+	ctxDefault, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+
+	// The precomputations of the EIP-7594 methods are computed upfront
+	usage, defaultUsage := ctxFull.MemoryFootprint(), ctxDefault.MemoryFootprint()
+	require.Equal(t, defaultUsage.SRS, usage.SRS)
+	require.Greater(t, usage.Domain, defaultUsage.Domain)
+	require.Greater(t, usage.PrecomputedTables, defaultUsage.PrecomputedTables)
+
+	blob := testutil.GenerateBlob(92)
+	cells, proofs, err := ctxFull.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	expectedCells, expectedProofs, err := ctxDefault.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, cells)
+	require.Equal(t, expectedProofs, proofs)
+	require.Equal(t, usage, ctxFull.MemoryFootprint())
+
+	// Verifying cells does not compute the coset domain again
+	commitment, err := ctxFull.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	err = ctxFull.VerifyCellKZGProofBatch([]gokzg4844.KZGCommitment{commitment, commitment}, []uint64{0, 5}, []gokzg4844.Cell{cells[0], cells[5]}, []gokzg4844.KZGProof{proofs[0], proofs[5]})
+	require.NoError(t, err)
+	require.Equal(t, usage, ctxFull.MemoryFootprint())
+
+	_, err = gokzg4844.NewContext4096Secure(gokzg4844.WithMode(gokzg4844.ModeFull), gokzg4844.WithoutMonomialSRS())
+	require.ErrorIs(t, err, gokzg4844.ErrMonomialSRSUnavailable)
+	_, err = gokzg4844.NewInsecureContextWithSecret(fr.NewElement(1234), 16, gokzg4844.WithMode(gokzg4844.ModeFull))
+	require.ErrorIs(t, err, gokzg4844.ErrContextSizeMismatch)
+}
+
+func TestBatchCancellation(t *testing.T) {
+	const numBlobs = 16
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(100 + i))
+	}
+	commitments, err := ctx.BlobsToKZGCommitments(blobs)
+	require.NoError(t, err)
+	proofs, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+
+	numGoroutines := runtime.NumGoroutine()
+
+	// A context which is already cancelled stops the batches before any work is done
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ctx.BlobsToKZGCommitmentsCtx(cancelled, blobs)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = ctx.ComputeBlobKZGProofBatchCtx(cancelled, blobs, commitments)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofBatchCtx(cancelled, blobs, commitments, proofs), context.Canceled)
+	for _, opts := range [][]gokzg4844.BatchOption{nil, {gokzg4844.WithFailFast()}, {gokzg4844.WithAllFailures()}, {gokzg4844.WithBisection()}} {
+		err := ctx.VerifyBlobKZGProofBatchParCtx(cancelled, blobs, commitments, proofs, opts...)
+		require.ErrorIs(t, err, context.Canceled)
+	}
+
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(&blobs[0])
+	require.NoError(t, err)
+	cellIndices := []uint64{3, 17, 64}
+	someCells := []gokzg4844.Cell{cells[3], cells[17], cells[64]}
+	someCellProofs := []gokzg4844.KZGProof{cellProofs[3], cellProofs[17], cellProofs[64]}
+	cellCommitments := []gokzg4844.KZGCommitment{commitments[0], commitments[0], commitments[0]}
+	_, _, err = ctx.ComputeCellsAndKZGProofsCtx(cancelled, &blobs[0])
+	require.ErrorIs(t, err, context.Canceled)
+	halfIndices := make([]uint64, gokzg4844.CellsPerExtBlob/2)
+	for i := range halfIndices {
+		halfIndices[i] = uint64(i)
+	}
+	_, _, err = ctx.RecoverCellsAndKZGProofsCtx(cancelled, halfIndices, cells[:len(halfIndices)])
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, ctx.VerifyCellKZGProofBatchCtx(cancelled, cellCommitments, cellIndices, someCells, someCellProofs), context.Canceled)
+	require.NoError(t, ctx.VerifyCellKZGProofBatchCtx(context.Background(), cellCommitments, cellIndices, someCells, someCellProofs))
+
+	// An empty batch has no work to stop
+	_, err = ctx.BlobsToKZGCommitmentsCtx(cancelled, nil)
+	require.NoError(t, err)
+
+	// Cancelling in the middle of a batch stops it once the blobs being processed are done,
+	// which takes much less time than processing the whole batch
+	start := time.Now()
+	_, err = ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(t, err)
+	batchDuration := time.Since(start)
+
+	for _, numGoRoutines := range []int{1, 4} {
+		ctxWithGoRoutines, err := gokzg4844.NewContext4096Secure(gokzg4844.WithNumGoRoutines(numGoRoutines))
+		require.NoError(t, err)
+
+		cancelCtx, cancel := context.WithCancel(context.Background())
+		timer := time.AfterFunc(batchDuration/16, cancel)
+		start := time.Now()
+		_, err = ctxWithGoRoutines.ComputeBlobKZGProofBatchCtx(cancelCtx, blobs, commitments)
+		elapsed := time.Since(start)
+		timer.Stop()
+		cancel()
+		require.ErrorIs(t, err, context.Canceled, "numGoRoutines=%d", numGoRoutines)
+		require.Less(t, elapsed, batchDuration*3/4, "numGoRoutines=%d", numGoRoutines)
+	}
+
+	// The same holds for the FK20 computation of the cells and proofs of a single blob
+	start = time.Now()
+	_, _, err = ctx.ComputeCellsAndKZGProofs(&blobs[1])
+	require.NoError(t, err)
+	cellsDuration := time.Since(start)
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	timer := time.AfterFunc(cellsDuration/16, cancel)
+	start = time.Now()
+	_, _, err = ctx.ComputeCellsAndKZGProofsCtx(cancelCtx, &blobs[1])
+	elapsed := time.Since(start)
+	timer.Stop()
+	cancel()
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, elapsed, cellsDuration*3/4)
+
+	// No go routine is left running once the batches return. The check is not done
+	// using require.Eventually, since it runs the condition in another go routine.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > numGoroutines && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), numGoroutines)
+}
+
+// cacheLookups returns the names of the commitment cache lookups among the operations.
+func cacheLookups(operations []observedOperation) []string {
+	var lookups []string
+	for _, operation := range operations {
+		if operation.name == gokzg4844.OperationCommitmentCacheHit || operation.name == gokzg4844.OperationCommitmentCacheMiss {
+			lookups = append(lookups, operation.name)
+		}
+	}
+	return lookups
+}
+
+func TestWithCommitmentCache(t *testing.T) {
+	const (
+		hit  = gokzg4844.OperationCommitmentCacheHit
+		miss = gokzg4844.OperationCommitmentCacheMiss
+	)
+
+	observer := &recordingObserver{}
+	cachedCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithCommitmentCache(2), gokzg4844.WithObserver(observer))
+	require.NoError(t, err)
+
+	blobs := make([]gokzg4844.Blob, 3)
+	expected := make([]gokzg4844.KZGCommitment, len(blobs))
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(120 + i))
+		expected[i], err = ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+	}
+	commit := func(i int) {
+		t.Helper()
+		commitment, err := cachedCtx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expected[i], commitment)
+	}
+
+	// The second commitment to a blob is a hit, which does not compute a multi exponentiation
+	commit(0)
+	require.Equal(t, []string{miss}, cacheLookups(observer.reset()))
+	commit(0)
+	require.Equal(t, []string{hit}, cacheLookups(observer.reset()))
+
+	// The cache does not hold a reference to the blob, so modifying it is a miss
+	modified := blobs[0]
+	modifyBlob(&modified, testutil.GenerateScalar(130), 0)
+	modifiedCommitment, err := cachedCtx.BlobToKZGCommitment(&modified, NumGoRoutines)
+	require.NoError(t, err)
+	require.NotEqual(t, expected[0], modifiedCommitment)
+	require.Equal(t, []string{miss}, cacheLookups(observer.reset()))
+
+	// The least recently used commitments are evicted once the cache is full
+	commit(1)
+	commit(2)
+	commit(0)
+	commit(2)
+	require.Equal(t, []string{miss, miss, miss, hit}, cacheLookups(observer.reset()))
+
+	// The batch variant uses the cache too
+	commitments, err := cachedCtx.BlobsToKZGCommitments([]gokzg4844.Blob{blobs[2], blobs[0]})
+	require.NoError(t, err)
+	require.Equal(t, []gokzg4844.KZGCommitment{expected[2], expected[0]}, commitments)
+	require.Equal(t, []string{hit, hit}, cacheLookups(observer.reset()))
+
+	// Invalid blobs are not cached
+	invalid := blobs[0]
+	modifyBlob(&invalid, testutil.GenerateNonCanonicalScalar(131), 0)
+	for i := 0; i < 2; i++ {
+		_, err = cachedCtx.BlobToKZGCommitment(&invalid, NumGoRoutines)
+		require.Error(t, err)
+	}
+	require.Equal(t, []string{miss, miss}, cacheLookups(observer.reset()))
+
+	// The cache is disabled by default and for a non-positive size
+	for _, opts := range [][]gokzg4844.ContextOption{nil, {gokzg4844.WithCommitmentCache(0)}} {
+		uncachedCtx, err := gokzg4844.NewContext4096Secure(append(opts, gokzg4844.WithObserver(observer))...)
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, err = uncachedCtx.BlobToKZGCommitment(&blobs[0], NumGoRoutines)
+			require.NoError(t, err)
+		}
+		require.Empty(t, cacheLookups(observer.reset()))
+	}
+}
+
+func TestTrustedCommitment(t *testing.T) {
+	blob := testutil.GenerateBlob(140)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	inputPoint := testutil.GenerateScalar(141)
+	pointProof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	trusted, err := ctx.ValidateCommitment(commitment)
+	require.NoError(t, err)
+	require.Equal(t, commitment, trusted.Commitment())
+	point, err := gokzg4844.DeserializeKZGCommitment(commitment)
+	require.NoError(t, err)
+	require.Equal(t, point, trusted.Point())
+	require.Equal(t, trusted, gokzg4844.UnsafeTrustedCommitmentFromPoint(point))
+
+	require.NoError(t, ctx.VerifyBlobKZGProofTrusted(blob, trusted, blobProof))
+	require.NoError(t, ctx.VerifyKZGProofTrusted(trusted, inputPoint, claimedValue, pointProof))
+	for _, cellIndex := range []uint64{0, 5, gokzg4844.CellsPerExtBlob - 1} {
+		require.NoError(t, ctx.VerifyCellKZGProofTrusted(trusted, cellIndex, &cells[cellIndex], cellProofs[cellIndex]))
+	}
+
+	// A commitment which was validated does not make invalid proofs verify
+	otherBlob := testutil.GenerateBlob(142)
+	otherCommitment, err := ctx.BlobToKZGCommitment(otherBlob, NumGoRoutines)
+	require.NoError(t, err)
+	otherTrusted, err := ctx.ValidateCommitment(otherCommitment)
+	require.NoError(t, err)
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofTrusted(blob, otherTrusted, blobProof), gokzg4844.ErrProofInvalid)
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofTrusted(otherBlob, trusted, blobProof), gokzg4844.ErrProofInvalid)
+	require.ErrorIs(t, ctx.VerifyKZGProofTrusted(otherTrusted, inputPoint, claimedValue, pointProof), gokzg4844.ErrProofInvalid)
+	require.ErrorIs(t, ctx.VerifyCellKZGProofTrusted(otherTrusted, 5, &cells[5], cellProofs[5]), gokzg4844.ErrProofInvalid)
+	require.ErrorIs(t, ctx.VerifyCellKZGProofTrusted(trusted, 6, &cells[5], cellProofs[5]), gokzg4844.ErrProofInvalid)
+
+	// The other inputs are still checked
+	require.ErrorIs(t, ctx.VerifyCellKZGProofTrusted(trusted, gokzg4844.CellsPerExtBlob, &cells[0], cellProofs[0]), gokzg4844.ErrInvalidCellIndex)
+	invalidBlob := *blob
+	modifyBlob(&invalidBlob, testutil.GenerateNonCanonicalScalar(143), 0)
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofTrusted(&invalidBlob, trusted, blobProof), gokzg4844.ErrNonCanonicalScalar)
+
+	// Invalid commitments are rejected when they are validated
+	var pointErr *gokzg4844.PointError
+	_, err = ctx.ValidateCommitment(gokzg4844.KZGCommitment{})
+	require.ErrorAs(t, err, &pointErr)
+	require.Equal(t, "commitment", pointErr.Kind)
+
+	// The zero value is rejected
+	require.ErrorIs(t, ctx.VerifyBlobKZGProofTrusted(blob, gokzg4844.TrustedCommitment{}, blobProof), gokzg4844.ErrTrustedCommitmentUnset)
+	require.ErrorIs(t, ctx.VerifyKZGProofTrusted(gokzg4844.TrustedCommitment{}, inputPoint, claimedValue, pointProof), gokzg4844.ErrTrustedCommitmentUnset)
+	require.ErrorIs(t, ctx.VerifyCellKZGProofTrusted(gokzg4844.TrustedCommitment{}, 0, &cells[0], cellProofs[0]), gokzg4844.ErrTrustedCommitmentUnset)
+}
+
+// blobWithPadding returns a random blob whose scalars at the given indices are replaced by padding, along with the
+// padding scalars. The padding only depends on the indices, and its scalars are full size so that committing to them
+// is not cheaper than committing to the rest of the blob.
+func blobWithPadding(seed int64, indices []uint64) (*gokzg4844.Blob, []fr.Element) {
+	blob := testutil.GenerateBlob(seed)
+	padding := make([]fr.Element, len(indices))
+	for i, index := range indices {
+		scalar := testutil.GenerateScalar(-1 - int64(index))
+		value, err := gokzg4844.DeserializeScalar(scalar)
+		if err != nil {
+			panic(err)
+		}
+		padding[i] = value
+		copy(blob[index*gokzg4844.SerializedScalarSize:], scalar[:])
+	}
+	return blob, padding
+}
+
+func TestPartialCommitment(t *testing.T) {
+	// The last quarter of the blob is padding, given in reverse order
+	var indices []uint64
+	for i := gokzg4844.ScalarsPerBlob - 1; i >= 3*gokzg4844.ScalarsPerBlob/4; i-- {
+		indices = append(indices, uint64(i))
+	}
+	blob, padding := blobWithPadding(150, indices)
+	partial, err := ctx.PrecomputePartialCommitment(indices, padding)
+	require.NoError(t, err)
+	require.Equal(t, indices, partial.Indices())
+
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := ctx.BlobToKZGCommitmentWithPartial(blob, partial, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// The same partial commitment is used for another blob with the same padding
+	otherBlob, _ := blobWithPadding(151, indices)
+	expected, err = ctx.BlobToKZGCommitment(otherBlob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err = ctx.BlobToKZGCommitmentWithPartial(otherBlob, partial, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// Partial commitments covering no index and every index
+	for _, covered := range [][]uint64{nil, allIndices()} {
+		coveredBlob, coveredPadding := blobWithPadding(152, covered)
+		expected, err := ctx.BlobToKZGCommitment(coveredBlob, NumGoRoutines)
+		require.NoError(t, err)
+		coveredPartial, err := ctx.PrecomputePartialCommitment(covered, coveredPadding)
+		require.NoError(t, err)
+		got, err := ctx.BlobToKZGCommitmentWithPartial(coveredBlob, coveredPartial, 1)
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+	}
+
+	// A blob which does not hold the padding
+	mismatched := *blob
+	mismatched[indices[10]*gokzg4844.SerializedScalarSize+31] ^= 1
+	_, err = ctx.BlobToKZGCommitmentWithPartial(&mismatched, partial, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrPartialCommitmentMismatch)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
+
+	// An invalid blob is rejected like by BlobToKZGCommitment
+	invalid := *blob
+	modifyBlob(&invalid, testutil.GenerateNonCanonicalScalar(153), 5*gokzg4844.SerializedScalarSize)
+	_, err = ctx.BlobToKZGCommitmentWithPartial(&invalid, partial, NumGoRoutines)
+	var scalarErr *gokzg4844.ScalarError
+	require.ErrorAs(t, err, &scalarErr)
+	require.Equal(t, 5, scalarErr.Index)
+
+	// Invalid indices
+	_, err = ctx.PrecomputePartialCommitment([]uint64{1, gokzg4844.ScalarsPerBlob}, padding[:2])
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPartialIndex)
+	_, err = ctx.PrecomputePartialCommitment([]uint64{1, 2, 1}, padding[:3])
+	require.ErrorIs(t, err, gokzg4844.ErrDuplicatePartialIndex)
+	_, err = ctx.PrecomputePartialCommitment(indices, padding[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+
+	// The zero value is rejected
+	_, err = ctx.BlobToKZGCommitmentWithPartial(blob, gokzg4844.PartialCommitment{}, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrPartialCommitmentUnset)
+}
+
+// allIndices returns the indices of every scalar of a blob.
+func allIndices() []uint64 {
+	indices := make([]uint64, gokzg4844.ScalarsPerBlob)
+	for i := range indices {
+		indices[i] = uint64(i)
+	}
+	return indices
 }
@@ -2,49 +2,16 @@ package gokzg4844_test
 
 import (
 	"bytes"
-	"crypto/sha256"
-	"encoding/binary"
 	"fmt"
-	"log"
 	"testing"
+	"time"
 
 	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
 	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
-	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
 	"github.com/stretchr/testify/require"
 )
 
-func deterministicRandomness(seed int64) [32]byte {
-	// Converts an int64 to a byte slice
-	buf := new(bytes.Buffer)
-	err := binary.Write(buf, binary.BigEndian, seed)
-	if err != nil {
-		log.Fatalf("Failed to write int64 to bytes buffer: %v", err)
-	}
-	bytes := buf.Bytes()
-
-	return sha256.Sum256(bytes)
-}
-
-// Returns a serialized random field element in big-endian
-func GetRandFieldElement(seed int64) [32]byte {
-	bytes := deterministicRandomness(seed)
-	var r fr.Element
-	r.SetBytes(bytes[:])
-
-	return gokzg4844.SerializeScalar(r)
-}
-
-func GetRandBlob(seed int64) *gokzg4844.Blob {
-	var blob gokzg4844.Blob
-	bytesPerBlob := gokzg4844.ScalarsPerBlob * gokzg4844.SerializedScalarSize
-	for i := 0; i < bytesPerBlob; i += gokzg4844.SerializedScalarSize {
-		fieldElementBytes := GetRandFieldElement(seed + int64(i))
-		copy(blob[i:i+gokzg4844.SerializedScalarSize], fieldElementBytes[:])
-	}
-	return &blob
-}
-
 func Benchmark(b *testing.B) {
 	const length = 64
 	blobs := make([]gokzg4844.Blob, length)
@@ -53,7 +20,7 @@ func Benchmark(b *testing.B) {
 	fields := make([]gokzg4844.Scalar, length)
 
 	for i := 0; i < length; i++ {
-		blob := GetRandBlob(int64(i))
+		blob := testutil.GenerateBlob(int64(i))
 		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
 		require.NoError(b, err)
 		proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
@@ -62,7 +29,7 @@ func Benchmark(b *testing.B) {
 		blobs[i] = *blob
 		commitments[i] = commitment
 		proofs[i] = proof
-		fields[i] = GetRandFieldElement(int64(i))
+		fields[i] = testutil.GenerateScalar(int64(i))
 	}
 
 	///////////////////////////////////////////////////////////////////////////
@@ -123,9 +90,71 @@ func Benchmark(b *testing.B) {
 	}
 }
 
+func BenchmarkNewContext(b *testing.B) {
+	b.Run("NewContext4096Secure", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := gokzg4844.NewContext4096Secure()
+			require.NoError(b, err)
+		}
+	})
+
+	ctx, err := gokzg4844.NewContext4096Secure()
+	require.NoError(b, err)
+	var cache bytes.Buffer
+	require.NoError(b, ctx.SaveSetupCache(&cache))
+
+	b.Run("NewContextFromSetupCache", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := gokzg4844.NewContextFromSetupCache(bytes.NewReader(cache.Bytes()))
+			require.NoError(b, err)
+		}
+	})
+}
+
+// BenchmarkBlobToKZGCommitmentLatency measures the latency of committing to a single blob depending on the number of
+// go routines, which are shared by the deserialization of the blob and the multi exponentiation.
+func BenchmarkBlobToKZGCommitmentLatency(b *testing.B) {
+	blob := testutil.GenerateBlob(13)
+	for _, numGoRoutines := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if _, err := ctx.BlobToKZGCommitment(blob, numGoRoutines); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBlobToKZGCommitmentStoredOrder compares committing with the points held in bit-reversed order, as done
+// by the contexts, with copying the polynomial into the order of the trusted setup for every commitment.
+func BenchmarkBlobToKZGCommitmentStoredOrder(b *testing.B) {
+	blob := testutil.GenerateBlob(13)
+	setupKey := setupOrderCommitKey()
+
+	b.Run("stored order", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("copied per call", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := commitInSetupOrder(blob, setupKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 func BenchmarkDeserializeBlob(b *testing.B) {
 	var (
-		blob       = GetRandBlob(int64(13))
+		blob       = testutil.GenerateBlob(int64(13))
 		first, err = gokzg4844.DeserializeBlob(blob)
 		second     kzg.Polynomial
 	)
@@ -144,3 +173,329 @@ func BenchmarkDeserializeBlob(b *testing.B) {
 		b.Fatalf("have %s want %s", have, want)
 	}
 }
+
+func BenchmarkValidateBlob(b *testing.B) {
+	blob := testutil.GenerateBlob(int64(13))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		if err := gokzg4844.ValidateBlob(blob); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBlobsToKZGCommitments(b *testing.B) {
+	const length = 16
+	blobs := make([]gokzg4844.Blob, length)
+	for i := 0; i < length; i++ {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+	}
+
+	b.Run(fmt.Sprintf("BlobToKZGCommitment(count=%v)", length), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for i := range blobs {
+				_, _ = ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+			}
+		}
+	})
+
+	b.Run(fmt.Sprintf("BlobsToKZGCommitments(count=%v)", length), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = ctx.BlobsToKZGCommitments(blobs)
+		}
+	})
+}
+
+func BenchmarkComputeBlobKZGProofBatch(b *testing.B) {
+	for _, length := range []int{6, 64} {
+		blobs := make([]gokzg4844.Blob, length)
+		commitments := make([]gokzg4844.KZGCommitment, length)
+		for i := 0; i < length; i++ {
+			blobs[i] = *testutil.GenerateBlob(int64(i))
+			commitment, err := ctx.BlobToKZGCommitment(&blobs[i], NumGoRoutines)
+			require.NoError(b, err)
+			commitments[i] = commitment
+		}
+
+		b.Run(fmt.Sprintf("ComputeBlobKZGProof(count=%v)", length), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				for i := range blobs {
+					_, _ = ctx.ComputeBlobKZGProof(&blobs[i], commitments[i], NumGoRoutines)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("ComputeBlobKZGProofBatch(count=%v)", length), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+			}
+		})
+	}
+}
+
+func BenchmarkComputeCellsAndKZGProofs(b *testing.B) {
+	blob := testutil.GenerateBlob(int64(13))
+
+	b.Run("ComputeCells", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = ctx.ComputeCells(blob)
+		}
+	})
+
+	// The first call computes the FK20 precomputations
+	_, _, err := ctx.ComputeCellsAndKZGProofs(blob)
+	require.NoError(b, err)
+	b.Run("ComputeCellsAndKZGProofs", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _, _ = ctx.ComputeCellsAndKZGProofs(blob)
+		}
+	})
+}
+
+func BenchmarkVerifyBlobKZGProofBatch(b *testing.B) {
+	const maxLength = 64
+	blobs := make([]gokzg4844.Blob, maxLength)
+	commitments := make([]gokzg4844.KZGCommitment, maxLength)
+	proofs := make([]gokzg4844.KZGProof, maxLength)
+	for i := 0; i < maxLength; i++ {
+		blob := testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(b, err)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+		require.NoError(b, err)
+
+		blobs[i] = *blob
+		commitments[i] = commitment
+		proofs[i] = proof
+	}
+
+	ctxSingleThreaded, err := gokzg4844.NewContext4096Secure(gokzg4844.WithNumGoRoutines(1))
+	require.NoError(b, err)
+	ctxTrusted, err := gokzg4844.NewContext4096Secure(gokzg4844.WithTrustedCommitments())
+	require.NoError(b, err)
+
+	contexts := []struct {
+		name string
+		ctx  *gokzg4844.Context
+	}{
+		{"goroutines=1", ctxSingleThreaded},
+		{"goroutines=default", ctx},
+		{"goroutines=default/trusted_commitments", ctxTrusted},
+	}
+	for _, length := range []int{1, 16, 64} {
+		for _, benchCtx := range contexts {
+			benchCtx := benchCtx
+			b.Run(fmt.Sprintf("count=%d/%s", length, benchCtx.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					err := benchCtx.ctx.VerifyBlobKZGProofBatch(blobs[:length], commitments[:length], proofs[:length])
+					require.NoError(b, err)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkVerifyBlobKZGProofBatchUncompressed(b *testing.B) {
+	const length = 64
+	blobs := make([]gokzg4844.Blob, length)
+	commitments := make([]gokzg4844.KZGCommitment, length)
+	proofs := make([]gokzg4844.KZGProof, length)
+	commitmentsUncompressed := make([]gokzg4844.KZGCommitmentUncompressed, length)
+	proofsUncompressed := make([]gokzg4844.KZGProofUncompressed, length)
+	for i := 0; i < length; i++ {
+		blob := testutil.GenerateBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(b, err)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+		require.NoError(b, err)
+
+		blobs[i] = *blob
+		commitments[i] = commitment
+		proofs[i] = proof
+		commitmentsUncompressed[i], err = gokzg4844.SerializeKZGCommitmentUncompressed(commitment)
+		require.NoError(b, err)
+		proofsUncompressed[i], err = gokzg4844.SerializeKZGProofUncompressed(proof)
+		require.NoError(b, err)
+	}
+
+	b.Run(fmt.Sprintf("compressed(count=%d)", length), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			require.NoError(b, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+		}
+	})
+
+	b.Run(fmt.Sprintf("uncompressed(count=%d)", length), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			require.NoError(b, ctx.VerifyBlobKZGProofBatchUncompressed(blobs, commitmentsUncompressed, proofsUncompressed))
+		}
+	})
+}
+
+// BenchmarkBlobToKZGCommitmentWithPartial commits to a blob whose last 1024 of 4096 scalars are covered by a partial
+// commitment, against committing to the whole blob.
+func BenchmarkBlobToKZGCommitmentWithPartial(b *testing.B) {
+	const numCovered = 1024
+	indices := make([]uint64, numCovered)
+	for i := range indices {
+		indices[i] = uint64(gokzg4844.ScalarsPerBlob - numCovered + i)
+	}
+	blob, padding := blobWithPadding(160, indices)
+	partial, err := ctx.PrecomputePartialCommitment(indices, padding)
+	require.NoError(b, err)
+
+	b.Run("full", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run(fmt.Sprintf("partial(covered=%d)", numCovered), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, err := ctx.BlobToKZGCommitmentWithPartial(blob, partial, NumGoRoutines)
+			require.NoError(b, err)
+		}
+	})
+}
+
+// BenchmarkVerifyRepeatedCommitments verifies batches of 128 items which only hold 2 distinct commitments, as in a
+// batch of cells from two blobs or a block where the same blob is proven several times.
+func BenchmarkVerifyRepeatedCommitments(b *testing.B) {
+	const (
+		length         = 128
+		numCommitments = 2
+	)
+	blobs := make([]gokzg4844.Blob, length)
+	commitments := make([]gokzg4844.KZGCommitment, length)
+	proofs := make([]gokzg4844.KZGProof, length)
+	cellIndices := make([]uint64, length)
+	cells := make([]gokzg4844.Cell, length)
+	cellProofs := make([]gokzg4844.KZGProof, length)
+	for i := 0; i < numCommitments; i++ {
+		fixture, err := testutil.GenerateProofFixture(ctx, int64(i))
+		require.NoError(b, err)
+		blobCells, blobCellProofs, err := ctx.ComputeCellsAndKZGProofs(fixture.Blob)
+		require.NoError(b, err)
+		for j := i; j < length; j += numCommitments {
+			blobs[j] = *fixture.Blob
+			commitments[j] = fixture.Commitment
+			proofs[j] = fixture.Proof
+			cellIndices[j] = uint64(j)
+			cells[j] = blobCells[j]
+			cellProofs[j] = blobCellProofs[j]
+		}
+	}
+
+	b.Run(fmt.Sprintf("blobs(count=%d,commitments=%d)", length, numCommitments), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			require.NoError(b, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+		}
+	})
+
+	b.Run(fmt.Sprintf("cells(count=%d,commitments=%d)", length, numCommitments), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			require.NoError(b, ctx.VerifyCellKZGProofBatch(commitments, cellIndices, cells, cellProofs))
+		}
+	})
+}
+
+type nopObserver struct{}
+
+func (nopObserver) OnOperation(string, int, time.Duration) {}
+
+func BenchmarkObserver(b *testing.B) {
+	const numBlobs = 6
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+	}
+	commitments, err := ctx.BlobsToKZGCommitments(blobs)
+	require.NoError(b, err)
+	proofs, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+	require.NoError(b, err)
+
+	observedCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithObserver(nopObserver{}))
+	require.NoError(b, err)
+
+	for _, test := range []struct {
+		name string
+		ctx  *gokzg4844.Context
+	}{{"Disabled", ctx}, {"Enabled", observedCtx}} {
+		b.Run(fmt.Sprintf("BlobToKZGCommitment/%s", test.name), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = test.ctx.BlobToKZGCommitment(&blobs[0], NumGoRoutines)
+			}
+		})
+		b.Run(fmt.Sprintf("VerifyBlobKZGProofBatch(count=%d)/%s", numBlobs, test.name), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_ = test.ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+			}
+		})
+	}
+}
+
+func BenchmarkTrustedCommitment(b *testing.B) {
+	blob := testutil.GenerateBlob(13)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(b, err)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	require.NoError(b, err)
+	inputPoint := testutil.GenerateScalar(14)
+	pointProof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(b, err)
+	trusted, err := ctx.ValidateCommitment(commitment)
+	require.NoError(b, err)
+
+	// The saving of each verification is the time taken by ValidateCommitment
+	b.Run("VerifyKZGProof", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_ = ctx.VerifyKZGProof(commitment, inputPoint, claimedValue, pointProof)
+		}
+	})
+
+	b.Run("VerifyKZGProofTrusted", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_ = ctx.VerifyKZGProofTrusted(trusted, inputPoint, claimedValue, pointProof)
+		}
+	})
+
+	b.Run("VerifyCellKZGProof", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			i := uint64(n % gokzg4844.CellsPerExtBlob)
+			_ = ctx.VerifyCellKZGProof(commitment, i, &cells[i], proofs[i])
+		}
+	})
+
+	b.Run("VerifyCellKZGProofTrusted", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			i := uint64(n % gokzg4844.CellsPerExtBlob)
+			_ = ctx.VerifyCellKZGProofTrusted(trusted, i, &cells[i], proofs[i])
+		}
+	})
+
+	b.Run("ValidateCommitment", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = ctx.ValidateCommitment(commitment)
+		}
+	})
+}
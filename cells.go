@@ -0,0 +1,380 @@
+package gokzg4844
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// ComputeCells implements [compute_cells] from EIP-7594. It extends the blob to twice its size and returns the
+// extension split into [CellsPerExtBlob] cells.
+//
+// The extended blob holds the evaluations of the polynomial represented by `blob` over the domain of size
+// 2*[ScalarsPerBlob], in bit-reversed order. Its first half is therefore `blob` itself, and the second half holds
+// the evaluations over the coset of the domain of the blob, which are computed with a coset FFT.
+//
+// [compute_cells]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_cells
+func (c *Context) ComputeCells(blob *Blob) ([CellsPerExtBlob]Cell, error) {
+	if !c.calls.acquire() {
+		return [CellsPerExtBlob]Cell{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, err
+	}
+
+	// 2. Extension
+	//
+	extended, err := c.domain.ExtendPolynomial(polynomial)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, err
+	}
+
+	// 3. Serialization
+	//
+	return serializeCells(extended), nil
+}
+
+// ComputeCellsAndKZGProofs implements [compute_cells_and_kzg_proofs] from EIP-7594. It returns the cells computed by
+// [Context.ComputeCells] and, for each of them, the proof that the polynomial represented by `blob` evaluates to the
+// scalars of the cell over its coset, which can be checked using [Context.VerifyCellKZGProof].
+//
+// The proofs are computed at once using the FK20 algorithm, in O(n log n) group operations rather than one multi
+// exponentiation per cell. This needs the monomial G1 points of the trusted setup, so [ErrMonomialSRSUnavailable] is
+// returned if the [Context] does not hold them. The FK20 precomputations are done on the first call and cached.
+//
+// [compute_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_cells_and_kzg_proofs
+func (c *Context) ComputeCellsAndKZGProofs(blob *Blob) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	return c.ComputeCellsAndKZGProofsCtx(context.Background(), blob)
+}
+
+// ComputeCellsAndKZGProofsCtx is [Context.ComputeCellsAndKZGProofs] which stops early and returns ctx.Err() if ctx is
+// done before the proofs have been computed.
+//
+// The context is checked between the steps of the computation, and before each of the multi exponentiations of FK20,
+// which cannot be interrupted. This bounds the time it takes to stop the computation of the cells of many blobs.
+func (c *Context) ComputeCellsAndKZGProofsCtx(ctx context.Context, blob *Blob) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	if !c.calls.acquire() {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	fk, err := c.cellProofKey()
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 2. Extension and proofs
+	//
+	return c.computeCellsAndKZGProofs(ctx, polynomial, fk)
+}
+
+// RecoverCellsAndKZGProofs implements [recover_cells_and_kzg_proofs] from EIP-7594. Given at least half of the cells of
+// an extended blob, where cells[i] is the cell at index cellIndices[i], it recovers all of the cells and returns them
+// with their proofs, as [Context.ComputeCellsAndKZGProofs] would for the original blob.
+//
+// The cell indices need not be sorted, but they must be distinct and less than [CellsPerExtBlob]. If a cell index or
+// a cell is invalid, a [*BlobError] holding its index in the input is returned. The cells are not checked against
+// each other: if they do not come from the same blob, the recovered cells do not match them. Like
+// [Context.ComputeCellsAndKZGProofs], this needs the monomial G1 points of the trusted setup.
+//
+// [recover_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#recover_cells_and_kzg_proofs
+func (c *Context) RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	return c.RecoverCellsAndKZGProofsCtx(context.Background(), cellIndices, cells)
+}
+
+// RecoverCellsAndKZGProofsCtx is [Context.RecoverCellsAndKZGProofs] which stops early and returns ctx.Err() if ctx is
+// done before the cells and proofs have been recovered.
+//
+// The context is checked after the recovery of the blob, and then as described in
+// [Context.ComputeCellsAndKZGProofsCtx].
+func (c *Context) RecoverCellsAndKZGProofsCtx(ctx context.Context, cellIndices []uint64, cells []Cell) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	if !c.calls.acquire() {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	if err := checkBatchLengths([]string{"cell indices", "cells"}, len(cellIndices), len(cells)); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+	fk, err := c.cellProofKey()
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 1. Deserialization
+	//
+	var seen [CellsPerExtBlob]bool
+	cellsScalars := make([][]fr.Element, len(cells))
+	for i, cellIndex := range cellIndices {
+		if cellIndex >= CellsPerExtBlob {
+			return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, &BlobError{Index: i, Err: fmt.Errorf("%w: got %d", ErrInvalidCellIndex, cellIndex)}
+		}
+		if seen[cellIndex] {
+			return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, &BlobError{Index: i, Err: fmt.Errorf("%w: %d is repeated", ErrDuplicateCellIndex, cellIndex)}
+		}
+		seen[cellIndex] = true
+
+		cellsScalars[i], err = deserializeCell(&cells[i])
+		if err != nil {
+			return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, &BlobError{Index: i, Err: err}
+		}
+	}
+	if len(cellIndices) < CellsPerExtBlob/2 {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, fmt.Errorf("%w: got %d", ErrNotEnoughCells, len(cellIndices))
+	}
+
+	// 2. Recovery of the blob
+	//
+	polynomial, err := c.domain.RecoverPolynomial(FieldElementsPerCell, cellIndices, cellsScalars)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 3. Extension and proofs
+	//
+	return c.computeCellsAndKZGProofs(ctx, polynomial, fk)
+}
+
+// VerifyCellKZGProof checks the proof that the polynomial committed to in `commitment` evaluates to the scalars of
+// `cell` over the coset of the cell at index `cellIndex`, as returned by [Context.ComputeCellsAndKZGProofs].
+//
+// It returns [ErrInvalidCellIndex] if cellIndex is not less than [CellsPerExtBlob], and the errors of
+// [Context.VerifyKZGProof] otherwise.
+func (c *Context) VerifyCellKZGProof(commitment KZGCommitment, cellIndex uint64, cell *Cell, proof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization
+	//
+	if cellIndex >= CellsPerExtBlob {
+		return fmt.Errorf("%w: got %d", ErrInvalidCellIndex, cellIndex)
+	}
+
+	polynomialCommitment, err := c.deserializeKZGCommitment(commitment)
+	if err != nil {
+		return err
+	}
+
+	return c.verifyCellKZGProof(&polynomialCommitment, cellIndex, cell, proof)
+}
+
+// verifyCellKZGProof implements [Context.VerifyCellKZGProof] for a commitment which has already been deserialized and
+// a cell index which has already been checked.
+func (c *Context) verifyCellKZGProof(polynomialCommitment *bls12381.G1Affine, cellIndex uint64, cell *Cell, proof KZGProof) error {
+	quotientCommitment, err := c.deserializeKZGProof(proof)
+	if err != nil {
+		return err
+	}
+
+	claimedValues, err := deserializeCell(cell)
+	if err != nil {
+		return err
+	}
+
+	// 2. Verify opening proof over the coset of the cell
+	//
+	openingProof := kzg.MultiOpeningProof{
+		QuotientCommitment: quotientCommitment,
+		InputPoints:        c.cellCoset(cellIndex),
+		ClaimedValues:      claimedValues,
+	}
+
+	return kzg.VerifyMulti(polynomialCommitment, &openingProof, c.openKey, c.numGoRoutines)
+}
+
+// VerifyCellKZGProofBatch implements [verify_cell_kzg_proof_batch] from EIP-7594: it checks that, for each i, proofs[i]
+// is a valid proof for the cell at index cellIndices[i] of the blob committed to in commitments[i]. This is faster
+// than calling [Context.VerifyCellKZGProof] for each cell, since the checks are combined into a single pairing check
+// with two pairings.
+//
+// The cells can come from several blobs, and each distinct commitment is only deserialized and checked once however
+// many of its cells are verified. If any of the inputs is invalid, a [*BlobError] holding its index in the batch is
+// returned, wrapping [ErrInvalidCellIndex] for a cell index which is not less than [CellsPerExtBlob]. This needs the
+// monomial G1 points of the trusted setup, so [ErrMonomialSRSUnavailable] is returned if the [Context] does not hold
+// them.
+//
+// [verify_cell_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch
+func (c *Context) VerifyCellKZGProofBatch(commitments []KZGCommitment, cellIndices []uint64, cells []Cell, proofs []KZGProof) error {
+	return c.VerifyCellKZGProofBatchCtx(context.Background(), commitments, cellIndices, cells, proofs)
+}
+
+// VerifyCellKZGProofBatchCtx is [Context.VerifyCellKZGProofBatch] which stops early and returns ctx.Err() if ctx is
+// done before the batch has been verified.
+//
+// The context is checked while deserializing the commitments, the proofs and each cell. The final check, made of two
+// multi exponentiations and a pairing check, cannot be interrupted.
+func (c *Context) VerifyCellKZGProofBatchCtx(ctx context.Context, commitments []KZGCommitment, cellIndices []uint64, cells []Cell, proofs []KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Check that all components in the batch have the same size
+	//
+	batchSize := len(commitments)
+	if err := checkBatchLengths([]string{"commitments", "cell indices", "cells", "proofs"}, batchSize, len(cellIndices), len(cells), len(proofs)); err != nil {
+		return err
+	}
+	if c.monomialCommitKey == nil {
+		return ErrMonomialSRSUnavailable
+	}
+	if batchSize == 0 {
+		return nil
+	}
+
+	// 2. Deserialization
+	//
+	// Each distinct commitment is deserialized once, and errors are reported at its first index in the batch
+	polynomialCommitments, commitmentIndices, err := c.deserializeUniqueKZGCommitments(ctx, commitments)
+	if err != nil {
+		return err
+	}
+
+	quotientCommitments, err := c.deserializeKZGProofs(ctx, proofs)
+	if err != nil {
+		return err
+	}
+
+	cosetProofs := make([]kzg.CosetOpeningProof, batchSize)
+	for i := range cells {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if cellIndices[i] >= CellsPerExtBlob {
+			return &BlobError{Index: i, Err: fmt.Errorf("%w: got %d", ErrInvalidCellIndex, cellIndices[i])}
+		}
+		claimedValues, err := deserializeCell(&cells[i])
+		if err != nil {
+			return &BlobError{Index: i, Err: err}
+		}
+		cosetProofs[i] = kzg.CosetOpeningProof{
+			QuotientCommitment: quotientCommitments[i],
+			CosetShift:         c.cellCoset(cellIndices[i])[0],
+			ClaimedValues:      claimedValues,
+		}
+	}
+
+	// 3. Verify the opening proofs over the cosets of the cells
+	//
+	return kzg.BatchVerifyCosets(polynomialCommitments, commitmentIndices, cosetProofs, c.cellCosetDomain(), c.monomialCommitKey, c.openKey, c.numGoRoutines, c.randomSource)
+}
+
+// cellCoset returns the points of the extended domain over which the cell at index `cellIndex` holds the evaluations
+// of the extended blob. The slice must not be modified.
+func (c *Context) cellCoset(cellIndex uint64) []fr.Element {
+	return c.extendedDomain().Roots[cellIndex*FieldElementsPerCell : (cellIndex+1)*FieldElementsPerCell]
+}
+
+// cellCosetDomain returns the bit-reversed domain of size [FieldElementsPerCell], so that the coset of the cell at
+// index i is made of the roots of this domain multiplied by the first point of the coset, in the same order. It is
+// computed on the first call and cached.
+func (c *Context) cellCosetDomain() *kzg.Domain {
+	c.cellCosetDomainOnce.Do(func() {
+		domain := kzg.NewDomain(FieldElementsPerCell)
+		domain.ReverseRoots()
+		c.cellCosetDomainCache = domain
+		c.extendedDomainSize.Add(int64(domain.MemorySize()))
+	})
+	return c.cellCosetDomainCache
+}
+
+// extendedDomain returns the bit-reversed domain of size 2*[ScalarsPerBlob] over which blobs are extended. It is
+// computed on the first call and cached.
+func (c *Context) extendedDomain() *kzg.Domain {
+	c.extendedDomainOnce.Do(func() {
+		domain := kzg.NewDomain(2 * ScalarsPerBlob)
+		domain.ReverseRoots()
+		c.extendedDomainCache = domain
+		c.extendedDomainSize.Store(int64(domain.MemorySize()))
+	})
+	return c.extendedDomainCache
+}
+
+// cellProofKey returns the FK20 precomputations used by [Context.ComputeCellsAndKZGProofs]. They are computed on the
+// first call and cached.
+func (c *Context) cellProofKey() (*kzg.FK20Key, error) {
+	if c.monomialCommitKey == nil {
+		return nil, ErrMonomialSRSUnavailable
+	}
+	if c.domain.Cardinality != ScalarsPerBlob {
+		return nil, ErrContextSizeMismatch
+	}
+	c.cellProofKeyOnce.Do(func() {
+		c.cellProofKeyCache, c.cellProofKeyErr = kzg.NewFK20Key(c.monomialCommitKey, ScalarsPerBlob, FieldElementsPerCell)
+		if c.cellProofKeyErr == nil {
+			c.cellProofKeySize.Store(int64(c.cellProofKeyCache.MemorySize()))
+		}
+	})
+	return c.cellProofKeyCache, c.cellProofKeyErr
+}
+
+// computeCellsAndKZGProofs extends the polynomial and returns the resulting cells with their proofs, computed using fk.
+// ctx is checked before each step, see [Context.ComputeCellsAndKZGProofsCtx].
+func (c *Context) computeCellsAndKZGProofs(ctx context.Context, polynomial kzg.Polynomial, fk *kzg.FK20Key) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	if err := ctx.Err(); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+	extended, err := c.domain.ExtendPolynomial(polynomial)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+	proofs, err := kzg.ComputeCosetProofs(ctx, c.domain, polynomial, fk)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	var serProofs [CellsPerExtBlob]KZGProof
+	for i := range proofs {
+		serProofs[i] = KZGProof(SerializeG1Point(proofs[i]))
+	}
+
+	return serializeCells(extended), serProofs, nil
+}
+
+// serializeCells splits the extension of a blob, in bit-reversed order, into cells.
+func serializeCells(extended []fr.Element) [CellsPerExtBlob]Cell {
+	var cells [CellsPerExtBlob]Cell
+	for i := range extended {
+		cell := &cells[i/FieldElementsPerCell]
+		offset := (i % FieldElementsPerCell) * SerializedScalarSize
+		scalar := SerializeScalar(extended[i])
+		copy(cell[offset:offset+SerializedScalarSize], scalar[:])
+	}
+	return cells
+}
+
+// deserializeCell converts a cell to its scalars. The error is a [*ScalarError] holding the index of the first
+// non-canonical scalar in the cell.
+func deserializeCell(cell *Cell) ([]fr.Element, error) {
+	scalars := make([]fr.Element, FieldElementsPerCell)
+	for i := range scalars {
+		var serScalar Scalar
+		copy(serScalar[:], cell[i*SerializedScalarSize:(i+1)*SerializedScalarSize])
+		scalar, err := DeserializeScalar(serScalar)
+		if err != nil {
+			return nil, &ScalarError{Index: i}
+		}
+		scalars[i] = scalar
+	}
+	return scalars, nil
+}
@@ -0,0 +1,286 @@
+package gokzg4844_test
+
+import (
+	"math/rand"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCells(t *testing.T) {
+	blob := testutil.GenerateBlob(7)
+	cells, err := ctx.ComputeCells(blob)
+	require.NoError(t, err)
+
+	// The first half of the extension is the blob itself
+	var firstHalf []byte
+	for _, cell := range cells[:gokzg4844.CellsPerExtBlob/2] {
+		firstHalf = append(firstHalf, cell[:]...)
+	}
+	require.Equal(t, blob[:], firstHalf)
+
+	// The extension holds the evaluations of the blob over the extended domain in bit-reversed order
+	extendedDomain := kzg.NewDomain(2 * gokzg4844.ScalarsPerBlob)
+	extendedDomain.ReverseRoots()
+	for _, cellIndex := range []int{0, 63, 64, 100, gokzg4844.CellsPerExtBlob - 1} {
+		points := make([]gokzg4844.Scalar, gokzg4844.FieldElementsPerCell)
+		for j := range points {
+			points[j] = gokzg4844.SerializeScalar(extendedDomain.Roots[cellIndex*gokzg4844.FieldElementsPerCell+j])
+		}
+		values, err := ctx.EvaluateBlobAtPoints(blob, points)
+		require.NoError(t, err)
+		for j, value := range values {
+			offset := j * gokzg4844.SerializedScalarSize
+			require.Equal(t, value[:], cells[cellIndex][offset:offset+gokzg4844.SerializedScalarSize], "cell %d, scalar %d", cellIndex, j)
+		}
+	}
+
+	// The extension of the zero blob is zero
+	cells, err = ctx.ComputeCells(&gokzg4844.Blob{})
+	require.NoError(t, err)
+	require.Equal(t, [gokzg4844.CellsPerExtBlob]gokzg4844.Cell{}, cells)
+
+	modifyBlob(blob, testutil.GenerateNonCanonicalScalar(7), 10*gokzg4844.SerializedScalarSize)
+	_, err = ctx.ComputeCells(blob)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestComputeCellsAndKZGProofs(t *testing.T) {
+	blob := testutil.GenerateBlob(8)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	expectedCells, err := ctx.ComputeCells(blob)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, cells)
+
+	// Verify a sample of the proofs, including the first and last cells of both halves of the extension
+	for _, cellIndex := range []uint64{0, 1, 63, 64, 99, gokzg4844.CellsPerExtBlob - 1} {
+		require.NoError(t, ctx.VerifyCellKZGProof(commitment, cellIndex, &cells[cellIndex], proofs[cellIndex]))
+	}
+
+	// A proof does not verify for another cell or another commitment
+	err = ctx.VerifyCellKZGProof(commitment, 2, &cells[2], proofs[3])
+	require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+	err = ctx.VerifyCellKZGProof(commitment, 3, &cells[2], proofs[2])
+	require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+	otherCommitment, err := ctx.BlobToKZGCommitment(testutil.GenerateBlob(9), NumGoRoutines)
+	require.NoError(t, err)
+	err = ctx.VerifyCellKZGProof(otherCommitment, 2, &cells[2], proofs[2])
+	require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+
+	// Invalid inputs
+	err = ctx.VerifyCellKZGProof(commitment, gokzg4844.CellsPerExtBlob, &cells[0], proofs[0])
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidCellIndex)
+	invalidCell := cells[5]
+	nonCanonical := testutil.GenerateNonCanonicalScalar(5)
+	copy(invalidCell[3*gokzg4844.SerializedScalarSize:], nonCanonical[:])
+	err = ctx.VerifyCellKZGProof(commitment, 5, &invalidCell, proofs[5])
+	var scalarErr *gokzg4844.ScalarError
+	require.ErrorAs(t, err, &scalarErr)
+	require.Equal(t, 3, scalarErr.Index)
+
+	modifyBlob(blob, testutil.GenerateNonCanonicalScalar(8), 0)
+	_, _, err = ctx.ComputeCellsAndKZGProofs(blob)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestComputeCellsAndKZGProofsWithoutMonomialSRS(t *testing.T) {
+	ctxNoMonomial, err := gokzg4844.NewContext4096Secure(gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+
+	_, _, err = ctxNoMonomial.ComputeCellsAndKZGProofs(testutil.GenerateBlob(1))
+	require.ErrorIs(t, err, gokzg4844.ErrMonomialSRSUnavailable)
+}
+
+func TestVerifyCellKZGProofBatch(t *testing.T) {
+	const numBlobs = 3
+	var (
+		commitments []gokzg4844.KZGCommitment
+		cellIndices []uint64
+		cells       []gokzg4844.Cell
+		proofs      []gokzg4844.KZGProof
+	)
+	for i := 0; i < numBlobs; i++ {
+		blob := testutil.GenerateBlob(int64(20 + i))
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		blobCells, blobProofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+		require.NoError(t, err)
+
+		// Several cells of each blob, so that the commitments are repeated
+		for _, cellIndex := range []uint64{uint64(i), 64 + uint64(i), gokzg4844.CellsPerExtBlob - 1} {
+			commitments = append(commitments, commitment)
+			cellIndices = append(cellIndices, cellIndex)
+			cells = append(cells, blobCells[cellIndex])
+			proofs = append(proofs, blobProofs[cellIndex])
+		}
+	}
+	require.NoError(t, ctx.VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs))
+	require.NoError(t, ctx.VerifyCellKZGProofBatch(commitments[:1], cellIndices[:1], cells[:1], proofs[:1]))
+	require.NoError(t, ctx.VerifyCellKZGProofBatch(nil, nil, nil, nil))
+
+	// A wrong cell index
+	wrongIndices := append([]uint64{}, cellIndices...)
+	wrongIndices[4] = 5
+	err := ctx.VerifyCellKZGProofBatch(commitments, wrongIndices, cells, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+
+	// Swapped proofs
+	swappedProofs := append([]gokzg4844.KZGProof{}, proofs...)
+	swappedProofs[0], swappedProofs[1] = swappedProofs[1], swappedProofs[0]
+	err = ctx.VerifyCellKZGProofBatch(commitments, cellIndices, cells, swappedProofs)
+	require.ErrorIs(t, err, gokzg4844.ErrProofInvalid)
+
+	// Invalid inputs are reported with their index in the batch
+	outOfRange := append([]uint64{}, cellIndices...)
+	outOfRange[7] = gokzg4844.CellsPerExtBlob
+	err = ctx.VerifyCellKZGProofBatch(commitments, outOfRange, cells, proofs)
+	var blobErr *gokzg4844.BlobError
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 7, blobErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidCellIndex)
+
+	invalidCommitments := append([]gokzg4844.KZGCommitment{}, commitments...)
+	for _, i := range []int{4, 5} {
+		invalidCommitments[i] = gokzg4844.KZGCommitment{0x9f}
+	}
+	err = ctx.VerifyCellKZGProofBatch(invalidCommitments, cellIndices, cells, proofs)
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 4, blobErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPointEncoding)
+
+	err = ctx.VerifyCellKZGProofBatch(commitments, cellIndices[1:], cells, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+}
+
+func TestRecoverCellsAndKZGProofs(t *testing.T) {
+	blob := testutil.GenerateBlob(30)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	// Drop a random half of the cells, and give the others in a random order
+	rng := rand.New(rand.NewSource(30))
+	var cellIndices []uint64
+	var knownCells []gokzg4844.Cell
+	for _, i := range rng.Perm(gokzg4844.CellsPerExtBlob)[:gokzg4844.CellsPerExtBlob/2] {
+		cellIndices = append(cellIndices, uint64(i))
+		knownCells = append(knownCells, cells[i])
+	}
+	recoveredCells, recoveredProofs, err := ctx.RecoverCellsAndKZGProofs(cellIndices, knownCells)
+	require.NoError(t, err)
+	require.Equal(t, cells, recoveredCells)
+	require.Equal(t, proofs, recoveredProofs)
+
+	// The first half of the cells is the blob itself
+	var recoveredBlob []byte
+	for _, cell := range recoveredCells[:gokzg4844.CellsPerExtBlob/2] {
+		recoveredBlob = append(recoveredBlob, cell[:]...)
+	}
+	require.Equal(t, blob[:], recoveredBlob)
+
+	// All of the cells
+	allIndices := make([]uint64, gokzg4844.CellsPerExtBlob)
+	for i := range allIndices {
+		allIndices[i] = uint64(i)
+	}
+	recoveredCells, _, err = ctx.RecoverCellsAndKZGProofs(allIndices, cells[:])
+	require.NoError(t, err)
+	require.Equal(t, cells, recoveredCells)
+
+	// Invalid inputs
+	_, _, err = ctx.RecoverCellsAndKZGProofs(cellIndices[1:], knownCells[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrNotEnoughCells)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
+
+	_, _, err = ctx.RecoverCellsAndKZGProofs(cellIndices[1:], knownCells)
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+
+	var blobErr *gokzg4844.BlobError
+	duplicate := append([]uint64{}, cellIndices...)
+	duplicate[5] = duplicate[2]
+	_, _, err = ctx.RecoverCellsAndKZGProofs(duplicate, knownCells)
+	require.ErrorIs(t, err, gokzg4844.ErrDuplicateCellIndex)
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 5, blobErr.Index)
+
+	outOfRange := append([]uint64{}, cellIndices...)
+	outOfRange[3] = gokzg4844.CellsPerExtBlob
+	_, _, err = ctx.RecoverCellsAndKZGProofs(outOfRange, knownCells)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidCellIndex)
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 3, blobErr.Index)
+
+	nonCanonical := append([]gokzg4844.Cell{}, knownCells...)
+	scalar := testutil.GenerateNonCanonicalScalar(30)
+	copy(nonCanonical[7][gokzg4844.SerializedScalarSize:], scalar[:])
+	_, _, err = ctx.RecoverCellsAndKZGProofs(cellIndices, nonCanonical)
+	var scalarErr *gokzg4844.ScalarError
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 7, blobErr.Index)
+	require.ErrorAs(t, err, &scalarErr)
+	require.Equal(t, 1, scalarErr.Index)
+}
+
+func TestExtendBlob(t *testing.T) {
+	blob := testutil.GenerateBlob(40)
+	extended, err := ctx.ExtendBlob(blob)
+	require.NoError(t, err)
+
+	// The first half of the extension is the blob itself, and the extension is made of the cells
+	cells, err := ctx.ComputeCells(blob)
+	require.NoError(t, err)
+	for i := range extended {
+		scalar := gokzg4844.SerializeScalar(extended[i])
+		if i < gokzg4844.ScalarsPerBlob {
+			require.Equal(t, blob[i*gokzg4844.SerializedScalarSize:(i+1)*gokzg4844.SerializedScalarSize], scalar[:])
+		}
+		cell := cells[i/gokzg4844.FieldElementsPerCell]
+		offset := (i % gokzg4844.FieldElementsPerCell) * gokzg4844.SerializedScalarSize
+		require.Equal(t, cell[offset:offset+gokzg4844.SerializedScalarSize], scalar[:])
+	}
+
+	// Recovery from a random half of the evaluations
+	rng := rand.New(rand.NewSource(40))
+	var indices []uint64
+	var evaluations []fr.Element
+	for _, i := range rng.Perm(len(extended))[:gokzg4844.ScalarsPerBlob] {
+		indices = append(indices, uint64(i))
+		evaluations = append(evaluations, extended[i])
+	}
+	recovered, err := ctx.RecoverBlob(indices, evaluations)
+	require.NoError(t, err)
+	require.Equal(t, blob, recovered)
+
+	// Invalid inputs
+	_, err = ctx.RecoverBlob(indices[1:], evaluations[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrNotEnoughEvaluations)
+
+	_, err = ctx.RecoverBlob(indices, evaluations[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+
+	var blobErr *gokzg4844.BlobError
+	duplicate := append([]uint64{}, indices...)
+	duplicate[9] = duplicate[4]
+	_, err = ctx.RecoverBlob(duplicate, evaluations)
+	require.ErrorIs(t, err, gokzg4844.ErrDuplicateExtensionIndex)
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 9, blobErr.Index)
+
+	outOfRange := append([]uint64{}, indices...)
+	outOfRange[6] = 2 * gokzg4844.ScalarsPerBlob
+	_, err = ctx.RecoverBlob(outOfRange, evaluations)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidExtensionIndex)
+	require.ErrorAs(t, err, &blobErr)
+	require.Equal(t, 6, blobErr.Index)
+
+	modifyBlob(blob, testutil.GenerateNonCanonicalScalar(40), 0)
+	_, err = ctx.ExtendBlob(blob)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
@@ -0,0 +1,156 @@
+//go:build ckzg_compare
+
+package ckzgcompare
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// Both libraries load the trusted setup from the root of the repository, which is the one embedded in each of them
+const trustedSetupPath = "../trusted_setup.txt"
+
+var (
+	numInputs = flag.Int("ckzg.inputs", 2048, "the number of inputs given to both libraries")
+	seed      = flag.Int64("ckzg.seed", 0, "the seed of the inputs")
+)
+
+// Every mismatch dumps the whole input, so a test stops after this many of them
+const maxMismatches = 8
+
+var (
+	ctx    *gokzg4844.Context
+	inputs []Input
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	if err := ckzg4844.LoadTrustedSetupFile(trustedSetupPath, 0); err != nil {
+		fmt.Fprintln(os.Stderr, "could not load the trusted setup into c-kzg-4844:", err)
+		os.Exit(1)
+	}
+	file, err := os.Open(trustedSetupPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	ctx, err = gokzg4844.NewContext4096FromTextReader(file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not create the context:", err)
+		os.Exit(1)
+	}
+	inputs, err = GenerateInputs(ctx, *seed, *numInputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not generate the inputs:", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	ckzg4844.FreeTrustedSetup()
+	os.Exit(code)
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	m := &mismatches{t: t}
+	for i := range inputs {
+		in := &inputs[i]
+		goCommitment, goErr := ctx.BlobToKZGCommitment(in.Blob, 0)
+		cCommitment, cErr := ckzg4844.BlobToKZGCommitment((*ckzg4844.Blob)(in.Blob))
+		compareOutputs(m, "BlobToKZGCommitment", in, goCommitment[:], goErr, cCommitment[:], cErr)
+	}
+}
+
+func TestComputeBlobKZGProof(t *testing.T) {
+	m := &mismatches{t: t}
+	for i := range inputs {
+		in := &inputs[i]
+		goProof, goErr := ctx.ComputeBlobKZGProof(in.Blob, in.Commitment, 0)
+		cProof, cErr := ckzg4844.ComputeBlobKZGProof((*ckzg4844.Blob)(in.Blob), ckzg4844.Bytes48(in.Commitment))
+		compareOutputs(m, "ComputeBlobKZGProof", in, goProof[:], goErr, cProof[:], cErr)
+	}
+}
+
+func TestVerifyBlobKZGProof(t *testing.T) {
+	m := &mismatches{t: t}
+	for i := range inputs {
+		in := &inputs[i]
+		goErr := ctx.VerifyBlobKZGProof(in.Blob, in.Commitment, in.Proof)
+		cValid, cErr := ckzg4844.VerifyBlobKZGProof((*ckzg4844.Blob)(in.Blob), ckzg4844.Bytes48(in.Commitment), ckzg4844.Bytes48(in.Proof))
+
+		var goDecision decision
+		switch {
+		case goErr == nil:
+			goDecision = accept
+		case errors.Is(goErr, gokzg4844.ErrProofInvalid):
+			goDecision = reject
+		case errors.Is(goErr, gokzg4844.ErrInvalidInput):
+			goDecision = invalid
+		default:
+			m.report("VerifyBlobKZGProof: unclassified error %v for the input %s", goErr, in.Hex())
+			continue
+		}
+		cDecision := invalid
+		if cErr == nil {
+			cDecision = reject
+			if cValid {
+				cDecision = accept
+			}
+		}
+
+		if goDecision != cDecision {
+			m.report("VerifyBlobKZGProof: go-kzg-4844 decided %s (%v), c-kzg-4844 decided %s (%v) for the input %s",
+				goDecision, goErr, cDecision, cErr, in.Hex())
+		}
+	}
+}
+
+// decision is the outcome of a verification: the proof is accepted, the proof is rejected, or the input is invalid.
+type decision int
+
+const (
+	accept decision = iota
+	reject
+	invalid
+)
+
+func (d decision) String() string {
+	return [...]string{"accept", "reject", "invalid input"}[d]
+}
+
+// compareOutputs reports a mismatch if only one of the libraries returned an error, or if both of them succeeded with
+// different outputs.
+func compareOutputs(m *mismatches, function string, in *Input, goOutput []byte, goErr error, cOutput []byte, cErr error) {
+	m.t.Helper()
+	switch {
+	case (goErr == nil) != (cErr == nil):
+		m.report("%s: go-kzg-4844 returned the error %v, c-kzg-4844 returned the error %v for the input %s",
+			function, goErr, cErr, in.Hex())
+	case goErr == nil && !bytes.Equal(goOutput, cOutput):
+		m.report("%s: go-kzg-4844 returned 0x%x, c-kzg-4844 returned 0x%x for the input %s",
+			function, goOutput, cOutput, in.Hex())
+	}
+}
+
+// mismatches reports the mismatches of a test, and stops it after maxMismatches of them.
+type mismatches struct {
+	t     *testing.T
+	count int
+}
+
+func (m *mismatches) report(format string, args ...any) {
+	m.t.Helper()
+	m.t.Errorf(format, args...)
+	m.count++
+	if m.count == maxMismatches {
+		m.t.Fatalf("stopping after %d mismatches", maxMismatches)
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/RiemaLabs/go-kzg-4844/ckzgcompare
+
+go 1.20
+
+require (
+	github.com/RiemaLabs/go-kzg-4844 v0.0.0
+	github.com/consensys/gnark-crypto v0.13.0
+	github.com/ethereum/c-kzg-4844/v2 v2.1.1
+)
+
+require (
+	github.com/bits-and-blooms/bitset v1.7.0 // indirect
+	github.com/consensys/bavard v0.1.13 // indirect
+	github.com/mmcloughlin/addchain v0.4.0 // indirect
+	golang.org/x/sync v0.1.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+	rsc.io/tmplfunc v0.0.3 // indirect
+)
+
+replace github.com/RiemaLabs/go-kzg-4844 => ../
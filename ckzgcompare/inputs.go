@@ -0,0 +1,206 @@
+// Package ckzgcompare cross-checks this library against [c-kzg-4844], by running both on the same random and
+// adversarial inputs and comparing their outputs byte for byte, and their decisions to accept or reject.
+//
+// The comparison links the C library using cgo, so it is only built with the ckzg_compare build tag, and this package
+// is a separate module so that the main module does not depend on cgo. It is run from the root of the repository
+// using:
+//
+//	make ckzg-compare
+//
+// [c-kzg-4844]: https://github.com/ethereum/c-kzg-4844
+package ckzgcompare
+
+import (
+	"fmt"
+	"math/rand"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Input is the blob, commitment and proof given to both libraries. Name describes how each of them was generated.
+type Input struct {
+	Name       string
+	Blob       *gokzg4844.Blob
+	Commitment gokzg4844.KZGCommitment
+	Proof      gokzg4844.KZGProof
+}
+
+// Hex returns the name of the input followed by the blob, commitment and proof as hex-strings, one per line, so that
+// a mismatch can be reproduced from the test output.
+func (in *Input) Hex() string {
+	return fmt.Sprintf("%s\nblob: 0x%x\ncommitment: 0x%x\nproof: 0x%x", in.Name, in.Blob[:], in.Commitment[:], in.Proof[:])
+}
+
+// GenerateInputs returns n inputs derived from the seed. Each input starts from the blob, commitment and proof of
+// [testutil.GenerateProofFixture], and each of the three is then replaced by an adversarial value with probability
+// one half, see [blobMutations] and [pointMutations]. The inputs only depend on the seed and on the trusted setup of
+// ctx.
+func GenerateInputs(ctx *gokzg4844.Context, seed int64, n int) ([]Input, error) {
+	fixtures := make([]*testutil.ProofFixture, n)
+	for i := range fixtures {
+		fixture, err := testutil.GenerateProofFixture(ctx, seed+int64(i))
+		if err != nil {
+			return nil, err
+		}
+		fixtures[i] = fixture
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	inputs := make([]Input, n)
+	for i, fixture := range fixtures {
+		// The commitment and proof of another blob are valid points which do not match the blob
+		other := fixtures[(i+1)%n]
+
+		blob := *fixture.Blob
+		blobName := mutateBlob(rng, &blob)
+		commitment := [48]byte(fixture.Commitment)
+		commitmentName := mutatePoint(rng, &commitment, other.Commitment, fixture.Proof)
+		proof := [48]byte(fixture.Proof)
+		proofName := mutatePoint(rng, &proof, other.Proof, fixture.Commitment)
+
+		inputs[i] = Input{
+			Name:       fmt.Sprintf("seed %d: blob %s, commitment %s, proof %s", seed+int64(i), blobName, commitmentName, proofName),
+			Blob:       &blob,
+			Commitment: commitment,
+			Proof:      proof,
+		}
+	}
+	return inputs, nil
+}
+
+// mutateBlob replaces the blob by one of [blobMutations] with probability one half, and returns the name of the
+// mutation.
+func mutateBlob(rng *rand.Rand, blob *gokzg4844.Blob) string {
+	if rng.Intn(2) == 0 {
+		return "valid"
+	}
+	mutation := blobMutations[rng.Intn(len(blobMutations))]
+	index := rng.Intn(gokzg4844.ScalarsPerBlob)
+	mutation.apply(blob, index)
+	if !mutation.atIndex {
+		return mutation.name
+	}
+	return fmt.Sprintf("%s at %d", mutation.name, index)
+}
+
+// blobMutations are the adversarial blobs, which either replace the whole blob or the scalar at a random index.
+var blobMutations = []struct {
+	name    string
+	atIndex bool
+	apply   func(blob *gokzg4844.Blob, index int)
+}{
+	{"zero", false, func(blob *gokzg4844.Blob, _ int) {
+		*blob = gokzg4844.Blob{}
+	}},
+	{"maximum scalars", false, func(blob *gokzg4844.Blob, _ int) {
+		var max fr.Element
+		max.SetOne().Neg(&max)
+		for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+			setScalar(blob, i, gokzg4844.SerializeScalar(max))
+		}
+	}},
+	{"non-canonical scalar", true, func(blob *gokzg4844.Blob, index int) {
+		offset := index * gokzg4844.SerializedScalarSize
+		setScalar(blob, index, testutil.NonCanonicalScalar(gokzg4844.Scalar(blob[offset:offset+gokzg4844.SerializedScalarSize])))
+	}},
+	{"modulus", true, func(blob *gokzg4844.Blob, index int) {
+		setScalar(blob, index, testutil.NonCanonicalScalar(gokzg4844.Scalar{}))
+	}},
+	{"all ones scalar", true, func(blob *gokzg4844.Blob, index int) {
+		var ones gokzg4844.Scalar
+		for i := range ones {
+			ones[i] = 0xff
+		}
+		setScalar(blob, index, ones)
+	}},
+}
+
+func setScalar(blob *gokzg4844.Blob, index int, scalar gokzg4844.Scalar) {
+	copy(blob[index*gokzg4844.SerializedScalarSize:], scalar[:])
+}
+
+// mutatePoint replaces the point by one of [pointMutations] with probability one half, and returns the name of the
+// mutation. other is a valid point of the same kind for another blob, and swapped is the valid point of the other
+// kind for the same blob, that is the proof for a commitment and the commitment for a proof.
+func mutatePoint(rng *rand.Rand, point *[48]byte, other, swapped [48]byte) string {
+	if rng.Intn(2) == 0 {
+		return "valid"
+	}
+	mutation := pointMutations[rng.Intn(len(pointMutations))]
+	mutation.apply(rng, point, other, swapped)
+	return mutation.name
+}
+
+// pointMutations are the adversarial points. The first ones are valid points which do not match the blob, and the
+// others are invalid encodings, which must be rejected by both libraries.
+var pointMutations = []struct {
+	name  string
+	apply func(rng *rand.Rand, point *[48]byte, other, swapped [48]byte)
+}{
+	{"of another blob", func(_ *rand.Rand, point *[48]byte, other, _ [48]byte) {
+		*point = other
+	}},
+	{"swapped", func(_ *rand.Rand, point *[48]byte, _, swapped [48]byte) {
+		*point = swapped
+	}},
+	{"negated", func(_ *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		point[0] ^= 0x20
+	}},
+	{"infinity", func(_ *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		*point = gokzg4844.PointAtInfinity
+	}},
+	{"infinity with a non-zero coordinate", func(_ *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		*point = gokzg4844.PointAtInfinity
+		point[47] = 1
+	}},
+	{"infinity with the sign flag", func(_ *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		*point = gokzg4844.PointAtInfinity
+		point[0] |= 0x20
+	}},
+	{"uncompressed flag", func(_ *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		point[0] &^= 0x80
+	}},
+	{"flipped bit", func(rng *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		bit := rng.Intn(8 * len(point))
+		point[bit/8] ^= 1 << (bit % 8)
+	}},
+	{"random bytes", func(rng *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		rng.Read(point[:])
+	}},
+	{"x not less than the modulus", func(_ *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		for i := range point {
+			point[i] = 0xff
+		}
+		point[0] = 0x9f
+	}},
+	{"not on the curve", func(rng *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		*point = compressedX(rng, false)
+	}},
+	{"not in the subgroup", func(rng *rand.Rand, point *[48]byte, _, _ [48]byte) {
+		*point = compressedX(rng, true)
+	}},
+}
+
+// compressedX returns the compressed encoding of a random x coordinate such that x^3 + 4 is a square if onCurve is
+// true, which is then a point on the curve that is almost certainly not in the subgroup, and such that it is not a
+// square otherwise, which is then not a point on the curve.
+func compressedX(rng *rand.Rand, onCurve bool) [48]byte {
+	var x, y2, four fp.Element
+	four.SetUint64(4)
+	for {
+		var xBytes [fp.Bytes]byte
+		rng.Read(xBytes[:])
+		x.SetBytes(xBytes[:])
+		y2.Square(&x).Mul(&y2, &x).Add(&y2, &four)
+		if (y2.Legendre() != -1) == onCurve {
+			break
+		}
+	}
+
+	point := x.Bytes()
+	point[0] |= 0x80
+	return point
+}
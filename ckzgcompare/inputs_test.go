@@ -0,0 +1,53 @@
+package ckzgcompare
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+)
+
+func TestGenerateInputs(t *testing.T) {
+	ctx, err := gokzg4844.NewContext4096Secure()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs, err := GenerateInputs(ctx, 7, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := GenerateInputs(ctx, 7, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(inputs, again) {
+		t.Errorf("the inputs generated from the same seed are different")
+	}
+
+	// Any mutation makes the proof invalid
+	for _, in := range inputs {
+		err := ctx.VerifyBlobKZGProof(in.Blob, in.Commitment, in.Proof)
+		valid := strings.HasSuffix(in.Name, ": blob valid, commitment valid, proof valid")
+		if valid != (err == nil) {
+			t.Errorf("%s: got the verification error %v", in.Name, err)
+		}
+	}
+}
+
+func TestCompressedX(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	_, err := gokzg4844.DeserializeKZGCommitment(compressedX(rng, false))
+	if !errors.Is(err, gokzg4844.ErrInvalidPointEncoding) {
+		t.Errorf("a point which is not on the curve was not rejected as an invalid encoding: %v", err)
+	}
+
+	_, err = gokzg4844.DeserializeKZGCommitment(compressedX(rng, true))
+	if !errors.Is(err, gokzg4844.ErrPointNotInSubgroup) {
+		t.Errorf("a point which is not in the subgroup was not rejected: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+// Command genvectors writes test vectors computed by this library in the layout of the consensus-spec-tests, for the
+// features which do not have official vectors yet. See [spectest.Generate].
+//
+// Usage:
+//
+//	genvectors -out dir [-seed n] [-handler name]
+//
+// The vectors can be checked using [spectest.RunAll], or by any implementation which runs the consensus-spec-tests.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/spectest"
+)
+
+func main() {
+	out := flag.String("out", "", "the directory the vectors are written to")
+	seed := flag.Int64("seed", 0, "the seed the inputs are derived from")
+	handler := flag.String("handler", "", "the handler to write the vectors of, one of "+
+		strings.Join(spectest.Handlers(), ", ")+"; all of them if empty")
+	flag.Parse()
+
+	if *out == "" || flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*out, *seed, *handler); err != nil {
+		fmt.Fprintln(os.Stderr, "genvectors:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, seed int64, handler string) error {
+	ctx, err := gokzg4844.NewContext4096Secure()
+	if err != nil {
+		return err
+	}
+	if handler == "" {
+		return spectest.GenerateAll(ctx, seed, out)
+	}
+	return spectest.Generate(ctx, handler, seed, out)
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+)
+
+// newContext creates the context used by the commands. It is a variable so that the tests can share one context.
+var newContext = func() (*gokzg4844.Context, error) {
+	return gokzg4844.NewContext4096Secure()
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("kzgtool "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// commit prints the commitment to the blob and its versioned hash.
+func commit(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("commit", stderr)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	blob, err := readBlob(positional[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, err := newContext()
+	if err != nil {
+		return err
+	}
+	commitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	if err != nil {
+		return err
+	}
+	versionedHash := gokzg4844.KZGToVersionedHash(commitment)
+	fmt.Fprintf(stdout, "commitment: %s\n", hexString(commitment[:]))
+	fmt.Fprintf(stdout, "versioned hash: %s\n", hexString(versionedHash[:]))
+	return nil
+}
+
+// prove prints the proof of the evaluation of the blob at -point along with the evaluation, or the blob proof if no
+// point is given. The blob proof is computed for -commitment, which defaults to the commitment to the blob.
+func prove(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("prove", stderr)
+	point := fs.String("point", "", "the point to evaluate the blob at, as a hex-string")
+	commitmentHex := fs.String("commitment", "", "the commitment to the blob for the blob proof, as a hex-string")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *point != "" && *commitmentHex != "" {
+		return fmt.Errorf("%w: -point and -commitment cannot be used together", errUsage)
+	}
+	blob, err := readBlob(positional[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, err := newContext()
+	if err != nil {
+		return err
+	}
+
+	if *point != "" {
+		z, err := parseScalar(*point)
+		if err != nil {
+			return err
+		}
+		proof, y, err := ctx.ComputeKZGProof(blob, z, 0)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "proof: %s\n", hexString(proof[:]))
+		fmt.Fprintf(stdout, "claim: %s\n", hexString(y[:]))
+		return nil
+	}
+
+	var commitment gokzg4844.KZGCommitment
+	if *commitmentHex != "" {
+		if err := commitment.UnmarshalText([]byte(*commitmentHex)); err != nil {
+			return fmt.Errorf("invalid commitment: %w", err)
+		}
+	} else if commitment, err = ctx.BlobToKZGCommitment(blob, 0); err != nil {
+		return err
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, 0)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "commitment: %s\n", hexString(commitment[:]))
+	fmt.Fprintf(stdout, "proof: %s\n", hexString(proof[:]))
+	return nil
+}
+
+// verify checks the blob proof of -blob, or the proof that the blob committed to evaluates to -claim at -point. It
+// returns an error wrapping errInvalid if the proof does not verify.
+func verify(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	blobArg := fs.String("blob", "", "the blob, as a file or a hex-string")
+	commitmentHex := fs.String("commitment", "", "the commitment, as a hex-string")
+	proofHex := fs.String("proof", "", "the proof, as a hex-string")
+	point := fs.String("point", "", "the point the blob is evaluated at, as a hex-string")
+	claim := fs.String("claim", "", "the evaluation of the blob at the point, as a hex-string")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	blobProof := *blobArg != ""
+	switch {
+	case *commitmentHex == "" || *proofHex == "":
+		return fmt.Errorf("%w: -commitment and -proof are required", errUsage)
+	case blobProof && (*point != "" || *claim != ""):
+		return fmt.Errorf("%w: -blob cannot be used with -point and -claim", errUsage)
+	case !blobProof && (*point == "" || *claim == ""):
+		return fmt.Errorf("%w: either -blob or both -point and -claim are required", errUsage)
+	}
+
+	var commitment gokzg4844.KZGCommitment
+	if err := commitment.UnmarshalText([]byte(*commitmentHex)); err != nil {
+		return fmt.Errorf("invalid commitment: %w", err)
+	}
+	var proof gokzg4844.KZGProof
+	if err := proof.UnmarshalText([]byte(*proofHex)); err != nil {
+		return fmt.Errorf("invalid proof: %w", err)
+	}
+
+	ctx, err := newContext()
+	if err != nil {
+		return err
+	}
+	if blobProof {
+		blob, err := readBlob(*blobArg)
+		if err != nil {
+			return err
+		}
+		err = ctx.VerifyBlobKZGProof(blob, commitment, proof)
+		return verificationResult(stdout, err)
+	}
+
+	z, err := parseScalar(*point)
+	if err != nil {
+		return err
+	}
+	y, err := parseScalar(*claim)
+	if err != nil {
+		return err
+	}
+	return verificationResult(stdout, ctx.VerifyKZGProof(commitment, z, y, proof))
+}
+
+// verificationResult prints the result of a verification which returned err, and returns an error wrapping
+// errInvalid if the proof does not verify. Invalid inputs are returned as they are.
+func verificationResult(stdout io.Writer, err error) error {
+	switch {
+	case err == nil:
+		fmt.Fprintln(stdout, "valid")
+		return nil
+	case errors.Is(err, gokzg4844.ErrProofInvalid):
+		fmt.Fprintln(stdout, "invalid")
+		return fmt.Errorf("%w: %v", errInvalid, err)
+	default:
+		return err
+	}
+}
+
+// isInvalidSetupError reports whether err means that the trusted setup was parsed but is not a valid setup.
+func isInvalidSetupError(err error) bool {
+	return errors.Is(err, gokzg4844.ErrInvalidTrustedSetupStructure) ||
+		errors.Is(err, gokzg4844.ErrTrustedSetupG2Generator) ||
+		errors.Is(err, gokzg4844.ErrTrustedSetupPointAtInfinity) ||
+		errors.Is(err, gokzg4844.ErrTrustedSetupDuplicatePoint)
+}
+
+// checkSetup checks that the trusted setup in the file is well-formed and made of successive powers of the same
+// secret, and prints its fingerprint. The file is read as JSON, or in the text format of c-kzg-4844 if its name ends
+// with .txt.
+func checkSetup(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("check-setup", stderr)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var ctx *gokzg4844.Context
+	if filepath.Ext(path) == ".txt" {
+		var trustedSetup *gokzg4844.JSONTrustedSetup
+		trustedSetup, err = gokzg4844.ParseTrustedSetupText(file)
+		if err != nil {
+			return err
+		}
+		if err = gokzg4844.CheckTrustedSetupStructure(trustedSetup); err == nil {
+			ctx, err = gokzg4844.NewContext4096(trustedSetup)
+		}
+	} else {
+		ctx, err = gokzg4844.NewContextFromJSONChecked(file)
+	}
+	if isInvalidSetupError(err) {
+		fmt.Fprintln(stdout, "invalid")
+		return fmt.Errorf("%w: %v", errInvalid, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fingerprint := ctx.SetupFingerprint()
+	fmt.Fprintln(stdout, "valid")
+	fmt.Fprintf(stdout, "fingerprint: %s\n", hexString(fingerprint[:]))
+	fmt.Fprintf(stdout, "mainnet: %t\n", fingerprint == gokzg4844.MainnetSetupFingerprint)
+	return nil
+}
+
+// readBlob reads the blob given as arg: a hex-string with the 0x prefix, or the path of a file holding either the
+// bytes of the blob or its hex-string, with or without the 0x prefix.
+func readBlob(arg string) (*gokzg4844.Blob, error) {
+	var blob gokzg4844.Blob
+	if strings.HasPrefix(arg, "0x") {
+		if err := blob.UnmarshalText([]byte(arg)); err != nil {
+			return nil, fmt.Errorf("invalid blob: %w", err)
+		}
+		return &blob, nil
+	}
+
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == len(blob) {
+		copy(blob[:], data)
+		return &blob, nil
+	}
+	if err := blob.UnmarshalText(bytes.TrimSpace(data)); err != nil {
+		return nil, fmt.Errorf("invalid blob in %s: expected %d bytes or their hex-string: %w", arg, len(blob), err)
+	}
+	return &blob, nil
+}
+
+// parseScalar decodes the hex-string s of a scalar, with or without the 0x prefix. The scalar is not checked to be
+// canonical, so that this is reported by the library like for the other inputs.
+func parseScalar(s string) (gokzg4844.Scalar, error) {
+	var scalar gokzg4844.Scalar
+	decoded, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return scalar, fmt.Errorf("invalid scalar: %w", err)
+	}
+	if len(decoded) != len(scalar) {
+		return scalar, fmt.Errorf("invalid scalar: expected %d bytes, got %d", len(scalar), len(decoded))
+	}
+	copy(scalar[:], decoded)
+	return scalar, nil
+}
+
+func hexString(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
@@ -0,0 +1,135 @@
+// Command kzgtool computes and verifies the commitments and proofs of blobs, and checks trusted setups, for debugging
+// blob issues.
+//
+// Usage:
+//
+//	kzgtool commit <blob>
+//	kzgtool prove <blob> [-point 0x...] [-commitment 0x...]
+//	kzgtool verify -commitment 0x... -proof 0x... (-blob <blob> | -point 0x... -claim 0x...)
+//	kzgtool check-setup <trusted setup>
+//
+// A blob is either the path of a file holding the 131072 bytes of the blob, or of a file holding them as a
+// hex-string, or the hex-string itself with the 0x prefix. The points, scalars and outputs are hex-strings. The
+// commands use the trusted setup from the Ethereum KZG ceremony.
+//
+// The exit code is 0 on success, 1 if an error occurred or if the input is invalid, 2 if the arguments are invalid
+// and 3 if a proof or a trusted setup does not verify.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// The exit codes of the command.
+const (
+	exitOK      = 0
+	exitError   = 1
+	exitUsage   = 2
+	exitInvalid = 3
+)
+
+// errUsage is returned by the commands when their arguments are invalid.
+var errUsage = errors.New("invalid arguments")
+
+// errInvalid is returned by the commands when a proof or a trusted setup does not verify.
+var errInvalid = errors.New("verification failed")
+
+// commands maps the name of each command to the function running it with the arguments following the name.
+var commands = map[string]func(args []string, stdout, stderr io.Writer) error{
+	"commit":      commit,
+	"prove":       prove,
+	"verify":      verify,
+	"check-setup": checkSetup,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command named by the first argument and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "kzgtool: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	err := command(args[1:], stdout, stderr)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "kzgtool %s: %v\n", args[0], err)
+		return exitUsage
+	case errors.Is(err, errInvalid):
+		fmt.Fprintf(stderr, "kzgtool %s: %v\n", args[0], err)
+		return exitInvalid
+	default:
+		fmt.Fprintf(stderr, "kzgtool %s: %v\n", args[0], err)
+		return exitError
+	}
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "usage: kzgtool <command> [arguments]")
+	fmt.Fprintln(w, "commands:")
+	for _, name := range names {
+		fmt.Fprintln(w, "  "+name)
+	}
+}
+
+// parseArgs parses the flags of fs, which can be given before, between or after the positional arguments, unlike
+// with [flag.FlagSet.Parse] which stops at the first positional argument. It returns the positional arguments, and an
+// error wrapping errUsage if there are not exactly numPositional of them or if a flag is invalid. Everything after
+// "--" is positional.
+//
+// The errors are reported by the caller, so only the help requested using -h is written to the output of fs.
+func parseArgs(fs *flag.FlagSet, args []string, numPositional int) ([]string, error) {
+	output := fs.Output()
+	fs.SetOutput(io.Discard)
+	defer fs.SetOutput(output)
+	var positional []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fs.SetOutput(output)
+				fs.Usage()
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		// fs.Parse stops after "--", after which every argument is positional
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	if len(positional) != numPositional {
+		return nil, fmt.Errorf("%w: expected %d positional arguments, got %d", errUsage, numPositional, len(positional))
+	}
+	return positional, nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx *gokzg4844.Context
+
+func TestMain(m *testing.M) {
+	var err error
+	ctx, err = gokzg4844.NewContext4096Secure()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not create the context:", err)
+		os.Exit(1)
+	}
+	newContext = func() (*gokzg4844.Context, error) {
+		return ctx, nil
+	}
+	os.Exit(m.Run())
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		numPositional int
+		positional    []string
+		point         string
+		err           error
+	}{
+		{"flag before", []string{"-point", "0x01", "blob.bin"}, 1, []string{"blob.bin"}, "0x01", nil},
+		{"flag after", []string{"blob.bin", "--point", "0x01"}, 1, []string{"blob.bin"}, "0x01", nil},
+		{"flag between", []string{"a", "-point=0x01", "b"}, 2, []string{"a", "b"}, "0x01", nil},
+		{"no flag", []string{"blob.bin"}, 1, []string{"blob.bin"}, "", nil},
+		{"terminator", []string{"-point", "0x01", "--", "-blob.bin"}, 1, []string{"-blob.bin"}, "0x01", nil},
+		{"missing positional", []string{"-point", "0x01"}, 1, nil, "", errUsage},
+		{"extra positional", []string{"a", "b"}, 1, nil, "", errUsage},
+		{"unknown flag", []string{"-unknown", "a"}, 1, nil, "", errUsage},
+		{"missing flag value", []string{"a", "-point"}, 1, nil, "", errUsage},
+		{"help", []string{"-h"}, 1, nil, "", flag.ErrHelp},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var help bytes.Buffer
+			fs := newFlagSet("test", &help)
+			point := fs.String("point", "", "")
+
+			positional, err := parseArgs(fs, test.args, test.numPositional)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				// Only the help is written, the errors are reported by run
+				require.Equal(t, errors.Is(err, flag.ErrHelp), help.Len() > 0)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.positional, positional)
+			require.Equal(t, test.point, *point)
+		})
+	}
+}
+
+func TestReadBlob(t *testing.T) {
+	blob := testutil.GenerateBlob(1)
+	dir := t.TempDir()
+	rawPath := filepath.Join(dir, "blob.bin")
+	require.NoError(t, os.WriteFile(rawPath, blob[:], 0o600))
+	hexPath := filepath.Join(dir, "blob.hex")
+	require.NoError(t, os.WriteFile(hexPath, []byte(hexString(blob[:])+"\n"), 0o600))
+	unprefixedHexPath := filepath.Join(dir, "blob.txt")
+	require.NoError(t, os.WriteFile(unprefixedHexPath, []byte(hex.EncodeToString(blob[:])), 0o600))
+
+	for _, arg := range []string{rawPath, hexPath, unprefixedHexPath, hexString(blob[:])} {
+		read, err := readBlob(arg)
+		require.NoError(t, err)
+		require.Equal(t, blob, read)
+	}
+
+	shortPath := filepath.Join(dir, "short.bin")
+	require.NoError(t, os.WriteFile(shortPath, blob[1:], 0o600))
+	_, err := readBlob(shortPath)
+	require.Error(t, err)
+	_, err = readBlob(hexString(blob[1:]))
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidHexString)
+	_, err = readBlob(filepath.Join(dir, "missing.bin"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// runCommand runs kzgtool with the arguments and returns the exit code and the output.
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// outputValue returns the value of the line of the output starting with the key.
+func outputValue(t *testing.T, output, key string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if value, ok := strings.CutPrefix(line, key+": "); ok {
+			return value
+		}
+	}
+	t.Fatalf("no %s in the output %q", key, output)
+	return ""
+}
+
+func TestCommands(t *testing.T) {
+	fixture, err := testutil.GenerateProofFixture(ctx, 1)
+	require.NoError(t, err)
+	blobPath := filepath.Join(t.TempDir(), "blob.bin")
+	require.NoError(t, os.WriteFile(blobPath, fixture.Blob[:], 0o600))
+	commitment := hexString(fixture.Commitment[:])
+	proof := hexString(fixture.Proof[:])
+
+	code, stdout, _ := runCommand("commit", blobPath)
+	require.Equal(t, exitOK, code)
+	require.Equal(t, commitment, outputValue(t, stdout, "commitment"))
+	versionedHash := gokzg4844.KZGToVersionedHash(fixture.Commitment)
+	require.Equal(t, hexString(versionedHash[:]), outputValue(t, stdout, "versioned hash"))
+
+	code, stdout, _ = runCommand("prove", blobPath)
+	require.Equal(t, exitOK, code)
+	require.Equal(t, proof, outputValue(t, stdout, "proof"))
+
+	code, stdout, _ = runCommand("verify", "-blob", blobPath, "-commitment", commitment, "-proof", proof)
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "valid\n", stdout)
+
+	// The proof of another blob does not verify
+	other, err := testutil.GenerateProofFixture(ctx, 2)
+	require.NoError(t, err)
+	code, stdout, stderr := runCommand("verify", "-blob", blobPath, "-commitment", commitment, "-proof", hexString(other.Proof[:]))
+	require.Equal(t, exitInvalid, code)
+	require.Equal(t, "invalid\n", stdout)
+	require.Contains(t, stderr, "verification failed")
+
+	// Evaluation proofs
+	z := testutil.GenerateScalar(3)
+	code, stdout, _ = runCommand("prove", blobPath, "-point", hexString(z[:]))
+	require.Equal(t, exitOK, code)
+	pointProof, claim := outputValue(t, stdout, "proof"), outputValue(t, stdout, "claim")
+	code, _, _ = runCommand("verify", "-commitment", commitment, "-proof", pointProof, "-point", hexString(z[:]), "-claim", claim)
+	require.Equal(t, exitOK, code)
+	code, _, _ = runCommand("verify", "-commitment", commitment, "-proof", pointProof, "-point", hexString(z[:]), "-claim", hexString(z[:]))
+	require.Equal(t, exitInvalid, code)
+
+	// Invalid inputs are errors rather than failed verifications
+	invalidBlobPath := filepath.Join(t.TempDir(), "invalid.bin")
+	require.NoError(t, os.WriteFile(invalidBlobPath, testutil.GenerateInvalidBlob(1, 7)[:], 0o600))
+	code, _, stderr = runCommand("commit", invalidBlobPath)
+	require.Equal(t, exitError, code)
+	require.Contains(t, stderr, "index 7")
+	code, _, _ = runCommand("verify", "-blob", invalidBlobPath, "-commitment", commitment, "-proof", proof)
+	require.Equal(t, exitError, code)
+}
+
+func TestUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"commit"},
+		{"commit", "a", "b"},
+		{"prove", "blob.bin", "-point", "0x01", "-commitment", "0x02"},
+		{"verify", "-blob", "blob.bin", "-proof", "0x01"},
+		{"verify", "-commitment", "0x01", "-proof", "0x01"},
+		{"verify", "-commitment", "0x01", "-proof", "0x01", "-point", "0x01"},
+		{"verify", "-blob", "blob.bin", "-commitment", "0x01", "-proof", "0x01", "-claim", "0x01"},
+	} {
+		code, _, stderr := runCommand(args...)
+		require.Equal(t, exitUsage, code, args)
+		require.NotEmpty(t, stderr, args)
+	}
+}
+
+func TestCheckSetup(t *testing.T) {
+	code, stdout, _ := runCommand("check-setup", filepath.Join("..", "..", "trusted_setup.txt"))
+	require.Equal(t, exitOK, code)
+	require.Equal(t, hexString(gokzg4844.MainnetSetupFingerprint[:]), outputValue(t, stdout, "fingerprint"))
+	require.Equal(t, "true", outputValue(t, stdout, "mainnet"))
+
+	// Swapping two lagrange G1 points keeps the setup well-formed, but not made of powers of the same secret
+	data, err := os.ReadFile(filepath.Join("..", "..", "trusted_setup.txt"))
+	require.NoError(t, err)
+	lines := strings.Split(string(data), "\n")
+	lines[2], lines[3] = lines[3], lines[2]
+	swappedPath := filepath.Join(t.TempDir(), "swapped.txt")
+	require.NoError(t, os.WriteFile(swappedPath, []byte(strings.Join(lines, "\n")), 0o600))
+	code, stdout, _ = runCommand("check-setup", swappedPath)
+	require.Equal(t, exitInvalid, code)
+	require.Equal(t, "invalid\n", stdout)
+
+	code, _, _ = runCommand("check-setup", filepath.Join(t.TempDir(), "missing.json"))
+	require.Equal(t, exitError, code)
+}
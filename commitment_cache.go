@@ -0,0 +1,71 @@
+package gokzg4844
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// commitmentCache is a least recently used cache of the commitments to blobs, keyed by the SHA-256 digest of the
+// blob. See [WithCommitmentCache].
+//
+// Only the digests and the commitments are stored, so the cache does not hold references to the blobs.
+type commitmentCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	// order holds the entries from the most recently used to the least recently used.
+	order   *list.List
+	entries map[[32]byte]*list.Element
+}
+
+// commitmentCacheEntry is the value of the elements of [commitmentCache.order].
+type commitmentCacheEntry struct {
+	key        [32]byte
+	commitment KZGCommitment
+}
+
+// newCommitmentCache returns an empty cache holding at most maxEntries commitments, which must be positive.
+func newCommitmentCache(maxEntries int) *commitmentCache {
+	return &commitmentCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[[32]byte]*list.Element, maxEntries),
+	}
+}
+
+// commitmentCacheKey returns the key of the commitment to `blob` in a [commitmentCache].
+func commitmentCacheKey(blob *Blob) [32]byte {
+	return sha256.Sum256(blob[:])
+}
+
+// get returns the commitment cached for the key, and marks it as the most recently used.
+func (cache *commitmentCache) get(key [32]byte) (KZGCommitment, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return KZGCommitment{}, false
+	}
+	cache.order.MoveToFront(element)
+	return element.Value.(*commitmentCacheEntry).commitment, true
+}
+
+// add caches the commitment for the key, evicting the least recently used commitment if the cache is full.
+func (cache *commitmentCache) add(key [32]byte, commitment KZGCommitment) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Another go routine may have added the same blob since the lookup
+	if element, ok := cache.entries[key]; ok {
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	if cache.order.Len() >= cache.maxEntries {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*commitmentCacheEntry).key)
+	}
+	cache.entries[key] = cache.order.PushFront(&commitmentCacheEntry{key: key, commitment: commitment})
+}
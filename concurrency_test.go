@@ -0,0 +1,216 @@
+package gokzg4844_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContextConcurrentUse shares a single context between many go routines, each of which keeps calling its methods
+// and checking the results against the ones computed sequentially. Run it with -race to check that the context is
+// not modified after its construction.
+func TestContextConcurrentUse(t *testing.T) {
+	const (
+		numGoRoutines = 32
+		numBlobs      = 4
+	)
+	duration := 3 * time.Second
+	if testing.Short() {
+		duration = 200 * time.Millisecond
+	}
+
+	// A new context, so that the lazily computed fingerprint is computed concurrently. Its commitment cache is smaller
+	// than the number of blobs, so that entries are also evicted concurrently.
+	sharedCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithCommitmentCache(numBlobs - 1))
+	require.NoError(t, err)
+
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	commitments := make([]gokzg4844.KZGCommitment, numBlobs)
+	proofs := make([]gokzg4844.KZGProof, numBlobs)
+	pointProofs := make([]gokzg4844.KZGProof, numBlobs)
+	claimedValues := make([]gokzg4844.Scalar, numBlobs)
+	inputPoint := testutil.GenerateScalar(7)
+	for i := range blobs {
+		blobs[i] = *testutil.GenerateBlob(int64(i))
+		commitments[i], err = ctx.BlobToKZGCommitment(&blobs[i], 1)
+		require.NoError(t, err)
+		proofs[i], err = ctx.ComputeBlobKZGProof(&blobs[i], commitments[i], 1)
+		require.NoError(t, err)
+		pointProofs[i], claimedValues[i], err = ctx.ComputeKZGProof(&blobs[i], inputPoint, 1)
+		require.NoError(t, err)
+	}
+	expectedRoot, err := ctx.DomainByIndex(17)
+	require.NoError(t, err)
+
+	operations := []func(i int) error{
+		func(i int) error {
+			commitment, err := sharedCtx.BlobToKZGCommitment(&blobs[i], 1)
+			if err == nil && commitment != commitments[i] {
+				err = errors.New("wrong commitment")
+			}
+			return err
+		},
+		func(i int) error {
+			proof, err := sharedCtx.ComputeBlobKZGProof(&blobs[i], commitments[i], 1)
+			if err == nil && proof != proofs[i] {
+				err = errors.New("wrong proof")
+			}
+			return err
+		},
+		func(i int) error {
+			proof, claimedValue, err := sharedCtx.ComputeKZGProof(&blobs[i], inputPoint, 1)
+			if err == nil && (proof != pointProofs[i] || claimedValue != claimedValues[i]) {
+				err = errors.New("wrong proof or claimed value")
+			}
+			return err
+		},
+		func(i int) error {
+			return sharedCtx.VerifyBlobKZGProof(&blobs[i], commitments[i], proofs[i])
+		},
+		func(i int) error {
+			return sharedCtx.VerifyKZGProof(commitments[i], inputPoint, claimedValues[i], pointProofs[i])
+		},
+		func(int) error {
+			return sharedCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+		},
+		func(int) error {
+			return sharedCtx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs)
+		},
+		func(int) error {
+			if sharedCtx.SetupFingerprint() != gokzg4844.MainnetSetupFingerprint {
+				return errors.New("wrong fingerprint")
+			}
+			root, err := sharedCtx.DomainByIndex(17)
+			if err == nil && !root.Equal(expectedRoot) {
+				err = errors.New("wrong root of unity")
+			}
+			return err
+		},
+	}
+
+	deadline := time.Now().Add(duration)
+	var wg sync.WaitGroup
+	for g := 0; g < numGoRoutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// Every go routine runs at least one operation, starting at a different one
+			for k := 0; k == 0 || time.Now().Before(deadline); k++ {
+				op := (g + k) % len(operations)
+				if err := operations[op]((g + k) % numBlobs); err != nil {
+					t.Errorf("operation %d: %v", op, err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+// blockingObserver blocks the first operation reported to it until release is closed, so that a call is in flight
+// for as long as the test needs.
+type blockingObserver struct {
+	once    sync.Once
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (o *blockingObserver) OnOperation(string, int, time.Duration) {
+	o.once.Do(func() {
+		close(o.entered)
+		<-o.release
+	})
+}
+
+func TestContextCloseWaitsForCalls(t *testing.T) {
+	observer := &blockingObserver{entered: make(chan struct{}), release: make(chan struct{})}
+	closingCtx, err := gokzg4844.NewContext4096Secure(gokzg4844.WithObserver(observer))
+	require.NoError(t, err)
+	blob := testutil.GenerateBlob(1)
+	expected, err := ctx.BlobToKZGCommitment(blob, 1)
+	require.NoError(t, err)
+
+	type result struct {
+		commitment gokzg4844.KZGCommitment
+		err        error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		commitment, err := closingCtx.BlobToKZGCommitment(blob, 1)
+		inFlight <- result{commitment, err}
+	}()
+	<-observer.entered
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- closingCtx.Close()
+	}()
+
+	// The calls made once Close started are rejected, while Close waits for the call in flight
+	require.Eventually(t, func() bool {
+		_, err := closingCtx.DomainByIndex(0)
+		return errors.Is(err, gokzg4844.ErrContextClosed)
+	}, 5*time.Second, time.Millisecond)
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a call was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(observer.release)
+	got := <-inFlight
+	require.NoError(t, got.err)
+	require.Equal(t, expected, got.commitment)
+	require.NoError(t, <-closed)
+	require.Equal(t, gokzg4844.MemoryUsage{}, closingCtx.MemoryFootprint())
+}
+
+// TestContextCloseConcurrentUse closes a context while many go routines use it. Run it with -race to check that
+// closing the context does not race with the calls in flight.
+func TestContextCloseConcurrentUse(t *testing.T) {
+	const numGoRoutines = 8
+	closingCtx, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+	blob := testutil.GenerateBlob(2)
+	commitment, err := ctx.BlobToKZGCommitment(blob, 1)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, 1)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	started := make(chan struct{}, numGoRoutines)
+	for g := 0; g < numGoRoutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for k := 0; ; k++ {
+				if k == 1 {
+					started <- struct{}{}
+				}
+				var err error
+				if (g+k)%2 == 0 {
+					err = closingCtx.VerifyBlobKZGProof(blob, commitment, proof)
+				} else {
+					_, err = closingCtx.BlobToKZGCommitment(blob, 1)
+				}
+				if errors.Is(err, gokzg4844.ErrContextClosed) {
+					return
+				}
+				if err != nil {
+					t.Errorf("go routine %d: %v", g, err)
+					return
+				}
+			}
+		}(g)
+	}
+	for g := 0; g < numGoRoutines; g++ {
+		<-started
+	}
+	require.NoError(t, closingCtx.Close())
+	wg.Wait()
+}
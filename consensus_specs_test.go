@@ -215,6 +215,7 @@ func TestVerifyKZGProof(t *testing.T) {
 			// validation errors
 			if err != nil && !errors.Is(err, kzg.ErrVerifyOpeningProof) {
 				require.False(t, testCaseValid)
+				require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
 			} else {
 				// Either the error is nil or it is a verification error
 				expectedOutput := *test.ProofIsValid
@@ -272,6 +273,7 @@ func TestVerifyBlobKZGProof(t *testing.T) {
 			// validation errors
 			if err != nil && !errors.Is(err, kzg.ErrVerifyOpeningProof) {
 				require.False(t, testCaseValid)
+				require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
 			} else {
 				// Either the error is nil or it is a verification error
 				expectedOutput := *test.ProofIsValid
@@ -345,6 +347,7 @@ func TestVerifyBlobKZGProofBatch(t *testing.T) {
 			// validation errors
 			if err != nil && err != kzg.ErrVerifyOpeningProof {
 				require.False(t, testCaseValid)
+				require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
 			} else {
 				// Either the error is nil or it is a verification error
 				expectedOutput := *test.ProofIsValid
@@ -1,13 +1,216 @@
 package gokzg4844
 
 import (
+	"sync"
+	"sync/atomic"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
+// DomainByIndex returns the index'th root of unity of the domain of the context, in the bit-reversed order in which
+// the scalars of a [Blob] are evaluations of its polynomial. It returns [ErrIndexOutOfRange] if the index is
+// negative or not smaller than the size of the domain.
+//
+// The returned element is a copy, so modifying it does not affect the context.
 func (c *Context) DomainByIndex(index int) (*fr.Element, error) {
-	if index > int(c.domain.Cardinality) {
+	if !c.calls.acquire() {
+		return nil, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	if index < 0 || index >= len(c.domain.Roots) {
 		return nil, ErrIndexOutOfRange
 	}
 
-	return &c.domain.Roots[index], nil
+	root := c.domain.Roots[index]
+	return &root, nil
+}
+
+// CommitKeyPoints returns a copy of the lagrange G1 points used by the context to commit to polynomials, or nil if
+// the context was created using [ModeVerifyOnly].
+//
+// The points are bit-reversed like the domain, so that the i'th point is the one the i'th scalar of a [Blob] is
+// multiplied by when computing its commitment. The copy can be modified freely; to avoid copying the points, use
+// [Context.CommitKeyPointsUnsafe].
+func (c *Context) CommitKeyPoints() []bls12381.G1Affine {
+	if !c.calls.acquire() {
+		return nil
+	}
+	defer c.calls.release()
+	if c.commitKey == nil {
+		return nil
+	}
+	points := make([]bls12381.G1Affine, len(c.commitKey.G1))
+	copy(points, c.commitKey.G1)
+	return points
+}
+
+// CommitKeyPointsUnsafe returns the lagrange G1 points used by the context to commit to polynomials, in the same
+// order as [Context.CommitKeyPoints], without copying them.
+//
+// The returned slice is shared with the context and MUST NOT be modified, since this would change the commitments
+// and proofs computed by the context.
+func (c *Context) CommitKeyPointsUnsafe() []bls12381.G1Affine {
+	if !c.calls.acquire() {
+		return nil
+	}
+	defer c.calls.release()
+	if c.commitKey == nil {
+		return nil
+	}
+	return c.commitKey.G1
+}
+
+// CommitKeyPointsBytes returns the compressed encoding of the points returned by [Context.CommitKeyPoints].
+func (c *Context) CommitKeyPointsBytes() []G1Point {
+	if !c.calls.acquire() {
+		return nil
+	}
+	defer c.calls.release()
+	if c.commitKey == nil {
+		return nil
+	}
+	points := make([]G1Point, len(c.commitKey.G1))
+	for i := range c.commitKey.G1 {
+		points[i] = SerializeG1Point(c.commitKey.G1[i])
+	}
+	return points
+}
+
+// VerifKeyG2 returns the G2 points used by the context to verify opening proofs: the generator [1]₂ and [τ]₂,
+// which are the first two G2 points of the trusted setup.
+func (c *Context) VerifKeyG2() [2]bls12381.G2Affine {
+	if !c.calls.acquire() {
+		return [2]bls12381.G2Affine{}
+	}
+	defer c.calls.release()
+	return [2]bls12381.G2Affine{c.openKey.GenG2, c.openKey.AlphaG2}
+}
+
+// VerifKeyG2Bytes returns the compressed encoding of the points returned by [Context.VerifKeyG2].
+func (c *Context) VerifKeyG2Bytes() [2]G2Point {
+	if !c.calls.acquire() {
+		return [2]G2Point{}
+	}
+	defer c.calls.release()
+	return [2]G2Point{c.openKey.GenG2.Bytes(), c.openKey.AlphaG2.Bytes()}
+}
+
+// MemoryUsage is an estimate of the memory held by a [Context], in bytes, as returned by [Context.MemoryFootprint].
+type MemoryUsage struct {
+	// SRS is the memory held by the points of the trusted setup: the lagrange and monomial G1 points and the G2
+	// points.
+	SRS int
+	// Domain is the memory held by the roots of unity and their precomputed inverses, including those of the
+	// extended domain used by the EIP-7594 methods once it has been computed.
+	Domain int
+	// PrecomputedTables is the memory held by the optional precomputations: the fixed base table computed using
+	// [WithPrecomputedSRS], the lines used to compute pairings and the precomputations used by
+	// [Context.ComputeCellsAndKZGProofs] once they have been computed.
+	PrecomputedTables int
+}
+
+// Total returns the sum of the components of the memory usage.
+func (m MemoryUsage) Total() int {
+	return m.SRS + m.Domain + m.PrecomputedTables
+}
+
+// MemoryFootprint returns an estimate of the memory held by the context. It only counts the large slices and tables,
+// so the actual usage is slightly higher. It depends on the [ContextMode]: a context created using [ModeVerifyOnly]
+// holds no G1 points, and one created using [ModeFull] holds the precomputations of the EIP-7594 methods. It returns
+// a zero [MemoryUsage] once the context has been closed.
+func (c *Context) MemoryFootprint() MemoryUsage {
+	if !c.calls.acquire() {
+		return MemoryUsage{}
+	}
+	defer c.calls.release()
+
+	openKeyPoints, pairingLines := c.openKey.MemorySize()
+	usage := MemoryUsage{
+		SRS:               openKeyPoints,
+		Domain:            c.domain.MemorySize() + int(c.extendedDomainSize.Load()),
+		PrecomputedTables: pairingLines + int(c.cellProofKeySize.Load()),
+	}
+	if c.commitKey != nil {
+		commitKeyPoints, fixedBaseTable := c.commitKey.MemorySize()
+		usage.SRS += commitKeyPoints
+		usage.PrecomputedTables += fixedBaseTable
+	}
+	if c.monomialCommitKey != nil {
+		monomialPoints, _ := c.monomialCommitKey.MemorySize()
+		usage.SRS += monomialPoints
+	}
+	return usage
+}
+
+// Close releases the trusted setup and the precomputations held by the context, so that their memory can be reclaimed
+// by the garbage collector even if the context itself is still referenced. Afterwards, the methods of the context
+// return [ErrContextClosed], and those which do not return an error return zero values. Calling Close again has no
+// effect, and the error it returns is always nil.
+//
+// Close can be called while other go routines use the context: it waits for the calls in flight to return, and the
+// calls made after it started return [ErrContextClosed]. It must not be called from a callback of the context, such
+// as an [Observer], since it would wait for the call running the callback.
+func (c *Context) Close() error {
+	if !c.calls.close() {
+		return nil
+	}
+	c.domain = nil
+	c.commitKey = nil
+	c.openKey = nil
+	c.monomialCommitKey = nil
+	c.extendedDomainCache = nil
+	c.cellCosetDomainCache = nil
+	c.cellProofKeyCache = nil
+	c.commitmentCache = nil
+	return nil
+}
+
+// callTracker counts the calls to the methods of a [Context] which are in flight, so that [Context.Close] can wait
+// for them to return before releasing the trusted setup. The zero value is an open context with no calls in flight.
+type callTracker struct {
+	closed   atomic.Bool
+	inFlight atomic.Int64
+
+	// drained is closed by the last call to return once the context is closed
+	drained     chan struct{}
+	drainedOnce sync.Once
+	signalOnce  sync.Once
+}
+
+// acquire registers a call and returns true, or returns false if the context is closed. Every successful call must
+// be followed by a call to release. Calls can be nested.
+func (t *callTracker) acquire() bool {
+	// The counter is incremented before closed is read, so that close either sees the call or the call sees close
+	t.inFlight.Add(1)
+	if t.closed.Load() {
+		t.release()
+		return false
+	}
+	return true
+}
+
+// release ends a call registered by acquire.
+func (t *callTracker) release() {
+	if t.inFlight.Add(-1) == 0 && t.closed.Load() {
+		t.signalOnce.Do(func() { close(t.drainedChan()) })
+	}
+}
+
+// close marks the context as closed and waits for the calls in flight to return. It returns false if the context
+// was already closed, in which case it does not wait.
+func (t *callTracker) close() bool {
+	if t.closed.Swap(true) {
+		return false
+	}
+	if t.inFlight.Load() > 0 {
+		<-t.drainedChan()
+	}
+	return true
+}
+
+func (t *callTracker) drainedChan() chan struct{} {
+	t.drainedOnce.Do(func() { t.drained = make(chan struct{}) })
+	return t.drained
 }
@@ -1,9 +1,244 @@
 package gokzg4844
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+)
+
+// ErrInvalidInput and ErrProofInvalid classify the errors returned by the verification methods in the same way as
+// the consensus specs, whose functions either raise an exception or return false:
+//   - Every error for an input which the specs reject with an exception wraps ErrInvalidInput: a blob holding a
+//     non-canonical scalar, a commitment or proof which is not a valid point in the correct subgroup, a byte slice
+//     of the wrong length, or batches whose numbers of blobs, commitments and proofs differ.
+//   - A well-formed proof which does not verify, for which the specs return false, is reported as ErrProofInvalid.
+//
+// A batch is only valid if all of its proofs are valid: batch methods never report a partial success. An empty batch
+// is valid, as in the consensus specs, and nil and empty slices are interchangeable: the batch verification methods
+// return nil for a batch with no elements, and the batch methods computing commitments or proofs return an empty,
+// non-nil slice. If the slices of a batch do not all have the same length, a [*BatchLengthError] is returned.
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrProofInvalid = kzg.ErrVerifyOpeningProof
+)
 
 var (
-	ErrBatchLengthCheck   = errors.New("the number of blobs, commitments, and proofs must be the same")
-	ErrNonCanonicalScalar = errors.New("scalar is not canonical when interpreted as a big integer in big-endian")
-	ErrIndexOutOfRange    = errors.New("index is out of cardinality")
+	ErrBatchLengthCheck     = invalidInputError("the number of blobs, commitments, and proofs must be the same")
+	ErrNonCanonicalScalar   = invalidInputError("scalar is not canonical when interpreted as a big integer in big-endian")
+	ErrIndexOutOfRange      = errors.New("index is out of cardinality")
+	ErrEquivalenceMismatch  = errors.New("the external evaluation does not match the evaluation of the blob")
+	ErrPointAtInfinity      = invalidInputError("the point at infinity is not allowed as a commitment or proof")
+	ErrInvalidPointEncoding = invalidInputError("the point is not a valid compressed encoding of a point on the curve")
+	ErrPointNotInSubgroup   = invalidInputError("the point is not in the correct subgroup")
+	ErrInvalidHexString     = invalidInputError("the hex-string does not encode the expected number of bytes")
+
+	ErrWrongLength = invalidInputError("the input does not have the expected length")
+
+	ErrInvalidCellIndex   = invalidInputError("the cell index is not less than the number of cells of an extended blob")
+	ErrDuplicateCellIndex = invalidInputError("the cell indices must be distinct")
+	ErrNotEnoughCells     = invalidInputError("at least half of the cells of an extended blob are needed to recover it")
+
+	ErrInvalidExtensionIndex   = invalidInputError("the index is not less than the number of evaluations of an extended blob")
+	ErrDuplicateExtensionIndex = invalidInputError("the indices of the evaluations must be distinct")
+	ErrNotEnoughEvaluations    = invalidInputError("at least half of the evaluations of an extended blob are needed to recover it")
+
+	ErrTrustedCommitmentUnset = invalidInputError("the trusted commitment was not created using Context.ValidateCommitment or UnsafeTrustedCommitmentFromPoint")
+
+	ErrInvalidPartialIndex       = invalidInputError("the index of a partial commitment is not less than the number of scalars in a blob")
+	ErrDuplicatePartialIndex     = invalidInputError("the indices of a partial commitment must be distinct")
+	ErrPartialCommitmentUnset    = invalidInputError("the partial commitment was not created using Context.PrecomputePartialCommitment")
+	ErrPartialCommitmentMismatch = invalidInputError("the blob does not hold the scalars of the partial commitment at the indices it covers")
+
+	ErrVersionedHashMismatch = &classifiedError{msg: "the versioned hash of the commitment to the blob does not match the expected versioned hash", class: ErrProofInvalid}
+
+	ErrInvalidTrustedSetupJSON  = errors.New("the trusted setup is not valid JSON")
+	ErrInvalidTrustedSetupText  = errors.New("the trusted setup is not in the expected text format")
+	ErrInvalidTrustedSetupSize  = errors.New("the trusted setup has the wrong number of points")
+	ErrInvalidTrustedSetupPoint = errors.New("the trusted setup contains an invalid point encoding")
+
+	ErrInvalidTrustedSetupStructure = errors.New("the trusted setup is not made of successive powers of the same secret")
+
+	ErrTrustedSetupG2Generator     = errors.New("the first G2 point of the trusted setup is not the canonical G2 generator")
+	ErrTrustedSetupPointAtInfinity = errors.New("the trusted setup contains a lagrange G1 point at infinity")
+	ErrTrustedSetupDuplicatePoint  = errors.New("the trusted setup contains duplicate lagrange G1 points")
+
+	ErrInvalidSetupCache  = errors.New("the trusted setup cache is malformed")
+	ErrSetupCacheVersion  = errors.New("the trusted setup cache was written using an unsupported format version")
+	ErrSetupCacheChecksum = errors.New("the points in the trusted setup cache do not match its checksum")
+
+	ErrSetupFingerprintMismatch = errors.New("the fingerprint of the trusted setup does not match the expected fingerprint")
+
+	ErrInvalidContextSize  = errors.New("the size of the context must be a power of two between 2 and 2^32, or 2^30 on 32-bit platforms")
+	ErrContextSizeMismatch = errors.New("the number of evaluations does not match the size of the context")
+	ErrContextClosed       = errors.New("the context has been closed")
+
+	ErrRandomSource = kzg.ErrRandomSource
+
+	ErrMonomialSRSUnavailable = errors.New("the context does not hold the monomial SRS")
+	ErrVerifyOnlyContext      = errors.New("the context was created using ModeVerifyOnly and cannot commit or prove")
+	ErrInvalidMonomialSRSSize = errors.New("the number of monomial G1 points does not match the number of lagrange G1 points")
 )
+
+// classifiedError is a sentinel error which wraps either [ErrInvalidInput] or [ErrProofInvalid].
+type classifiedError struct {
+	msg   string
+	class error
+}
+
+// invalidInputError returns a sentinel error wrapping [ErrInvalidInput].
+func invalidInputError(msg string) error {
+	return &classifiedError{msg: msg, class: ErrInvalidInput}
+}
+
+func (e *classifiedError) Error() string {
+	return e.msg
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.class
+}
+
+// BlobError is returned by batch methods to report which of the blobs, or of the commitments or proofs at the same
+// index, caused the error.
+type BlobError struct {
+	// Index of the blob in the batch
+	Index int
+	// Err is the error returned for this blob
+	Err error
+}
+
+func (e *BlobError) Error() string {
+	return fmt.Sprintf("blob at index %d: %v", e.Index, e.Err)
+}
+
+func (e *BlobError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned by [Context.VerifyBlobKZGProofBatchPar] with [WithAllFailures] or [WithBisection] to report
+// every invalid blob, commitment or proof of a batch. It wraps a [*BlobError] for each of the failing indices.
+type BatchError struct {
+	// Errs holds the errors of the failing indices, in increasing order of index
+	Errs []*BlobError
+}
+
+// newBatchError returns a [*BatchError] for the non-nil errors of errs, which holds the error of every index of a
+// batch, or nil if there is none.
+func newBatchError(errs []error) error {
+	var batchErr BatchError
+	for i, err := range errs {
+		if err != nil {
+			batchErr.Errs = append(batchErr.Errs, &BlobError{Index: i, Err: err})
+		}
+	}
+	if len(batchErr.Errs) == 0 {
+		return nil
+	}
+	return &batchErr
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d invalid blobs, commitments or proofs in the batch, the first one is %v", len(e.Errs), e.Errs[0])
+}
+
+// Indices returns the failing indices of the batch, in increasing order.
+func (e *BatchError) Indices() []int {
+	indices := make([]int, len(e.Errs))
+	for i, err := range e.Errs {
+		indices[i] = err.Index
+	}
+	return indices
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errs))
+	for i, err := range e.Errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// BatchLengthError is returned by batch methods when their slices do not all have the same length. It names each of
+// the slices along with its length, and wraps [ErrBatchLengthCheck]. A nil slice has length 0.
+type BatchLengthError struct {
+	// Names of the slices, in the order of the parameters of the method
+	Names []string
+	// Lengths of the slices, in the same order as Names
+	Lengths []int
+}
+
+// checkBatchLengths returns a [*BatchLengthError] if the slices of a batch, whose names and lengths are given in the
+// same order, do not all have the same length.
+func checkBatchLengths(names []string, lengths ...int) error {
+	for _, length := range lengths[1:] {
+		if length != lengths[0] {
+			return &BatchLengthError{Names: names, Lengths: lengths}
+		}
+	}
+	return nil
+}
+
+func (e *BatchLengthError) Error() string {
+	got := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		got[i] = fmt.Sprintf("%d %s", e.Lengths[i], name)
+	}
+	return fmt.Sprintf("%v: got %s", ErrBatchLengthCheck, strings.Join(got, ", "))
+}
+
+func (e *BatchLengthError) Unwrap() error {
+	return ErrBatchLengthCheck
+}
+
+// ScalarError is returned when a scalar of a blob is not canonical. It holds the index of the scalar in the blob and
+// wraps [ErrNonCanonicalScalar]. Batch methods wrap it in a [*BlobError] holding the index of the blob.
+type ScalarError struct {
+	// Index of the scalar in the blob
+	Index int
+}
+
+func (e *ScalarError) Error() string {
+	return fmt.Sprintf("scalar at index %d: %v", e.Index, ErrNonCanonicalScalar)
+}
+
+func (e *ScalarError) Unwrap() error {
+	return ErrNonCanonicalScalar
+}
+
+// PointError is returned when a commitment or a proof is invalid. It wraps the reason, which is one of
+// [ErrInvalidPointEncoding], [ErrPointNotInSubgroup], [ErrPointAtInfinity] or, for the methods taking byte slices,
+// a [*LengthError]. Batch methods wrap it in a [*BlobError]
+// holding the index of the commitment or proof.
+type PointError struct {
+	// Kind is "commitment" or "proof"
+	Kind string
+	// Err is the reason the point is invalid
+	Err error
+}
+
+func (e *PointError) Error() string {
+	return fmt.Sprintf("invalid %s: %v", e.Kind, e.Err)
+}
+
+func (e *PointError) Unwrap() error {
+	return e.Err
+}
+
+// LengthError is returned by the methods taking byte slices when a slice does not have the length of the type it
+// encodes. It wraps [ErrWrongLength].
+type LengthError struct {
+	// Got is the length of the slice
+	Got int
+	// Want is the expected length
+	Want int
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("%v: got %d bytes, want %d", ErrWrongLength, e.Got, e.Want)
+}
+
+func (e *LengthError) Unwrap() error {
+	return ErrWrongLength
+}
@@ -0,0 +1,86 @@
+package gokzg4844
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// EvaluateBlobAt evaluates the polynomial represented by `blob` at the point `inputPointBytes` and returns the
+// serialized result, without computing a proof. It is the claimed value returned by [Context.ComputeKZGProof] for
+// the same point.
+//
+// If the point is in the domain, the result is the scalar of the blob at the index of this point.
+func (c *Context) EvaluateBlobAt(blob *Blob, inputPointBytes Scalar) (Scalar, error) {
+	if !c.calls.acquire() {
+		return Scalar{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization
+	//
+	inputPoint, err := DeserializeScalar(inputPointBytes)
+	if err != nil {
+		return Scalar{}, err
+	}
+
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return Scalar{}, err
+	}
+
+	// 2. Evaluation
+	//
+	outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, inputPoint)
+	if err != nil {
+		return Scalar{}, err
+	}
+
+	// 3. Serialization
+	//
+	return SerializeScalar(*outputPoint), nil
+}
+
+// EvaluateBlobAtPoints evaluates the polynomial represented by `blob` at each of the points in `inputPointsBytes`,
+// as if calling [Context.EvaluateBlobAt] for each of them. The blob is only deserialized once and a single batch
+// inversion is shared between all the points.
+//
+// If a point is not canonical, an error wrapping [ErrNonCanonicalScalar] and holding its index is returned.
+func (c *Context) EvaluateBlobAtPoints(blob *Blob, inputPointsBytes []Scalar) ([]Scalar, error) {
+	if !c.calls.acquire() {
+		return nil, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization
+	//
+	inputPoints := make([]fr.Element, len(inputPointsBytes))
+	for i := range inputPointsBytes {
+		inputPoint, err := DeserializeScalar(inputPointsBytes[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: input point at index %d", err, i)
+		}
+		inputPoints[i] = inputPoint
+	}
+
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Evaluation
+	//
+	outputPoints, err := c.domain.EvaluateLagrangePolynomialAtPoints(polynomial, inputPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Serialization
+	//
+	outputPointsBytes := make([]Scalar, len(outputPoints))
+	for i := range outputPoints {
+		outputPointsBytes[i] = SerializeScalar(outputPoints[i])
+	}
+
+	return outputPointsBytes, nil
+}
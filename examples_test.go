@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -11,7 +12,7 @@ import (
 var ctx, _ = gokzg4844.NewContext4096Secure()
 
 func TestBlobProveVerifyRandomPointIntegration(t *testing.T) {
-	blob := GetRandBlob(123)
+	blob := testutil.GenerateBlob(123)
 	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
 	require.NoError(t, err)
 	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
@@ -21,10 +22,10 @@ func TestBlobProveVerifyRandomPointIntegration(t *testing.T) {
 }
 
 func TestBlobProveVerifySpecifiedPointIntegration(t *testing.T) {
-	blob := GetRandBlob(123)
+	blob := testutil.GenerateBlob(123)
 	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
 	require.NoError(t, err)
-	inputPoint := GetRandFieldElement(123)
+	inputPoint := testutil.GenerateScalar(123)
 	proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
 	require.NoError(t, err)
 	err = ctx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof)
@@ -38,7 +39,7 @@ func TestBlobProveVerifyBatchIntegration(t *testing.T) {
 	proofs := make([]gokzg4844.KZGProof, batchSize)
 
 	for i := 0; i < batchSize; i++ {
-		blob := GetRandBlob(int64(i))
+		blob := testutil.GenerateBlob(int64(i))
 		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
 		require.NoError(t, err)
 		proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
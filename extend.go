@@ -0,0 +1,90 @@
+package gokzg4844
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// ExtendBlob returns the Reed-Solomon extension with rate 1/2 of the polynomial represented by `blob`, which is made of
+// its evaluations over the domain of size 2*[ScalarsPerBlob], in bit-reversed order.
+//
+// Since the domain of the blob is also in bit-reversed order, the first half of the extension is the blob itself. The
+// cells returned by [Context.ComputeCells] are the chunks of [FieldElementsPerCell] evaluations of the extension.
+func (c *Context) ExtendBlob(blob *Blob) ([2 * ScalarsPerBlob]fr.Element, error) {
+	if !c.calls.acquire() {
+		return [2 * ScalarsPerBlob]fr.Element{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return [2 * ScalarsPerBlob]fr.Element{}, err
+	}
+
+	// 2. Extension
+	//
+	extended, err := c.domain.ExtendPolynomial(polynomial)
+	if err != nil {
+		return [2 * ScalarsPerBlob]fr.Element{}, err
+	}
+
+	var result [2 * ScalarsPerBlob]fr.Element
+	copy(result[:], extended)
+	return result, nil
+}
+
+// RecoverBlob is the inverse of [Context.ExtendBlob]: it returns the blob whose extension holds evaluations[i] at index
+// indices[i], given at least [ScalarsPerBlob] of the 2*[ScalarsPerBlob] evaluations.
+//
+// The indices need not be sorted, but they must be distinct and less than 2*[ScalarsPerBlob]. If an index is invalid,
+// a [*BlobError] holding its position in the input is returned. The evaluations are not checked against each other: if
+// they do not come from the extension of a blob, the extension of the recovered blob does not match them.
+//
+// Recovery from the evaluations over whole cells is faster using [Context.RecoverCellsAndKZGProofs].
+func (c *Context) RecoverBlob(indices []uint64, evaluations []fr.Element) (*Blob, error) {
+	if !c.calls.acquire() {
+		return nil, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	if err := checkBatchLengths([]string{"indices", "evaluations"}, len(indices), len(evaluations)); err != nil {
+		return nil, err
+	}
+	if c.domain.Cardinality != ScalarsPerBlob {
+		return nil, ErrContextSizeMismatch
+	}
+
+	// 1. Validation of the indices
+	//
+	seen := make([]bool, 2*ScalarsPerBlob)
+	for i, index := range indices {
+		if index >= 2*ScalarsPerBlob {
+			return nil, &BlobError{Index: i, Err: fmt.Errorf("%w: got %d", ErrInvalidExtensionIndex, index)}
+		}
+		if seen[index] {
+			return nil, &BlobError{Index: i, Err: fmt.Errorf("%w: %d is repeated", ErrDuplicateExtensionIndex, index)}
+		}
+		seen[index] = true
+	}
+	if len(indices) < ScalarsPerBlob {
+		return nil, fmt.Errorf("%w: got %d", ErrNotEnoughEvaluations, len(indices))
+	}
+
+	// 2. Recovery, treating each evaluation as a coset of size one
+	//
+	cosetEvaluations := make([][]fr.Element, len(evaluations))
+	for i := range evaluations {
+		cosetEvaluations[i] = evaluations[i : i+1]
+	}
+	polynomial, err := c.domain.RecoverPolynomial(1, indices, cosetEvaluations)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Serialization
+	//
+	return SerializePoly(polynomial), nil
+}
@@ -1,9 +1,13 @@
 package gokzg4844
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
+	"hash"
+	"sync"
 
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
@@ -14,22 +18,155 @@ import (
 // [FIAT_SHAMIR_PROTOCOL_DOMAIN]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob
 const DomSepProtocol = "FSBLOBVERIFY_V1_"
 
-// computeChallenge is provided to match the spec at [compute_challenge].
+// DomSepEquivalence is a Domain Separator to identify the transcript used by
+// [Context.ComputeEquivalenceProof] and [Context.VerifyEquivalenceProof].
 //
-// [compute_challenge]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_challenge
+// The version suffix must be bumped whenever the transcript described in [ComputeEquivalenceChallenge] changes.
+const DomSepEquivalence = "KZGEQUIVALENCE_V1"
+
+// ComputeChallenge returns the Fiat-Shamir challenge at which the polynomial represented by `blob` is opened by
+// [Context.ComputeBlobKZGProof] and [Context.VerifyBlobKZGProof], matching [compute_challenge] in the spec. It is
+// exported so that other protocols can bind to the same evaluation point.
+//
+// The challenge is computed as [hash_to_bls_field](SHA-256(transcript)), where transcript is the concatenation of:
+//   - DomSepProtocol as ASCII bytes
+//   - ScalarsPerBlob as a 16 byte big endian integer
+//   - the blob
+//   - the 48 byte compressed KZG commitment
 //
+// hash_to_bls_field interprets the 32 byte digest as a big endian integer and reduces it modulo the scalar field order.
+//
+// [compute_challenge]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_challenge
 // [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
-func computeChallenge(blob *Blob, commitment KZGCommitment) fr.Element {
-	h := sha256.New()
-	h.Write([]byte(DomSepProtocol))
-	h.Write(u64ToByteArray16(ScalarsPerBlob))
-	h.Write(blob[:])
+func ComputeChallenge(blob *Blob, commitment KZGCommitment) fr.Element {
+	scratch := challengeScratchPool.Get().(*challengeScratch)
+	defer challengeScratchPool.Put(scratch)
+
+	// The components are written to the transcript one after the other, so that the blob is not copied
+	t := &scratch.transcript
+	t.resetUnframed()
+	t.appendUnframed(challengePrefix)
+	t.appendUnframed(blob[:])
+	// commitment is copied, since slicing the parameter would make it escape to the heap
+	scratch.commitment = commitment
+	t.appendUnframed(scratch.commitment[:])
+
+	return t.challengeUnframed()
+}
+
+// challengeDegree is ScalarsPerBlob as it is written in the transcript of [ComputeChallenge].
+var challengeDegree = u64ToByteArray16(ScalarsPerBlob)
+
+// challengePrefix is the start of the transcript of [ComputeChallenge], which does not depend on its inputs.
+var challengePrefix = append([]byte(DomSepProtocol), challengeDegree...)
+
+// challengeScratch holds a transcript and a buffer for the commitment, which are reused by [ComputeChallenge] so
+// that computing a challenge does not allocate.
+type challengeScratch struct {
+	transcript Transcript
+	commitment KZGCommitment
+}
+
+var challengeScratchPool = sync.Pool{
+	New: func() any {
+		return &challengeScratch{transcript: Transcript{hasher: sha256.New()}}
+	},
+}
+
+// computeChallenge is [ComputeChallenge] with the hash function and domain separator of the context, see
+// [WithChallengeHasher] and [WithChallengeDomainSeparator].
+func (c *Context) computeChallenge(blob *Blob, commitment KZGCommitment) fr.Element {
+	if c.newChallengeHasher == nil && c.challengeDomainSeparator == DomSepProtocol {
+		return ComputeChallenge(blob, commitment)
+	}
+
+	// The hashers are pooled by the context, since they cannot be shared with other contexts
+	scratch, ok := c.challengeScratchPool.Get().(*challengeScratch)
+	if !ok {
+		scratch = &challengeScratch{transcript: Transcript{hasher: c.challengeHasher()}}
+	}
+	defer c.challengeScratchPool.Put(scratch)
+
+	t := &scratch.transcript
+	t.resetUnframed()
+	t.appendUnframed([]byte(c.challengeDomainSeparator))
+	t.appendUnframed(challengeDegree)
+	t.appendUnframed(blob[:])
+	scratch.commitment = commitment
+	t.appendUnframed(scratch.commitment[:])
+	return t.challengeUnframed()
+}
+
+// computeChallenges returns the evaluation challenge of every blob and its commitment, see
+// [Context.computeChallenge]. The blobs are hashed concurrently using numGoRoutines go routines, and the hashers are
+// reused between the blobs.
+//
+// ctx is checked before hashing each blob.
+func (c *Context) computeChallenges(ctx context.Context, blobs []Blob, commitments []KZGCommitment, numGoRoutines int) ([]fr.Element, error) {
+	challenges := make([]fr.Element, len(blobs))
+	err := utils.ParallelForCtx(ctx, len(blobs), numGoRoutines, func(i int) error {
+		challenges[i] = c.computeChallenge(&blobs[i], commitments[i])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// challengeHasher returns a new hasher for the Fiat-Shamir challenges of the context.
+func (c *Context) challengeHasher() hash.Hash {
+	if c.newChallengeHasher == nil {
+		return sha256.New()
+	}
+	return c.newChallengeHasher()
+}
+
+// computeEquivalenceChallenge hashes the transcript of [ComputeEquivalenceChallenge] with `h`, starting with
+// domainSeparator instead of DomSepEquivalence.
+func computeEquivalenceChallenge(h hash.Hash, domainSeparator string, commitment KZGCommitment, externalCommitment []byte) fr.Element {
+	h.Write([]byte(domainSeparator))
+	h.Write(challengeDegree)
 	h.Write(commitment[:])
+	h.Write(u64ToByteArray16(uint64(len(externalCommitment))))
+	h.Write(externalCommitment)
+	return utils.HashToBLSFieldFromHash(h, nil)
+}
+
+// ComputeEquivalenceChallenge returns the challenge used by [Context.ComputeEquivalenceProof] for the
+// given commitments, so that the external commitment can be opened at the same point.
+//
+// The challenge is computed as hash_to_bls_field(SHA-256(transcript)), where transcript is the concatenation of:
+//   - DomSepEquivalence as ASCII bytes
+//   - ScalarsPerBlob as a 16 byte big endian integer
+//   - the 48 byte compressed KZG commitment
+//   - the length of the external commitment in bytes as a 16 byte big endian integer
+//   - the external commitment
+//
+// The length prefix ensures that the transcript is unambiguous for external commitments of any size.
+//
+// The challenge of a [Context] created with [WithChallengeHasher] or [WithChallengeDomainSeparator] is returned by
+// [Context.ComputeEquivalenceChallenge] instead.
+//
+// [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
+func ComputeEquivalenceChallenge(blobCommitment KZGCommitment, externalCommitment []byte) Scalar {
+	return SerializeScalar(computeEquivalenceChallenge(sha256.New(), DomSepEquivalence, blobCommitment, externalCommitment))
+}
+
+// ComputeEquivalenceChallenge is [ComputeEquivalenceChallenge] with the hash function and domain separator of the
+// context. When a domain separator is set using [WithChallengeDomainSeparator], the transcript starts with it followed
+// by DomSepEquivalence, so that the equivalence challenges of a deployment differ from its evaluation challenges.
+func (c *Context) ComputeEquivalenceChallenge(blobCommitment KZGCommitment, externalCommitment []byte) Scalar {
+	return SerializeScalar(c.computeEquivalenceChallenge(blobCommitment, externalCommitment))
+}
 
-	digest := h.Sum(nil)
-	var challenge fr.Element
-	challenge.SetBytes(digest[:])
-	return challenge
+// computeEquivalenceChallenge is the implementation of [Context.ComputeEquivalenceChallenge].
+func (c *Context) computeEquivalenceChallenge(commitment KZGCommitment, externalCommitment []byte) fr.Element {
+	domainSeparator := DomSepEquivalence
+	if c.challengeDomainSeparator != DomSepProtocol {
+		domainSeparator = c.challengeDomainSeparator + DomSepEquivalence
+	}
+	return computeEquivalenceChallenge(c.challengeHasher(), domainSeparator, commitment, externalCommitment)
 }
 
 // u64ToByteArray16 converts a uint64 to a byte slice of length 16 in big endian format. This implies that the first 8 bytes of the result are always 0.
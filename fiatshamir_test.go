@@ -1,20 +1,27 @@
 package gokzg4844
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
 	"testing"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
 )
 
 // This is both an interop test and a regression check
-// If the way computeChallenge is computed is updated
+// If the way ComputeChallenge is computed is updated
 // then this test will fail
 func TestComputeChallengeInterop(t *testing.T) {
 	blob := &Blob{}
 	commitment := SerializeG1Point(bls12381.G1Affine{})
-	challenge := computeChallenge(blob, KZGCommitment(commitment))
+	challenge := ComputeChallenge(blob, KZGCommitment(commitment))
 	expected := []byte{
 		0x04, 0xb7, 0xb2, 0x2a, 0xf6, 0x3d, 0x2b, 0x2f,
 		0x1c, 0xed, 0x8d, 0x55, 0x05, 0x60, 0xe5, 0xd1,
@@ -25,6 +32,133 @@ func TestComputeChallengeInterop(t *testing.T) {
 	require.Equal(t, expected, got[:])
 }
 
+// The expected values were computed independently by hashing the transcript documented
+// in ComputeChallenge and reducing it modulo the field order.
+func TestComputeChallengeVectors(t *testing.T) {
+	// The i-th scalar of the blob is i
+	var blob Blob
+	for i := 0; i < ScalarsPerBlob; i++ {
+		binary.BigEndian.PutUint64(blob[(i+1)*SerializedScalarSize-8:(i+1)*SerializedScalarSize], uint64(i))
+	}
+	generator, err := hex.DecodeString("97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb")
+	require.NoError(t, err)
+	infinity := SerializeG1Point(bls12381.G1Affine{})
+
+	tests := []struct {
+		name       string
+		blob       *Blob
+		commitment KZGCommitment
+		expected   string
+	}{
+		{"zero blob, commitment at infinity", &Blob{}, KZGCommitment(infinity), "04b7b22af63d2b2f1ced8d550560e5d1e4b01e355903dee22781e87826856096"},
+		{"nonzero blob, generator commitment", &blob, KZGCommitment(generator), "16e13374f0f11047e3c9b05295c0cf68fe8e85ea4018726711508d96f72d0f70"},
+		{"nonzero blob, commitment at infinity", &blob, KZGCommitment(infinity), "1dde9c9ca56af2cc53b5a4229dc6c7790f20d9110a7f8eb41f6acd9b9a482c56"},
+	}
+	for _, test := range tests {
+		got := SerializeScalar(ComputeChallenge(test.blob, test.commitment))
+		require.Equal(t, test.expected, hex.EncodeToString(got[:]), test.name)
+	}
+}
+
+// This is both an interop test and a regression check for the transcript
+// documented in ComputeEquivalenceChallenge. The expected value was computed
+// independently by hashing the transcript and reducing it modulo the field order.
+func TestComputeEquivalenceChallengeInterop(t *testing.T) {
+	commitment := KZGCommitment(SerializeG1Point(bls12381.G1Affine{}))
+	got := ComputeEquivalenceChallenge(commitment, []byte("external"))
+	expected := []byte{
+		0x24, 0x22, 0x3e, 0x07, 0xc9, 0x3f, 0x00, 0xac,
+		0x5b, 0x8b, 0x9e, 0x8d, 0x1a, 0xae, 0x88, 0x9e,
+		0xe5, 0x60, 0x5a, 0x6b, 0x59, 0x52, 0xfc, 0x09,
+		0x13, 0x1c, 0xbf, 0xb0, 0x18, 0x6b, 0x0c, 0xc7,
+	}
+	require.Equal(t, expected, got[:])
+
+	// The length prefix separates the external commitment from the KZG commitment
+	require.NotEqual(t, got, ComputeEquivalenceChallenge(commitment, []byte("externa")))
+}
+
+// newBLAKE2b returns a BLAKE2b-256 hasher, the hash function of the deployments which
+// WithChallengeHasher was added for.
+func newBLAKE2b() hash.Hash {
+	h, err := blake2b.New256(nil)
+	if err != nil {
+		// Only returned for keys longer than 64 bytes
+		panic(err)
+	}
+	return h
+}
+
+// The expected values were computed independently by hashing the transcripts documented in
+// ComputeChallenge and ComputeEquivalenceChallenge with BLAKE2b-256 instead of SHA-256 and
+// reducing the digests modulo the field order.
+func TestChallengeHasherVectors(t *testing.T) {
+	newTestContext := func(opts ...ContextOption) *Context {
+		config := newContextConfig(opts)
+		return &Context{newChallengeHasher: config.newChallengeHasher, challengeDomainSeparator: config.challengeDomainSeparator}
+	}
+	infinity := KZGCommitment(SerializeG1Point(bls12381.G1Affine{}))
+
+	tests := []struct {
+		name                string
+		opts                []ContextOption
+		expected            string
+		expectedEquivalence string
+	}{
+		{"default", nil, "04b7b22af63d2b2f1ced8d550560e5d1e4b01e355903dee22781e87826856096", "24223e07c93f00ac5b8b9e8d1aae889ee5605a6b5952fc09131cbfb0186b0cc7"},
+		{"hasher", []ContextOption{WithChallengeHasher(newBLAKE2b)}, "6654c19c0cedff386b61998793f7f6fa2839ec680332e5166e19a9273e874c8b", "420e29d15f679cb4f05e03cf5dcda8e65e1d347fa05d9bfbc90e8befc266893a"},
+		{"domain separator", []ContextOption{WithChallengeDomainSeparator("EXAMPLECHAIN_FS_V1")}, "42ff0bbf83005efeeb44667f0c553505f37bbb8c5fef4e29c15d17394ce35950", "71ed719231509691c48de3fcc1fcbf5122d66826595405a14170956ce01bcd01"},
+		{"hasher and domain separator", []ContextOption{WithChallengeHasher(newBLAKE2b), WithChallengeDomainSeparator("EXAMPLECHAIN_FS_V1")}, "07b33bf541114d5f6a6715628572e6f9e24a9e7c0a11bb8c109ef8e104027797", "399059dbb7969c96e58f09640a6efb7e99ffee657d8059e370aa0dd7212fc61d"},
+	}
+	for _, test := range tests {
+		testCtx := newTestContext(test.opts...)
+		got := SerializeScalar(testCtx.computeChallenge(&Blob{}, infinity))
+		require.Equal(t, test.expected, hex.EncodeToString(got[:]), test.name)
+		got = testCtx.ComputeEquivalenceChallenge(infinity, []byte("external"))
+		require.Equal(t, test.expectedEquivalence, hex.EncodeToString(got[:]), test.name)
+	}
+}
+
+func TestComputeChallenges(t *testing.T) {
+	const numBlobs = 5
+	blobs := make([]Blob, numBlobs)
+	commitments := make([]KZGCommitment, numBlobs)
+	for i := range blobs {
+		_, err := rand.Read(blobs[i][:])
+		require.NoError(t, err)
+		_, err = rand.Read(commitments[i][:])
+		require.NoError(t, err)
+	}
+
+	for _, ctx := range []*Context{
+		{challengeDomainSeparator: DomSepProtocol},
+		{newChallengeHasher: newBLAKE2b, challengeDomainSeparator: "EXAMPLECHAIN_FS_V1"},
+	} {
+		for _, numGoRoutines := range []int{0, 1, 2, numBlobs + 1} {
+			challenges, err := ctx.computeChallenges(context.Background(), blobs, commitments, numGoRoutines)
+			require.NoError(t, err)
+			require.Len(t, challenges, numBlobs)
+			for i := range blobs {
+				require.Equal(t, ctx.computeChallenge(&blobs[i], commitments[i]), challenges[i])
+			}
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := (&Context{challengeDomainSeparator: DomSepProtocol}).computeChallenges(cancelled, blobs, commitments, 1)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestComputeChallengeAllocs(t *testing.T) {
+	blob := &Blob{}
+	commitment := KZGCommitment(SerializeG1Point(bls12381.G1Affine{}))
+	allocs := testing.AllocsPerRun(100, func() {
+		ComputeChallenge(blob, commitment)
+	})
+	require.Zero(t, allocs)
+}
+
 func TestTo16Bytes(t *testing.T) {
 	number := uint64(4096)
 	// Generated using the following python snippet:
@@ -51,8 +185,42 @@ func BenchmarkComputeChallenge(b *testing.B) {
 	b.ResetTimer()
 	b.ReportAllocs()
 	for n := 0; n < b.N; n++ {
-		challenge = computeChallenge(blob, KZGCommitment(commitment))
+		challenge = ComputeChallenge(blob, KZGCommitment(commitment))
 	}
 	have := SerializeScalar(challenge)
 	require.Equal(b, want, have[:])
 }
+
+func BenchmarkComputeChallengeRandomBlob(b *testing.B) {
+	var blob Blob
+	_, err := rand.Read(blob[:])
+	require.NoError(b, err)
+	commitment := KZGCommitment(SerializeG1Point(bls12381.G1Affine{}))
+	b.SetBytes(int64(len(blob)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ComputeChallenge(&blob, commitment)
+	}
+}
+
+func BenchmarkComputeChallenges(b *testing.B) {
+	const numBlobs = 64
+	blobs := make([]Blob, numBlobs)
+	commitments := make([]KZGCommitment, numBlobs)
+	for i := range blobs {
+		_, err := rand.Read(blobs[i][:])
+		require.NoError(b, err)
+	}
+	ctx := &Context{challengeDomainSeparator: DomSepProtocol}
+
+	for _, numGoRoutines := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			b.SetBytes(int64(numBlobs * len(Blob{})))
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = ctx.computeChallenges(context.Background(), blobs, commitments, numGoRoutines)
+			}
+		})
+	}
+}
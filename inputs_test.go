@@ -0,0 +1,420 @@
+package gokzg4844_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// withSpareCapacity returns a copy of s with one more element of capacity than its length, holding a copy of the
+// first element, so that a method appending to s instead of to a new slice is caught by [backingArray].
+func withSpareCapacity[T any](s []T) []T {
+	out := make([]T, len(s), len(s)+1)
+	copy(out, s)
+	out = append(out, s[0])
+	return out[:len(s)]
+}
+
+// backingArray returns a copy of s up to its capacity.
+func backingArray[T any](s []T) []T {
+	return append([]T(nil), s[:cap(s)]...)
+}
+
+// TestInputsNotModified calls the public API and checks that none of the blobs, slices and byte slices passed by the
+// caller are modified, including the spare capacity of the slices. The inputs are given in an order which is neither
+// sorted nor deduplicated, so that an in-place sort or permutation would be caught.
+func TestInputsNotModified(t *testing.T) {
+	const numBlobs = 3
+
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	commitments := make([]gokzg4844.KZGCommitment, numBlobs)
+	proofs := make([]gokzg4844.KZGProof, numBlobs)
+	versionedHashes := make([][32]byte, numBlobs)
+	uncompressedCommitments := make([]gokzg4844.KZGCommitmentUncompressed, numBlobs)
+	uncompressedProofs := make([]gokzg4844.KZGProofUncompressed, numBlobs)
+	for i := range blobs {
+		fixture, err := testutil.GenerateProofFixture(ctx, int64(numBlobs-i))
+		require.NoError(t, err)
+		blobs[i] = *fixture.Blob
+		commitments[i] = fixture.Commitment
+		proofs[i] = fixture.Proof
+		versionedHashes[i] = gokzg4844.KZGToVersionedHash(fixture.Commitment)
+		uncompressedCommitments[i], err = gokzg4844.SerializeKZGCommitmentUncompressed(fixture.Commitment)
+		require.NoError(t, err)
+		uncompressedProofs[i], err = gokzg4844.SerializeKZGProofUncompressed(fixture.Proof)
+		require.NoError(t, err)
+	}
+	blobs = withSpareCapacity(blobs)
+	commitments = withSpareCapacity(commitments)
+	proofs = withSpareCapacity(proofs)
+	versionedHashes = withSpareCapacity(versionedHashes)
+	uncompressedCommitments = withSpareCapacity(uncompressedCommitments)
+	uncompressedProofs = withSpareCapacity(uncompressedProofs)
+
+	blob := &blobs[0]
+	blobBytes := withSpareCapacity(blob[:])
+	commitmentBytes := withSpareCapacity(commitments[0][:])
+	proofBytes := withSpareCapacity(proofs[0][:])
+
+	polynomial, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	evaluations := withSpareCapacity([]fr.Element(polynomial))
+
+	points := withSpareCapacity([]gokzg4844.Scalar{testutil.GenerateScalar(9), testutil.GenerateScalar(8), testutil.GenerateScalar(9)})
+	inputPoint := points[0]
+	pointProof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	precompileInput := withSpareCapacity(bytes.Join([][]byte{
+		versionedHashes[0][:], inputPoint[:], claimedValue[:], commitments[0][:], pointProof[:],
+	}, nil))
+	externalCommitment := withSpareCapacity(merkleRoot(blob))
+
+	// The cells of the first blob, in reverse order and with the commitment repeated for each of them
+	extendedCells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	var cellIndices []uint64
+	var cells []gokzg4844.Cell
+	var cellCommitments []gokzg4844.KZGCommitment
+	var cellKZGProofs []gokzg4844.KZGProof
+	for i := gokzg4844.CellsPerExtBlob - 1; i >= 0; i -= 2 {
+		cellIndices = append(cellIndices, uint64(i))
+		cells = append(cells, extendedCells[i])
+		cellCommitments = append(cellCommitments, commitments[0])
+		cellKZGProofs = append(cellKZGProofs, cellProofs[i])
+	}
+	cellIndices = withSpareCapacity(cellIndices)
+	cells = withSpareCapacity(cells)
+	cellCommitments = withSpareCapacity(cellCommitments)
+	cellKZGProofs = withSpareCapacity(cellKZGProofs)
+
+	// Half of the extension of the first blob, in reverse order
+	extension, err := ctx.ExtendBlob(blob)
+	require.NoError(t, err)
+	var extensionIndices []uint64
+	var extensionEvaluations []fr.Element
+	for i := len(extension) - 1; i >= 0; i -= 2 {
+		extensionIndices = append(extensionIndices, uint64(i))
+		extensionEvaluations = append(extensionEvaluations, extension[i])
+	}
+	extensionIndices = withSpareCapacity(extensionIndices)
+	extensionEvaluations = withSpareCapacity(extensionEvaluations)
+
+	oracle := func(challenge gokzg4844.Scalar) (gokzg4844.Scalar, error) {
+		return evaluateBlob(t, blob, challenge), nil
+	}
+
+	// snapshot copies every input, including the spare capacity of the slices
+	type inputs struct {
+		blobs                   []gokzg4844.Blob
+		commitments             []gokzg4844.KZGCommitment
+		proofs                  []gokzg4844.KZGProof
+		versionedHashes         [][32]byte
+		uncompressedCommitments []gokzg4844.KZGCommitmentUncompressed
+		uncompressedProofs      []gokzg4844.KZGProofUncompressed
+		blobBytes               []byte
+		commitmentBytes         []byte
+		proofBytes              []byte
+		evaluations             []fr.Element
+		points                  []gokzg4844.Scalar
+		precompileInput         []byte
+		externalCommitment      []byte
+		cellIndices             []uint64
+		cells                   []gokzg4844.Cell
+		cellCommitments         []gokzg4844.KZGCommitment
+		cellKZGProofs           []gokzg4844.KZGProof
+		extensionIndices        []uint64
+		extensionEvaluations    []fr.Element
+	}
+	snapshot := func() inputs {
+		return inputs{
+			blobs:                   backingArray(blobs),
+			commitments:             backingArray(commitments),
+			proofs:                  backingArray(proofs),
+			versionedHashes:         backingArray(versionedHashes),
+			uncompressedCommitments: backingArray(uncompressedCommitments),
+			uncompressedProofs:      backingArray(uncompressedProofs),
+			blobBytes:               backingArray(blobBytes),
+			commitmentBytes:         backingArray(commitmentBytes),
+			proofBytes:              backingArray(proofBytes),
+			evaluations:             backingArray(evaluations),
+			points:                  backingArray(points),
+			precompileInput:         backingArray(precompileInput),
+			externalCommitment:      backingArray(externalCommitment),
+			cellIndices:             backingArray(cellIndices),
+			cells:                   backingArray(cells),
+			cellCommitments:         backingArray(cellCommitments),
+			cellKZGProofs:           backingArray(cellKZGProofs),
+			extensionIndices:        backingArray(extensionIndices),
+			extensionEvaluations:    backingArray(extensionEvaluations),
+		}
+	}
+
+	calls := []struct {
+		name string
+		call func() error
+	}{
+		{"BlobToKZGCommitment", func() error {
+			_, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+			return err
+		}},
+		{"BlobToKZGCommitmentBytes", func() error {
+			_, err := ctx.BlobToKZGCommitmentBytes(blobBytes, NumGoRoutines)
+			return err
+		}},
+		{"BlobsToKZGCommitments", func() error {
+			_, err := ctx.BlobsToKZGCommitments(blobs)
+			return err
+		}},
+		{"ComputeBlobKZGProof", func() error {
+			_, err := ctx.ComputeBlobKZGProof(blob, commitments[0], NumGoRoutines)
+			return err
+		}},
+		{"ComputeBlobKZGProofBytes", func() error {
+			_, err := ctx.ComputeBlobKZGProofBytes(blobBytes, commitmentBytes, NumGoRoutines)
+			return err
+		}},
+		{"ComputeBlobKZGProofBatch", func() error {
+			_, err := ctx.ComputeBlobKZGProofBatch(blobs, commitments)
+			return err
+		}},
+		{"ComputeKZGProof", func() error {
+			_, _, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+			return err
+		}},
+		{"CommitToPolynomial", func() error {
+			_, err := ctx.CommitToPolynomial(evaluations, NumGoRoutines)
+			return err
+		}},
+		{"ComputePolynomialKZGProof", func() error {
+			_, _, err := ctx.ComputePolynomialKZGProof(evaluations, inputPoint, NumGoRoutines)
+			return err
+		}},
+		{"CommitToMonomialPolynomial", func() error {
+			_, err := ctx.CommitToMonomialPolynomial(evaluations)
+			return err
+		}},
+		{"ComputeEquivalenceProof", func() error {
+			_, _, _, err := ctx.ComputeEquivalenceProof(blob, commitments[0], externalCommitment, oracle, NumGoRoutines)
+			return err
+		}},
+		{"EvaluateBlobAtPoints", func() error {
+			_, err := ctx.EvaluateBlobAtPoints(blob, points)
+			return err
+		}},
+		{"VerifyBlobKZGProof", func() error {
+			return ctx.VerifyBlobKZGProof(blob, commitments[0], proofs[0])
+		}},
+		{"VerifyBlobKZGProofBytes", func() error {
+			return ctx.VerifyBlobKZGProofBytes(blobBytes, commitmentBytes, proofBytes)
+		}},
+		{"VerifyBlobKZGProofBatch", func() error {
+			return ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+		}},
+		{"VerifyBlobKZGProofBatchCtx", func() error {
+			return ctx.VerifyBlobKZGProofBatchCtx(context.Background(), blobs, commitments, proofs)
+		}},
+		{"VerifyBlobKZGProofBatchUncompressed", func() error {
+			return ctx.VerifyBlobKZGProofBatchUncompressed(blobs, uncompressedCommitments, uncompressedProofs)
+		}},
+		{"VerifyBlobKZGProofBatchPar", func() error {
+			return ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs)
+		}},
+		{"VerifyBlobKZGProofBatchPar with bisection", func() error {
+			return ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs, gokzg4844.WithBisection())
+		}},
+		{"VerifyBlobKZGProofBatchPar with all failures", func() error {
+			return ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs, gokzg4844.WithAllFailures())
+		}},
+		{"VerifyBlobSidecarBatch", func() error {
+			return ctx.VerifyBlobSidecarBatch(blobs, commitments, versionedHashes, proofs)
+		}},
+		{"VerifyEquivalenceProof", func() error {
+			_, value, proof, err := ctx.ComputeEquivalenceProof(blob, commitments[0], externalCommitment, oracle, NumGoRoutines)
+			if err != nil {
+				return err
+			}
+			return ctx.VerifyEquivalenceProof(commitments[0], externalCommitment, value, proof)
+		}},
+		{"PointEvaluation", func() error {
+			_, err := ctx.PointEvaluation(precompileInput)
+			return err
+		}},
+		{"VerifyCellKZGProofBatch", func() error {
+			return ctx.VerifyCellKZGProofBatch(cellCommitments, cellIndices, cells, cellKZGProofs)
+		}},
+		{"RecoverCellsAndKZGProofs", func() error {
+			_, _, err := ctx.RecoverCellsAndKZGProofs(cellIndices, cells)
+			return err
+		}},
+		{"RecoverBlob", func() error {
+			_, err := ctx.RecoverBlob(extensionIndices, extensionEvaluations)
+			return err
+		}},
+		{"DeserializeBlobBytes", func() error {
+			_, err := gokzg4844.DeserializeBlobBytes(blobBytes)
+			return err
+		}},
+		{"WriteBlobTo", func() error {
+			return gokzg4844.WriteBlobTo(&bytes.Buffer{}, evaluations)
+		}},
+		{"SerializePoly", func() error {
+			gokzg4844.SerializePoly(evaluations)
+			return nil
+		}},
+		{"ReverseBlobEndianness", func() error {
+			gokzg4844.ReverseBlobEndianness(blob)
+			return nil
+		}},
+	}
+	for _, test := range calls {
+		t.Run(test.name, func(t *testing.T) {
+			before := snapshot()
+			require.NoError(t, test.call())
+			require.Equal(t, before, snapshot())
+		})
+	}
+}
+
+// Variants of a slice argument of a batch method in TestEmptyAndMismatchedBatches.
+const (
+	nilArg = iota
+	emptyArg
+	singleArg
+	numArgVariants
+)
+
+// batchArg returns a nil, empty or single element slice holding element, depending on variant.
+func batchArg[T any](element T, variant int) []T {
+	switch variant {
+	case nilArg:
+		return nil
+	case emptyArg:
+		return []T{}
+	}
+	return []T{element}
+}
+
+// TestEmptyAndMismatchedBatches calls every batch method with each combination of nil, empty and single element
+// slices. Batches whose slices have the same length behave in the same way whether the empty slices are nil or not,
+// and batches whose slices have different lengths return a [*gokzg4844.BatchLengthError] naming every slice.
+func TestEmptyAndMismatchedBatches(t *testing.T) {
+	fixture, err := testutil.GenerateProofFixture(ctx, 11)
+	require.NoError(t, err)
+	blob, commitment, proof := *fixture.Blob, fixture.Commitment, fixture.Proof
+	versionedHash := gokzg4844.KZGToVersionedHash(commitment)
+	uncompressedCommitment, err := gokzg4844.SerializeKZGCommitmentUncompressed(commitment)
+	require.NoError(t, err)
+	uncompressedProof, err := gokzg4844.SerializeKZGProofUncompressed(proof)
+	require.NoError(t, err)
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(&blob)
+	require.NoError(t, err)
+	extension, err := ctx.ExtendBlob(&blob)
+	require.NoError(t, err)
+	point := testutil.GenerateScalar(11)
+
+	blobNames := []string{"blobs", "commitments", "proofs"}
+	tests := []struct {
+		name  string
+		names []string
+		// call calls the method with the given variant of each of its slices, and returns its result if it has one
+		call func(v []int) (any, error)
+		// emptyErr and singleErr are the errors expected for batches with no elements and with a single element
+		emptyErr, singleErr error
+	}{
+		{"BlobsToKZGCommitments", []string{"blobs"}, func(v []int) (any, error) {
+			return ctx.BlobsToKZGCommitments(batchArg(blob, v[0]))
+		}, nil, nil},
+		{"ComputeBlobKZGProofBatch", []string{"blobs", "commitments"}, func(v []int) (any, error) {
+			return ctx.ComputeBlobKZGProofBatch(batchArg(blob, v[0]), batchArg(commitment, v[1]))
+		}, nil, nil},
+		{"ComputeBlobKZGProofBatchCtx", []string{"blobs", "commitments"}, func(v []int) (any, error) {
+			return ctx.ComputeBlobKZGProofBatchCtx(context.Background(), batchArg(blob, v[0]), batchArg(commitment, v[1]))
+		}, nil, nil},
+		{"EvaluateBlobAtPoints", []string{"points"}, func(v []int) (any, error) {
+			return ctx.EvaluateBlobAtPoints(&blob, batchArg(point, v[0]))
+		}, nil, nil},
+		{"VerifyBlobKZGProofBatch", blobNames, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobKZGProofBatch(batchArg(blob, v[0]), batchArg(commitment, v[1]), batchArg(proof, v[2]))
+		}, nil, nil},
+		{"VerifyBlobKZGProofBatchCtx", blobNames, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobKZGProofBatchCtx(context.Background(), batchArg(blob, v[0]), batchArg(commitment, v[1]), batchArg(proof, v[2]))
+		}, nil, nil},
+		{"VerifyBlobKZGProofBatchUncompressed", blobNames, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobKZGProofBatchUncompressed(batchArg(blob, v[0]), batchArg(uncompressedCommitment, v[1]), batchArg(uncompressedProof, v[2]))
+		}, nil, nil},
+		{"VerifyBlobKZGProofBatchPar", blobNames, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobKZGProofBatchPar(batchArg(blob, v[0]), batchArg(commitment, v[1]), batchArg(proof, v[2]))
+		}, nil, nil},
+		{"VerifyBlobKZGProofBatchPar with fail fast", blobNames, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobKZGProofBatchPar(batchArg(blob, v[0]), batchArg(commitment, v[1]), batchArg(proof, v[2]), gokzg4844.WithFailFast())
+		}, nil, nil},
+		{"VerifyBlobKZGProofBatchPar with all failures", blobNames, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobKZGProofBatchPar(batchArg(blob, v[0]), batchArg(commitment, v[1]), batchArg(proof, v[2]), gokzg4844.WithAllFailures())
+		}, nil, nil},
+		{"VerifyBlobKZGProofBatchPar with bisection", blobNames, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobKZGProofBatchPar(batchArg(blob, v[0]), batchArg(commitment, v[1]), batchArg(proof, v[2]), gokzg4844.WithBisection())
+		}, nil, nil},
+		{"VerifyBlobSidecarBatch", []string{"blobs", "commitments", "versioned hashes", "proofs"}, func(v []int) (any, error) {
+			return nil, ctx.VerifyBlobSidecarBatch(batchArg(blob, v[0]), batchArg(commitment, v[1]), batchArg(versionedHash, v[2]), batchArg(proof, v[3]))
+		}, nil, nil},
+		{"VerifyCellKZGProofBatch", []string{"commitments", "cell indices", "cells", "proofs"}, func(v []int) (any, error) {
+			return nil, ctx.VerifyCellKZGProofBatch(batchArg(commitment, v[0]), batchArg(uint64(5), v[1]), batchArg(cells[5], v[2]), batchArg(cellProofs[5], v[3]))
+		}, nil, nil},
+		{"RecoverCellsAndKZGProofs", []string{"cell indices", "cells"}, func(v []int) (any, error) {
+			_, _, err := ctx.RecoverCellsAndKZGProofs(batchArg(uint64(5), v[0]), batchArg(cells[5], v[1]))
+			return nil, err
+		}, gokzg4844.ErrNotEnoughCells, gokzg4844.ErrNotEnoughCells},
+		{"RecoverBlob", []string{"indices", "evaluations"}, func(v []int) (any, error) {
+			_, err := ctx.RecoverBlob(batchArg(uint64(5), v[0]), batchArg(extension[5], v[1]))
+			return nil, err
+		}, gokzg4844.ErrNotEnoughEvaluations, gokzg4844.ErrNotEnoughEvaluations},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Every combination of the variants of the slices
+			numArgs := len(test.names)
+			numCases := 1
+			for range test.names {
+				numCases *= numArgVariants
+			}
+			for c := 0; c < numCases; c++ {
+				variants := make([]int, numArgs)
+				lengths := make([]int, numArgs)
+				sameLength := true
+				for i, rest := 0, c; i < numArgs; i, rest = i+1, rest/numArgVariants {
+					variants[i] = rest % numArgVariants
+					if variants[i] == singleArg {
+						lengths[i] = 1
+					}
+					sameLength = sameLength && lengths[i] == lengths[0]
+				}
+
+				result, err := test.call(variants)
+				switch {
+				case !sameLength:
+					var lengthErr *gokzg4844.BatchLengthError
+					require.ErrorAs(t, err, &lengthErr, "variants %v", variants)
+					require.Equal(t, gokzg4844.BatchLengthError{Names: test.names, Lengths: lengths}, *lengthErr)
+					require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+					require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
+				case lengths[0] == 0:
+					require.ErrorIs(t, err, test.emptyErr, "variants %v", variants)
+					if test.emptyErr == nil && result != nil {
+						// The result of an empty batch is an empty slice, even for nil inputs
+						value := reflect.ValueOf(result)
+						require.False(t, value.IsNil(), "variants %v", variants)
+						require.Zero(t, value.Len(), "variants %v", variants)
+					}
+				default:
+					require.ErrorIs(t, err, test.singleErr, "variants %v", variants)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func BenchmarkBatchVerifyMultiPoints(b *testing.B) {
+	domain := NewDomain(4096)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	const maxBatchSize = 128
+	commitments := make([]Commitment, maxBatchSize)
+	proofs := make([]OpeningProof, maxBatchSize)
+	for i := 0; i < maxBatchSize; i++ {
+		proofs[i], commitments[i] = randValidOpeningProof(b, *domain, *srs)
+	}
+
+	for _, batchSize := range []int{8, 32, 128} {
+		b.Run(fmt.Sprintf("Verify(count=%d)", batchSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				for i := 0; i < batchSize; i++ {
+					if err := Verify(&commitments[i], &proofs[i], &srs.OpeningKey); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("BatchVerifyMultiPoints(count=%d)", batchSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if err := BatchVerifyMultiPoints(commitments[:batchSize], proofs[:batchSize], &srs.OpeningKey, 0, rand.Reader, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkComputeAllProofs(b *testing.B) {
+	const size = 4096
+	const numNaiveProofs = 64
+	secret := big.NewInt(1234)
+	domain := NewDomain(size)
+	srsLagrange, err := newLagrangeSRSInsecure(*domain, secret)
+	if err != nil {
+		b.Fatal(err)
+	}
+	srsMonomial, err := newMonomialSRSInsecureUint64(size, secret)
+	if err != nil {
+		b.Fatal(err)
+	}
+	fk, err := NewFK20Key(&srsMonomial.CommitKey, size, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	poly := randPoly(b, *domain)
+
+	b.Run(fmt.Sprintf("Open(count=%d)", numNaiveProofs), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for i := 0; i < numNaiveProofs; i++ {
+				if _, err := Open(domain, poly, domain.Roots[i], &srsLagrange.CommitKey, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run(fmt.Sprintf("ComputeAllProofs(count=%d)", size), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := ComputeAllProofs(domain, poly, fk); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkOpenOnDomain(b *testing.B) {
+	domain := NewDomain(4096)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	if err != nil {
+		b.Fatal(err)
+	}
+	domain.ReverseRoots()
+	srs.CommitKey.ReversePoints()
+
+	poly := randPoly(b, *domain)
+	const index = 1234
+
+	b.Run("QuotientBatchInvert", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_ = computeQuotientPolyOnDomainBatchInvert(*domain, poly, index)
+		}
+	})
+
+	b.Run("QuotientPrecomputed", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := domain.computeQuotientPolyOnDomain(poly, index); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	outsidePoint := randomScalarNotInDomain(b, *domain)
+	for _, tc := range []struct {
+		name  string
+		point fr.Element
+	}{{"Open(in domain)", domain.Roots[index]}, {"Open(outside domain)", outsidePoint}} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if _, err := Open(domain, poly, tc.point, &srs.CommitKey, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEvaluateLagrangePolynomial(b *testing.B) {
+	domain := NewDomain(4096)
+	domain.ReverseRoots()
+	poly := randPoly(b, *domain)
+
+	b.Run("EvaluateLagrangePolynomial", func(b *testing.B) {
+		point := randomScalarNotInDomain(b, *domain)
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := domain.EvaluateLagrangePolynomial(poly, point); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	for _, numPoints := range []int{1, 16} {
+		points := make([]fr.Element, numPoints)
+		for i := range points {
+			points[i] = randomScalarNotInDomain(b, *domain)
+		}
+		b.Run(fmt.Sprintf("EvaluateLagrangePolynomialAtPoints(%d)", numPoints), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if _, err := domain.EvaluateLagrangePolynomialAtPoints(poly, points); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkCommit compares committing with the points stored in bit-reversed order, as done by the
+// contexts, with permuting the polynomial for every commitment to match the order of the trusted setup.
+func BenchmarkCommit(b *testing.B) {
+	domain := NewDomain(4096)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	if err != nil {
+		b.Fatal(err)
+	}
+	setupKey := CommitKey{G1: make([]bls12381.G1Affine, len(srs.CommitKey.G1))}
+	copy(setupKey.G1, srs.CommitKey.G1)
+	domain.ReverseRoots()
+	srs.CommitKey.ReversePoints()
+
+	poly := randPoly(b, *domain)
+
+	b.Run("Commit(stored order)", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := Commit(poly, &srs.CommitKey, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Commit(permuted per call)", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			permuted := make(Polynomial, len(poly))
+			copy(permuted, poly)
+			bitReverse(permuted)
+			if _, err := Commit(permuted, &setupKey, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkVerify(b *testing.B) {
+	domain := NewDomain(16)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	if err != nil {
+		b.Fatal(err)
+	}
+	proof, commitment := randValidOpeningProof(b, *domain, *srs)
+
+	precomputedKey := srs.OpeningKey
+	precomputedKey.PrecomputePairingLines()
+
+	for _, tc := range []struct {
+		name    string
+		openKey *OpeningKey
+	}{{"Verify", &srs.OpeningKey}, {"Verify(precomputed lines)", &precomputedKey}} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if err := Verify(&commitment, &proof, tc.openKey); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
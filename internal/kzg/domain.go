@@ -2,8 +2,11 @@ package kzg
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"math/bits"
+	"sync"
+	"unsafe"
 
 	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -39,40 +42,61 @@ type Domain struct {
 	// f(x)/g(x) where g(x) is a linear polynomial
 	// which vanishes on a point on the domain
 	PreComputedInverses []fr.Element
+
+	// invRootsMinusOne[k] holds 1 / (w^k - 1) where w is the Generator, for 0 < k < Cardinality.
+	// invRootsMinusOne[0] is zero.
+	//
+	// Unlike Roots, this is indexed by the exponent k and not affected by ReverseRoots.
+	// It lets us compute quotients by X - z for z in the domain without any inversions.
+	invRootsMinusOne []fr.Element
+
+	// isBitReversed indicates whether Roots and PreComputedInverses
+	// are currently in bit-reversed order.
+	isBitReversed bool
 }
 
-// NewDomain returns a new domain with the desired number of points x.
-//
-// We only support powers of 2 for x.
+// maxOrderRoot is the 2-adicity of the scalar field, i.e. the largest power of two
+// dividing the order of its multiplicative group.
+const maxOrderRoot uint64 = 32
+
+// MaxDomainSize is the largest number of points a domain can have, since the
+// scalar field has no roots of unity of larger power of two order.
+const MaxDomainSize uint64 = 1 << maxOrderRoot
+
+// maxDomainSizeForInt returns the largest number of points a domain can have on a platform whose int has intSize
+// bits. The slices of a domain are indexed by int, so its size must also fit in an int: on 32-bit platforms, this
+// is 2^30 rather than [MaxDomainSize].
+func maxDomainSizeForInt(intSize int) uint64 {
+	// The largest power of two which fits in a signed int of intSize bits
+	maxIntPowerOfTwo := uint64(1) << (intSize - 2)
+	if maxIntPowerOfTwo < MaxDomainSize {
+		return maxIntPowerOfTwo
+	}
+	return MaxDomainSize
+}
+
+// CheckDomainSize returns [ErrInvalidDomainSize] if a domain cannot have x points on this platform: x must be a
+// power of two no larger than [MaxDomainSize], and must fit in an int.
+func CheckDomainSize(x uint64) error {
+	if !utils.IsPowerOfTwo(x) || x > maxDomainSizeForInt(bits.UintSize) {
+		return ErrInvalidDomainSize
+	}
+	return nil
+}
+
+// NewDomainChecked returns a new domain with the desired number of points x, or [ErrInvalidDomainSize] if
+// [CheckDomainSize] rejects x.
 //
 // Modified from [gnark-crypto].
 //
 // [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/fft/domain.go#L66
-func NewDomain(x uint64) *Domain {
-	if bits.OnesCount64(x) != 1 {
-		panic(fmt.Sprintf("x (%d) is not a power of 2. This library only supports domain sizes that are powers of two", x))
+func NewDomainChecked(x uint64) (*Domain, error) {
+	if err := CheckDomainSize(x); err != nil {
+		return nil, err
 	}
 	domain := &Domain{}
 	domain.Cardinality = x
-
-	// Generator of the largest 2-adic subgroup.
-	// This particular element has order 2^maxOrderRoot == 2^32.
-	var rootOfUnity fr.Element
-	_, err := rootOfUnity.SetString("10238227357739495823651030575849232062558860180284477541189508159991286009131")
-	if err != nil {
-		panic("failed to initialize root of unity")
-	}
-	const maxOrderRoot uint64 = 32
-
-	// Find generator subgroup of order x.
-	// This can be constructed by powering a generator of the largest 2-adic subgroup of order 2^32 by an exponent
-	// of (2^32)/x, provided x is <= 2^32.
-	logx := uint64(bits.TrailingZeros64(x))
-	if logx > maxOrderRoot {
-		panic(fmt.Sprintf("x (%d) is too big: the required root of unity does not exist", x))
-	}
-	expo := uint64(1 << (maxOrderRoot - logx))
-	domain.Generator.Exp(rootOfUnity, big.NewInt(int64(expo))) // Domain.Generator has order x now.
+	domain.Generator = primitiveRootOfUnity(x) // Domain.Generator has order x now.
 
 	// Store Inverse of the generator and inverse of the domain size (as field elements).
 	domain.GeneratorInv.Inverse(&domain.Generator)
@@ -94,9 +118,57 @@ func NewDomain(x uint64) *Domain {
 	// We use BatchInvert instead of the above for clarity.
 	domain.PreComputedInverses = fr.BatchInvert(domain.Roots)
 
+	// Compute 1 / (w^k - 1). The first element is zero, which gnark-crypto's BatchInvert leaves as zero.
+	rootsMinusOne := make([]fr.Element, x)
+	one := fr.One()
+	for k := uint64(1); k < x; k++ {
+		rootsMinusOne[k].Sub(&domain.Roots[k], &one)
+	}
+	domain.invRootsMinusOne = fr.BatchInvert(rootsMinusOne)
+
+	return domain, nil
+}
+
+// NewDomain is [NewDomainChecked] for sizes which are known to be valid. It panics if x is rejected by
+// [CheckDomainSize].
+func NewDomain(x uint64) *Domain {
+	domain, err := NewDomainChecked(x)
+	if err != nil {
+		panic(fmt.Sprintf("x (%d) is not a valid domain size: %v", x, err))
+	}
 	return domain
 }
 
+// MemorySize returns the number of bytes held by the precomputed roots and inverses of the domain.
+func (domain *Domain) MemorySize() int {
+	numElements := len(domain.Roots) + len(domain.PreComputedInverses) + len(domain.invRootsMinusOne)
+	return numElements * int(unsafe.Sizeof(fr.Element{}))
+}
+
+// primitiveRootOfUnity returns a generator of the multiplicative subgroup of order x, which must be a power of 2
+// no larger than [MaxDomainSize].
+func primitiveRootOfUnity(x uint64) fr.Element {
+	// Generator of the largest 2-adic subgroup.
+	// This particular element has order 2^maxOrderRoot == 2^32.
+	var rootOfUnity fr.Element
+	_, err := rootOfUnity.SetString("10238227357739495823651030575849232062558860180284477541189508159991286009131")
+	if err != nil {
+		panic("failed to initialize root of unity")
+	}
+	// Find generator subgroup of order x.
+	// This can be constructed by powering a generator of the largest 2-adic subgroup of order 2^32 by an exponent
+	// of (2^32)/x, provided x is <= 2^32.
+	logx := uint64(bits.TrailingZeros64(x))
+	if logx > maxOrderRoot {
+		panic(fmt.Sprintf("x (%d) is too big: the required root of unity does not exist", x))
+	}
+	expo := uint64(1) << (maxOrderRoot - logx)
+
+	var generator fr.Element
+	generator.Exp(rootOfUnity, new(big.Int).SetUint64(expo))
+	return generator
+}
+
 /*
 Taken from a chat with Dr Dankrad Feist:
 - Samples are going to be contiguous when we switch on full sharding.
@@ -150,6 +222,16 @@ func bitReverse[K interface{}](list []K) {
 func (domain *Domain) ReverseRoots() {
 	bitReverse(domain.Roots)
 	bitReverse(domain.PreComputedInverses)
+	domain.isBitReversed = !domain.isBitReversed
+}
+
+// rootExponent returns the exponent k such that domain.Roots[index] = w^k where w is the Generator.
+func (domain *Domain) rootExponent(index uint64) uint64 {
+	if !domain.isBitReversed {
+		return index
+	}
+	// See bitReverse for the shift correction
+	return bits.Reverse64(index) >> (64 - bits.TrailingZeros64(domain.Cardinality))
 }
 
 // findRootIndex returns the index of the element in the domain or -1 if not found.
@@ -157,9 +239,9 @@ func (domain *Domain) ReverseRoots() {
 //   - If point is in the domain (meaning that point is a domain.Cardinality'th root of unity), returns the index of the point in the domain.
 //   - If point is not in the domain, returns -1.
 func (domain *Domain) findRootIndex(point fr.Element) int64 {
-	for i := int64(0); i < int64(domain.Cardinality); i++ {
+	for i := range domain.Roots {
 		if point.Equal(&domain.Roots[i]) {
-			return i
+			return int64(i)
 		}
 	}
 
@@ -174,8 +256,64 @@ func (domain *Domain) findRootIndex(point fr.Element) int64 {
 // [evaluate_polynomial_in_evaluation_form]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#evaluate_polynomial_in_evaluation_form
 func (domain *Domain) EvaluateLagrangePolynomial(poly Polynomial, evalPoint fr.Element) (*fr.Element, error) {
 	outputPoint, _, err := domain.evaluateLagrangePolynomial(poly, evalPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outputPoint, nil
+}
+
+// EvaluateLagrangePolynomialAtPoints evaluates a Lagrange polynomial at each of the given points of evaluation.
+//
+// It returns the same values as calling [Domain.EvaluateLagrangePolynomial] for each point, but shares a single
+// batch inversion between all the points which are not in the domain.
+func (domain *Domain) EvaluateLagrangePolynomialAtPoints(poly Polynomial, evalPoints []fr.Element) ([]fr.Element, error) {
+	if domain.Cardinality != uint64(len(poly)) {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+
+	results := make([]fr.Element, len(evalPoints))
+
+	// Points in the domain index the polynomial, the others need the denominators (z - w_j)
+	outsideDomain := make([]int, 0, len(evalPoints))
+	for i := range evalPoints {
+		if indexInDomain := domain.findRootIndex(evalPoints[i]); indexInDomain != -1 {
+			results[i].Set(&poly[indexInDomain])
+			continue
+		}
+		outsideDomain = append(outsideDomain, i)
+	}
+	if len(outsideDomain) == 0 {
+		return results, nil
+	}
+
+	// The numerators f(w_j) * w_j do not depend on the point, so they are computed once
+	cardinality := len(poly)
+	numDenominators, ok := mulInt(len(outsideDomain), cardinality)
+	if !ok {
+		return nil, ErrSizeOverflow
+	}
+	numerators := make([]fr.Element, cardinality)
+	mulVectors(numerators, poly, domain.Roots)
+
+	denom := make([]fr.Element, numDenominators)
+	for k, i := range outsideDomain {
+		subFromScalar(denom[k*cardinality:(k+1)*cardinality], evalPoints[i], domain.Roots)
+	}
+	invDenom := fr.BatchInvert(denom)
+
+	one := fr.One()
+	for k, i := range outsideDomain {
+		result := innerProduct(numerators, invDenom[k*cardinality:(k+1)*cardinality])
+
+		// result * (x^width - 1) * 1/width
+		tmp := domain.powCardinality(evalPoints[i])
+		tmp.Sub(&tmp, &one)
+		tmp.Mul(&tmp, &domain.CardinalityInv)
+		results[i].Mul(&tmp, &result)
+	}
 
-	return outputPoint, err
+	return results, nil
 }
 
 // evaluateLagrangePolynomial is the implementation for [EvaluateLagrangePolynomial].
@@ -186,11 +324,11 @@ func (domain *Domain) EvaluateLagrangePolynomial(poly Polynomial, evalPoint fr.E
 //   - indexInDomain is the index inside domain.Roots, if evalPoint is among them, -1 otherwise
 //
 // This semantics was copied from the go library, see: https://cs.opensource.google/go/x/exp/+/522b1b58:slices/slices.go;l=117
-func (domain *Domain) evaluateLagrangePolynomial(poly Polynomial, evalPoint fr.Element) (*fr.Element, int64, error) {
+func (domain *Domain) evaluateLagrangePolynomial(poly Polynomial, evalPoint fr.Element) (fr.Element, int64, error) {
 	var indexInDomain int64 = -1
 
 	if domain.Cardinality != uint64(len(poly)) {
-		return nil, indexInDomain, ErrPolynomialMismatchedSizeDomain
+		return fr.Element{}, indexInDomain, ErrPolynomialMismatchedSizeDomain
 	}
 
 	// If the evaluation point is in the domain
@@ -199,33 +337,109 @@ func (domain *Domain) evaluateLagrangePolynomial(poly Polynomial, evalPoint fr.E
 	// that the evaluation point is in, in the domain
 	indexInDomain = domain.findRootIndex(evalPoint)
 	if indexInDomain != -1 {
-		return &poly[indexInDomain], indexInDomain, nil
+		return poly[indexInDomain], indexInDomain, nil
 	}
 
-	denom := make([]fr.Element, domain.Cardinality)
-	for i := range denom {
-		denom[i].Sub(&evalPoint, &domain.Roots[i])
-	}
-	invDenom := fr.BatchInvert(denom)
-
-	var result fr.Element
-	for i := 0; i < int(domain.Cardinality); i++ {
-		var num fr.Element
-		num.Mul(&poly[i], &domain.Roots[i])
-
-		var div fr.Element
-		div.Mul(&num, &invDenom[i])
+	// The scratch holds the denominators, which are inverted in place, the running products used to invert them and
+	// the numerators
+	n := len(poly)
+	scratch := getLagrangeScratch(3 * n)
+	defer lagrangeScratchPool.Put(scratch)
+	invDenom, products, numerators := (*scratch)[:n], (*scratch)[n:2*n], (*scratch)[2*n:]
+	subFromScalar(invDenom, evalPoint, domain.Roots)
+	batchInvertNonZero(invDenom, products)
+	mulVectors(numerators, poly, domain.Roots)
 
-		result.Add(&result, &div)
-	}
+	result := innerProduct(numerators, invDenom)
 
 	// result * (x^width - 1) * 1/width
-	var tmp fr.Element
-	tmp.Exp(evalPoint, big.NewInt(0).SetUint64(domain.Cardinality))
+	tmp := domain.powCardinality(evalPoint)
 	one := fr.One()
 	tmp.Sub(&tmp, &one)
 	tmp.Mul(&tmp, &domain.CardinalityInv)
 	result.Mul(&tmp, &result)
 
-	return &result, indexInDomain, nil
+	return result, indexInDomain, nil
+}
+
+// mulInt returns a * b for non-negative a and b, and false if the product overflows an int.
+func mulInt(a, b int) (int, bool) {
+	if a != 0 && b > math.MaxInt/a {
+		return 0, false
+	}
+	return a * b, true
+}
+
+// powCardinality returns x^Cardinality, which is computed by squaring x since the cardinality is a power of two.
+func (domain *Domain) powCardinality(x fr.Element) fr.Element {
+	for i := uint64(1); i < domain.Cardinality; i *= 2 {
+		x.Square(&x)
+	}
+	return x
+}
+
+// lagrangeScratchPool holds the buffers used by [Domain.evaluateLagrangePolynomial], which are as large as the
+// polynomial, so that evaluating a polynomial does not allocate them.
+var lagrangeScratchPool = sync.Pool{
+	New: func() any {
+		return new([]fr.Element)
+	},
+}
+
+// getLagrangeScratch returns a buffer of n elements from [lagrangeScratchPool]. Their values are unspecified.
+func getLagrangeScratch(n int) *[]fr.Element {
+	scratch := lagrangeScratchPool.Get().(*[]fr.Element)
+	if cap(*scratch) < n {
+		*scratch = make([]fr.Element, n)
+	}
+	*scratch = (*scratch)[:n]
+	return scratch
+}
+
+// The helpers below operate on whole slices of field elements of the same length, like the vector operations of
+// later versions of gnark-crypto's fr.Vector, so that the evaluation loops can be moved to them.
+
+// subFromScalar sets dst[i] to x - v[i].
+func subFromScalar(dst []fr.Element, x fr.Element, v []fr.Element) {
+	for i := range dst {
+		dst[i].Sub(&x, &v[i])
+	}
+}
+
+// mulVectors sets dst[i] to a[i] * b[i].
+func mulVectors(dst, a, b []fr.Element) {
+	for i := range dst {
+		dst[i].Mul(&a[i], &b[i])
+	}
+}
+
+// innerProduct returns the sum of the products a[i] * b[i].
+func innerProduct(a, b []fr.Element) fr.Element {
+	var result, product fr.Element
+	for i := range a {
+		product.Mul(&a[i], &b[i])
+		result.Add(&result, &product)
+	}
+	return result
+}
+
+// batchInvertNonZero sets each element of values, none of which can be zero, to its inverse using a single
+// inversion. products must be as long as values, and is overwritten.
+//
+// Unlike fr.BatchInvert, it inverts the values in place and does not allocate.
+func batchInvertNonZero(values, products []fr.Element) {
+	var accumulator fr.Element
+	accumulator.SetOne()
+	for i := range values {
+		products[i] = accumulator
+		accumulator.Mul(&accumulator, &values[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+	for i := len(values) - 1; i >= 0; i-- {
+		var inverse fr.Element
+		inverse.Mul(&accumulator, &products[i])
+		accumulator.Mul(&accumulator, &values[i])
+		values[i] = inverse
+	}
 }
@@ -120,7 +120,7 @@ func TestEvalPolynomialSmoke(t *testing.T) {
 
 		expectedOutputPoint := lagrangePoly[i]
 
-		if !expectedOutputPoint.Equal(gotOutputPoint) {
+		if !expectedOutputPoint.Equal(&gotOutputPoint) {
 			t.Fatalf("incorrect output point computed from evaluateLagrangePolynomial")
 		}
 
@@ -146,7 +146,7 @@ func TestEvalPolynomialSmoke(t *testing.T) {
 		// on the point outside of the domain
 		expectedPoint := f(*inputPoint)
 
-		if !expectedPoint.Equal(gotOutputPoint) {
+		if !expectedPoint.Equal(&gotOutputPoint) {
 			t.Fatalf("unexpected evaluation of polynomial at point %v", inputPoint.Bytes())
 		}
 
@@ -156,6 +156,48 @@ func TestEvalPolynomialSmoke(t *testing.T) {
 	}
 }
 
+func TestEvaluateLagrangePolynomialAtPoints(t *testing.T) {
+	domain := NewDomain(16)
+	poly := randPoly(t, *domain)
+
+	// Mix points inside and outside of the domain, including duplicates
+	points := []fr.Element{
+		domain.Roots[3],
+		*samplePointOutsideDomain(*domain),
+		domain.Roots[0],
+		*samplePointOutsideDomain(*domain),
+		domain.Roots[3],
+	}
+	points = append(points, points[1])
+
+	got, err := domain.EvaluateLagrangePolynomialAtPoints(poly, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(points) {
+		t.Fatalf("expected %d evaluations, got %d", len(points), len(got))
+	}
+	for i := range points {
+		expected, err := domain.EvaluateLagrangePolynomial(poly, points[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !expected.Equal(&got[i]) {
+			t.Fatalf("incorrect evaluation at point %d", i)
+		}
+	}
+
+	got, err = domain.EvaluateLagrangePolynomialAtPoints(poly, nil)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected no evaluations and no error, got %v and %v", got, err)
+	}
+
+	_, err = domain.EvaluateLagrangePolynomialAtPoints(poly[1:], points)
+	if err != ErrPolynomialMismatchedSizeDomain {
+		t.Fatalf("expected %v, got %v", ErrPolynomialMismatchedSizeDomain, err)
+	}
+}
+
 func samplePointOutsideDomain(domain Domain) *fr.Element {
 	var randElement fr.Element
 
@@ -185,3 +227,41 @@ func testScalars(size int) []fr.Element {
 	}
 	return res
 }
+
+func TestCheckDomainSize(t *testing.T) {
+	for _, size := range []uint64{0, 3, 6, MaxDomainSize + 1, MaxDomainSize << 1, math.MaxUint64} {
+		if err := CheckDomainSize(size); err != ErrInvalidDomainSize {
+			t.Errorf("expected %v for size %d, got %v", ErrInvalidDomainSize, size, err)
+		}
+		if _, err := NewDomainChecked(size); err != ErrInvalidDomainSize {
+			t.Errorf("expected NewDomainChecked to reject size %d, got %v", size, err)
+		}
+	}
+	for _, size := range []uint64{1, 2, 4096} {
+		if err := CheckDomainSize(size); err != nil {
+			t.Errorf("expected size %d to be accepted, got %v", size, err)
+		}
+	}
+
+	if got := maxDomainSizeForInt(32); got != 1<<30 {
+		t.Errorf("expected 32-bit platforms to be limited to 2^30 points, got %d", got)
+	}
+	if got := maxDomainSizeForInt(64); got != MaxDomainSize {
+		t.Errorf("expected 64-bit platforms to be limited to %d points, got %d", uint64(MaxDomainSize), got)
+	}
+	if bits.UintSize == 64 && CheckDomainSize(MaxDomainSize) != nil {
+		t.Errorf("expected the maximum domain size to be accepted on 64-bit platforms")
+	}
+}
+
+func TestMulInt(t *testing.T) {
+	if got, ok := mulInt(3, 5); !ok || got != 15 {
+		t.Errorf("expected 15, got %d (ok=%v)", got, ok)
+	}
+	if got, ok := mulInt(0, math.MaxInt); !ok || got != 0 {
+		t.Errorf("expected 0, got %d (ok=%v)", got, ok)
+	}
+	if _, ok := mulInt(2, math.MaxInt/2+1); ok {
+		t.Errorf("expected an overflow to be reported")
+	}
+}
@@ -8,4 +8,17 @@ var (
 	ErrVerifyOpeningProof             = errors.New("can't verify opening proof")
 	ErrPolynomialMismatchedSizeDomain = errors.New("domain size does not equal the number of evaluations in the polynomial")
 	ErrMinSRSSize                     = errors.New("minimum srs size is 2")
+	ErrInvalidDomainSize              = errors.New("domain size must be a power of two no larger than 2^32 which fits in an int")
+	ErrSizeOverflow                   = errors.New("the number of elements overflows an int on this platform")
+	ErrNoOpeningPoints                = errors.New("at least one opening point is required")
+	ErrDuplicateOpeningPoints         = errors.New("opening points must be distinct")
+	ErrMismatchedNumEvaluations       = errors.New("number of claimed values is not the same as the number of opening points")
+	ErrTooManyOpeningPoints           = errors.New("not enough G2 points in the SRS for the number of opening points")
+	ErrInvalidCosetSize               = errors.New("coset size and number of cosets must be powers of two that cover the polynomial")
+	ErrInvalidDegreeBound             = errors.New("degree bound must be between 1 and the size of the SRS")
+	ErrDegreeBoundShiftUnavailable    = errors.New("the SRS does not contain the G2 power needed for this degree bound")
+	ErrPolynomialExceedsDegreeBound   = errors.New("polynomial degree is not below the degree bound")
+	ErrRandomSource                   = errors.New("failed to read from the random source")
+	ErrInvalidCosetIndices            = errors.New("coset indices must be distinct and less than the number of cosets")
+	ErrNotEnoughCosets                = errors.New("at least half of the cosets are needed to recover the polynomial")
 )
@@ -42,6 +42,75 @@ func (domain *Domain) IfftG1(values []bls12381.G1Affine) []bls12381.G1Affine {
 	return inverseFFT
 }
 
+// Computes an FFT (Fast Fourier Transform) of the scalars.
+//
+// The elements are returned in order as opposed to being returned in
+// bit-reversed order.
+func (domain *Domain) FftFr(values []fr.Element) []fr.Element {
+	return fftFr(values, domain.Generator)
+}
+
+// Computes an IFFT(Inverse Fast Fourier Transform) of the scalars.
+//
+// The elements are returned in order as opposed to being returned in
+// bit-reversed order.
+func (domain *Domain) IfftFr(values []fr.Element) []fr.Element {
+	inverseFFT := fftFr(values, domain.GeneratorInv)
+
+	// scale by the inverse of the domain size
+	for i := 0; i < len(inverseFFT); i++ {
+		inverseFFT[i].Mul(&inverseFFT[i], &domain.CardinalityInv)
+	}
+
+	return inverseFFT
+}
+
+// lagrangeToMonomial converts a polynomial in lagrange form, ordered in the same way
+// as domain.Roots, to its coefficients in monomial form.
+//
+// The input polynomial is not modified. len(p) must be equal to domain.Cardinality.
+func (domain *Domain) lagrangeToMonomial(p Polynomial) []fr.Element {
+	// The inverse FFT expects the evaluations in natural order.
+	evaluations := make(Polynomial, len(p))
+	copy(evaluations, p)
+	if domain.isBitReversed {
+		bitReverse(evaluations)
+	}
+	return domain.IfftFr(evaluations)
+}
+
+// ExtendPolynomial returns the evaluations of the polynomial `p`, given in lagrange form over the domain, over the
+// domain twice as large. This is the Reed-Solomon extension of the evaluations with rate 1/2.
+//
+// The evaluations are returned in bit-reversed order. The first half is then made of the evaluations over the domain
+// in bit-reversed order, which are the values of `p` if the domain is bit-reversed. The second half is made of the
+// evaluations over the coset gH, where g is a square root of the generator of the domain, which are computed with a
+// coset FFT.
+//
+// If len(p) != domain.Cardinality, returns an error.
+func (domain *Domain) ExtendPolynomial(p Polynomial) ([]fr.Element, error) {
+	if domain.Cardinality != uint64(len(p)) {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+	n := len(p)
+
+	extended := make([]fr.Element, 2*n)
+	copy(extended[:n], p)
+	if !domain.isBitReversed {
+		bitReverse(extended[:n])
+	}
+
+	// Scale the i'th coefficient by g^i, so that the FFT over the domain evaluates p over gH
+	cosetGenerator := primitiveRootOfUnity(2 * domain.Cardinality)
+	coeffs := domain.lagrangeToMonomial(p)
+	scaleByPowers(coeffs, cosetGenerator)
+	cosetEvaluations := domain.FftFr(coeffs)
+	bitReverse(cosetEvaluations)
+	copy(extended[n:], cosetEvaluations)
+
+	return extended, nil
+}
+
 // fftG1 computes an FFT (Fast Fourier Transform) of the G1 elements.
 //
 // This is the actual implementation of [FftG1] with the same convention.
@@ -92,6 +161,38 @@ func fftG1(values []bls12381.G1Affine, nthRootOfUnity fr.Element) []bls12381.G1A
 	return evaluations
 }
 
+// fftFr computes an FFT (Fast Fourier Transform) of the scalars.
+//
+// This is the scalar version of [fftG1] with the same conventions.
+func fftFr(values []fr.Element, nthRootOfUnity fr.Element) []fr.Element {
+	n := len(values)
+	if n == 1 {
+		return values
+	}
+
+	var generatorSquared fr.Element
+	generatorSquared.Square(&nthRootOfUnity) // generator with order n/2
+
+	even, odd := takeEvenOdd(values)
+
+	fftEven := fftFr(even, generatorSquared)
+	fftOdd := fftFr(odd, generatorSquared)
+
+	inputPoint := fr.One()
+	evaluations := make([]fr.Element, n)
+	for k := 0; k < n/2; k++ {
+		var tmp fr.Element
+		tmp.Mul(&inputPoint, &fftOdd[k])
+
+		evaluations[k].Add(&fftEven[k], &tmp)
+		evaluations[k+n/2].Sub(&fftEven[k], &tmp)
+
+		inputPoint.Mul(&inputPoint, &nthRootOfUnity)
+	}
+
+	return evaluations
+}
+
 // takeEvenOdd Takes a slice and return two slices
 // The first slice contains (a copy of) all of the elements
 // at even indices, the second slice contains
@@ -26,3 +26,73 @@ func TestSRSConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestFftFrRoundTrip(t *testing.T) {
+	n := uint64(64)
+	domain := NewDomain(n)
+
+	coeffs := testScalars(int(n))
+	evaluations := domain.FftFr(coeffs)
+
+	// The FFT evaluates the polynomial at the roots of unity
+	for i := uint64(0); i < n; i++ {
+		expected := evaluateMonomial(coeffs, domain.Roots[i])
+		if !expected.Equal(&evaluations[i]) {
+			t.Fatalf("incorrect evaluation at index %d", i)
+		}
+	}
+
+	gotCoeffs := domain.IfftFr(evaluations)
+	for i := uint64(0); i < n; i++ {
+		if !gotCoeffs[i].Equal(&coeffs[i]) {
+			t.Fatalf("inverse fft did not recover the coefficients")
+		}
+	}
+}
+
+func TestExtendPolynomial(t *testing.T) {
+	n := uint64(16)
+	coeffs := testScalars(int(n))
+
+	// The extension is in bit-reversed order whatever the order of the domain
+	extendedDomain := NewDomain(2 * n)
+	extendedDomain.ReverseRoots()
+
+	for _, reversed := range []bool{false, true} {
+		domain := NewDomain(n)
+		if reversed {
+			domain.ReverseRoots()
+		}
+		p := make(Polynomial, n)
+		for i := range p {
+			p[i] = evaluateMonomial(coeffs, domain.Roots[i])
+		}
+
+		extended, err := domain.ExtendPolynomial(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(extended) != int(2*n) {
+			t.Fatalf("expected %d evaluations, got %d", 2*n, len(extended))
+		}
+		for i := range extended {
+			expected := evaluateMonomial(coeffs, extendedDomain.Roots[i])
+			if !expected.Equal(&extended[i]) {
+				t.Fatalf("incorrect evaluation at index %d (reversed domain: %v)", i, reversed)
+			}
+		}
+
+		// Over a bit-reversed domain, the first half is the polynomial itself
+		if reversed {
+			for i := range p {
+				if !p[i].Equal(&extended[i]) {
+					t.Fatalf("the extension does not start with the polynomial at index %d", i)
+				}
+			}
+		}
+
+		if _, err := domain.ExtendPolynomial(p[1:]); err != ErrPolynomialMismatchedSizeDomain {
+			t.Fatalf("expected %v, got %v", ErrPolynomialMismatchedSizeDomain, err)
+		}
+	}
+}
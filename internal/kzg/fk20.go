@@ -0,0 +1,243 @@
+package kzg
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/multiexp"
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// In this file we implement the FK20 algorithm from [Feist-Khovratovich], which computes
+// opening proofs for a polynomial at every point of a domain (or every coset of a domain)
+// in O(n log n) group operations instead of O(n^2).
+//
+// Let f(X) = f_0 + f_1 X + ... + f_{n-1} X^{n-1}. Dividing f(X) by X^l - c, the quotient is
+//
+//	q(X) = Σ_{m>=0} c^m * Σ_{i >= (m+1)l} f_i X^{i - (m+1)l}
+//
+// so the proof for the coset {x : x^l = c} is [q(α)]₁ = Σ_m c^m * h_m, where
+//
+//	h_m = Σ_{i >= (m+1)l} f_i * [α^{i - (m+1)l}]₁
+//
+// The vector h does not depend on c. Computing it is a Toeplitz matrix-vector product which
+// we embed into a circulant matrix of twice the size and compute using FFTs. Once h is known,
+// the proofs for all cosets are computed at once with a single FFT since the values of c are
+// roots of unity.
+//
+// [Feist-Khovratovich]: https://eprint.iacr.org/2023/033
+
+// FK20Key holds the precomputations over the monomial SRS needed to compute
+// opening proofs using FK20 for polynomials with a fixed number of coefficients
+// and a fixed coset size.
+type FK20Key struct {
+	// Number of coefficients in the polynomials that we can compute proofs for.
+	polySize uint64
+	// Number of points in each coset that we compute a proof for.
+	// A coset size of 1 means that we compute single point opening proofs.
+	cosetSize uint64
+	// Domain of size 2 * polySize / cosetSize used for the circulant embedding
+	// of the Toeplitz matrix.
+	circulantDomain *Domain
+	// FFT of the (reversed and zero-padded) SRS for each of the `cosetSize` strides.
+	//
+	// transformedSRS[w][b] holds the w'th evaluation for stride b, so that the
+	// points needed at each evaluation are contiguous.
+	transformedSRS [][]bls12381.G1Affine
+}
+
+// NewFK20Key computes an [FK20Key] from the monomial commit key.
+//
+// polySize and cosetSize must be powers of two with cosetSize < polySize
+// and the commit key must have at least polySize points.
+func NewFK20Key(ck *CommitKey, polySize, cosetSize uint64) (*FK20Key, error) {
+	if !utils.IsPowerOfTwo(polySize) || !utils.IsPowerOfTwo(cosetSize) || cosetSize >= polySize {
+		return nil, ErrInvalidCosetSize
+	}
+	if uint64(len(ck.G1)) < polySize {
+		return nil, ErrInvalidPolynomialSize
+	}
+
+	numStrides := cosetSize
+	toeplitzSize := polySize / cosetSize
+	circulantDomain := NewDomain(2 * toeplitzSize)
+
+	transformedSRS := make([][]bls12381.G1Affine, 2*toeplitzSize)
+	for w := range transformedSRS {
+		transformedSRS[w] = make([]bls12381.G1Affine, numStrides)
+	}
+
+	for b := uint64(0); b < numStrides; b++ {
+		// The second half is left as the identity to zero-pad the vector.
+		srsStride := make([]bls12381.G1Affine, 2*toeplitzSize)
+		for v := uint64(0); v < toeplitzSize; v++ {
+			srsStride[v] = ck.G1[(toeplitzSize-1-v)*cosetSize+b]
+		}
+
+		transformed := circulantDomain.FftG1(srsStride)
+		for w := range transformed {
+			transformedSRS[w][b] = transformed[w]
+		}
+	}
+
+	return &FK20Key{
+		polySize:        polySize,
+		cosetSize:       cosetSize,
+		circulantDomain: circulantDomain,
+		transformedSRS:  transformedSRS,
+	}, nil
+}
+
+// MemorySize returns the number of bytes held by the transformed SRS and the circulant domain of the key.
+func (fk *FK20Key) MemorySize() int {
+	size := fk.circulantDomain.MemorySize()
+	for _, points := range fk.transformedSRS {
+		size += len(points) * int(unsafe.Sizeof(bls12381.G1Affine{}))
+	}
+	return size
+}
+
+// ComputeMultiProofs computes opening proofs for the polynomial with the given monomial coefficients
+// over the cosets of a domain of size numCosets * cosetSize.
+//
+// Let ω be a generator of that domain. The r'th proof opens the polynomial at the coset
+// {ω^(r + numCosets * t) : 0 <= t < cosetSize}, that is the points x for which x^cosetSize = ω^(r * cosetSize).
+// The proofs are returned in order of r, and can be checked using [VerifyMulti]; when the coset size
+// is 1, the r'th proof opens the polynomial at ω^r and can be checked using [Verify].
+//
+// numCosets * cosetSize must be at least the number of coefficients the key was created for.
+//
+// ctx is checked between the steps of the algorithm and before each multi exponentiation, and ctx.Err() is returned
+// if it is done.
+func (fk *FK20Key) ComputeMultiProofs(ctx context.Context, coeffs []fr.Element, numCosets uint64) ([]bls12381.G1Affine, error) {
+	if len(coeffs) == 0 || uint64(len(coeffs)) > fk.polySize {
+		return nil, ErrInvalidPolynomialSize
+	}
+	if !utils.IsPowerOfTwo(numCosets) || numCosets*fk.cosetSize < fk.polySize {
+		return nil, ErrInvalidCosetSize
+	}
+
+	numStrides := fk.cosetSize
+	toeplitzSize := fk.polySize / fk.cosetSize
+	circulantSize := 2 * toeplitzSize
+
+	// 1. Compute the FFT of the coefficients of each stride.
+	//
+	// transformedCoeffs[w][b] holds the w'th evaluation for stride b.
+	transformedCoeffs := make([][]fr.Element, circulantSize)
+	for w := range transformedCoeffs {
+		transformedCoeffs[w] = make([]fr.Element, numStrides)
+	}
+	for b := uint64(0); b < numStrides; b++ {
+		// The second half is left as zero to zero-pad the vector.
+		coeffStride := make([]fr.Element, circulantSize)
+		for u := uint64(0); u < toeplitzSize; u++ {
+			index := u*fk.cosetSize + b
+			if index < uint64(len(coeffs)) {
+				coeffStride[u] = coeffs[index]
+			}
+		}
+
+		transformed := fk.circulantDomain.FftFr(coeffStride)
+		for w := range transformed {
+			transformedCoeffs[w][b] = transformed[w]
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 2. Multiply point-wise and sum over the strides.
+	//
+	// Since the FFT is linear, we can sum the products for each stride before
+	// applying the inverse FFT, so we only need one inverse FFT in total.
+	products := make([]bls12381.G1Affine, circulantSize)
+	err := utils.ParallelForCtx(ctx, len(products), 0, func(w int) error {
+		product, err := multiexp.MultiExp(transformedCoeffs[w], fk.transformedSRS[w], 1)
+		if err != nil {
+			return err
+		}
+		products[w] = *product
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. The Toeplitz matrix-vector product is the second half of the
+	// circulant matrix-vector product.
+	circulantProduct := fk.circulantDomain.IfftG1(products)
+
+	// The last entry of h is the identity, as are the entries used for zero-padding.
+	h := make([]bls12381.G1Affine, numCosets)
+	copy(h, circulantProduct[toeplitzSize:circulantSize-1])
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 4. Evaluate Σ_m c^m * h_m at every c = ω^(r * cosetSize).
+	return NewDomain(numCosets).FftG1(h), nil
+}
+
+// ComputeAllProofs computes the opening proofs of `p` at every point in the domain using FK20.
+//
+// `p` is a polynomial in lagrange form, ordered in the same way as domain.Roots and fk must
+// have been created with a coset size of 1 and a polynomial size of domain.Cardinality.
+// The i'th proof opens `p` at domain.Roots[i] to p[i].
+func ComputeAllProofs(domain *Domain, p Polynomial, fk *FK20Key) ([]bls12381.G1Affine, error) {
+	if domain.Cardinality != uint64(len(p)) {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+	if fk.cosetSize != 1 || fk.polySize != domain.Cardinality {
+		return nil, ErrInvalidCosetSize
+	}
+
+	coeffs := domain.lagrangeToMonomial(p)
+
+	proofs, err := fk.ComputeMultiProofs(context.Background(), coeffs, domain.Cardinality)
+	if err != nil {
+		return nil, err
+	}
+
+	// The proofs are in natural order, so we reorder them to match the domain.
+	if domain.isBitReversed {
+		bitReverse(proofs)
+	}
+
+	return proofs, nil
+}
+
+// ComputeCosetProofs computes, using FK20, the opening proofs of `p` over the cosets of size fk.cosetSize which make
+// up the domain twice as large as `domain`.
+//
+// `p` is a polynomial in lagrange form, ordered in the same way as domain.Roots and fk must have been created with a
+// polynomial size of domain.Cardinality. The proofs are returned in bit-reversed order, so that the r'th proof opens
+// `p` at the points of the r'th chunk of fk.cosetSize evaluations returned by [Domain.ExtendPolynomial].
+//
+// ctx is checked as described in [FK20Key.ComputeMultiProofs].
+func ComputeCosetProofs(ctx context.Context, domain *Domain, p Polynomial, fk *FK20Key) ([]bls12381.G1Affine, error) {
+	if domain.Cardinality != uint64(len(p)) {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+	if fk.polySize != domain.Cardinality {
+		return nil, ErrInvalidCosetSize
+	}
+
+	coeffs := domain.lagrangeToMonomial(p)
+
+	proofs, err := fk.ComputeMultiProofs(ctx, coeffs, 2*domain.Cardinality/fk.cosetSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// The r'th chunk of the extension in bit-reversed order holds the evaluations at the
+	// points x for which x^cosetSize = ω^(bitreverse(r) * cosetSize), which are opened by
+	// the proof at index bitreverse(r).
+	bitReverse(proofs)
+
+	return proofs, nil
+}
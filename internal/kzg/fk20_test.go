@@ -0,0 +1,248 @@
+package kzg
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeAllProofsSmoke(t *testing.T) {
+	const size = 32
+	secret := big.NewInt(1234)
+	srsMonomial, err := newMonomialSRSInsecureUint64(size, secret)
+	require.NoError(t, err)
+
+	fk, err := NewFK20Key(&srsMonomial.CommitKey, size, 1)
+	require.NoError(t, err)
+
+	// Check both orderings of the domain
+	for _, reversed := range []bool{false, true} {
+		domain := NewDomain(size)
+		srsLagrange, err := newLagrangeSRSInsecure(*domain, secret)
+		require.NoError(t, err)
+		if reversed {
+			domain.ReverseRoots()
+			srsLagrange.CommitKey.ReversePoints()
+		}
+
+		poly := randPoly(t, *domain)
+		commitment, err := Commit(poly, &srsLagrange.CommitKey, 0)
+		require.NoError(t, err)
+
+		proofs, err := ComputeAllProofs(domain, poly, fk)
+		require.NoError(t, err)
+		require.Len(t, proofs, size)
+
+		// Check a subset of the proofs using the single point verifier
+		// and compare them to the proofs computed naively.
+		for _, i := range []int{0, 1, 7, 16, 31} {
+			proof := OpeningProof{
+				QuotientCommitment: proofs[i],
+				InputPoint:         domain.Roots[i],
+				ClaimedValue:       poly[i],
+			}
+			require.NoError(t, Verify(commitment, &proof, &srsLagrange.OpeningKey))
+
+			expected, err := Open(domain, poly, domain.Roots[i], &srsLagrange.CommitKey, 0)
+			require.NoError(t, err)
+			require.True(t, expected.QuotientCommitment.Equal(&proofs[i]))
+		}
+	}
+}
+
+func TestComputeMultiProofsCosets(t *testing.T) {
+	const polySize = 16
+	const cosetSize = 4
+	// Extend the domain by a factor of two, as is done for data availability sampling
+	const extendedSize = 2 * polySize
+	const numCosets = extendedSize / cosetSize
+
+	secret := big.NewInt(1234)
+	srsMonomial, err := newMonomialSRSInsecureUint64(polySize, secret)
+	require.NoError(t, err)
+
+	fk, err := NewFK20Key(&srsMonomial.CommitKey, polySize, cosetSize)
+	require.NoError(t, err)
+
+	coeffs := make([]fr.Element, polySize)
+	for i := 0; i < polySize; i++ {
+		_, err := coeffs[i].SetRandom()
+		require.NoError(t, err)
+	}
+	commitment, err := Commit(coeffs, &srsMonomial.CommitKey, 0)
+	require.NoError(t, err)
+
+	proofs, err := fk.ComputeMultiProofs(context.Background(), coeffs, numCosets)
+	require.NoError(t, err)
+	require.Len(t, proofs, numCosets)
+
+	extendedDomain := NewDomain(extendedSize)
+	for r := 0; r < numCosets; r++ {
+		points := make([]fr.Element, cosetSize)
+		values := make([]fr.Element, cosetSize)
+		for i := 0; i < cosetSize; i++ {
+			points[i] = extendedDomain.Roots[r+numCosets*i]
+			values[i] = evaluateMonomial(coeffs, points[i])
+		}
+
+		proof := MultiOpeningProof{
+			QuotientCommitment: proofs[r],
+			InputPoints:        points,
+			ClaimedValues:      values,
+		}
+		require.NoError(t, VerifyMulti(commitment, &proof, &srsMonomial.OpeningKey, 0))
+	}
+
+	// Proofs for the wrong coset should not verify
+	wrongProof := MultiOpeningProof{
+		QuotientCommitment: proofs[1],
+		InputPoints:        []fr.Element{extendedDomain.Roots[0], extendedDomain.Roots[numCosets]},
+		ClaimedValues:      []fr.Element{evaluateMonomial(coeffs, extendedDomain.Roots[0]), evaluateMonomial(coeffs, extendedDomain.Roots[numCosets])},
+	}
+	require.ErrorIs(t, VerifyMulti(commitment, &wrongProof, &srsMonomial.OpeningKey, 0), ErrVerifyOpeningProof)
+}
+
+func TestComputeCosetProofs(t *testing.T) {
+	const polySize = 16
+	const cosetSize = 4
+	const numCosets = 2 * polySize / cosetSize
+
+	secret := big.NewInt(1234)
+	srsMonomial, err := newMonomialSRSInsecureUint64(polySize, secret)
+	require.NoError(t, err)
+	fk, err := NewFK20Key(&srsMonomial.CommitKey, polySize, cosetSize)
+	require.NoError(t, err)
+
+	coeffs := testScalars(polySize)
+	commitment, err := Commit(coeffs, &srsMonomial.CommitKey, 0)
+	require.NoError(t, err)
+
+	domain := NewDomain(polySize)
+	domain.ReverseRoots()
+	p := make(Polynomial, polySize)
+	for i := range p {
+		p[i] = evaluateMonomial(coeffs, domain.Roots[i])
+	}
+
+	extended, err := domain.ExtendPolynomial(p)
+	require.NoError(t, err)
+	proofs, err := ComputeCosetProofs(context.Background(), domain, p, fk)
+	require.NoError(t, err)
+	require.Len(t, proofs, numCosets)
+
+	// The r'th proof opens the r'th chunk of the extension
+	extendedDomain := NewDomain(2 * polySize)
+	extendedDomain.ReverseRoots()
+	for r := 0; r < numCosets; r++ {
+		proof := MultiOpeningProof{
+			QuotientCommitment: proofs[r],
+			InputPoints:        extendedDomain.Roots[r*cosetSize : (r+1)*cosetSize],
+			ClaimedValues:      extended[r*cosetSize : (r+1)*cosetSize],
+		}
+		require.NoError(t, VerifyMulti(commitment, &proof, &srsMonomial.OpeningKey, 0))
+	}
+
+	_, err = ComputeCosetProofs(context.Background(), domain, p[1:], fk)
+	require.ErrorIs(t, err, ErrPolynomialMismatchedSizeDomain)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ComputeCosetProofs(cancelled, domain, p, fk)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBatchVerifyCosets(t *testing.T) {
+	const polySize = 16
+	const cosetSize = 4
+	const numCosets = 2 * polySize / cosetSize
+
+	secret := big.NewInt(1234)
+	srsMonomial, err := newMonomialSRSInsecureUint64(polySize, secret)
+	require.NoError(t, err)
+	fk, err := NewFK20Key(&srsMonomial.CommitKey, polySize, cosetSize)
+	require.NoError(t, err)
+
+	domain := NewDomain(polySize)
+	domain.ReverseRoots()
+	extendedDomain := NewDomain(2 * polySize)
+	extendedDomain.ReverseRoots()
+	cosetDomain := NewDomain(cosetSize)
+	cosetDomain.ReverseRoots()
+
+	// Extend two polynomials and compute the proofs for all of their cosets
+	commitments := make([]Commitment, 2)
+	extensions := make([][]fr.Element, 2)
+	allProofs := make([][]bls12381.G1Affine, 2)
+	for i := range commitments {
+		p := randPoly(t, *domain)
+		coeffs := domain.lagrangeToMonomial(p)
+		commitment, err := Commit(coeffs, &srsMonomial.CommitKey, 0)
+		require.NoError(t, err)
+		commitments[i] = *commitment
+		extensions[i], err = domain.ExtendPolynomial(p)
+		require.NoError(t, err)
+		allProofs[i], err = ComputeCosetProofs(context.Background(), domain, p, fk)
+		require.NoError(t, err)
+	}
+	cosetProof := func(i, r int) CosetOpeningProof {
+		return CosetOpeningProof{
+			QuotientCommitment: allProofs[i][r],
+			CosetShift:         extendedDomain.Roots[r*cosetSize],
+			ClaimedValues:      extensions[i][r*cosetSize : (r+1)*cosetSize],
+		}
+	}
+
+	// The first commitment is opened several times
+	commitmentIndices := []uint64{0, 1, 0, 0}
+	cosets := []int{0, numCosets - 1, 3, numCosets / 2}
+	proofs := make([]CosetOpeningProof, len(cosets))
+	for k := range proofs {
+		proofs[k] = cosetProof(int(commitmentIndices[k]), cosets[k])
+	}
+	require.NoError(t, BatchVerifyCosets(commitments, commitmentIndices, proofs, cosetDomain, &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, rand.Reader))
+	require.NoError(t, BatchVerifyCosets(commitments, nil, nil, cosetDomain, &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, rand.Reader))
+
+	// Opening the wrong commitment, or using the proof of another coset, fails
+	wrongIndices := []uint64{0, 1, 1, 0}
+	err = BatchVerifyCosets(commitments, wrongIndices, proofs, cosetDomain, &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, rand.Reader)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+	swapped := append([]CosetOpeningProof{}, proofs...)
+	swapped[2].QuotientCommitment, swapped[3].QuotientCommitment = swapped[3].QuotientCommitment, swapped[2].QuotientCommitment
+	err = BatchVerifyCosets(commitments, commitmentIndices, swapped, cosetDomain, &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, rand.Reader)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+
+	// Invalid inputs
+	err = BatchVerifyCosets(commitments, commitmentIndices[1:], proofs, cosetDomain, &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, rand.Reader)
+	require.ErrorIs(t, err, ErrInvalidNumDigests)
+	err = BatchVerifyCosets(commitments, []uint64{0, 1, 2, 0}, proofs, cosetDomain, &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, rand.Reader)
+	require.ErrorIs(t, err, ErrInvalidNumDigests)
+	err = BatchVerifyCosets(commitments, commitmentIndices, proofs, NewDomain(2*cosetSize), &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, rand.Reader)
+	require.ErrorIs(t, err, ErrMismatchedNumEvaluations)
+	err = BatchVerifyCosets(commitments, commitmentIndices, proofs, cosetDomain, &srsMonomial.CommitKey, &srsMonomial.OpeningKey, 0, bytes.NewReader(nil))
+	require.ErrorIs(t, err, ErrRandomSource)
+}
+
+func TestFK20InvalidSizes(t *testing.T) {
+	srsMonomial, err := newMonomialSRSInsecureUint64(8, big.NewInt(1234))
+	require.NoError(t, err)
+
+	_, err = NewFK20Key(&srsMonomial.CommitKey, 8, 8)
+	require.ErrorIs(t, err, ErrInvalidCosetSize)
+	_, err = NewFK20Key(&srsMonomial.CommitKey, 8, 3)
+	require.ErrorIs(t, err, ErrInvalidCosetSize)
+	_, err = NewFK20Key(&srsMonomial.CommitKey, 16, 1)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+
+	fk, err := NewFK20Key(&srsMonomial.CommitKey, 8, 2)
+	require.NoError(t, err)
+	_, err = fk.ComputeMultiProofs(context.Background(), make([]fr.Element, 9), 4)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	_, err = fk.ComputeMultiProofs(context.Background(), make([]fr.Element, 8), 2)
+	require.ErrorIs(t, err, ErrInvalidCosetSize)
+}
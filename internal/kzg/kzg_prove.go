@@ -1,13 +1,15 @@
 package kzg
 
 import (
+	"github.com/RiemaLabs/go-kzg-4844/internal/multiexp"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
 // Open verifies that a polynomial f(x) when evaluated at a point `z` is equal to `f(z)`
 //
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
-// value to a negative number or 0 will make it default to the number of CPUs.
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
 //
 // [compute_kzg_proof_impl]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof_impl
 func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKey, numGoRoutines int) (OpeningProof, error) {
@@ -21,7 +23,7 @@ func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKe
 	}
 
 	// Compute the quotient polynomial
-	quotientPoly, err := domain.computeQuotientPoly(p, indexInDomain, *outputPoint, evaluationPoint)
+	quotientPoly, err := domain.computeQuotientPoly(p, indexInDomain, outputPoint, evaluationPoint)
 	if err != nil {
 		return OpeningProof{}, err
 	}
@@ -34,7 +36,7 @@ func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKe
 
 	res := OpeningProof{
 		InputPoint:   evaluationPoint,
-		ClaimedValue: *outputPoint,
+		ClaimedValue: outputPoint,
 	}
 
 	res.QuotientCommitment.Set(quotientCommit)
@@ -42,6 +44,109 @@ func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKe
 	return res, nil
 }
 
+// OpenMulti creates a single proof that a polynomial f(x) evaluates to `f(z_i)` at each of the given points `z_i`.
+//
+// The proof is a commitment to the quotient q(X) = (f(X) - I(X)) / Z(X), where I(X) is the polynomial interpolating
+// the claimed values and Z(X) is the polynomial vanishing on the opening points. Since 1/Z(X) = Σ_i 1/(Z'(z_i) * (X - z_i)),
+// q(X) can be written as a linear combination of the single point quotients (f(X) - f(z_i)) / (X - z_i), which lets
+// us reuse [computeQuotientPoly] and avoid any conversion out of lagrange form.
+//
+// The points must be distinct. They may or may not be in the domain.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func OpenMulti(domain *Domain, p Polynomial, points []fr.Element, ck *CommitKey, numGoRoutines int) (MultiOpeningProof, error) {
+	if len(p) == 0 || len(p) > len(ck.G1) {
+		return MultiOpeningProof{}, ErrInvalidPolynomialSize
+	}
+	if len(points) == 0 {
+		return MultiOpeningProof{}, ErrNoOpeningPoints
+	}
+	if hasDuplicates(points) {
+		return MultiOpeningProof{}, ErrDuplicateOpeningPoints
+	}
+
+	// 1/Z'(z_i) for each opening point
+	factors := lagrangeDenominatorsInverse(points)
+
+	claimedValues := make([]fr.Element, len(points))
+	quotientPoly := make(Polynomial, len(p))
+	for i := 0; i < len(points); i++ {
+		outputPoint, indexInDomain, err := domain.evaluateLagrangePolynomial(p, points[i])
+		if err != nil {
+			return MultiOpeningProof{}, err
+		}
+		claimedValues[i].Set(&outputPoint)
+
+		quotient, err := domain.computeQuotientPoly(p, indexInDomain, outputPoint, points[i])
+		if err != nil {
+			return MultiOpeningProof{}, err
+		}
+
+		for j := 0; j < len(quotient); j++ {
+			var term fr.Element
+			term.Mul(&quotient[j], &factors[i])
+			quotientPoly[j].Add(&quotientPoly[j], &term)
+		}
+	}
+
+	// Commit to Quotient polynomial
+	quotientCommit, err := Commit(quotientPoly, ck, numGoRoutines)
+	if err != nil {
+		return MultiOpeningProof{}, err
+	}
+
+	inputPoints := make([]fr.Element, len(points))
+	copy(inputPoints, points)
+
+	res := MultiOpeningProof{
+		InputPoints:   inputPoints,
+		ClaimedValues: claimedValues,
+	}
+
+	res.QuotientCommitment.Set(quotientCommit)
+
+	return res, nil
+}
+
+// OpenDegreeBound creates a proof that the polynomial `p` has degree less than `degreeBound`.
+//
+// The proof is a commitment to X^(n - degreeBound) * f(X), where n is domain.Cardinality. Since the SRS has no powers
+// above α^(n-1), such a commitment can only be computed if f(X) has degree less than degreeBound.
+//
+// `p` is a polynomial in lagrange form, ordered in the same way as domain.Roots, and `ck` must hold the
+// G1 points of the SRS in monomial form.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func OpenDegreeBound(domain *Domain, p Polynomial, degreeBound uint64, ck *CommitKey, numGoRoutines int) (bls12381.G1Affine, error) {
+	if domain.Cardinality != uint64(len(p)) {
+		return bls12381.G1Affine{}, ErrPolynomialMismatchedSizeDomain
+	}
+	if degreeBound == 0 || degreeBound > domain.Cardinality {
+		return bls12381.G1Affine{}, ErrInvalidDegreeBound
+	}
+	if uint64(len(ck.G1)) < domain.Cardinality {
+		return bls12381.G1Affine{}, ErrInvalidPolynomialSize
+	}
+
+	coeffs := domain.lagrangeToMonomial(p)
+	for i := degreeBound; i < uint64(len(coeffs)); i++ {
+		if !coeffs[i].IsZero() {
+			return bls12381.G1Affine{}, ErrPolynomialExceedsDegreeBound
+		}
+	}
+
+	// Multiplying by X^shift moves the i'th coefficient to the (i + shift)'th power of α
+	shift := domain.Cardinality - degreeBound
+	proof, err := multiexp.MultiExp(coeffs[:degreeBound], ck.G1[shift:domain.Cardinality], numGoRoutines)
+	if err != nil {
+		return bls12381.G1Affine{}, err
+	}
+
+	return *proof, nil
+}
+
 // computeQuotientPoly computes q(X) = (f(X) - f(z)) / (X - z) in Lagrange form.
 //
 // We refer to the result q(X) as the quotient polynomial.
@@ -113,67 +218,55 @@ func (domain *Domain) computeQuotientPolyOutsideDomain(f Polynomial, fz, z fr.El
 //
 // This is the implementation of computeQuotientPoly for the case where the evaluation point is in the domain.
 //
+// Let z = w^a and w^b be another point of the domain. Then 1 / (w^b - z) = (1/z) * 1 / (w^(b-a) - 1), so the
+// inverses can be looked up in domain.invRootsMinusOne and no inversion is needed.
+//
 // [compute_quotient_eval_within_domain]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_quotient_eval_within_domain
 func (domain *Domain) computeQuotientPolyOnDomain(f Polynomial, index uint64) (Polynomial, error) {
 	fz := f[index]
-	z := domain.Roots[index]
 	invZ := domain.PreComputedInverses[index]
+	n := domain.Cardinality
+	a := domain.rootExponent(index)
 
-	// Compute the evaluation of X - z at every point in the domain.
-	rootsMinusZ := make([]fr.Element, domain.Cardinality)
-	for i := 0; i < int(domain.Cardinality); i++ {
-		rootsMinusZ[i].Sub(&domain.Roots[i], &z)
-	}
-
-	// Since we know that `z` is in the domain, rootsMinusZ[index] will be zero.
-	// We set this value to `1` instead to compute the batch inversion without having to special-case here.
-	// This way, the value of rootsMinusZ[index] will stay untouched.
-	// Note: The underlying gnark-crypto library will not panic if
-	// one of the elements is zero, but this is not common across libraries so we just set it to one.
-	rootsMinusZ[index].SetOne()
+	quotientPoly := make(Polynomial, n)
 
-	// Evaluation of 1/(X-z) at every point of the domain, except for index.
-	invRootsMinusZ := fr.BatchInvert(rootsMinusZ)
-
-	// The rootsMinusZ is now free to reuse, since BatchInvert returned
-	// a fresh slice. But we need to ensure to set the value for 'index' to zero
-	quotientPoly := rootsMinusZ
-	quotientPoly[index] = fr.Element{}
-
-	for j := 0; j < int(domain.Cardinality); j++ {
+	// Note: For notations below, we use `m` to denote `index`
+	//
+	// The m'th evaluation of the quotient is given by
+	//   q_m = Σ_{j != m} (f_j - f_m) / (w^m - w^j) * (w^j / w^m)
+	//
+	// Writing w^j / w^m = w^k, the j'th term is -q_j * w^k and
+	// w^k / (w^k - 1) = 1 + 1 / (w^k - 1), so the term simplifies to
+	//   -q_j - (f_j - f_m) / w^m
+	//
+	// That is, q_m is the sum of the other evaluations of the quotient negated,
+	// minus the sum of the numerators divided by z.
+	var sumQuotients, sumNumerators fr.Element
+	for j := uint64(0); j < n; j++ {
 		// Check if we are on the current root of unity
-		// Note: For notations below, we use `m` to denote `index`
-		if uint64(j) == index {
+		if j == index {
 			continue
 		}
 
-		// Compute q_j = f_j / w^j - w^m for j != m.
-		// This is exactly the same as in the computeQuotientPolyOutsideDomain - case.
-		//
-		// Note: f_j is the numerator of the quotient polynomial ie f_j = f[j] - f(z)
-		//
+		// Compute q_j = (f_j - f_m) / (w^j - w^m) for j != m.
 		//
+		// Note: f_j - f_m is the numerator of the quotient polynomial
+		var numerator fr.Element
+		numerator.Sub(&f[j], &fz)
+
+		k := (domain.rootExponent(j) + n - a) % n
 		var q_j fr.Element
-		q_j.Sub(&f[j], &fz)
-		q_j.Mul(&q_j, &invRootsMinusZ[j])
+		q_j.Mul(&numerator, &domain.invRootsMinusOne[k])
+		q_j.Mul(&q_j, &invZ)
 		quotientPoly[j] = q_j
 
-		// Compute the contribution to q_m coming from the j'th term of the input.
-		// This term is given by
-		// q_m_j = (f_j / w^m - w^j) * (w^j/w^m) , where w^m = z
-		//		 = - q_j * w^{j-m}
-		//
-		// We _could_ find 1 / w^{j-m} via a lookup table
-		// but we want to avoid lookup tables because
-		// the roots are bit-reversed which can make the
-		// code less readable.
-		var q_m_j fr.Element
-		q_m_j.Neg(&q_j)
-		q_m_j.Mul(&q_m_j, &domain.Roots[j])
-		q_m_j.Mul(&q_m_j, &invZ)
-
-		quotientPoly[index].Add(&quotientPoly[index], &q_m_j)
+		sumQuotients.Add(&sumQuotients, &q_j)
+		sumNumerators.Add(&sumNumerators, &numerator)
 	}
 
+	sumNumerators.Mul(&sumNumerators, &invZ)
+	quotientPoly[index].Add(&sumQuotients, &sumNumerators)
+	quotientPoly[index].Neg(&quotientPoly[index])
+
 	return quotientPoly, nil
 }
@@ -1,6 +1,9 @@
 package kzg
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"math/big"
 	"testing"
 
@@ -40,17 +43,281 @@ func TestBatchVerifySmoke(t *testing.T) {
 	}
 
 	// Check that these verify successfully.
-	err := BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey)
+	err := BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey, 0, rand.Reader, nil)
 	require.NoError(t, err)
 
 	// Add an invalid proof, to ensure that it fails
 	proof, _ := randValidOpeningProof(t, *domain, *srs)
 	commitments = append(commitments, bls12381.G1Affine{})
 	proofs = append(proofs, proof)
-	err = BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey)
+	err = BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey, 0, rand.Reader, nil)
 	require.Error(t, err, "An invalid proof was added to the list, however verification returned true")
 }
 
+func TestBatchVerifyCorruptedProof(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	numProofs := 8
+	commitments := make([]Commitment, numProofs)
+	proofs := make([]OpeningProof, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proofs[i], commitments[i] = randValidOpeningProof(t, *domain, *srs)
+	}
+
+	// Use a fixed source of randomness so that the test is deterministic
+	randReader := bytes.NewReader(bytes.Repeat([]byte{0x42}, 32))
+	err := BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey, 0, randReader, nil)
+	require.NoError(t, err)
+
+	// Corrupt a single quotient commitment in the middle of the batch
+	proofs[numProofs/2].QuotientCommitment.Add(&proofs[numProofs/2].QuotientCommitment, &srs.OpeningKey.GenG1)
+	randReader = bytes.NewReader(bytes.Repeat([]byte{0x42}, 32))
+	err = BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey, 0, randReader, nil)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+
+	// Not enough randomness should produce an error rather than a weak combination
+	proofs[numProofs/2], commitments[numProofs/2] = randValidOpeningProof(t, *domain, *srs)
+	err = BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey, 0, bytes.NewReader([]byte{0x42}), nil)
+	require.ErrorIs(t, err, ErrRandomSource)
+}
+
+func TestRandomScalar(t *testing.T) {
+	// The same bytes give the same scalar
+	seed := bytes.Repeat([]byte{0x42}, 64)
+	first, err := RandomScalar(bytes.NewReader(seed))
+	require.NoError(t, err)
+	second, err := RandomScalar(bytes.NewReader(seed))
+	require.NoError(t, err)
+	require.True(t, first.Equal(&second))
+
+	// Values which are not less than the modulus once the top bit is cleared are rejected
+	// and the next 32 bytes are used instead
+	tooLarge := bytes.Repeat([]byte{0xff}, 32)
+	resampled, err := RandomScalar(bytes.NewReader(append(tooLarge, seed...)))
+	require.NoError(t, err)
+	require.True(t, first.Equal(&resampled))
+
+	// The top bit is ignored
+	withTopBit := append([]byte{0xc2}, seed[1:]...)
+	masked, err := RandomScalar(bytes.NewReader(withTopBit))
+	require.NoError(t, err)
+	require.True(t, first.Equal(&masked))
+
+	// Running out of randomness is an error, also while resampling
+	_, err = RandomScalar(bytes.NewReader(seed[:31]))
+	require.ErrorIs(t, err, ErrRandomSource)
+	_, err = RandomScalar(bytes.NewReader(tooLarge))
+	require.ErrorIs(t, err, ErrRandomSource)
+}
+
+// Each of the terms in the verification equation is negated in turn.
+// If the implementation had the wrong sign for one of them, then either
+// the valid proof or one of the proofs with a flipped sign would be
+// accepted incorrectly.
+func TestVerifySignFlips(t *testing.T) {
+	domain := NewDomain(16)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	require.NoError(t, err)
+
+	proof, commitment := randValidOpeningProof(t, *domain, *srs)
+	otherProof, otherCommitment := randValidOpeningProof(t, *domain, *srs)
+	require.NoError(t, Verify(&commitment, &proof, &srs.OpeningKey))
+	require.NoError(t, BatchVerifyMultiPoints([]Commitment{commitment, otherCommitment}, []OpeningProof{proof, otherProof}, &srs.OpeningKey, 0, rand.Reader, nil))
+
+	flips := map[string]func(*Commitment, *OpeningProof){
+		"commitment": func(c *Commitment, _ *OpeningProof) { c.Neg(c) },
+		"quotient":   func(_ *Commitment, p *OpeningProof) { p.QuotientCommitment.Neg(&p.QuotientCommitment) },
+		"point":      func(_ *Commitment, p *OpeningProof) { p.InputPoint.Neg(&p.InputPoint) },
+		"value":      func(_ *Commitment, p *OpeningProof) { p.ClaimedValue.Neg(&p.ClaimedValue) },
+	}
+	for name, flip := range flips {
+		flippedCommitment, flippedProof := commitment, proof
+		flip(&flippedCommitment, &flippedProof)
+
+		err := Verify(&flippedCommitment, &flippedProof, &srs.OpeningKey)
+		require.ErrorIs(t, err, ErrVerifyOpeningProof, name)
+
+		err = BatchVerifyMultiPoints([]Commitment{flippedCommitment, otherCommitment}, []OpeningProof{flippedProof, otherProof}, &srs.OpeningKey, 0, rand.Reader, nil)
+		require.ErrorIs(t, err, ErrVerifyOpeningProof, name)
+	}
+}
+
+func TestVerifyWithPrecomputedPairingLines(t *testing.T) {
+	domain := NewDomain(16)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	require.NoError(t, err)
+
+	precomputedKey := srs.OpeningKey
+	precomputedKey.PrecomputePairingLines()
+
+	var commitments []Commitment
+	var proofs []OpeningProof
+	for i := 0; i < 4; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	invalidProof := proofs[0]
+	invalidProof.ClaimedValue.SetOne()
+
+	// Verify several times, to check that the precomputed lines are not modified
+	for i := 0; i < 3; i++ {
+		for j := range proofs {
+			require.NoError(t, Verify(&commitments[j], &proofs[j], &precomputedKey))
+		}
+		require.ErrorIs(t, Verify(&commitments[0], &invalidProof, &precomputedKey), ErrVerifyOpeningProof)
+		require.ErrorIs(t, Verify(&commitments[0], &invalidProof, &srs.OpeningKey), ErrVerifyOpeningProof)
+
+		require.NoError(t, BatchVerifyMultiPoints(commitments, proofs, &precomputedKey, 0, rand.Reader, nil))
+		err := BatchVerifyMultiPoints(commitments, append([]OpeningProof{invalidProof}, proofs[1:]...), &precomputedKey, 0, rand.Reader, nil)
+		require.ErrorIs(t, err, ErrVerifyOpeningProof)
+	}
+}
+
+func TestMultiProofVerifySmoke(t *testing.T) {
+	domain := NewDomain(8)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	poly := randPoly(t, *domain)
+	comm, _ := Commit(poly, &srs.CommitKey, 0)
+
+	// Open at a mix of points inside and outside of the domain
+	points := []fr.Element{domain.Roots[1], *samplePointOutsideDomain(*domain), domain.Roots[5], *samplePointOutsideDomain(*domain)}
+	proof, err := OpenMulti(domain, poly, points, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	for i := 0; i < len(points); i++ {
+		expected, _ := domain.EvaluateLagrangePolynomial(poly, points[i])
+		require.True(t, expected.Equal(&proof.ClaimedValues[i]))
+	}
+
+	err = VerifyMulti(comm, &proof, &srs.OpeningKey, 0)
+	require.NoError(t, err)
+
+	// A single wrong evaluation should make the proof fail
+	proof.ClaimedValues[2].Add(&proof.ClaimedValues[2], &proof.ClaimedValues[0])
+	err = VerifyMulti(comm, &proof, &srs.OpeningKey, 0)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+}
+
+func TestMultiProofInvalidInputs(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	poly := randPoly(t, *domain)
+	comm, _ := Commit(poly, &srs.CommitKey, 0)
+
+	_, err := OpenMulti(domain, poly, []fr.Element{}, &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrNoOpeningPoints)
+
+	point := *samplePointOutsideDomain(*domain)
+	_, err = OpenMulti(domain, poly, []fr.Element{point, point}, &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrDuplicateOpeningPoints)
+
+	// The insecure SRS has 4 G2 points, so we can verify openings at 3 points at most
+	points := []fr.Element{domain.Roots[0], domain.Roots[1], domain.Roots[2], domain.Roots[3]}
+	proof, err := OpenMulti(domain, poly, points, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	err = VerifyMulti(comm, &proof, &srs.OpeningKey, 0)
+	require.ErrorIs(t, err, ErrTooManyOpeningPoints)
+
+	proof, err = OpenMulti(domain, poly, points[:3], &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.NoError(t, VerifyMulti(comm, &proof, &srs.OpeningKey, 0))
+
+	proof.ClaimedValues = proof.ClaimedValues[:2]
+	err = VerifyMulti(comm, &proof, &srs.OpeningKey, 0)
+	require.ErrorIs(t, err, ErrMismatchedNumEvaluations)
+}
+
+func TestMultiProofRegression(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(100))
+
+	poly := Polynomial{fr.NewElement(12345), fr.NewElement(123456), fr.NewElement(1234567), fr.NewElement(12345678)}
+	points := []fr.Element{fr.NewElement(5), domain.Roots[2]}
+	proof, err := OpenMulti(domain, poly, points, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	proofBytes := proof.QuotientCommitment.Bytes()
+	gotProof := hex.EncodeToString(proofBytes[:])
+	expectedProof := "83a28332f2e4b08b80409539257833dd6abe53283705e8331a2c7a55cfb921328bca16a5a2f6945cf21f48234cd4e2f1"
+	require.Equal(t, expectedProof, gotProof)
+	require.True(t, proof.ClaimedValues[1].Equal(&poly[2]))
+}
+
+func TestDegreeBoundProof(t *testing.T) {
+	const size = 16
+	const degreeBound = 12
+	domain := NewDomain(size)
+	srsMonomial, err := newMonomialSRSInsecureUint64(size, big.NewInt(1234))
+	require.NoError(t, err)
+
+	// polyWithDegree returns a random polynomial of the given degree in lagrange form
+	// and its commitment.
+	polyWithDegree := func(degree int) (Polynomial, Commitment) {
+		coeffs := make([]fr.Element, size)
+		for i := 0; i <= degree; i++ {
+			_, err := coeffs[i].SetRandom()
+			require.NoError(t, err)
+		}
+		commitment, err := Commit(coeffs, &srsMonomial.CommitKey, 0)
+		require.NoError(t, err)
+		return domain.FftFr(coeffs), *commitment
+	}
+
+	// Exactly at the bound and below it
+	for _, degree := range []int{degreeBound - 1, 3, 0} {
+		poly, commitment := polyWithDegree(degree)
+		proof, err := OpenDegreeBound(domain, poly, degreeBound, &srsMonomial.CommitKey, 0)
+		require.NoError(t, err)
+		require.NoError(t, VerifyDegreeBound(&commitment, &proof, degreeBound, size, &srsMonomial.OpeningKey))
+	}
+
+	// A polynomial exceeding the bound cannot be proven honestly
+	poly, commitment := polyWithDegree(degreeBound)
+	_, err = OpenDegreeBound(domain, poly, degreeBound, &srsMonomial.CommitKey, 0)
+	require.ErrorIs(t, err, ErrPolynomialExceedsDegreeBound)
+
+	// Forge a proof by dropping the coefficients that do not fit in the SRS after the shift
+	coeffs := domain.IfftFr(poly)
+	forged, err := OpenDegreeBound(domain, domain.FftFr(append(coeffs[:degreeBound:degreeBound], make([]fr.Element, size-degreeBound)...)), degreeBound, &srsMonomial.CommitKey, 0)
+	require.NoError(t, err)
+	err = VerifyDegreeBound(&commitment, &forged, degreeBound, size, &srsMonomial.OpeningKey)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+
+	// The same polynomial satisfies a looser bound
+	proof, err := OpenDegreeBound(domain, poly, degreeBound+1, &srsMonomial.CommitKey, 0)
+	require.NoError(t, err)
+	require.NoError(t, VerifyDegreeBound(&commitment, &proof, degreeBound+1, size, &srsMonomial.OpeningKey))
+}
+
+func TestDegreeBoundInvalidInputs(t *testing.T) {
+	const size = 16
+	domain := NewDomain(size)
+	srsMonomial, err := newMonomialSRSInsecureUint64(size, big.NewInt(1234))
+	require.NoError(t, err)
+	poly := randPoly(t, *domain)
+
+	_, err = OpenDegreeBound(domain, poly, 0, &srsMonomial.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidDegreeBound)
+	_, err = OpenDegreeBound(domain, poly, size+1, &srsMonomial.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidDegreeBound)
+
+	var commitment bls12381.G1Affine
+	proof := srsMonomial.OpeningKey.GenG1
+	err = VerifyDegreeBound(&commitment, &proof, 0, size, &srsMonomial.OpeningKey)
+	require.ErrorIs(t, err, ErrInvalidDegreeBound)
+
+	// The opening key only holds the G2 powers up to α^(size-1)
+	openKey := srsMonomial.OpeningKey
+	openKey.G2 = openKey.G2[:4]
+	err = VerifyDegreeBound(&commitment, &proof, size-4, size, &openKey)
+	require.ErrorIs(t, err, ErrDegreeBoundShiftUnavailable)
+	err = VerifyDegreeBound(&commitment, &proof, size-3, size, &openKey)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+}
+
 func TestComputeQuotientPolySmoke(t *testing.T) {
 	numEvaluations := 128
 	domain := NewDomain(uint64(numEvaluations))
@@ -151,7 +418,7 @@ func computeQuotientEvalWithinDomain(domain Domain, z fr.Element, polynomial Pol
 	return result
 }
 
-func randValidOpeningProof(t *testing.T, domain Domain, srs SRS) (OpeningProof, Commitment) {
+func randValidOpeningProof(t testing.TB, domain Domain, srs SRS) (OpeningProof, Commitment) {
 	t.Helper()
 	poly := randPoly(t, domain)
 	comm, _ := Commit(poly, &srs.CommitKey, 0)
@@ -160,7 +427,7 @@ func randValidOpeningProof(t *testing.T, domain Domain, srs SRS) (OpeningProof,
 	return proof, *comm
 }
 
-func randPoly(t *testing.T, domain Domain) Polynomial {
+func randPoly(t testing.TB, domain Domain) Polynomial {
 	t.Helper()
 	var poly Polynomial
 	for i := 0; i < int(domain.Cardinality); i++ {
@@ -170,7 +437,7 @@ func randPoly(t *testing.T, domain Domain) Polynomial {
 	return poly
 }
 
-func randomScalarNotInDomain(t *testing.T, domain Domain) fr.Element {
+func randomScalarNotInDomain(t testing.TB, domain Domain) fr.Element {
 	t.Helper()
 	var randFr fr.Element
 	for {
@@ -184,3 +451,80 @@ func randomScalarNotInDomain(t *testing.T, domain Domain) fr.Element {
 	}
 	return randFr
 }
+
+func TestComputeQuotientPolyOnDomainAllRoots(t *testing.T) {
+	// Use the same ordering as the Context
+	domain := NewDomain(4096)
+	domain.ReverseRoots()
+	poly := randPoly(t, *domain)
+
+	// Comparing every root takes several seconds, so we use a stride that is coprime to the size
+	for i := uint64(0); i < domain.Cardinality; i += 7 {
+		got, err := domain.computeQuotientPolyOnDomain(poly, i)
+		require.NoError(t, err)
+		expected := computeQuotientPolyOnDomainBatchInvert(*domain, poly, i)
+		for j := range got {
+			if !got[j].Equal(&expected[j]) {
+				t.Fatalf("quotients differ for root %d at index %d", i, j)
+			}
+		}
+	}
+}
+
+func TestOpenOnDomainAllRoots(t *testing.T) {
+	// A smaller domain keeps the test fast
+	domain := NewDomain(64)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	require.NoError(t, err)
+	domain.ReverseRoots()
+	srs.CommitKey.ReversePoints()
+
+	poly := randPoly(t, *domain)
+	commitment, err := Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	for i := uint64(0); i < domain.Cardinality; i++ {
+		proof, err := Open(domain, poly, domain.Roots[i], &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.NoError(t, Verify(commitment, &proof, &srs.OpeningKey))
+
+		expected, err := Commit(computeQuotientPolyOnDomainBatchInvert(*domain, poly, i), &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(&proof.QuotientCommitment))
+	}
+}
+
+// computeQuotientPolyOnDomainBatchInvert computes the quotient by X - z for `z` in the domain,
+// using a batch inversion of w^j - z instead of precomputed inverses.
+func computeQuotientPolyOnDomainBatchInvert(domain Domain, f Polynomial, index uint64) Polynomial {
+	fz := f[index]
+	z := domain.Roots[index]
+	invZ := domain.PreComputedInverses[index]
+
+	rootsMinusZ := make([]fr.Element, domain.Cardinality)
+	for i := 0; i < int(domain.Cardinality); i++ {
+		rootsMinusZ[i].Sub(&domain.Roots[i], &z)
+	}
+	rootsMinusZ[index].SetOne()
+	invRootsMinusZ := fr.BatchInvert(rootsMinusZ)
+
+	quotientPoly := make(Polynomial, domain.Cardinality)
+	for j := 0; j < int(domain.Cardinality); j++ {
+		if uint64(j) == index {
+			continue
+		}
+
+		// q_j = (f_j - f(z)) / (w^j - z)
+		quotientPoly[j].Sub(&f[j], &fz)
+		quotientPoly[j].Mul(&quotientPoly[j], &invRootsMinusZ[j])
+
+		// q_m += -q_j * w^j / z
+		var q_m_j fr.Element
+		q_m_j.Neg(&quotientPoly[j])
+		q_m_j.Mul(&q_m_j, &domain.Roots[j])
+		q_m_j.Mul(&q_m_j, &invZ)
+		quotientPoly[index].Add(&quotientPoly[index], &q_m_j)
+	}
+
+	return quotientPoly
+}
@@ -1,6 +1,8 @@
 package kzg
 
 import (
+	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
@@ -22,70 +24,162 @@ type OpeningProof struct {
 	ClaimedValue fr.Element
 }
 
+// MultiOpeningProof is a struct holding a (cryptographic) proof to the claim that a polynomial f(X) (represented by a
+// commitment to it) evaluates at each of the points `z_i` to `f(z_i)`.
+type MultiOpeningProof struct {
+	// Commitment to quotient polynomial (f(X) - I(X))/Z(X)
+	QuotientCommitment bls12381.G1Affine
+
+	// Points that we are evaluating the polynomial at : `z_i`
+	InputPoints []fr.Element
+
+	// ClaimedValues purported values : `f(z_i)`
+	ClaimedValues []fr.Element
+}
+
 // Verify a single KZG proof. See [verify_kzg_proof_impl]. Returns `nil` if verification was successful, an error
 // otherwise. If verification failed due to the pairings check it will return [ErrVerifyOpeningProof].
 //
-// Note: We could make this method faster by storing pre-computations for the generators in G1 and G2
-// as we only do scalar multiplications with those in this method.
+// The specs check e(C - [f(z)]G₁, [1]G₂) == e(π, [α - z]G₂). Moving the `z` term to the other side gives
+//
+//	e(C - [f(z)]G₁ + [z]π, [1]G₂) == e(π, [α]G₂)
+//
+// which lets us compute both scalar multiplications in G₁, rather than one of them in G₂, and has the same
+// form as the equation checked by [BatchVerifyMultiPoints]. Both sides are checked using a single multi-pairing.
 //
 // Modified from [gnark-crypto].
 //
 // [verify_kzg_proof_impl]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_impl
 // [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L166
 func Verify(commitment *Commitment, proof *OpeningProof, openKey *OpeningKey) error {
-	// [-1]G₂
-	// It's possible to precompute this, however Negation
-	// is cheap (2 Fp negations), so doing it per verify
-	// should be insignificant compared to the rest of Verify.
-	var negG2 bls12381.G2Affine
-	negG2.Neg(&openKey.GenG2)
-
-	// Convert the G2 generator to Jacobian for
-	// later computations.
-	var genG2Jac bls12381.G2Jac
-	genG2Jac.FromAffine(&openKey.GenG2)
-
-	// This has been changed slightly from the way that gnark-crypto
-	// does it to show the symmetry in the computation required for
-	// G₂ and G₁. This is the way it is done in the specs.
-
-	// [z]G₂
-	var inputPointG2Jac bls12381.G2Jac
+	// [f(z)]G₁
+	var claimedValueG1Jac bls12381.G1Jac
+	var claimedValueBigInt big.Int
+	proof.ClaimedValue.BigInt(&claimedValueBigInt)
+	claimedValueG1Jac.FromAffine(&openKey.GenG1)
+	claimedValueG1Jac.ScalarMultiplication(&claimedValueG1Jac, &claimedValueBigInt)
+
+	// [z]π
+	var inputPointQuotientJac bls12381.G1Jac
 	var pointBigInt big.Int
 	proof.InputPoint.BigInt(&pointBigInt)
-	inputPointG2Jac.ScalarMultiplication(&genG2Jac, &pointBigInt)
+	inputPointQuotientJac.FromAffine(&proof.QuotientCommitment)
+	inputPointQuotientJac.ScalarMultiplication(&inputPointQuotientJac, &pointBigInt)
 
-	// In the specs, this is denoted as `X_minus_z`
+	// [f(α) - f(z) + z * q(α)]G₁
+	var lhsJac bls12381.G1Jac
+	lhsJac.FromAffine(commitment)
+	lhsJac.SubAssign(&claimedValueG1Jac)
+	lhsJac.AddAssign(&inputPointQuotientJac)
+
+	var lhs bls12381.G1Affine
+	lhs.FromJacobian(&lhsJac)
+
+	// [-q(α)]G₁
 	//
-	// [α - z]G₂
-	var alphaMinusZG2Jac bls12381.G2Jac
-	alphaMinusZG2Jac.FromAffine(&openKey.AlphaG2)
-	alphaMinusZG2Jac.SubAssign(&inputPointG2Jac)
+	// Negation is cheap (one Fp negation), so we negate
+	// the quotient rather than one of the G₂ points.
+	var negQuotient bls12381.G1Affine
+	negQuotient.Neg(&proof.QuotientCommitment)
 
-	// [α-z]G₂ (Convert to Affine format)
-	var alphaMinusZG2Aff bls12381.G2Affine
-	alphaMinusZG2Aff.FromJacobian(&alphaMinusZG2Jac)
+	check, err := openKey.pairingCheck(lhs, negQuotient)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
 
-	// [f(z)]G₁
-	var claimedValueG1Jac bls12381.G1Jac
-	var claimedValueBigInt big.Int
-	proof.ClaimedValue.BigInt(&claimedValueBigInt)
-	claimedValueG1Jac.ScalarMultiplicationAffine(&openKey.GenG1, &claimedValueBigInt)
+	return nil
+}
 
-	//  In the specs, this is denoted as `P_minus_y`
-	//
-	// [f(α) - f(z)]G₁
-	var fminusfzG1Jac bls12381.G1Jac
-	fminusfzG1Jac.FromAffine(commitment)
-	fminusfzG1Jac.SubAssign(&claimedValueG1Jac)
+// VerifyMulti verifies a proof that a polynomial evaluates to the claimed values at multiple points. Returns `nil` if
+// verification was successful, an error otherwise. If verification failed due to the pairings check it will return
+// [ErrVerifyOpeningProof].
+//
+// The check is e(C - [I(α)]₁, [1]₂) == e(π, [Z(α)]₂), where I(X) interpolates the claimed values and Z(X) vanishes on
+// the opening points. By bilinearity, e([I(α)]₁, [1]₂) == e([1]₁, [I(α)]₂), so we compute I(α) in G₂ and only
+// need the G₂ powers of the trusted setup. Opening at k points requires k+1 of them.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func VerifyMulti(commitment *Commitment, proof *MultiOpeningProof, openKey *OpeningKey, numGoRoutines int) error {
+	numPoints := len(proof.InputPoints)
+	if numPoints == 0 {
+		return ErrNoOpeningPoints
+	}
+	if numPoints != len(proof.ClaimedValues) {
+		return ErrMismatchedNumEvaluations
+	}
+	if numPoints+1 > len(openKey.G2) {
+		return ErrTooManyOpeningPoints
+	}
+	if hasDuplicates(proof.InputPoints) {
+		return ErrDuplicateOpeningPoints
+	}
+
+	config := ecc.MultiExpConfig{NbTasks: utils.NumGoRoutines(numGoRoutines)}
+
+	// [Z(α)]₂
+	vanishingPoly := vanishingPolynomial(proof.InputPoints)
+	var vanishingG2 bls12381.G2Affine
+	_, err := vanishingG2.MultiExp(openKey.G2[:len(vanishingPoly)], vanishingPoly, config)
+	if err != nil {
+		return err
+	}
+
+	// [I(α)]₂
+	interpolationPoly := interpolatePolynomial(proof.InputPoints, proof.ClaimedValues)
+	var interpolationG2 bls12381.G2Affine
+	_, err = interpolationG2.MultiExp(openKey.G2[:len(interpolationPoly)], interpolationPoly, config)
+	if err != nil {
+		return err
+	}
+
+	// [-1]G₁
+	var negG1 bls12381.G1Affine
+	negG1.Neg(&openKey.GenG1)
 
-	// [f(α) - f(z)]G₁ (Convert to Affine format)
-	var fminusfzG1Aff bls12381.G1Affine
-	fminusfzG1Aff.FromJacobian(&fminusfzG1Jac)
+	// [-π]G₁
+	var negQuotient bls12381.G1Affine
+	negQuotient.Neg(&proof.QuotientCommitment)
 
+	// e(C, [1]₂) * e(-π, [Z(α)]₂) * e([-1]₁, [I(α)]₂) == 1
 	check, err := bls12381.PairingCheck(
-		[]bls12381.G1Affine{fminusfzG1Aff, proof.QuotientCommitment},
-		[]bls12381.G2Affine{negG2, alphaMinusZG2Aff},
+		[]bls12381.G1Affine{*commitment, negQuotient, negG1},
+		[]bls12381.G2Affine{openKey.GenG2, vanishingG2, interpolationG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+
+	return nil
+}
+
+// VerifyDegreeBound verifies a proof, created by [OpenDegreeBound], that the polynomial committed to in `commitment`
+// has degree less than `degreeBound`. polySize is the number of G1 points in the SRS used to create the proof.
+//
+// The check is e(π, [1]₂) == e(C, [α^(polySize - degreeBound)]₂), so the G₂ points of the SRS must contain
+// that power. Otherwise, [ErrDegreeBoundShiftUnavailable] is returned.
+func VerifyDegreeBound(commitment *Commitment, proof *bls12381.G1Affine, degreeBound, polySize uint64, openKey *OpeningKey) error {
+	if degreeBound == 0 || degreeBound > polySize {
+		return ErrInvalidDegreeBound
+	}
+	shift := polySize - degreeBound
+	if shift >= uint64(len(openKey.G2)) {
+		return ErrDegreeBoundShiftUnavailable
+	}
+
+	// [-C]G₁
+	var negCommitment bls12381.G1Affine
+	negCommitment.Neg(commitment)
+
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{*proof, negCommitment},
+		[]bls12381.G2Affine{openKey.GenG2, openKey.G2[shift]},
 	)
 	if err != nil {
 		return err
@@ -102,11 +196,23 @@ func Verify(commitment *Commitment, proof *OpeningProof, openKey *OpeningKey) er
 //   - This method is more efficient than calling [Verify] multiple times.
 //   - Randomness is used to combine multiple proofs into one.
 //
+// Regardless of the batch size, verification costs two multi-exponentiations and a
+// single pairing check with two pairings.
+//
+// numGoRoutines is used to configure the amount of concurrency needed by the multi-exponentiations.
+// Setting this value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+//
+// The random number used to combine the proofs is sampled from `randReader` using [RandomScalar], which
+// MUST be a cryptographically secure source such as crypto/rand.Reader outside of tests.
+//
+// If observer is not nil, it receives the duration of the multi exponentiations and of the pairing check, named
+// [PhaseMultiExp] and [PhasePairing], for batches of at least two proofs.
+//
 // Modified from [gnark-crypto].
 //
 // [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
 // [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L367)
-func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) error {
+func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, numGoRoutines int, randReader io.Reader, observer Observer) error {
 	// Check consistency number of proofs is equal to the number of commitments.
 	if len(commitments) != len(proofs) {
 		return ErrInvalidNumDigests
@@ -131,8 +237,7 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	// compute powers of that random number. This works
 	// since powers will produce a vandermonde matrix
 	// which is linearly independent.
-	var randomNumber fr.Element
-	_, err := randomNumber.SetRandom()
+	randomNumber, err := RandomScalar(randReader)
 	if err != nil {
 		return err
 	}
@@ -140,54 +245,178 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 
 	// Combine random_i*quotient_i
 	var foldedQuotients bls12381.G1Affine
-	quotients := make([]bls12381.G1Affine, len(proofs))
+	quotients := make([]bls12381.G1Affine, batchSize)
 	for i := 0; i < batchSize; i++ {
 		quotients[i].Set(&proofs[i].QuotientCommitment)
 	}
-	config := ecc.MultiExpConfig{}
+	config := ecc.MultiExpConfig{NbTasks: utils.NumGoRoutines(numGoRoutines)}
+	start := startPhase(observer)
 	_, err = foldedQuotients.MultiExp(quotients, randomNumbers, config)
 	if err != nil {
 		return err
 	}
+	endPhase(observer, PhaseMultiExp, batchSize, start)
+
+	// Compute the `lhs` of the first pairing with a single multi-exponentiation:
+	//
+	// Σ random_i*commitment_i + Σ (random_i*point_i)*quotient_i - (Σ random_i*claimedValue_i)*G₁
+	points := make([]bls12381.G1Affine, 2*batchSize+1)
+	scalars := make([]fr.Element, 2*batchSize+1)
+	var foldedEvaluations, tmp fr.Element
+	for i := 0; i < batchSize; i++ {
+		points[i].Set(&commitments[i])
+		scalars[i].Set(&randomNumbers[i])
 
-	// Fold commitments and evaluations using randomness
-	evaluations := make([]fr.Element, batchSize)
-	for i := 0; i < len(randomNumbers); i++ {
-		evaluations[i].Set(&proofs[i].ClaimedValue)
+		points[batchSize+i].Set(&quotients[i])
+		scalars[batchSize+i].Mul(&randomNumbers[i], &proofs[i].InputPoint)
+
+		tmp.Mul(&randomNumbers[i], &proofs[i].ClaimedValue)
+		foldedEvaluations.Add(&foldedEvaluations, &tmp)
 	}
-	foldedCommitments, foldedEvaluations, err := fold(commitments, evaluations, randomNumbers)
+	points[2*batchSize].Set(&openKey.GenG1)
+	scalars[2*batchSize].Neg(&foldedEvaluations)
+
+	var lhs bls12381.G1Affine
+	start = startPhase(observer)
+	_, err = lhs.MultiExp(points, scalars, config)
 	if err != nil {
 		return err
 	}
+	endPhase(observer, PhaseMultiExp, len(points), start)
 
-	// Compute commitment to folded Eval
-	var foldedEvaluationsCommit bls12381.G1Affine
-	var foldedEvaluationsBigInt big.Int
-	foldedEvaluations.BigInt(&foldedEvaluationsBigInt)
-	foldedEvaluationsCommit.ScalarMultiplication(&openKey.GenG1, &foldedEvaluationsBigInt)
+	// `lhs` second pairing
+	foldedQuotients.Neg(&foldedQuotients)
 
-	// Compute F = foldedCommitments - foldedEvaluationsCommit
-	foldedCommitments.Sub(&foldedCommitments, &foldedEvaluationsCommit)
+	start = startPhase(observer)
+	check, err := openKey.pairingCheck(lhs, foldedQuotients)
+	if err != nil {
+		return err
+	}
+	endPhase(observer, PhasePairing, 2, start)
+	if !check {
+		return ErrVerifyOpeningProof
+	}
 
-	// Combine random_i*(point_i*quotient_i)
-	var foldedPointsQuotients bls12381.G1Affine
-	for i := 0; i < batchSize; i++ {
-		randomNumbers[i].Mul(&randomNumbers[i], &proofs[i].InputPoint)
+	return nil
+}
+
+// CosetOpeningProof is a proof that a polynomial f(X) evaluates to the claimed values over a coset hH of the
+// subgroup H of a coset domain. The proof is the commitment to the quotient (f(X) - I(X))/(X^n - h^n), where I(X)
+// interpolates the claimed values over hH and n is the size of H.
+type CosetOpeningProof struct {
+	// Commitment to quotient polynomial (f(X) - I(X))/(X^n - h^n)
+	QuotientCommitment bls12381.G1Affine
+
+	// CosetShift is `h`
+	CosetShift fr.Element
+
+	// ClaimedValues purported values : `f(h * w_i)`, where w_i are the roots of the coset domain in its order
+	ClaimedValues []fr.Element
+}
+
+// BatchVerifyCosets verifies multiple [CosetOpeningProof], where the k'th proof opens the polynomial committed to in
+// commitments[commitmentIndices[k]] over a coset of the subgroup given by `cosetDomain`. Returns `nil` if verification
+// was successful, an error otherwise. If verification failed due to the pairings check it will return
+// [ErrVerifyOpeningProof].
+//
+// For each proof, f(τ) - I(τ) = q(τ)(τ^n - h^n), that is C - [I(τ)]₁ + h^n π = τ^n π. The equations are combined
+// using the powers of a random scalar r sampled from `randReader`, and checked using a single multi-pairing:
+//
+//	e(Σ_i (Σ_{k: commitmentIndices[k] = i} r^k) C_i - [Σ_k r^k I_k(τ)]₁ + Σ_k r^k h_k^n π_k, [1]₂) == e(Σ_k r^k π_k, [τ^n]₂)
+//
+// Each commitment only appears once in the multi-exponentiation however many proofs open it. The interpolation
+// polynomials are computed with an inverse FFT over the coset domain and combined before being committed to, which
+// needs the first n monomial G1 points of the SRS and the G₂ power [τ^n]₂.
+//
+// numGoRoutines is used to configure the amount of concurrency needed by the multi-exponentiations.
+// Setting this value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func BatchVerifyCosets(commitments []Commitment, commitmentIndices []uint64, proofs []CosetOpeningProof, cosetDomain *Domain, monomialCK *CommitKey, openKey *OpeningKey, numGoRoutines int, randReader io.Reader) error {
+	if len(commitmentIndices) != len(proofs) {
+		return ErrInvalidNumDigests
 	}
-	_, err = foldedPointsQuotients.MultiExp(quotients, randomNumbers, config)
+	batchSize := len(proofs)
+	if batchSize == 0 {
+		return nil
+	}
+	n := cosetDomain.Cardinality
+	if n+1 > uint64(len(openKey.G2)) {
+		return ErrTooManyOpeningPoints
+	}
+	if n > uint64(len(monomialCK.G1)) {
+		return ErrInvalidPolynomialSize
+	}
+	for k := range proofs {
+		if commitmentIndices[k] >= uint64(len(commitments)) {
+			return ErrInvalidNumDigests
+		}
+		if uint64(len(proofs[k].ClaimedValues)) != n {
+			return ErrMismatchedNumEvaluations
+		}
+	}
+
+	randomNumber, err := RandomScalar(randReader)
 	if err != nil {
 		return err
 	}
+	randomNumbers := utils.ComputePowers(randomNumber, uint(batchSize))
+
+	// Σ_k r^k I_k(X), where I_k(h_k X) interpolates the claimed values over the coset domain
+	foldedInterpolation := make([]fr.Element, n)
+	for k := range proofs {
+		coeffs := cosetDomain.lagrangeToMonomial(proofs[k].ClaimedValues)
+
+		var shiftInv, power fr.Element
+		shiftInv.Inverse(&proofs[k].CosetShift)
+		power.Set(&randomNumbers[k])
+		for j := range coeffs {
+			coeffs[j].Mul(&coeffs[j], &power)
+			foldedInterpolation[j].Add(&foldedInterpolation[j], &coeffs[j])
+			power.Mul(&power, &shiftInv)
+		}
+	}
 
-	// `lhs` first pairing
-	foldedCommitments.Add(&foldedCommitments, &foldedPointsQuotients)
+	// Compute the `lhs` of the first pairing with a single multi-exponentiation:
+	//
+	// Σ_i weight_i*C_i + Σ_k (r^k*h_k^n)*π_k - [Σ_k r^k I_k(τ)]₁
+	numPoints := len(commitments) + batchSize + len(foldedInterpolation)
+	points := make([]bls12381.G1Affine, numPoints)
+	scalars := make([]fr.Element, numPoints)
+	copy(points, commitments)
+	for k := range proofs {
+		i := commitmentIndices[k]
+		scalars[i].Add(&scalars[i], &randomNumbers[k])
+	}
+	quotients := make([]bls12381.G1Affine, batchSize)
+	exponent := big.NewInt(0).SetUint64(n)
+	for k := range proofs {
+		quotients[k].Set(&proofs[k].QuotientCommitment)
+		points[len(commitments)+k].Set(&proofs[k].QuotientCommitment)
+
+		var shiftPower fr.Element
+		shiftPower.Exp(proofs[k].CosetShift, exponent)
+		scalars[len(commitments)+k].Mul(&randomNumbers[k], &shiftPower)
+	}
+	copy(points[len(commitments)+batchSize:], monomialCK.G1[:n])
+	for j := range foldedInterpolation {
+		scalars[len(commitments)+batchSize+j].Neg(&foldedInterpolation[j])
+	}
 
-	// `lhs` second pairing
+	config := ecc.MultiExpConfig{NbTasks: utils.NumGoRoutines(numGoRoutines)}
+	var lhs bls12381.G1Affine
+	if _, err := lhs.MultiExp(points, scalars, config); err != nil {
+		return err
+	}
+
+	// -Σ_k r^k π_k for the second pairing
+	var foldedQuotients bls12381.G1Affine
+	if _, err := foldedQuotients.MultiExp(quotients, randomNumbers, config); err != nil {
+		return err
+	}
 	foldedQuotients.Neg(&foldedQuotients)
 
 	check, err := bls12381.PairingCheck(
-		[]bls12381.G1Affine{foldedCommitments, foldedQuotients},
-		[]bls12381.G2Affine{openKey.GenG2, openKey.AlphaG2},
+		[]bls12381.G1Affine{lhs, foldedQuotients},
+		[]bls12381.G2Affine{openKey.GenG2, openKey.G2[n]},
 	)
 	if err != nil {
 		return err
@@ -199,31 +428,24 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	return nil
 }
 
-// fold computes two inner products with the same factors:
-//
-//   - Between commitments and factors; This is a multi-exponentiation.
-//   - Between evaluations and factors; This is a dot product.
+// RandomScalar samples a uniformly random field element using the bytes read from `randReader`.
 //
-// Modified slightly from [gnark-crypto].
+// It reads 32 bytes at a time and clears the most significant bit, since the scalar field order is
+// less than 2^255. Values which are not less than the order are rejected and sampled again, which
+// happens with probability ~0.1 each time, so the result is uniform rather than slightly biased.
 //
-// [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L464
-func fold(commitments []Commitment, evaluations, factors []fr.Element) (Commitment, fr.Element, error) {
-	// Length inconsistency between commitments and evaluations should have been done before calling this function
-	batchSize := len(commitments)
-
-	// Fold the claimed values
-	var foldedEvaluations, tmp fr.Element
-	for i := 0; i < batchSize; i++ {
-		tmp.Mul(&evaluations[i], &factors[i])
-		foldedEvaluations.Add(&foldedEvaluations, &tmp)
+// If reading from `randReader` fails, an error wrapping [ErrRandomSource] is returned.
+func RandomScalar(randReader io.Reader) (fr.Element, error) {
+	var buf [fr.Bytes]byte
+	for {
+		if _, err := io.ReadFull(randReader, buf[:]); err != nil {
+			return fr.Element{}, fmt.Errorf("%w: %v", ErrRandomSource, err)
+		}
+		buf[0] &= 0x7f
+
+		scalar, err := utils.ReduceCanonicalBigEndian(buf[:])
+		if err == nil {
+			return scalar, nil
+		}
 	}
-
-	// Fold the commitments
-	var foldedCommitments Commitment
-	_, err := foldedCommitments.MultiExp(commitments, factors, ecc.MultiExpConfig{})
-	if err != nil {
-		return foldedCommitments, foldedEvaluations, err
-	}
-
-	return foldedCommitments, foldedEvaluations, nil
 }
@@ -0,0 +1,96 @@
+package kzg
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The methods in this file operate on polynomials in monomial form, ie a list of
+// coefficients where the i'th element is the coefficient of X^i.
+//
+// These are only used for the small polynomials which appear when opening at
+// multiple points. The polynomials that we commit to are always in lagrange form.
+
+// vanishingPolynomial returns the monomial form of Z(X) = (X - z_0)(X - z_1)...(X - z_{k-1}).
+//
+// The result has len(points) + 1 coefficients.
+func vanishingPolynomial(points []fr.Element) []fr.Element {
+	coeffs := make([]fr.Element, len(points)+1)
+	coeffs[0].SetOne()
+
+	// Multiply the running product by (X - z) for each point z.
+	var tmp fr.Element
+	for i := 0; i < len(points); i++ {
+		for j := i + 1; j > 0; j-- {
+			tmp.Mul(&coeffs[j], &points[i])
+			coeffs[j].Sub(&coeffs[j-1], &tmp)
+		}
+		coeffs[0].Mul(&coeffs[0], &points[i])
+		coeffs[0].Neg(&coeffs[0])
+	}
+
+	return coeffs
+}
+
+// interpolatePolynomial returns the monomial form of the unique polynomial I(X) of degree
+// less than len(points) such that I(points[i]) = values[i].
+//
+// The points are assumed to be distinct and len(points) == len(values).
+func interpolatePolynomial(points, values []fr.Element) []fr.Element {
+	k := len(points)
+	result := make([]fr.Element, k)
+
+	vanishing := vanishingPolynomial(points)
+	denominators := lagrangeDenominatorsInverse(points)
+
+	for i := 0; i < k; i++ {
+		// Compute Z(X) / (X - z_i) using synthetic division
+		quotient := make([]fr.Element, k)
+		quotient[k-1].Set(&vanishing[k])
+		for j := k - 1; j > 0; j-- {
+			quotient[j-1].Mul(&quotient[j], &points[i])
+			quotient[j-1].Add(&quotient[j-1], &vanishing[j])
+		}
+
+		// Scale by values[i] / Z'(z_i) and accumulate
+		var scale fr.Element
+		scale.Mul(&values[i], &denominators[i])
+		for j := 0; j < k; j++ {
+			var term fr.Element
+			term.Mul(&quotient[j], &scale)
+			result[j].Add(&result[j], &term)
+		}
+	}
+
+	return result
+}
+
+// lagrangeDenominatorsInverse returns 1 / Z'(z_i) = 1 / ∏_{j != i} (z_i - z_j) for each point z_i.
+//
+// The points are assumed to be distinct.
+func lagrangeDenominatorsInverse(points []fr.Element) []fr.Element {
+	denominators := make([]fr.Element, len(points))
+	for i := 0; i < len(points); i++ {
+		denominators[i].SetOne()
+		for j := 0; j < len(points); j++ {
+			if i == j {
+				continue
+			}
+			var diff fr.Element
+			diff.Sub(&points[i], &points[j])
+			denominators[i].Mul(&denominators[i], &diff)
+		}
+	}
+	return fr.BatchInvert(denominators)
+}
+
+// hasDuplicates returns true if any element appears more than once in points.
+func hasDuplicates(points []fr.Element) bool {
+	seen := make(map[fr.Element]struct{}, len(points))
+	for _, point := range points {
+		if _, ok := seen[point]; ok {
+			return true
+		}
+		seen[point] = struct{}{}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestVanishingPolynomialSmoke(t *testing.T) {
+	points := []fr.Element{fr.NewElement(2), fr.NewElement(3), fr.NewElement(7)}
+	vanishing := vanishingPolynomial(points)
+
+	if len(vanishing) != len(points)+1 {
+		t.Fatalf("expected %d coefficients, got %d", len(points)+1, len(vanishing))
+	}
+
+	for _, point := range points {
+		eval := evaluateMonomial(vanishing, point)
+		if !eval.IsZero() {
+			t.Error("vanishing polynomial should be zero on the given points")
+		}
+	}
+
+	notAPoint := fr.NewElement(5)
+	eval := evaluateMonomial(vanishing, notAPoint)
+	if eval.IsZero() {
+		t.Error("vanishing polynomial should not be zero outside of the given points")
+	}
+}
+
+func TestInterpolatePolynomialSmoke(t *testing.T) {
+	points := []fr.Element{fr.NewElement(2), fr.NewElement(3), fr.NewElement(7), fr.NewElement(11)}
+	values := []fr.Element{fr.NewElement(100), fr.NewElement(0), fr.NewElement(42), fr.NewElement(1)}
+
+	interpolated := interpolatePolynomial(points, values)
+	if len(interpolated) != len(points) {
+		t.Fatalf("expected %d coefficients, got %d", len(points), len(interpolated))
+	}
+
+	for i := 0; i < len(points); i++ {
+		eval := evaluateMonomial(interpolated, points[i])
+		if !eval.Equal(&values[i]) {
+			t.Errorf("interpolated polynomial does not match the value at index %d", i)
+		}
+	}
+}
+
+func evaluateMonomial(coeffs []fr.Element, x fr.Element) fr.Element {
+	var result fr.Element
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result.Mul(&result, &x)
+		result.Add(&result, &coeffs[i])
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package kzg
+
+import "time"
+
+// Names of the phases reported to an [Observer].
+const (
+	PhaseMultiExp = "MultiExp"
+	PhasePairing  = "Pairing"
+)
+
+// Observer receives the duration of the phases of the verification functions which accept one. A nil Observer
+// disables the reporting.
+type Observer interface {
+	OnOperation(name string, itemCount int, d time.Duration)
+}
+
+// startPhase returns the start time of a phase, or the zero time if observer is nil so that the clock is not read.
+func startPhase(observer Observer) time.Time {
+	if observer == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// endPhase reports the duration of the phase started at `start` to observer, if it is not nil.
+func endPhase(observer Observer, name string, itemCount int, start time.Time) {
+	if observer != nil {
+		observer.OnOperation(name, itemCount, time.Since(start))
+	}
+}
@@ -0,0 +1,126 @@
+package kzg
+
+import (
+	"math/bits"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// In this file we implement the erasure decoding of the Reed-Solomon extension computed
+// by [Domain.ExtendPolynomial], given the evaluations over at least half of its cosets.
+//
+// Let E(X) be the polynomial which agrees with the extension on the known cosets and is zero
+// on the missing ones, and let Z(X) be the polynomial vanishing exactly on the missing cosets.
+// Then (E*Z)(X) = (P*Z)(X) on the whole extended domain, where P is the polynomial we look for,
+// and since deg(P*Z) is less than the size of the extended domain, both are equal as polynomials.
+// We recover P by dividing E*Z by Z pointwise over a shifted copy of the extended domain, where
+// Z does not vanish.
+//
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#recover_polynomialcoeff
+
+// recoveryCosetShift is the shift of the coset of the extended domain over which we divide by
+// the vanishing polynomial. It is a generator of the multiplicative group of the field, so it is
+// not a root of unity of the extended domain.
+const recoveryCosetShift = 7
+
+// RecoverPolynomial recovers the polynomial `p` from the evaluations over some of the cosets of size cosetSize which
+// make up the domain twice as large as `domain`.
+//
+// The cosets are the chunks of cosetSize evaluations returned by [Domain.ExtendPolynomial]: cosetEvaluations[i]
+// holds the chunk at index cosetIndices[i]. The indices must be distinct and cover at least half of the chunks. The
+// polynomial is returned in lagrange form, ordered in the same way as domain.Roots.
+//
+// If the known evaluations are not those of a polynomial of degree less than domain.Cardinality, the result is still
+// a polynomial, but it does not agree with them.
+func (domain *Domain) RecoverPolynomial(cosetSize uint64, cosetIndices []uint64, cosetEvaluations [][]fr.Element) (Polynomial, error) {
+	extendedSize := 2 * domain.Cardinality
+	if !utils.IsPowerOfTwo(cosetSize) || cosetSize > domain.Cardinality {
+		return nil, ErrInvalidCosetSize
+	}
+	numCosets := extendedSize / cosetSize
+	if len(cosetIndices) != len(cosetEvaluations) {
+		return nil, ErrMismatchedNumEvaluations
+	}
+
+	// 1. Place the known evaluations in the extension, in bit-reversed order
+	//
+	known := make([]bool, numCosets)
+	extended := make([]fr.Element, extendedSize)
+	for i, cosetIndex := range cosetIndices {
+		if cosetIndex >= numCosets || known[cosetIndex] {
+			return nil, ErrInvalidCosetIndices
+		}
+		if uint64(len(cosetEvaluations[i])) != cosetSize {
+			return nil, ErrMismatchedNumEvaluations
+		}
+		known[cosetIndex] = true
+		copy(extended[cosetIndex*cosetSize:], cosetEvaluations[i])
+	}
+	if uint64(len(cosetIndices)) < numCosets/2 {
+		return nil, ErrNotEnoughCosets
+	}
+	bitReverse(extended)
+
+	// 2. Compute the vanishing polynomial of the missing cosets
+	//
+	// The chunk at index r is the coset {x : x^cosetSize = w^bitreverse(r)} where w is a
+	// primitive numCosets'th root of unity, so Z(X) = Π (X^cosetSize - w^bitreverse(r)).
+	var missingRoots []fr.Element
+	cosetsDomain := NewDomain(numCosets)
+	shiftCorrection := 64 - bits.TrailingZeros64(numCosets)
+	for r := uint64(0); r < numCosets; r++ {
+		if !known[r] {
+			missingRoots = append(missingRoots, cosetsDomain.Roots[bits.Reverse64(r)>>shiftCorrection])
+		}
+	}
+	shortVanishingPoly := vanishingPolynomial(missingRoots)
+	vanishingPoly := make([]fr.Element, extendedSize)
+	for i := range shortVanishingPoly {
+		vanishingPoly[uint64(i)*cosetSize] = shortVanishingPoly[i]
+	}
+
+	// 3. Compute E*Z in monomial form
+	//
+	extendedDomain := NewDomain(extendedSize)
+	vanishingEvals := extendedDomain.FftFr(vanishingPoly)
+	for i := range extended {
+		extended[i].Mul(&extended[i], &vanishingEvals[i])
+	}
+	extendedTimesVanishing := extendedDomain.IfftFr(extended)
+
+	// 4. Divide E*Z by Z over the shifted domain
+	//
+	var shift, shiftInv fr.Element
+	shift.SetUint64(recoveryCosetShift)
+	shiftInv.Inverse(&shift)
+
+	scaleByPowers(extendedTimesVanishing, shift)
+	scaleByPowers(vanishingPoly, shift)
+	numeratorEvals := extendedDomain.FftFr(extendedTimesVanishing)
+	denominatorEvals := fr.BatchInvert(extendedDomain.FftFr(vanishingPoly))
+	for i := range numeratorEvals {
+		numeratorEvals[i].Mul(&numeratorEvals[i], &denominatorEvals[i])
+	}
+	coeffs := extendedDomain.IfftFr(numeratorEvals)
+	scaleByPowers(coeffs, shiftInv)
+
+	// 5. Evaluate P over the domain
+	//
+	p := domain.FftFr(coeffs[:domain.Cardinality])
+	if domain.isBitReversed {
+		bitReverse(p)
+	}
+
+	return p, nil
+}
+
+// scaleByPowers multiplies the i'th coefficient of the polynomial by factor^i, so that
+// evaluating the result at x is the same as evaluating the polynomial at factor*x.
+func scaleByPowers(coeffs []fr.Element, factor fr.Element) {
+	power := fr.One()
+	for i := range coeffs {
+		coeffs[i].Mul(&coeffs[i], &power)
+		power.Mul(&power, &factor)
+	}
+}
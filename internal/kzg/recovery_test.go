@@ -0,0 +1,76 @@
+package kzg
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestRecoverPolynomial(t *testing.T) {
+	n := uint64(64)
+	cosetSize := uint64(4)
+	numCosets := 2 * n / cosetSize
+	rng := rand.New(rand.NewSource(42))
+
+	for _, reversed := range []bool{false, true} {
+		domain := NewDomain(n)
+		if reversed {
+			domain.ReverseRoots()
+		}
+		p := randPoly(t, *domain)
+		extended, err := domain.ExtendPolynomial(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Any half of the cosets is enough, in any order
+		for _, numKnown := range []uint64{numCosets / 2, numCosets/2 + 3, numCosets} {
+			var cosetIndices []uint64
+			var cosetEvaluations [][]fr.Element
+			for _, r := range rng.Perm(int(numCosets))[:numKnown] {
+				cosetIndices = append(cosetIndices, uint64(r))
+				cosetEvaluations = append(cosetEvaluations, extended[uint64(r)*cosetSize:uint64(r+1)*cosetSize])
+			}
+
+			recovered, err := domain.RecoverPolynomial(cosetSize, cosetIndices, cosetEvaluations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range p {
+				if !p[i].Equal(&recovered[i]) {
+					t.Fatalf("incorrect evaluation at index %d (reversed domain: %v, known cosets: %d)", i, reversed, numKnown)
+				}
+			}
+		}
+	}
+
+	domain := NewDomain(n)
+	cosetIndices := make([]uint64, numCosets/2)
+	cosetEvaluations := make([][]fr.Element, numCosets/2)
+	for i := range cosetIndices {
+		cosetIndices[i] = uint64(i)
+		cosetEvaluations[i] = make([]fr.Element, cosetSize)
+	}
+
+	if _, err := domain.RecoverPolynomial(cosetSize, cosetIndices[1:], cosetEvaluations[1:]); err != ErrNotEnoughCosets {
+		t.Fatalf("expected %v, got %v", ErrNotEnoughCosets, err)
+	}
+	if _, err := domain.RecoverPolynomial(cosetSize, cosetIndices, cosetEvaluations[1:]); err != ErrMismatchedNumEvaluations {
+		t.Fatalf("expected %v, got %v", ErrMismatchedNumEvaluations, err)
+	}
+	if _, err := domain.RecoverPolynomial(3, cosetIndices, cosetEvaluations); err != ErrInvalidCosetSize {
+		t.Fatalf("expected %v, got %v", ErrInvalidCosetSize, err)
+	}
+
+	duplicate := append([]uint64{}, cosetIndices...)
+	duplicate[1] = duplicate[0]
+	if _, err := domain.RecoverPolynomial(cosetSize, duplicate, cosetEvaluations); err != ErrInvalidCosetIndices {
+		t.Fatalf("expected %v, got %v", ErrInvalidCosetIndices, err)
+	}
+	outOfRange := append([]uint64{}, cosetIndices...)
+	outOfRange[1] = numCosets
+	if _, err := domain.RecoverPolynomial(cosetSize, outOfRange, cosetEvaluations); err != ErrInvalidCosetIndices {
+		t.Fatalf("expected %v, got %v", ErrInvalidCosetIndices, err)
+	}
+}
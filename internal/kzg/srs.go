@@ -1,6 +1,9 @@
 package kzg
 
 import (
+	"sync"
+	"unsafe"
+
 	"github.com/RiemaLabs/go-kzg-4844/internal/multiexp"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 )
@@ -16,6 +19,66 @@ type OpeningKey struct {
 	// This is the degree-1 G_2 element in the trusted setup.
 	// In the specs, this is denoted as `KZG_SETUP_G2[1]`
 	AlphaG2 bls12381.G2Affine
+	// These are all of the G_2 elements in the trusted setup.
+	// In the specs, this is denoted as `KZG_SETUP_G2`.
+	//
+	// Only the first two are needed for single point openings.
+	// The rest are needed to verify openings at multiple points.
+	G2 []bls12381.G2Affine
+
+	// lines holds the optional precomputed Miller loop lines for GenG2 and AlphaG2.
+	// See [OpeningKey.PrecomputePairingLines].
+	lines *pairingLines
+}
+
+// PrecomputePairingLines precomputes the line functions of the Miller loop for GenG2 and AlphaG2,
+// which are the G₂ points used to verify single point opening proofs. The lines take ~48KB and
+// make [Verify] and [BatchVerifyMultiPoints] faster, since they are then not recomputed for every
+// pairing.
+//
+// This must be called again if GenG2 or AlphaG2 are modified.
+func (o *OpeningKey) PrecomputePairingLines() {
+	o.lines = &pairingLines{
+		bls12381.PrecomputeLines(o.GenG2),
+		bls12381.PrecomputeLines(o.AlphaG2),
+	}
+}
+
+// MemorySize returns the number of bytes held by the G2 points of the opening key and
+// by its precomputed lines, which is zero if they were not computed.
+func (o *OpeningKey) MemorySize() (points, lines int) {
+	points = len(o.G2) * int(unsafe.Sizeof(bls12381.G2Affine{}))
+	if o.lines != nil {
+		lines = int(unsafe.Sizeof(*o.lines))
+	}
+	return points, lines
+}
+
+// pairingCheck returns whether e(genG2Term, GenG2) * e(alphaG2Term, AlphaG2) == 1,
+// using the precomputed lines if they are available.
+func (o *OpeningKey) pairingCheck(genG2Term, alphaG2Term bls12381.G1Affine) (bool, error) {
+	g1Points := []bls12381.G1Affine{genG2Term, alphaG2Term}
+	if o.lines == nil {
+		return bls12381.PairingCheck(g1Points, []bls12381.G2Affine{o.GenG2, o.AlphaG2})
+	}
+
+	// gnark-crypto evaluates the lines at the G₁ points in place, so we pass
+	// a copy to keep the precomputed lines intact. The copies are pooled,
+	// since they are as large as the lines and needed for every check.
+	lines := pairingLinesPool.Get().(*pairingLines)
+	defer pairingLinesPool.Put(lines)
+	*lines = *o.lines
+	return bls12381.PairingCheckFixedQ(g1Points, lines[:])
+}
+
+// pairingLines holds the Miller loop lines of GenG2 and AlphaG2, see [OpeningKey.PrecomputePairingLines].
+type pairingLines = [2][2][len(bls12381.LoopCounter) - 1]bls12381.LineEvaluationAff
+
+// pairingLinesPool holds the buffers into which [OpeningKey.pairingCheck] copies the precomputed lines.
+var pairingLinesPool = sync.Pool{
+	New: func() any {
+		return new(pairingLines)
+	},
 }
 
 // CommitKey holds the data needed to commit to polynomials and by proxy make opening proofs
@@ -25,12 +88,44 @@ type CommitKey struct {
 	// we processed it with `ifftG1`. Once we compute `ifftG1`
 	// then this list is denoted as `KZG_SETUP_LAGRANGE` in the specs.
 	G1 []bls12381.G1Affine
+
+	// fixedBaseTable holds optional precomputations over G1
+	// which speed up committing. See [CommitKey.PrecomputeFixedBaseTable].
+	fixedBaseTable *multiexp.FixedBaseTable
 }
 
 // ReversePoints applies the bit reversal permutation
 // to the G1 points stored inside the CommitKey c.
+//
+// This discards the fixed base table, if one was computed.
 func (c *CommitKey) ReversePoints() {
 	bitReverse(c.G1)
+	c.fixedBaseTable = nil
+}
+
+// PrecomputeFixedBaseTable computes a fixed base table with the given window size for the G1 points,
+// which will then be used by [Commit] instead of a generic multi exponentiation.
+// See [multiexp.FixedBaseTable] for the memory and time trade-offs.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func (c *CommitKey) PrecomputeFixedBaseTable(windowBits, numGoRoutines int) error {
+	table, err := multiexp.NewFixedBaseTable(c.G1, windowBits, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	c.fixedBaseTable = table
+	return nil
+}
+
+// MemorySize returns the number of bytes held by the G1 points of the commit key and
+// by its fixed base table, which is zero if none was computed.
+func (c *CommitKey) MemorySize() (points, table int) {
+	points = len(c.G1) * int(unsafe.Sizeof(bls12381.G1Affine{}))
+	if c.fixedBaseTable != nil {
+		table = c.fixedBaseTable.MemorySize()
+	}
+	return points, table
 }
 
 // SRS holds the structured reference string (SRS) for making
@@ -48,12 +143,20 @@ type SRS struct {
 // Commit commits to a polynomial using a multi exponentiation with the
 // Commitment key.
 //
+// The i'th evaluation of p is paired with ck.G1[i], so the commit key must be in the same order
+// as the polynomial. When the polynomials are in bit-reversed order, the points are permuted once
+// using [CommitKey.ReversePoints], rather than for every commitment.
+//
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
-// value to a negative number or 0 will make it default to the number of CPUs.
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
 func Commit(p Polynomial, ck *CommitKey, numGoRoutines int) (*Commitment, error) {
 	if len(p) == 0 || len(p) > len(ck.G1) {
 		return nil, ErrInvalidPolynomialSize
 	}
 
+	if ck.fixedBaseTable != nil {
+		return multiexp.FixedBaseMultiExp(p, ck.fixedBaseTable, numGoRoutines)
+	}
+
 	return multiexp.MultiExp(p, ck.G1[:len(p)], numGoRoutines)
 }
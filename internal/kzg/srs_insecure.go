@@ -16,7 +16,7 @@ import (
 //
 // This method should not be used in production because as the secret is supplied as input.
 func newLagrangeSRSInsecure(domain Domain, bAlpha *big.Int) (*SRS, error) {
-	return newSRSInsecure(domain, bAlpha, true)
+	return NewSRSInsecure(domain, bAlpha, true)
 }
 
 // newMonomialSRSInsecure creates a new SRS object with the secret `bAlpha`.
@@ -24,14 +24,14 @@ func newLagrangeSRSInsecure(domain Domain, bAlpha *big.Int) (*SRS, error) {
 //
 // This method should not be used in production because as the secret is supplied as input.
 func newMonomialSRSInsecure(domain Domain, bAlpha *big.Int) (*SRS, error) {
-	return newSRSInsecure(domain, bAlpha, false)
+	return NewSRSInsecure(domain, bAlpha, false)
 }
 
-// newSRSInsecure creates a new SRS object with the secret `bAlpha`.
+// NewSRSInsecure creates a new SRS object with the secret `bAlpha`.
 // convertToLagrange controls whether the result is in monomial or Lagrange basis.
 //
 // This method should not be used in production because as the secret is supplied as input.
-func newSRSInsecure(domain Domain, bAlpha *big.Int, convertToLagrange bool) (*SRS, error) {
+func NewSRSInsecure(domain Domain, bAlpha *big.Int, convertToLagrange bool) (*SRS, error) {
 	srs, err := newMonomialSRSInsecureUint64(domain.Cardinality, bAlpha)
 	if err != nil {
 		return nil, err
@@ -83,6 +83,11 @@ func newMonomialSRSInsecureUint64(size uint64, bAlpha *big.Int) (*SRS, error) {
 	g1s := bls12381.BatchScalarMultiplicationG1(&gen1Aff, alphas)
 	copy(commitKey.G1[1:], g1s)
 
+	openKey.G2 = make([]bls12381.G2Affine, size)
+	openKey.G2[0] = gen2Aff
+	g2s := bls12381.BatchScalarMultiplicationG2(&gen2Aff, alphas)
+	copy(openKey.G2[1:], g2s)
+
 	return &SRS{
 		CommitKey:  commitKey,
 		OpeningKey: openKey,
@@ -43,3 +43,40 @@ func TestCommitRegression(t *testing.T) {
 	expectedCommitment := "85bdf872da5b8561d23055d32db3fc86c672b0be7543b8c1e48634af07231bf7ab6385b765750921017cbcdbcd14f8e0"
 	require.Equal(t, expectedCommitment, gotCommitment)
 }
+
+func TestCommitWithFixedBaseTable(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(100))
+	poly := randPoly(t, *domain)
+
+	expected, err := Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	err = srs.CommitKey.PrecomputeFixedBaseTable(8, 0)
+	require.NoError(t, err)
+	got, err := Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// Reversing the points discards the table, since it would no longer match the points
+	srs.CommitKey.ReversePoints()
+	require.Nil(t, srs.CommitKey.fixedBaseTable)
+}
+
+// The points are reversed once, rather than the polynomial for every commitment, which gives the same commitment
+func TestCommitReversedPoints(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(100))
+	poly := randPoly(t, *domain)
+
+	permuted := make(Polynomial, len(poly))
+	copy(permuted, poly)
+	bitReverse(permuted)
+	expected, err := Commit(permuted, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	srs.CommitKey.ReversePoints()
+	got, err := Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+}
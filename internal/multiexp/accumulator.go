@@ -0,0 +1,172 @@
+package multiexp
+
+import (
+	"sync"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// defaultAccumulatorChunkSize is the number of pairs buffered by an [Accumulator] before a partial
+// multi exponentiation is started, if no chunk size is given.
+const defaultAccumulatorChunkSize = 1024
+
+// Accumulator computes a multi exponentiation from pairs of scalars and points which are added
+// over time, for instance while the scalars are being deserialized.
+//
+// The pairs are buffered into chunks of chunkSize pairs, and the multi exponentiation of each full
+// chunk is computed in the background while more pairs are added. At most numGoRoutines partial
+// multi exponentiations run at once: adding a pair blocks while they are all running, so that at
+// most (numGoRoutines + 1) * chunkSize pairs are held in memory. The result is the sum of the
+// partial results, so it is the same as calling [MultiExp] with all of the pairs, in any order.
+//
+// An Accumulator is safe for concurrent use. Pairs added from different go routines are all
+// included in the result, since the order of the pairs does not matter.
+type Accumulator struct {
+	chunkSize int
+
+	// mu guards the current chunk and finalized
+	mu        sync.Mutex
+	scalars   []fr.Element
+	points    []bls12381.G1Affine
+	finalized bool
+
+	// running bounds the number of partial multi exponentiations running at once
+	running chan struct{}
+	wg      sync.WaitGroup
+
+	// resultMu guards the sum of the partial results
+	resultMu sync.Mutex
+	result   bls12381.G1Jac
+	err      error
+}
+
+// NewAccumulator returns an empty [Accumulator].
+//
+// chunkSize is the number of pairs in each partial multi exponentiation. Setting this value to a
+// negative number or 0 will make it default to 1024.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+//
+// Returns an error if the numGoRoutines exceeds 1024.
+func NewAccumulator(chunkSize, numGoRoutines int) (*Accumulator, error) {
+	err := isValidNumGoRoutines(numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+	if chunkSize <= 0 {
+		chunkSize = defaultAccumulatorChunkSize
+	}
+	numGoRoutines = utils.NumGoRoutines(numGoRoutines)
+
+	return &Accumulator{
+		chunkSize: chunkSize,
+		scalars:   make([]fr.Element, 0, chunkSize),
+		points:    make([]bls12381.G1Affine, 0, chunkSize),
+		running:   make(chan struct{}, numGoRoutines),
+	}, nil
+}
+
+// Add adds scalar * point to the result.
+//
+// Returns [ErrAccumulatorFinalized] if [Accumulator.Finalize] has been called.
+func (a *Accumulator) Add(scalar fr.Element, point bls12381.G1Affine) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.finalized {
+		return ErrAccumulatorFinalized
+	}
+
+	a.scalars = append(a.scalars, scalar)
+	a.points = append(a.points, point)
+	if len(a.scalars) == a.chunkSize {
+		a.flush()
+	}
+	return nil
+}
+
+// AddBatch adds scalars[0]*points[0] + ... + scalars[n-1]*points[n-1] to the result.
+//
+// Returns an error wrapping [ErrLengthMismatch] if the slices differ in length, and
+// [ErrAccumulatorFinalized] if [Accumulator.Finalize] has been called.
+func (a *Accumulator) AddBatch(scalars []fr.Element, points []bls12381.G1Affine) error {
+	err := checkLengths(len(scalars), len(points))
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.finalized {
+		return ErrAccumulatorFinalized
+	}
+
+	for len(scalars) > 0 {
+		n := a.chunkSize - len(a.scalars)
+		if n > len(scalars) {
+			n = len(scalars)
+		}
+		a.scalars = append(a.scalars, scalars[:n]...)
+		a.points = append(a.points, points[:n]...)
+		scalars, points = scalars[n:], points[n:]
+
+		if len(a.scalars) == a.chunkSize {
+			a.flush()
+		}
+	}
+	return nil
+}
+
+// Finalize waits for all of the partial multi exponentiations and returns their sum. The result
+// is the identity if no pairs were added.
+//
+// Returns [ErrAccumulatorFinalized] if Finalize has already been called.
+func (a *Accumulator) Finalize() (*bls12381.G1Affine, error) {
+	a.mu.Lock()
+	if a.finalized {
+		a.mu.Unlock()
+		return nil, ErrAccumulatorFinalized
+	}
+	a.finalized = true
+	if len(a.scalars) > 0 {
+		a.flush()
+	}
+	a.mu.Unlock()
+
+	a.wg.Wait()
+	if a.err != nil {
+		return nil, a.err
+	}
+	return new(bls12381.G1Affine).FromJacobian(&a.result), nil
+}
+
+// flush starts the partial multi exponentiation of the current chunk, waiting until fewer than
+// numGoRoutines of them are running. It must be called with a.mu held.
+func (a *Accumulator) flush() {
+	scalars, points := a.scalars, a.points
+	a.scalars = make([]fr.Element, 0, a.chunkSize)
+	a.points = make([]bls12381.G1Affine, 0, a.chunkSize)
+
+	a.running <- struct{}{}
+	a.wg.Add(1)
+	go func() {
+		defer func() {
+			<-a.running
+			a.wg.Done()
+		}()
+
+		partialResult, err := MultiExp(scalars, points, 1)
+
+		a.resultMu.Lock()
+		defer a.resultMu.Unlock()
+		if err != nil {
+			if a.err == nil {
+				a.err = err
+			}
+			return
+		}
+		a.result.AddMixed(partialResult)
+	}()
+}
@@ -0,0 +1,127 @@
+package multiexp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestAccumulatorMatchesMultiExp(t *testing.T) {
+	instanceSize := uint(100)
+	points := genG1Points(instanceSize)
+	scalars := randScalars(instanceSize)
+
+	expected, err := MultiExp(scalars, points, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, chunkSize := range []int{0, 1, 7, 64, 1000} {
+		for _, numGoRoutines := range []int{0, 1, 3} {
+			acc, err := NewAccumulator(chunkSize, numGoRoutines)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Interleave single pairs and batches of various sizes
+			i := 0
+			for _, batchSize := range []int{1, 0, 5, 1, 30, 1, 1, 13} {
+				if batchSize == 1 {
+					err = acc.Add(scalars[i], points[i])
+				} else {
+					err = acc.AddBatch(scalars[i:i+batchSize], points[i:i+batchSize])
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				i += batchSize
+			}
+			if err := acc.AddBatch(scalars[i:], points[i:]); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := acc.Finalize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(expected) {
+				t.Errorf("accumulator is inconsistent with multi-exp (chunk size: %d, go routines: %d)", chunkSize, numGoRoutines)
+			}
+		}
+	}
+}
+
+func TestAccumulatorConcurrentAdd(t *testing.T) {
+	instanceSize := uint(128)
+	points := genG1Points(instanceSize)
+	scalars := randScalars(instanceSize)
+
+	expected, err := MultiExp(scalars, points, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc, err := NewAccumulator(8, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numWorkers := 4
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < int(instanceSize); i += numWorkers {
+				if err := acc.Add(scalars[i], points[i]); err != nil {
+					t.Error(err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	got, err := acc.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(expected) {
+		t.Error("accumulator with concurrent additions is inconsistent with multi-exp")
+	}
+}
+
+func TestAccumulatorInvalidUse(t *testing.T) {
+	if _, err := NewAccumulator(0, 1024); !errors.Is(err, ErrTooManyGoRoutines) {
+		t.Errorf("expected %v but got %v", ErrTooManyGoRoutines, err)
+	}
+
+	acc, err := NewAccumulator(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := genG1Points(2)
+	if err := acc.AddBatch(randScalars(1), points); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected %v but got %v", ErrLengthMismatch, err)
+	}
+
+	// Nothing was added
+	result, err := acc.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsInfinity() {
+		t.Error("result should be identity when no pairs were added")
+	}
+
+	if err := acc.Add(fr.One(), points[0]); !errors.Is(err, ErrAccumulatorFinalized) {
+		t.Errorf("expected %v but got %v", ErrAccumulatorFinalized, err)
+	}
+	if err := acc.AddBatch([]fr.Element{}, []bls12381.G1Affine{}); !errors.Is(err, ErrAccumulatorFinalized) {
+		t.Errorf("expected %v but got %v", ErrAccumulatorFinalized, err)
+	}
+	if _, err := acc.Finalize(); !errors.Is(err, ErrAccumulatorFinalized) {
+		t.Errorf("expected %v but got %v", ErrAccumulatorFinalized, err)
+	}
+}
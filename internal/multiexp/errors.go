@@ -2,4 +2,11 @@ package multiexp
 
 import "errors"
 
-var ErrTooManyGoRoutines = errors.New("cannot configure more than 1024 go routines")
+var (
+	ErrTooManyGoRoutines = errors.New("cannot configure more than 1024 go routines")
+	ErrInvalidWindowBits = errors.New("window size for the fixed base table must be between 4 and 8 bits")
+	ErrTooManyScalars    = errors.New("number of scalars is larger than the number of points in the fixed base table")
+	ErrLengthMismatch    = errors.New("number of scalars differs from the number of points")
+
+	ErrAccumulatorFinalized = errors.New("the accumulator has already been finalized")
+)
@@ -0,0 +1,374 @@
+package multiexp
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// MinWindowBits and MaxWindowBits are the supported range for the window size
+// of a [FixedBaseTable].
+const (
+	MinWindowBits = 4
+	MaxWindowBits = 8
+)
+
+// FixedBaseTable holds precomputed multiples of a fixed list of points,
+// which allows multi exponentiations against those points to skip
+// all of the doublings in a generic MSM.
+//
+// For each point P_i and each window j, the table stores 2^(j*windowBits) * P_i.
+// A scalar s_i is split into windows of windowBits bits, s_i = Σ_j d_ij * 2^(j*windowBits),
+// so that the multi exponentiation becomes Σ_i Σ_j d_ij * (2^(j*windowBits) * P_i).
+// This is a single multi exponentiation with small (windowBits-bit) scalars, which we
+// compute using 2^windowBits - 1 buckets.
+//
+// The table holds numPoints * ceil(255 / windowBits) affine points of 96 bytes each.
+// For 4096 points, this ranges from ~25MB (windowBits = 4) to ~12.6MB (windowBits = 8).
+// A multi exponentiation costs roughly one mixed addition per table entry plus
+// 2^(windowBits+1) additions per go routine to combine the buckets, so both the
+// memory and the time decrease as the window size increases. For windows of 7 or 8
+// bits, the additions are batched in affine form, which makes the multi exponentiation
+// faster than the generic one; for smaller windows it is slower.
+type FixedBaseTable struct {
+	windowBits int
+	numWindows int
+	numPoints  int
+	// points[i*numWindows+j] = 2^(j*windowBits) * P_i
+	points []bls12381.G1Affine
+}
+
+// NewFixedBaseTable precomputes the table for `points` with the given window size.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func NewFixedBaseTable(points []bls12381.G1Affine, windowBits, numGoRoutines int) (*FixedBaseTable, error) {
+	if windowBits < MinWindowBits || windowBits > MaxWindowBits {
+		return nil, ErrInvalidWindowBits
+	}
+	err := isValidNumGoRoutines(numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
+	numWindows := (fr.Bits + windowBits - 1) / windowBits
+	table := &FixedBaseTable{
+		windowBits: windowBits,
+		numWindows: numWindows,
+		numPoints:  len(points),
+		points:     make([]bls12381.G1Affine, len(points)*numWindows),
+	}
+
+	forEachChunk(len(points), numGoRoutines, func(start, end int) {
+		multiples := make([]bls12381.G1Jac, numWindows)
+		for i := start; i < end; i++ {
+			multiples[0].FromAffine(&points[i])
+			for j := 1; j < numWindows; j++ {
+				multiples[j].Set(&multiples[j-1])
+				for k := 0; k < windowBits; k++ {
+					multiples[j].DoubleAssign()
+				}
+			}
+			copy(table.points[i*numWindows:(i+1)*numWindows], bls12381.BatchJacobianToAffineG1(multiples))
+		}
+	})
+
+	return table, nil
+}
+
+// MemorySize returns the number of bytes held by the points of the table.
+func (table *FixedBaseTable) MemorySize() int {
+	return len(table.points) * int(unsafe.Sizeof(bls12381.G1Affine{}))
+}
+
+// NumPoints returns the number of points that the table was created for.
+func (table *FixedBaseTable) NumPoints() int {
+	return table.numPoints
+}
+
+// FixedBaseMultiExp computes scalars[0]*points[0] + ... + scalars[n-1]*points[n-1], where the points
+// are the first n points that the table was created for.
+//
+// It returns an error if there are more scalars than points in the table. The result is the same as
+// calling [MultiExp] with the first n points.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func FixedBaseMultiExp(scalars []fr.Element, table *FixedBaseTable, numGoRoutines int) (*bls12381.G1Affine, error) {
+	err := isValidNumGoRoutines(numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+	if len(scalars) > table.numPoints {
+		return nil, ErrTooManyScalars
+	}
+
+	var mu sync.Mutex
+	var result bls12381.G1Jac
+	forEachChunk(len(scalars), numGoRoutines, func(start, end int) {
+		var partialResult bls12381.G1Jac
+		if table.windowBits >= batchAffineMinWindowBits {
+			partialResult = table.bucketSumBatchAffine(scalars, start, end)
+		} else {
+			partialResult = table.bucketSumJacobian(scalars, start, end)
+		}
+
+		mu.Lock()
+		result.AddAssign(&partialResult)
+		mu.Unlock()
+	})
+
+	return new(bls12381.G1Affine).FromJacobian(&result), nil
+}
+
+// batchAffineMinWindowBits is the smallest window size for which we accumulate the buckets
+// using batched affine additions. For smaller windows, there are too few buckets to fill
+// a batch with additions to distinct buckets, so mixed Jacobian additions are faster.
+const batchAffineMinWindowBits = 7
+
+// bucketSumJacobian computes Σ_i scalars[i] * P_i for i in [start, end), keeping the buckets in Jacobian form.
+func (table *FixedBaseTable) bucketSumJacobian(scalars []fr.Element, start, end int) bls12381.G1Jac {
+	numBuckets := (1 << table.windowBits) - 1
+	windowMask := uint64(numBuckets)
+
+	// buckets[d-1] holds the sum of all table points whose digit is d
+	buckets := make([]bls12381.G1Jac, numBuckets)
+	for i := start; i < end; i++ {
+		// Digits are taken from the regular (non-Montgomery) form of the scalar
+		limbs := scalars[i].Bits()
+		for j := 0; j < table.numWindows; j++ {
+			digit := extractWindow(limbs, j*table.windowBits, windowMask)
+			if digit != 0 {
+				buckets[digit-1].AddMixed(&table.points[i*table.numWindows+j])
+			}
+		}
+	}
+
+	// Σ_d d * buckets[d-1] using a running sum
+	var runningSum, sum bls12381.G1Jac
+	for d := numBuckets - 1; d >= 0; d-- {
+		runningSum.AddAssign(&buckets[d])
+		sum.AddAssign(&runningSum)
+	}
+	return sum
+}
+
+// bucketSumBatchAffine computes Σ_i scalars[i] * P_i for i in [start, end), keeping the buckets in affine form.
+func (table *FixedBaseTable) bucketSumBatchAffine(scalars []fr.Element, start, end int) bls12381.G1Jac {
+	numBuckets := (1 << table.windowBits) - 1
+	windowMask := uint64(numBuckets)
+
+	// buckets.points[d-1] holds the sum of all table points whose digit is d
+	buckets := newBatchAffineBuckets(numBuckets)
+	for i := start; i < end; i++ {
+		limbs := scalars[i].Bits()
+		for j := 0; j < table.numWindows; j++ {
+			digit := extractWindow(limbs, j*table.windowBits, windowMask)
+			if digit != 0 {
+				buckets.add(int(digit-1), &table.points[i*table.numWindows+j])
+			}
+		}
+	}
+	return buckets.weightedSum()
+}
+
+// batchAffineBuckets accumulates points into buckets kept in affine form.
+//
+// An affine addition needs a field inversion, which we amortize by collecting
+// additions to distinct buckets into a batch and inverting all of the denominators
+// at once using Montgomery's trick. This makes each addition cheaper than a
+// mixed addition in Jacobian coordinates.
+//
+// An addition to a bucket which is already in the current batch is retried once
+// all of the points have been added. If the bucket is in the batch again, the point
+// is accumulated into a second bucket kept in Jacobian form instead. Retrying it until
+// it fits in a batch would take quadratic time when most of the points fall into a
+// few buckets, for instance when many of the scalars are equal.
+type batchAffineBuckets struct {
+	points []bls12381.G1Affine
+
+	// Additions in the current batch; inBatch[b] is set if bucket b is in the batch.
+	batchBuckets []int
+	batchPoints  []*bls12381.G1Affine
+	inBatch      []bool
+
+	// Additions which target a bucket already in the current batch.
+	// These are retried once, and then added to the overflow buckets.
+	pendingBuckets []int
+	pendingPoints  []*bls12381.G1Affine
+	retrying       bool
+	overflow       []bls12381.G1Jac
+
+	// Scratch space for the batch inversion
+	denominators []fp.Element
+	inverses     []fp.Element
+}
+
+func newBatchAffineBuckets(numBuckets int) *batchAffineBuckets {
+	batchSize := numBuckets / 4
+	if batchSize < 4 {
+		batchSize = 4
+	}
+	return &batchAffineBuckets{
+		points:       make([]bls12381.G1Affine, numBuckets),
+		batchBuckets: make([]int, 0, batchSize),
+		batchPoints:  make([]*bls12381.G1Affine, 0, batchSize),
+		inBatch:      make([]bool, numBuckets),
+		overflow:     make([]bls12381.G1Jac, numBuckets),
+		denominators: make([]fp.Element, batchSize),
+		inverses:     make([]fp.Element, batchSize),
+	}
+}
+
+// add schedules the addition of point to the given bucket.
+func (b *batchAffineBuckets) add(bucket int, point *bls12381.G1Affine) {
+	if b.inBatch[bucket] {
+		if b.retrying {
+			b.overflow[bucket].AddMixed(point)
+		} else {
+			b.pendingBuckets = append(b.pendingBuckets, bucket)
+			b.pendingPoints = append(b.pendingPoints, point)
+		}
+		return
+	}
+
+	bucketPoint := &b.points[bucket]
+	if bucketPoint.IsInfinity() {
+		bucketPoint.Set(point)
+		return
+	}
+	if point.IsInfinity() {
+		return
+	}
+	if bucketPoint.X.Equal(&point.X) {
+		// Doubling, or the result is the point at infinity.
+		// This is very unlikely, so we do not bother batching it.
+		bucketPoint.Add(bucketPoint, point)
+		return
+	}
+
+	b.inBatch[bucket] = true
+	b.batchBuckets = append(b.batchBuckets, bucket)
+	b.batchPoints = append(b.batchPoints, point)
+	if len(b.batchBuckets) == cap(b.batchBuckets) {
+		b.flush()
+	}
+}
+
+// flush computes all of the additions in the current batch.
+func (b *batchAffineBuckets) flush() {
+	batchSize := len(b.batchBuckets)
+	if batchSize == 0 {
+		return
+	}
+
+	// λ = (y2 - y1) / (x2 - x1)
+	var accumulator fp.Element
+	accumulator.SetOne()
+	for i := 0; i < batchSize; i++ {
+		b.denominators[i].Sub(&b.batchPoints[i].X, &b.points[b.batchBuckets[i]].X)
+		b.inverses[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &b.denominators[i])
+	}
+	accumulator.Inverse(&accumulator)
+	for i := batchSize - 1; i >= 0; i-- {
+		b.inverses[i].Mul(&b.inverses[i], &accumulator)
+		accumulator.Mul(&accumulator, &b.denominators[i])
+	}
+
+	var lambda, tmp fp.Element
+	var sum bls12381.G1Affine
+	for i := 0; i < batchSize; i++ {
+		p := b.batchPoints[i]
+		r := &b.points[b.batchBuckets[i]]
+
+		lambda.Sub(&p.Y, &r.Y)
+		lambda.Mul(&lambda, &b.inverses[i])
+
+		// x3 = λ² - x1 - x2
+		sum.X.Square(&lambda)
+		sum.X.Sub(&sum.X, &r.X)
+		sum.X.Sub(&sum.X, &p.X)
+		// y3 = λ(x1 - x3) - y1
+		tmp.Sub(&r.X, &sum.X)
+		sum.Y.Mul(&lambda, &tmp)
+		sum.Y.Sub(&sum.Y, &r.Y)
+		r.Set(&sum)
+
+		b.inBatch[b.batchBuckets[i]] = false
+	}
+
+	b.batchBuckets = b.batchBuckets[:0]
+	b.batchPoints = b.batchPoints[:0]
+}
+
+// weightedSum computes all of the scheduled additions and returns Σ_d d * bucket[d-1].
+func (b *batchAffineBuckets) weightedSum() bls12381.G1Jac {
+	b.flush()
+	b.retrying = true
+	for i := range b.pendingBuckets {
+		b.add(b.pendingBuckets[i], b.pendingPoints[i])
+	}
+	b.flush()
+
+	// Σ_d d * buckets[d-1] using a running sum
+	var runningSum, sum bls12381.G1Jac
+	for d := len(b.points) - 1; d >= 0; d-- {
+		runningSum.AddMixed(&b.points[d])
+		runningSum.AddAssign(&b.overflow[d])
+		sum.AddAssign(&runningSum)
+	}
+	return sum
+}
+
+// extractWindow returns the bits [offset, offset+windowBits) of the 256-bit little-endian integer `limbs`.
+//
+// windowMask is 2^windowBits - 1.
+func extractWindow(limbs [fr.Limbs]uint64, offset int, windowMask uint64) uint64 {
+	limbIndex := offset / 64
+	bitIndex := uint(offset % 64)
+
+	window := limbs[limbIndex] >> bitIndex
+	// The window may span two limbs
+	if bitIndex != 0 && limbIndex+1 < fr.Limbs {
+		window |= limbs[limbIndex+1] << (64 - bitIndex)
+	}
+
+	return window & windowMask
+}
+
+// forEachChunk splits [0, n) into contiguous chunks and calls f on each of them from its own go routine. If there is
+// a single chunk, f is called from the calling go routine.
+//
+// numGoRoutines is the maximum number of chunks. Setting this value to a negative number or 0 will
+// make it default to runtime.GOMAXPROCS(0), see [utils.NumGoRoutines].
+func forEachChunk(n, numGoRoutines int, f func(start, end int)) {
+	numGoRoutines = utils.NumGoRoutines(numGoRoutines)
+	if n == 0 {
+		return
+	}
+
+	chunkSize := (n + numGoRoutines - 1) / numGoRoutines
+	if chunkSize == n {
+		f(0, n)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunkSize {
+		end := start + chunkSize
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,156 @@
+package multiexp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestFixedBaseMultiExpMatchesMultiExp(t *testing.T) {
+	instanceSize := uint(64)
+	points := genG1Points(instanceSize)
+	scalars := randScalars(instanceSize)
+
+	// Make sure that the edge cases for the digits are covered
+	scalars[0].SetZero()
+	scalars[1].SetOne()
+	scalars[2].SetOne()
+	scalars[2].Neg(&scalars[2])
+
+	expected, err := MultiExp(scalars, points, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for windowBits := MinWindowBits; windowBits <= MaxWindowBits; windowBits++ {
+		table, err := NewFixedBaseTable(points, windowBits, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := FixedBaseMultiExp(scalars, table, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("fixed base multi-exp with %d window bits is inconsistent with multi-exp", windowBits)
+		}
+
+		// Using a prefix of the points should match as well
+		prefixExpected, err := MultiExp(scalars[:10], points[:10], 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err = FixedBaseMultiExp(scalars[:10], table, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(prefixExpected) {
+			t.Errorf("fixed base multi-exp with %d window bits is inconsistent for a prefix of the points", windowBits)
+		}
+	}
+}
+
+func TestFixedBaseMultiExpRepeatedPoints(t *testing.T) {
+	// Repeated points and their negations make buckets hit the doubling
+	// and point at infinity cases when adding points.
+	generators := genG1Points(2)
+	points := make([]bls12381.G1Affine, 32)
+	scalars := make([]fr.Element, 32)
+	for i := range points {
+		points[i] = generators[i%2]
+		if i%4 == 3 {
+			points[i].Neg(&points[i])
+		}
+		scalars[i].SetUint64(uint64(i % 3))
+		scalars[i].Neg(&scalars[i])
+	}
+
+	expected, err := MultiExp(scalars, points, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for windowBits := MinWindowBits; windowBits <= MaxWindowBits; windowBits++ {
+		table, err := NewFixedBaseTable(points, windowBits, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := FixedBaseMultiExp(scalars, table, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("fixed base multi-exp with %d window bits is inconsistent with multi-exp for repeated points", windowBits)
+		}
+	}
+}
+
+func TestFixedBaseMultiExpInvalidInputs(t *testing.T) {
+	points := genG1Points(4)
+
+	for _, windowBits := range []int{0, MinWindowBits - 1, MaxWindowBits + 1} {
+		_, err := NewFixedBaseTable(points, windowBits, 0)
+		if !errors.Is(err, ErrInvalidWindowBits) {
+			t.Errorf("expected %v but got %v", ErrInvalidWindowBits, err)
+		}
+	}
+
+	table, err := NewFixedBaseTable(points, MinWindowBits, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = FixedBaseMultiExp(randScalars(5), table, 0)
+	if !errors.Is(err, ErrTooManyScalars) {
+		t.Errorf("expected %v but got %v", ErrTooManyScalars, err)
+	}
+	_, err = FixedBaseMultiExp(randScalars(4), table, 1024)
+	if !errors.Is(err, ErrTooManyGoRoutines) {
+		t.Errorf("expected %v but got %v", ErrTooManyGoRoutines, err)
+	}
+}
+
+func BenchmarkFixedBaseMultiExp(b *testing.B) {
+	instanceSize := uint(4096)
+	points := genG1Points(instanceSize)
+	scalars := randScalars(instanceSize)
+
+	b.Run("MultiExp", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = MultiExp(scalars, points, 0)
+		}
+	})
+
+	for windowBits := MinWindowBits; windowBits <= MaxWindowBits; windowBits++ {
+		b.Run(fmt.Sprintf("NewFixedBaseTable(windowBits=%d)", windowBits), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = NewFixedBaseTable(points, windowBits, 0)
+			}
+		})
+
+		table, err := NewFixedBaseTable(points, windowBits, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("FixedBaseMultiExp(windowBits=%d)", windowBits), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = FixedBaseMultiExp(scalars, table, 0)
+			}
+		})
+	}
+}
+
+func randScalars(n uint) []fr.Element {
+	scalars := make([]fr.Element, n)
+	for i := range scalars {
+		if _, err := scalars[i].SetRandom(); err != nil {
+			panic(err)
+		}
+	}
+	return scalars
+}
@@ -1,6 +1,9 @@
 package multiexp
 
 import (
+	"fmt"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
 	"github.com/consensys/gnark-crypto/ecc"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -9,10 +12,14 @@ import (
 // MultiExp computes a multi exponentiation -- That is, an inner product between points and scalars.
 //
 // More precisely, the result is set to scalars[0]*points[0] + ... + scalars[n-1]*points[n-1], where n is the length of both slices
-// If the slices differ in length, this function returns an error.
+// If the slices differ in length, this function returns an error wrapping [ErrLengthMismatch]. If both slices
+// are empty, the result is the identity, which is what g1_lincomb returns for an empty input.
+//
+// If all of the scalars fit in 128 bits, for instance when they are small random
+// combiners, the windows holding the high bits of the scalars are skipped.
 //
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
-// value to a negative number or 0 will make it default to the number of CPUs.
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
 //
 // Returns an error if the numGoRoutines exceeds 1024.
 //
@@ -22,7 +29,96 @@ func MultiExp(scalars []fr.Element, points []bls12381.G1Affine, numGoRoutines in
 	if err != nil {
 		return nil, err
 	}
-	return new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: numGoRoutines})
+	err = checkLengths(len(scalars), len(points))
+	if err != nil {
+		return nil, err
+	}
+
+	// When all of the scalars are short, only the low windows need to be processed
+	limbs, maxBitLen := scalarsMaxBitLen(scalars)
+	if maxBitLen <= smallScalarMaxBits {
+		return multiExpSmallScalars(limbs, points, maxBitLen, numGoRoutines), nil
+	}
+
+	return new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: utils.NumGoRoutines(numGoRoutines)})
+}
+
+// MultiExpBatch computes the multi exponentiation of each of the scalar vectors with the same points, so that the
+// i'th result is the same as MultiExp(scalarVectors[i], points, numGoRoutines). This is the case of committing to many
+// polynomials at once.
+//
+// The vectors are split between the go routines, and if there are fewer vectors than go routines, the remaining go
+// routines are shared by the multi exponentiations of each vector. This keeps every go routine busy with large
+// multi exponentiations rather than splitting each of them in small parts. The work on the points which does not
+// depend on the scalars can be shared between the vectors behind this API.
+//
+// It returns an error wrapping [ErrLengthMismatch] if the length of one of the vectors differs from the number of
+// points.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+//
+// Returns an error if the numGoRoutines exceeds 1024.
+func MultiExpBatch(scalarVectors [][]fr.Element, points []bls12381.G1Affine, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	err := isValidNumGoRoutines(numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+	for i := range scalarVectors {
+		err = checkLengths(len(scalarVectors[i]), len(points))
+		if err != nil {
+			return nil, fmt.Errorf("vector %d: %w", i, err)
+		}
+	}
+
+	numVectors := len(scalarVectors)
+	numGoRoutines = utils.NumGoRoutines(numGoRoutines)
+	numTasks := 1
+	if numGoRoutines > numVectors && numVectors > 0 {
+		numTasks = numGoRoutines / numVectors
+	}
+
+	results := make([]bls12381.G1Affine, numVectors)
+	errs := make([]error, numVectors)
+	forEachChunk(numVectors, numGoRoutines, func(start, end int) {
+		for i := start; i < end; i++ {
+			_, errs[i] = results[i].MultiExp(points, scalarVectors[i], ecc.MultiExpConfig{NbTasks: numTasks})
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// MultiExpG2 is [MultiExp] for G2 points: the result is set to scalars[0]*points[0] + ... + scalars[n-1]*points[n-1]
+// and an error wrapping [ErrLengthMismatch] is returned if the slices differ in length. The result is the identity if the slices are empty.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+//
+// Returns an error if the numGoRoutines exceeds 1024.
+func MultiExpG2(scalars []fr.Element, points []bls12381.G2Affine, numGoRoutines int) (*bls12381.G2Affine, error) {
+	err := isValidNumGoRoutines(numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+	err = checkLengths(len(scalars), len(points))
+	if err != nil {
+		return nil, err
+	}
+	return new(bls12381.G2Affine).MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: utils.NumGoRoutines(numGoRoutines)})
+}
+
+// checkLengths returns an error wrapping [ErrLengthMismatch] if the number of scalars and points differ.
+func checkLengths(numScalars, numPoints int) error {
+	if numScalars != numPoints {
+		return fmt.Errorf("%w: got %d scalars for %d points", ErrLengthMismatch, numScalars, numPoints)
+	}
+	return nil
 }
 
 // isValidNumGoRoutines will return an error if the number
@@ -2,6 +2,7 @@ package multiexp
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -32,6 +33,26 @@ func TestMultiExpSmoke(t *testing.T) {
 	}
 }
 
+func TestMultiExpNumGoRoutines(t *testing.T) {
+	instanceSize := uint(4096)
+	points := genG1Points(instanceSize)
+	scalars := randScalars(instanceSize)
+
+	expected, err := MultiExp(scalars, points, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, numGoRoutines := range []int{-1, 0, 2, 8, 1023} {
+		got, err := MultiExp(scalars, points, numGoRoutines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("the result with %d go routines differs from the result with a single one", numGoRoutines)
+		}
+	}
+}
+
 func TestMultiExpMismatchedLength(t *testing.T) {
 	var base fr.Element
 	base.SetInt64(123)
@@ -42,15 +63,18 @@ func TestMultiExpMismatchedLength(t *testing.T) {
 	points := genG1Points(instanceSize + 1)
 
 	_, err := MultiExp(powers, points, 0)
-	if err == nil {
-		t.Error("number of points != number of scalars. Should produce an error")
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected %v but got %v", ErrLengthMismatch, err)
+	}
+	if err.Error() != "number of scalars differs from the number of points: got 16 scalars for 17 points" {
+		t.Errorf("the error does not hold the lengths: %v", err)
 	}
 
 	powers = utils.ComputePowers(base, instanceSize+1)
 	points = genG1Points(instanceSize)
 	_, err = MultiExp(powers, points, 0)
-	if err == nil {
-		t.Error("number of points != number of scalars. Should produce an error")
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected %v but got %v", ErrLengthMismatch, err)
 	}
 }
 
@@ -75,6 +99,132 @@ func TestMultiExpErrOnMoreThan1024(t *testing.T) {
 	}
 }
 
+func TestMultiExpBatchSmoke(t *testing.T) {
+	instanceSize := uint(64)
+	points := genG1Points(instanceSize)
+
+	for _, numVectors := range []int{1, 3, 16} {
+		scalarVectors := make([][]fr.Element, numVectors)
+		for i := range scalarVectors {
+			scalarVectors[i] = randScalars(instanceSize)
+		}
+
+		for _, numGoRoutines := range []int{0, 1, 2, 32} {
+			results, err := MultiExpBatch(scalarVectors, points, numGoRoutines)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != numVectors {
+				t.Fatalf("expected %d results, got %d", numVectors, len(results))
+			}
+			for i := range scalarVectors {
+				expected, err := MultiExp(scalarVectors[i], points, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !results[i].Equal(expected) {
+					t.Fatalf("result %d differs from MultiExp (vectors: %d, go routines: %d)", i, numVectors, numGoRoutines)
+				}
+			}
+		}
+	}
+}
+
+func TestMultiExpBatchMismatchedLength(t *testing.T) {
+	instanceSize := uint(16)
+	points := genG1Points(instanceSize)
+	scalarVectors := [][]fr.Element{randScalars(instanceSize), randScalars(instanceSize + 1)}
+
+	_, err := MultiExpBatch(scalarVectors, points, 0)
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected %v but got %v", ErrLengthMismatch, err)
+	}
+}
+
+func TestMultiExpBatchZeroLength(t *testing.T) {
+	results, err := MultiExpBatch(nil, genG1Points(4), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+}
+
+func TestMultiExpBatchErrOnMoreThan1024(t *testing.T) {
+	_, err := MultiExpBatch(nil, nil, 1024)
+	if !errors.Is(err, ErrTooManyGoRoutines) {
+		t.Errorf("expected %v but got %v", ErrTooManyGoRoutines, err)
+	}
+}
+
+func TestMultiExpG2Smoke(t *testing.T) {
+	for _, instanceSize := range []uint{1, 2, 7, 64} {
+		scalars := make([]fr.Element, instanceSize)
+		for i := range scalars {
+			if _, err := scalars[i].SetRandom(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		points := genG2Points(instanceSize)
+
+		got, err := MultiExpG2(scalars, points, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := slowMultiExpG2(scalars, points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("inconsistent multi-exp result for %d points", instanceSize)
+		}
+	}
+}
+
+func TestMultiExpG2MismatchedLength(t *testing.T) {
+	var base fr.Element
+	base.SetInt64(123)
+
+	instanceSize := uint(16)
+
+	powers := utils.ComputePowers(base, instanceSize)
+	points := genG2Points(instanceSize + 1)
+
+	_, err := MultiExpG2(powers, points, 0)
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected %v but got %v", ErrLengthMismatch, err)
+	}
+	if err.Error() != "number of scalars differs from the number of points: got 16 scalars for 17 points" {
+		t.Errorf("the error does not hold the lengths: %v", err)
+	}
+
+	powers = utils.ComputePowers(base, instanceSize+1)
+	points = genG2Points(instanceSize)
+	_, err = MultiExpG2(powers, points, 0)
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected %v but got %v", ErrLengthMismatch, err)
+	}
+}
+
+func TestMultiExpG2ZeroLength(t *testing.T) {
+	result, err := MultiExpG2([]fr.Element{}, []bls12381.G2Affine{}, 0)
+	if err != nil {
+		t.Errorf("unexpected error for an empty instance: %v", err)
+	}
+
+	if !result.IsInfinity() {
+		t.Error("result should be identity when instance size is 0")
+	}
+}
+
+func TestMultiExpG2ErrOnMoreThan1024(t *testing.T) {
+	_, err := MultiExpG2([]fr.Element{}, []bls12381.G2Affine{}, 1024)
+	if !errors.Is(err, ErrTooManyGoRoutines) {
+		t.Errorf("expected %v but got %v", ErrTooManyGoRoutines, err)
+	}
+}
+
 func TestIsIdentitySmoke(t *testing.T) {
 	// Check that the identity point is encoded as (0,0) which is the point at infinity
 	// Really this is an abstraction leak from gnark
@@ -95,6 +245,47 @@ func TestIsIdentitySmoke(t *testing.T) {
 	}
 }
 
+func BenchmarkMultiExp(b *testing.B) {
+	instanceSize := uint(4096)
+	points := genG1Points(instanceSize)
+	scalars := randScalars(instanceSize)
+
+	// 0 lets gnark-crypto use as many tasks as there are CPUs
+	for _, numGoRoutines := range []int{0, 1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = MultiExp(scalars, points, numGoRoutines)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpBatch(b *testing.B) {
+	instanceSize := uint(4096)
+	numVectors := 16
+	points := genG1Points(instanceSize)
+	scalarVectors := make([][]fr.Element, numVectors)
+	for i := range scalarVectors {
+		scalarVectors[i] = randScalars(instanceSize)
+	}
+
+	b.Run("MultiExpBatch", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = MultiExpBatch(scalarVectors, points, 0)
+		}
+	})
+	b.Run(fmt.Sprintf("%dxMultiExp", numVectors), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for i := range scalarVectors {
+				_, _ = MultiExp(scalarVectors[i], points, 0)
+			}
+		}
+	})
+}
+
 func slowMultiExp(scalars []fr.Element, points []bls12381.G1Affine) (*bls12381.G1Affine, error) {
 	if len(scalars) != len(points) {
 		return nil, errors.New("number of scalars != number of points")
@@ -131,3 +322,41 @@ func genG1Points(n uint) []bls12381.G1Affine {
 	}
 	return points
 }
+
+// slowMultiExpG2 computes the multi exponentiation with a double-and-add loop over the bits of each scalar.
+func slowMultiExpG2(scalars []fr.Element, points []bls12381.G2Affine) (*bls12381.G2Affine, error) {
+	if len(scalars) != len(points) {
+		return nil, errors.New("number of scalars != number of points")
+	}
+
+	var result bls12381.G2Jac
+	for i := range scalars {
+		var bi big.Int
+		scalars[i].BigInt(&bi)
+
+		var point, term bls12381.G2Jac
+		point.FromAffine(&points[i])
+		for j := bi.BitLen() - 1; j >= 0; j-- {
+			term.DoubleAssign()
+			if bi.Bit(j) == 1 {
+				term.AddAssign(&point)
+			}
+		}
+		result.AddAssign(&term)
+	}
+
+	return new(bls12381.G2Affine).FromJacobian(&result), nil
+}
+
+func genG2Points(n uint) []bls12381.G2Affine {
+	_, _, _, g2Gen := bls12381.Generators()
+
+	points := make([]bls12381.G2Affine, n)
+	if n > 0 {
+		points[0] = g2Gen
+	}
+	for i := uint(1); i < n; i++ {
+		points[i].Add(&g2Gen, &points[i-1])
+	}
+	return points
+}
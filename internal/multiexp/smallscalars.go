@@ -0,0 +1,130 @@
+package multiexp
+
+import (
+	"math/bits"
+	"sync"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// smallScalarMaxBits is the largest bit length of the scalars for which [MultiExp] uses
+// [multiExpSmallScalars] rather than the generic multi exponentiation.
+//
+// The generic multi exponentiation processes every window of a 255-bit scalar, so
+// when all of the scalars are short, such as random 64 or 128-bit combiners, most of
+// its work is spent on windows whose digits are all zero.
+const smallScalarMaxBits = 128
+
+// maxSmallScalarWindowBits bounds the window size of [multiExpSmallScalars].
+const maxSmallScalarWindowBits = 16
+
+// scalarsMaxBitLen returns the scalars in regular (non-Montgomery) form, along with the largest bit length among them.
+func scalarsMaxBitLen(scalars []fr.Element) ([][fr.Limbs]uint64, int) {
+	limbs := make([][fr.Limbs]uint64, len(scalars))
+	maxBitLen := 0
+	for i := range scalars {
+		limbs[i] = scalars[i].Bits()
+		for j := fr.Limbs - 1; j >= 0; j-- {
+			if limbs[i][j] != 0 {
+				bitLen := 64*j + bits.Len64(limbs[i][j])
+				if bitLen > maxBitLen {
+					maxBitLen = bitLen
+				}
+				break
+			}
+		}
+	}
+	return limbs, maxBitLen
+}
+
+// multiExpSmallScalars computes Σ_i scalars[i] * points[i], where the scalars are given in regular form by `limbs`
+// and all fit in numBits bits.
+//
+// This is the bucket method, where only the ceil(numBits / windowBits) windows which can hold
+// non-zero digits are processed. The points are split between the go routines, and each
+// go routine runs the bucket method on its own points.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func multiExpSmallScalars(limbs [][fr.Limbs]uint64, points []bls12381.G1Affine, numBits, numGoRoutines int) *bls12381.G1Affine {
+	var mu sync.Mutex
+	var result bls12381.G1Jac
+	if numBits == 0 {
+		return new(bls12381.G1Affine).FromJacobian(&result)
+	}
+
+	forEachChunk(len(points), numGoRoutines, func(start, end int) {
+		partialResult := bucketSumSmallScalars(limbs[start:end], points[start:end], numBits)
+
+		mu.Lock()
+		result.AddAssign(&partialResult)
+		mu.Unlock()
+	})
+
+	return new(bls12381.G1Affine).FromJacobian(&result)
+}
+
+// bucketSumSmallScalars computes Σ_i limbs[i] * points[i], where all of the scalars fit in numBits bits.
+func bucketSumSmallScalars(limbs [][fr.Limbs]uint64, points []bls12381.G1Affine, numBits int) bls12381.G1Jac {
+	windowBits := smallScalarWindowBits(len(points), numBits)
+	numWindows := (numBits + windowBits - 1) / windowBits
+	numBuckets := (1 << windowBits) - 1
+	windowMask := uint64(numBuckets)
+
+	// Horner's rule over the windows, starting from the most significant one
+	var result bls12381.G1Jac
+	for j := numWindows - 1; j >= 0; j-- {
+		for k := 0; k < windowBits; k++ {
+			result.DoubleAssign()
+		}
+
+		var windowSum bls12381.G1Jac
+		if windowBits >= batchAffineMinWindowBits {
+			// buckets.points[d-1] holds the sum of all points whose digit is d
+			buckets := newBatchAffineBuckets(numBuckets)
+			for i := range points {
+				digit := extractWindow(limbs[i], j*windowBits, windowMask)
+				if digit != 0 {
+					buckets.add(int(digit-1), &points[i])
+				}
+			}
+			windowSum = buckets.weightedSum()
+		} else {
+			// buckets[d-1] holds the sum of all points whose digit is d
+			buckets := make([]bls12381.G1Jac, numBuckets)
+			for i := range points {
+				digit := extractWindow(limbs[i], j*windowBits, windowMask)
+				if digit != 0 {
+					buckets[digit-1].AddMixed(&points[i])
+				}
+			}
+
+			// Σ_d d * buckets[d-1] using a running sum
+			var runningSum bls12381.G1Jac
+			for d := numBuckets - 1; d >= 0; d-- {
+				runningSum.AddAssign(&buckets[d])
+				windowSum.AddAssign(&runningSum)
+			}
+		}
+
+		result.AddAssign(&windowSum)
+	}
+
+	return result
+}
+
+// smallScalarWindowBits returns the window size minimizing the number of additions of the
+// bucket method for numPoints scalars of numBits bits. Each of the ceil(numBits / windowBits)
+// windows costs one addition per point, plus two additions per bucket to combine the buckets.
+func smallScalarWindowBits(numPoints, numBits int) int {
+	bestWindowBits, bestCost := 1, -1
+	for windowBits := 1; windowBits <= maxSmallScalarWindowBits; windowBits++ {
+		numWindows := (numBits + windowBits - 1) / windowBits
+		cost := numWindows * (numPoints + 2<<windowBits)
+		if bestCost < 0 || cost < bestCost {
+			bestWindowBits, bestCost = windowBits, cost
+		}
+	}
+	return bestWindowBits
+}
@@ -0,0 +1,114 @@
+package multiexp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestMultiExpSmallScalars(t *testing.T) {
+	instanceSize := uint(256)
+	points := genG1Points(instanceSize)
+
+	for _, numBits := range [][]int{{1}, {64}, {128}, {255}, {1, 64}, {1, 64, 128}, {1, 64, 128, 255}} {
+		scalars := randScalarsOfBitLen(instanceSize, numBits)
+		// Make sure that the edge cases for the digits are covered
+		scalars[0].SetZero()
+		scalars[1].SetOne()
+
+		expected, err := new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, numGoRoutines := range []int{0, 1, 3} {
+			got, err := MultiExp(scalars, points, numGoRoutines)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(expected) {
+				t.Errorf("multi-exp with scalars of %v bits is inconsistent with the generic multi-exp (go routines: %d)", numBits, numGoRoutines)
+			}
+		}
+	}
+
+	// All of the scalars are equal, so that all of the points fall into the same buckets
+	scalars := make([]fr.Element, instanceSize)
+	for i := range scalars {
+		scalars[i].SetUint64(0xdeadbeefcafe)
+	}
+	expected, err := new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := MultiExp(scalars, points, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(expected) {
+		t.Error("multi-exp with equal scalars is inconsistent with the generic multi-exp")
+	}
+
+	// All of the scalars are zero
+	scalars = make([]fr.Element, instanceSize)
+	got, err = MultiExp(scalars, points, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsInfinity() {
+		t.Error("result should be identity when all of the scalars are zero")
+	}
+}
+
+func TestSmallScalarWindowBits(t *testing.T) {
+	for _, numPoints := range []int{1, 16, 4096, 1 << 20} {
+		for _, numBits := range []int{1, 64, 128} {
+			windowBits := smallScalarWindowBits(numPoints, numBits)
+			if windowBits < 1 || windowBits > maxSmallScalarWindowBits {
+				t.Errorf("invalid window size %d for %d points of %d bits", windowBits, numPoints, numBits)
+			}
+		}
+	}
+}
+
+func BenchmarkMultiExpSmallScalars(b *testing.B) {
+	instanceSize := uint(4096)
+	points := genG1Points(instanceSize)
+	scalars := randScalarsOfBitLen(instanceSize, []int{64})
+
+	for _, numGoRoutines := range []int{0, 1} {
+		b.Run(fmt.Sprintf("MultiExp/numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = MultiExp(scalars, points, numGoRoutines)
+			}
+		})
+		b.Run(fmt.Sprintf("generic/numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: numGoRoutines})
+			}
+		})
+	}
+}
+
+// randScalarsOfBitLen returns n random scalars, where the i'th scalar has numBits[i % len(numBits)] bits.
+func randScalarsOfBitLen(n uint, numBits []int) []fr.Element {
+	scalars := make([]fr.Element, n)
+	for i := range scalars {
+		bitLen := numBits[i%len(numBits)]
+		max := new(big.Int).Lsh(big.NewInt(1), uint(bitLen))
+		value, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		// Set the top bit so that the scalar has exactly bitLen bits
+		value.SetBit(value, bitLen-1, 1)
+		scalars[i].SetBigInt(value)
+	}
+	return scalars
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrPanic is wrapped by the error returned by [ParallelFor] when a call panics.
+var ErrPanic = errors.New("panic in a parallel call")
+
+// ParallelFor calls fn(i) for every i in [0, n) using a pool of `workers` go routines, each of which
+// processes a contiguous chunk of the indices. Setting workers to a negative number or 0 will make it
+// default to runtime.GOMAXPROCS(0). The number of workers is resolved by [NumGoRoutines].
+//
+// If fn returns an error, the remaining go routines stop early and the error is returned. If several calls
+// return an error, it is not specified which of the errors is returned. A panic in fn is recovered and
+// returned as an error wrapping [ErrPanic], which holds the index, the panic value and the stack trace.
+//
+// With a single go routine, fn is called in order from the calling go routine and the first error is returned.
+// Otherwise the calls are made in an unspecified order, so results should be written at their index.
+func ParallelFor(n, workers int, fn func(i int) error) error {
+	return ParallelForCtx(context.Background(), n, workers, fn)
+}
+
+// ParallelForCtx is [ParallelFor] which also stops early if ctx is done, in which case ctx.Err() is
+// returned. The context is checked before every call to fn, which cannot be interrupted.
+func ParallelForCtx(ctx context.Context, n, workers int, fn func(i int) error) error {
+	if n == 0 {
+		return nil
+	}
+
+	numWorkers := NumGoRoutines(workers)
+	if numWorkers > n {
+		numWorkers = n
+	}
+	if numWorkers == 1 {
+		for i := 0; i < n; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := callRecover(fn, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	chunkSize := (n + numWorkers - 1) / numWorkers
+
+	errG, groupCtx := errgroup.WithContext(ctx)
+	for start := 0; start < n; start += chunkSize {
+		start, end := start, start+chunkSize // Capture the values for this chunk
+		if end > n {
+			end = n
+		}
+		errG.Go(func() error {
+			for i := start; i < end; i++ {
+				// Stop early if another go routine returned an error or if ctx is done,
+				// in which case ctx.Err() is not nil and is returned.
+				if groupCtx.Err() != nil {
+					return ctx.Err()
+				}
+				if err := callRecover(fn, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return errG.Wait()
+}
+
+// callRecover calls fn(i), returning an error wrapping [ErrPanic] if it panics.
+func callRecover(fn func(i int) error, i int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w at index %d: %v\n%s", ErrPanic, i, r, debug.Stack())
+		}
+	}()
+	return fn(i)
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelFor(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 100} {
+		for _, workers := range []int{-1, 0, 1, 3, 200} {
+			counts := make([]int32, n)
+			err := ParallelFor(n, workers, func(i int) error {
+				atomic.AddInt32(&counts[i], 1)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range counts {
+				if counts[i] != 1 {
+					t.Errorf("index %d was processed %d times (n=%d, workers=%d)", i, counts[i], n, workers)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelForOrder(t *testing.T) {
+	// With a single worker, the calls are made in order
+	var order []int
+	err := ParallelFor(100, 1, func(i int) error {
+		order = append(order, i)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range order {
+		if order[i] != i {
+			t.Fatalf("call %d was made for index %d", i, order[i])
+		}
+	}
+
+	// Otherwise, results written at their index are in the order of the input
+	results := make([]int, 1000)
+	err = ParallelFor(len(results), 8, func(i int) error {
+		results[i] = i * i
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range results {
+		if results[i] != i*i {
+			t.Fatalf("incorrect result at index %d", i)
+		}
+	}
+}
+
+func TestParallelForErrors(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	var calls int32
+	err := ParallelFor(1000, 1, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 10 {
+			return errInvalid
+		}
+		return nil
+	})
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("expected %v but got %v", errInvalid, err)
+	}
+	// With a single go routine, the indices after the error are not processed
+	if calls != 11 {
+		t.Errorf("expected 11 calls, got %d", calls)
+	}
+
+	for _, workers := range []int{1, 4} {
+		err = ParallelFor(100, workers, func(i int) error {
+			if i == 42 {
+				var m map[int]int
+				m[i] = i // panics
+			}
+			return nil
+		})
+		if !errors.Is(err, ErrPanic) {
+			t.Fatalf("expected %v but got %v (workers=%d)", ErrPanic, err, workers)
+		}
+		if !strings.Contains(err.Error(), "at index 42") || !strings.Contains(err.Error(), "parallel_test.go") {
+			t.Errorf("the error does not hold the index and the stack trace: %v", err)
+		}
+	}
+}
+
+func TestParallelForWorkers(t *testing.T) {
+	for _, workers := range []int{1, 2, 5} {
+		var running, maxRunning int32
+		err := ParallelFor(50, workers, func(i int) error {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				seen := atomic.LoadInt32(&maxRunning)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if maxRunning > int32(workers) {
+			t.Errorf("%d calls ran at once with %d workers", maxRunning, workers)
+		}
+	}
+}
+
+func TestParallelForCtx(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int32
+		err := ParallelForCtx(ctx, 1000, workers, func(i int) error {
+			if atomic.AddInt32(&calls, 1) == 10 {
+				cancel()
+			}
+			return nil
+		})
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v but got %v (workers=%d)", context.Canceled, err, workers)
+		}
+		// Every go routine stops at its next index once the context is cancelled
+		if got := atomic.LoadInt32(&calls); got > int32(10+workers-1) {
+			t.Errorf("%d calls were made after the cancellation (workers=%d)", got-10, workers)
+		}
+	}
+}
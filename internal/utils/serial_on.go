@@ -0,0 +1,6 @@
+//go:build gokzg_serial
+
+package utils
+
+// serialBuild is set by the gokzg_serial build tag, see [NumGoRoutines].
+const serialBuild = true
@@ -1,9 +1,18 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+	"runtime"
+
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
+var ErrInvalidBatchSize = errors.New("the destination must hold one element per 32 bytes of the source")
+
 // The spec includes a method to compute the modular inverse.
 // This method is named .Inverse on `fr.Element`
 // When the element to invert is zero, this method will return zero
@@ -42,9 +51,174 @@ func IsPowerOfTwo(value uint64) bool {
 	return value > 0 && (value&(value-1) == 0)
 }
 
+// HashToBLSField implements [hash_to_bls_field] for the concatenation of `data`: the SHA-256 digest is interpreted
+// as a big endian integer and reduced modulo the scalar field order. Digests which are not canonical are reduced
+// rather than rejected.
+//
+// [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
+func HashToBLSField(data ...[]byte) fr.Element {
+	h := sha256.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+	var digest [sha256.Size]byte
+	return HashToBLSFieldFromHash(h, digest[:0])
+}
+
+// HashToBLSFieldFromHash is [HashToBLSField] for the data already written to `h`, which should be a SHA-256 hasher
+// to match the specs. The state of `h` is not changed.
+//
+// The digest is appended to buf, so that callers can reuse a buffer to avoid an allocation. buf may be nil.
+func HashToBLSFieldFromHash(h hash.Hash, buf []byte) fr.Element {
+	sum := h.Sum(buf)
+	if len(sum) == fr.Bytes {
+		return reduceBigEndian((*[fr.Bytes]byte)(sum))
+	}
+	// Longer digests, such as those of the hashers given to WithChallengeHasher, are reduced using big integers
+	var element fr.Element
+	element.SetBytes(sum)
+	return element
+}
+
+// modulusLimbs holds the scalar field order in little endian 64-bit limbs.
+var modulusLimbs = func() [4]uint64 {
+	var modulus [fr.Bytes]byte
+	fr.Modulus().FillBytes(modulus[:])
+	return bigEndianLimbs(&modulus)
+}()
+
+// bigEndianLimbs returns the 32-byte big endian integer b in little endian 64-bit limbs.
+func bigEndianLimbs(b *[fr.Bytes]byte) [4]uint64 {
+	var limbs [4]uint64
+	for i := range limbs {
+		limbs[i] = binary.BigEndian.Uint64(b[fr.Bytes-8*(i+1):])
+	}
+	return limbs
+}
+
+// reduceBigEndian returns the 32-byte big endian integer b reduced modulo the scalar field order, like
+// fr.Element.SetBytes does. SetBytes uses big integers for the values which are not canonical, which allocates,
+// whereas the values are smaller than 3 times the order so we subtract it at most twice.
+func reduceBigEndian(b *[fr.Bytes]byte) fr.Element {
+	limbs := bigEndianLimbs(b)
+	for !lessThanModulus(&limbs) {
+		var borrow uint64
+		for i := range limbs {
+			limbs[i], borrow = bits.Sub64(limbs[i], modulusLimbs[i], borrow)
+		}
+	}
+
+	var canonical [fr.Bytes]byte
+	for i := range limbs {
+		binary.BigEndian.PutUint64(canonical[fr.Bytes-8*(i+1):], limbs[i])
+	}
+	// This cannot fail since the value is canonical
+	element, _ := fr.BigEndian.Element(&canonical)
+	return element
+}
+
+// lessThanModulus reports whether the integer held in little endian limbs is smaller than the scalar field order.
+func lessThanModulus(limbs *[4]uint64) bool {
+	for i := len(limbs) - 1; i >= 0; i-- {
+		if limbs[i] != modulusLimbs[i] {
+			return limbs[i] < modulusLimbs[i]
+		}
+	}
+	return false
+}
+
 func ReduceCanonicalBigEndian(serScalar []byte) (fr.Element, error) {
 	var scalar fr.Element
 	err := scalar.SetBytesCanonical(serScalar)
 
 	return scalar, err
 }
+
+// ReduceCanonicalBatch is [ReduceCanonicalBigEndian] for the 32-byte scalars held back to back in src, which are written
+// to dst. The length of dst must be len(src) / 32.
+//
+// Each scalar is compared with the limbs of the modulus, without using big integers. If a scalar is not canonical,
+// its index and an error are returned, and the elements of dst from that index on are left unchanged. Otherwise, the
+// index is -1.
+func ReduceCanonicalBatch(dst []fr.Element, src []byte) (int, error) {
+	if len(src)%fr.Bytes != 0 || len(dst) != len(src)/fr.Bytes {
+		return -1, ErrInvalidBatchSize
+	}
+
+	for i := range dst {
+		scalar, err := fr.BigEndian.Element((*[fr.Bytes]byte)(src[i*fr.Bytes : (i+1)*fr.Bytes]))
+		if err != nil {
+			return i, err
+		}
+		dst[i] = scalar
+	}
+	return -1, nil
+}
+
+// ReverseSlice reverses the elements of s in place.
+func ReverseSlice[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// ReverseBytesCopy writes the bytes of src to dst in reverse order. It panics if dst and src have different
+// lengths.
+//
+// dst and src may overlap, in particular dst may be src, in which case src is reversed in place.
+func ReverseBytesCopy(dst, src []byte) {
+	if len(dst) != len(src) {
+		panic("utils: ReverseBytesCopy called with slices of different lengths")
+	}
+	// copy handles overlapping slices
+	copy(dst, src)
+	ReverseSlice(dst)
+}
+
+// SetFrLittleEndian sets dst to the 32-byte little endian integer b, without copying or reversing b. It returns
+// false, leaving dst unchanged, if b is not 32 bytes long or the integer is not canonical, that is not smaller than
+// the scalar field order.
+func SetFrLittleEndian(dst *fr.Element, b []byte) bool {
+	if len(b) != fr.Bytes {
+		return false
+	}
+	element, err := fr.LittleEndian.Element((*[fr.Bytes]byte)(b))
+	if err != nil {
+		return false
+	}
+	*dst = element
+	return true
+}
+
+// ZeroizeFr, ZeroizeFrSlice and ZeroizeBytes overwrite secret material, such as the secret of a trusted setup
+// generated for tests, with zeros once it is no longer needed. runtime.KeepAlive is used so that the compiler
+// cannot remove the writes as dead stores.
+//
+// Go gives limited guarantees: only the given memory is overwritten. Copies made by passing values around, by
+// the runtime when it grows a stack or moves data, or by the libraries the secret was given to are not reached,
+// and neither are values still held in registers. These helpers reduce the lifetime of secrets in memory, but
+// cannot ensure that no copy remains.
+
+// ZeroizeFr overwrites each of the field elements with zero.
+func ZeroizeFr(elems ...*fr.Element) {
+	for _, elem := range elems {
+		*elem = fr.Element{}
+	}
+	runtime.KeepAlive(elems)
+}
+
+// ZeroizeFrSlice overwrites the field elements in `elems` with zero.
+func ZeroizeFrSlice(elems []fr.Element) {
+	for i := range elems {
+		elems[i] = fr.Element{}
+	}
+	runtime.KeepAlive(elems)
+}
+
+// ZeroizeBytes overwrites the bytes in `b` with zero.
+func ZeroizeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	runtime.KeepAlive(b)
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"math"
 	"math/big"
 	"testing"
@@ -118,6 +120,262 @@ func TestCanonicalEncoding(t *testing.T) {
 	}
 }
 
+func TestReduceCanonicalBatch(t *testing.T) {
+	modulus := fr.Modulus()
+	var modulusMinusOne, modulusPlusOne big.Int
+	modulusMinusOne.Sub(modulus, big.NewInt(1))
+	modulusPlusOne.Add(modulus, big.NewInt(1))
+	allOnes := new(big.Int).SetBytes(bytes.Repeat([]byte{0xff}, fr.Bytes))
+
+	tests := []struct {
+		value     *big.Int
+		canonical bool
+	}{
+		{big.NewInt(0), true},
+		{big.NewInt(1), true},
+		{&modulusMinusOne, true},
+		{modulus, false},
+		{&modulusPlusOne, false},
+		{allOnes, false},
+	}
+	for _, test := range tests {
+		// The scalar is placed after a canonical one, so that the index is checked
+		src := make([]byte, 2*fr.Bytes)
+		big.NewInt(7).FillBytes(src[:fr.Bytes])
+		test.value.FillBytes(src[fr.Bytes:])
+
+		dst := make([]fr.Element, 2)
+		index, err := ReduceCanonicalBatch(dst, src)
+		if !test.canonical {
+			if err == nil || index != 1 {
+				t.Errorf("expected an error at index 1 for %v, got index %d and error %v", test.value, index, err)
+			}
+			continue
+		}
+		if err != nil || index != -1 {
+			t.Fatalf("unexpected error at index %d for %v: %v", index, test.value, err)
+		}
+		if dst[1].BigInt(new(big.Int)).Cmp(test.value) != 0 || !dst[0].Equal(new(fr.Element).SetUint64(7)) {
+			t.Errorf("incorrect field elements for %v", test.value)
+		}
+	}
+
+	// The destination must hold one element per scalar
+	for _, test := range []struct{ numElements, numBytes int }{{1, fr.Bytes - 1}, {1, fr.Bytes + 1}, {2, fr.Bytes}, {0, fr.Bytes}} {
+		if _, err := ReduceCanonicalBatch(make([]fr.Element, test.numElements), make([]byte, test.numBytes)); err != ErrInvalidBatchSize {
+			t.Errorf("expected %v for %d elements and %d bytes, got %v", ErrInvalidBatchSize, test.numElements, test.numBytes, err)
+		}
+	}
+	if index, err := ReduceCanonicalBatch(nil, nil); err != nil || index != -1 {
+		t.Errorf("unexpected error for an empty batch: %v", err)
+	}
+}
+
+// FuzzReduceCanonicalBatch checks that [ReduceCanonicalBatch] accepts and rejects the same scalars as
+// [ReduceCanonicalBigEndian] called on each of them.
+func FuzzReduceCanonicalBatch(f *testing.F) {
+	modulus := fr.Modulus()
+	var modulusMinusOne big.Int
+	modulusMinusOne.Sub(modulus, big.NewInt(1))
+	for _, seed := range []*big.Int{big.NewInt(0), &modulusMinusOne, modulus} {
+		serScalar := seed.FillBytes(make([]byte, fr.Bytes))
+		f.Add(serScalar)
+		f.Add(append(serScalar, serScalar...))
+	}
+	f.Add(bytes.Repeat([]byte{0xff}, 3*fr.Bytes))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		src := data[:len(data)-len(data)%fr.Bytes]
+		dst := make([]fr.Element, len(src)/fr.Bytes)
+		index, err := ReduceCanonicalBatch(dst, src)
+
+		expectedIndex := -1
+		for i := range dst {
+			expected, err := ReduceCanonicalBigEndian(src[i*fr.Bytes : (i+1)*fr.Bytes])
+			if err != nil {
+				expectedIndex = i
+				break
+			}
+			if !dst[i].Equal(&expected) {
+				t.Fatalf("incorrect field element at index %d", i)
+			}
+		}
+		if index != expectedIndex || (err != nil) != (expectedIndex != -1) {
+			t.Fatalf("expected index %d, got index %d and error %v", expectedIndex, index, err)
+		}
+	})
+}
+
+// The expected values were computed independently by reducing the SHA-256 digest of the
+// data, interpreted as a big endian integer, modulo the field order.
+func TestHashToBLSField(t *testing.T) {
+	tests := []struct {
+		data     [][]byte
+		expected string
+	}{
+		// The digest is canonical
+		{[][]byte{[]byte("0")}, "5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9"},
+		// The digests are not canonical
+		{nil, "6fc31cef6f5e9ecc67c21cc08fcde11ed3f09de1649d374da495991c7852b854"},
+		{[][]byte{[]byte("abc")}, "468a6f6c656452a20e0768d6540c4a1e5c45bda096191e9db410ff62f20015ac"},
+		{[][]byte{[]byte("a"), nil, []byte("bc")}, "468a6f6c656452a20e0768d6540c4a1e5c45bda096191e9db410ff62f20015ac"},
+		{[][]byte{[]byte("FSBLOBVERIFY_V1_")}, "34bcc67d6f32aed45e9756b8a1506d08477c683f2083a2aa1796907f868c512b"},
+		{[][]byte{[]byte("hash_to_bls_field")}, "20276eff8a779aa9e96dab5a53c908eaa99c475a327278f6ea1db15af7c60723"},
+	}
+	for _, test := range tests {
+		got := HashToBLSField(test.data...)
+		gotBytes := got.Bytes()
+		if hex.EncodeToString(gotBytes[:]) != test.expected {
+			t.Errorf("incorrect hash_to_bls_field(%q): got %x, expected %s", test.data, gotBytes, test.expected)
+		}
+
+		h := sha256.New()
+		for _, d := range test.data {
+			h.Write(d)
+		}
+		fromHash := HashToBLSFieldFromHash(h, make([]byte, 0, sha256.Size))
+		if !fromHash.Equal(&got) {
+			t.Errorf("HashToBLSFieldFromHash is inconsistent with HashToBLSField for %q", test.data)
+		}
+	}
+}
+
+func TestReduceBigEndian(t *testing.T) {
+	modulus := fr.Modulus()
+	maxValue := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(modulus, big.NewInt(1)),
+		modulus,
+		new(big.Int).Add(modulus, big.NewInt(1)),
+		new(big.Int).Mul(modulus, big.NewInt(2)),
+		new(big.Int).Sub(new(big.Int).Mul(modulus, big.NewInt(2)), big.NewInt(1)),
+		maxValue,
+	}
+	for i := 0; i < 100; i++ {
+		digest := sha256.Sum256([]byte{byte(i)})
+		values = append(values, new(big.Int).SetBytes(digest[:]))
+	}
+
+	for _, value := range values {
+		var b [fr.Bytes]byte
+		value.FillBytes(b[:])
+		got := reduceBigEndian(&b)
+
+		var expected fr.Element
+		expected.SetBigInt(new(big.Int).Mod(value, modulus))
+		if !got.Equal(&expected) {
+			t.Errorf("incorrect reduction of %x: got %s, expected %s", b, got.String(), expected.String())
+		}
+	}
+}
+
+func TestReverseSlice(t *testing.T) {
+	for _, n := range []int{0, 1, 4, 7} {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		ReverseSlice(s)
+		for i := range s {
+			if s[i] != n-1-i {
+				t.Errorf("length %d: got %v", n, s)
+				break
+			}
+		}
+	}
+}
+
+func TestReverseBytesCopy(t *testing.T) {
+	// An odd length leaves the middle byte in place
+	src := []byte{1, 2, 3, 4, 5}
+	dst := make([]byte, len(src))
+	ReverseBytesCopy(dst, src)
+	if !bytes.Equal(dst, []byte{5, 4, 3, 2, 1}) || !bytes.Equal(src, []byte{1, 2, 3, 4, 5}) {
+		t.Errorf("got dst %v and src %v", dst, src)
+	}
+
+	// dst == src reverses in place
+	for _, n := range []int{0, 1, 32, 33} {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte(i)
+		}
+		ReverseBytesCopy(b, b)
+		for i := range b {
+			if b[i] != byte(n-1-i) {
+				t.Errorf("length %d: got %v", n, b)
+				break
+			}
+		}
+	}
+
+	// Overlapping slices are reversed as if src had been copied first
+	b := []byte{1, 2, 3, 4, 5, 6}
+	ReverseBytesCopy(b[1:], b[:5])
+	if !bytes.Equal(b, []byte{1, 5, 4, 3, 2, 1}) {
+		t.Errorf("got %v", b)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("slices of different lengths must panic")
+		}
+	}()
+	ReverseBytesCopy(make([]byte, 2), make([]byte, 3))
+}
+
+func TestSetFrLittleEndian(t *testing.T) {
+	var x fr.Element
+	_, _ = x.SetRandom()
+	bigEndian := x.Bytes()
+	littleEndian := make([]byte, fr.Bytes)
+	ReverseBytesCopy(littleEndian, bigEndian[:])
+
+	var got fr.Element
+	if !SetFrLittleEndian(&got, littleEndian) || !got.Equal(&x) {
+		t.Error("the little endian bytes must decode to the same element")
+	}
+
+	// The modulus is not canonical, and dst is left unchanged
+	modulus := make([]byte, fr.Bytes)
+	fr.Modulus().FillBytes(modulus)
+	ReverseSlice(modulus)
+	if SetFrLittleEndian(&got, modulus) || !got.Equal(&x) {
+		t.Error("the modulus must be rejected without changing dst")
+	}
+
+	for _, n := range []int{0, fr.Bytes - 1, fr.Bytes + 1} {
+		if SetFrLittleEndian(&got, make([]byte, n)) {
+			t.Errorf("%d bytes must be rejected", n)
+		}
+	}
+}
+
+func TestZeroize(t *testing.T) {
+	elems := make([]fr.Element, 4)
+	for i := range elems {
+		_, _ = elems[i].SetRandom()
+	}
+	ZeroizeFr(&elems[0], &elems[2])
+	if !elems[0].IsZero() || !elems[2].IsZero() || elems[1].IsZero() || elems[3].IsZero() {
+		t.Error("ZeroizeFr must overwrite exactly the given elements")
+	}
+	ZeroizeFrSlice(elems[1:])
+	for i := range elems {
+		if elems[i] != (fr.Element{}) {
+			t.Errorf("element %d was not overwritten", i)
+		}
+	}
+
+	b := bytes.Repeat([]byte{0xff}, 33)
+	ZeroizeBytes(b[1:])
+	if b[0] != 0xff || !bytes.Equal(b[1:], make([]byte, 32)) {
+		t.Error("ZeroizeBytes must overwrite exactly the given bytes")
+	}
+}
+
 // Adds the modulus to the big integer
 // we need to do it with a big.Int
 // since an fr.Element will apply the
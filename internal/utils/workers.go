@@ -0,0 +1,19 @@
+package utils
+
+import "runtime"
+
+// NumGoRoutines returns the number of go routines to use when numGoRoutines are requested: numGoRoutines itself if
+// it is positive, and runtime.GOMAXPROCS(0) otherwise, so that the default is serial when GOMAXPROCS is 1 as on
+// js/wasm and wasip1.
+//
+// When the library is built with the gokzg_serial tag, it always returns 1 and the parallel paths run in the calling
+// go routine.
+func NumGoRoutines(numGoRoutines int) int {
+	if serialBuild {
+		return 1
+	}
+	if numGoRoutines > 0 {
+		return numGoRoutines
+	}
+	return runtime.GOMAXPROCS(0)
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestNumGoRoutines(t *testing.T) {
+	if serialBuild {
+		for _, numGoRoutines := range []int{-1, 0, 1, 8} {
+			if got := NumGoRoutines(numGoRoutines); got != 1 {
+				t.Errorf("expected 1 go routine with the gokzg_serial tag, got %d for %d", got, numGoRoutines)
+			}
+		}
+		return
+	}
+
+	if got := NumGoRoutines(8); got != 8 {
+		t.Errorf("expected the 8 requested go routines, got %d", got)
+	}
+	for _, maxProcs := range []int{1, 4} {
+		previous := runtime.GOMAXPROCS(maxProcs)
+		for _, numGoRoutines := range []int{-1, 0} {
+			if got := NumGoRoutines(numGoRoutines); got != maxProcs {
+				t.Errorf("expected %d go routines with GOMAXPROCS=%d, got %d", maxProcs, maxProcs, got)
+			}
+		}
+		runtime.GOMAXPROCS(previous)
+	}
+}
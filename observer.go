@@ -0,0 +1,45 @@
+package gokzg4844
+
+import (
+	"time"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+)
+
+// Names of the operations reported to an [Observer].
+const (
+	// The methods of the [Context]. The item count is the number of blobs.
+	OperationBlobToKZGCommitment     = "BlobToKZGCommitment"
+	OperationComputeBlobKZGProof     = "ComputeBlobKZGProof"
+	OperationVerifyBlobKZGProofBatch = "VerifyBlobKZGProofBatch"
+
+	// The lookups in the cache set using [WithCommitmentCache]. The item count is 1 and the duration includes hashing
+	// the blob.
+	OperationCommitmentCacheHit  = "CommitmentCacheHit"
+	OperationCommitmentCacheMiss = "CommitmentCacheMiss"
+
+	// The phases of the batch verification of two proofs or more. The item count is the number of points in the
+	// multi exponentiation, or the number of pairings.
+	OperationMultiExp = kzg.PhaseMultiExp
+	OperationPairing  = kzg.PhasePairing
+)
+
+// Observer receives the duration of the expensive operations of a [Context] which was created using [WithObserver],
+// for example to export them as metrics or tracing spans.
+//
+// OnOperation is called when each operation returns, whether it failed or not, from the go routine which ran it, so it
+// must be safe for concurrent use and should return quickly. The phases of an operation are reported before the
+// operation itself.
+type Observer interface {
+	OnOperation(name string, itemCount int, d time.Duration)
+}
+
+// observeSince reports the duration of an operation started at `start` to the observer of the [Context]. It must only
+// be called when the observer is not nil, which lets callers avoid reading the clock otherwise:
+//
+//	if c.observer != nil {
+//		defer c.observeSince(OperationBlobToKZGCommitment, 1, time.Now())
+//	}
+func (c *Context) observeSince(name string, itemCount int, start time.Time) {
+	c.observer.OnOperation(name, itemCount, time.Since(start))
+}
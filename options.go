@@ -0,0 +1,388 @@
+package gokzg4844
+
+import (
+	"crypto/rand"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+// ContextOption configures optional behavior of a [Context] at construction time.
+type ContextOption func(*contextConfig)
+
+// contextConfig holds the settings which can be modified by passing a [ContextOption]
+// to the [Context] constructors.
+type contextConfig struct {
+	// skipMonomialSRS indicates that the monomial G1 points in the trusted setup
+	// should not be parsed and retained.
+	skipMonomialSRS bool
+
+	// mode selects the parts of the trusted setup which are retained. See [WithMode].
+	mode ContextMode
+
+	// precomputeSRS indicates that a fixed base table with a window size of
+	// precomputedSRSWindowBits should be computed for the lagrange G1 points.
+	precomputeSRS            bool
+	precomputedSRSWindowBits int
+
+	// rejectInfinityCommitments and rejectInfinityProofs indicate that the verification
+	// methods should reject commitments and proofs which are the point at infinity.
+	rejectInfinityCommitments bool
+	rejectInfinityProofs      bool
+
+	// trustedCommitments indicates that the verification methods should not check
+	// that commitments are in the correct subgroup.
+	trustedCommitments bool
+
+	// subgroupChecks indicates that the points of the trusted setup should be
+	// checked to be in the correct subgroup when they are parsed.
+	subgroupChecks bool
+
+	// exhaustiveSetupCheck indicates that [NewContextFromJSONChecked] should check the
+	// lagrange G1 points using an FFT instead of a randomized check.
+	exhaustiveSetupCheck bool
+
+	// numGoRoutines is the number of go routines used by methods which do not take
+	// it as a parameter. A value <= 0 means that runtime.GOMAXPROCS(0) is used.
+	numGoRoutines int
+
+	// randomSource is the source of the random scalars used to batch verifications
+	// and to check the structure of the trusted setup. It defaults to crypto/rand.
+	randomSource io.Reader
+
+	// observer receives the duration of the expensive operations. It is nil by default.
+	observer Observer
+
+	// commitmentCacheSize is the maximum number of commitments cached by
+	// [Context.BlobToKZGCommitment]. A value <= 0 disables the cache.
+	commitmentCacheSize int
+
+	// newChallengeHasher and challengeDomainSeparator define the transcripts of the
+	// Fiat-Shamir challenges. newChallengeHasher is nil for SHA-256, and
+	// challengeDomainSeparator defaults to DomSepProtocol.
+	newChallengeHasher       func() hash.Hash
+	challengeDomainSeparator string
+}
+
+// newContextConfig returns the default configuration with the given options applied.
+func newContextConfig(opts []ContextOption) *contextConfig {
+	config := &contextConfig{randomSource: rand.Reader, challengeDomainSeparator: DomSepProtocol}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// retainMonomialSRS reports whether the monomial G1 points of the trusted setup should be parsed and retained.
+func (config *contextConfig) retainMonomialSRS() bool {
+	return !config.skipMonomialSRS && config.mode != ModeVerifyOnly
+}
+
+// ContextMode selects the parts of the trusted setup retained by a [Context], and so the methods it supports. See
+// [WithMode].
+type ContextMode int
+
+const (
+	// ModeProveAndVerify is the default mode. The context retains the lagrange G1 points, the G2 points and, unless
+	// [WithoutMonomialSRS] is passed, the monomial G1 points. The precomputations of [Context.ComputeCellsAndKZGProofs]
+	// are computed on its first call.
+	ModeProveAndVerify ContextMode = iota
+	// ModeVerifyOnly only retains the G2 points and the generator of G1, which are all that is needed to verify
+	// blob and evaluation proofs. The methods which commit to polynomials or compute proofs, including
+	// [Context.VerifyBlobKZGProofAgainstVersionedHash] which recomputes the commitment, return
+	// [ErrVerifyOnlyContext], and the methods which need the monomial G1 points return [ErrMonomialSRSUnavailable].
+	// The fingerprint of the trusted setup is computed when the context is created.
+	ModeVerifyOnly
+	// ModeFull is ModeProveAndVerify with the precomputations of [Context.ComputeCellsAndKZGProofs] computed when the
+	// context is created rather than on the first call. The constructors return [ErrMonomialSRSUnavailable] if the
+	// trusted setup does not hold the monomial G1 points or [WithoutMonomialSRS] is passed, and
+	// [ErrContextSizeMismatch] if the context is not created for polynomials of [ScalarsPerBlob] evaluations.
+	ModeFull
+)
+
+// String returns the name of the mode, such as "verify only".
+func (mode ContextMode) String() string {
+	switch mode {
+	case ModeProveAndVerify:
+		return "prove and verify"
+	case ModeVerifyOnly:
+		return "verify only"
+	case ModeFull:
+		return "full"
+	default:
+		return fmt.Sprintf("ContextMode(%d)", int(mode))
+	}
+}
+
+// WithMode sets the parts of the trusted setup retained by the [Context], see [ContextMode]. Verification-only
+// consumers can use [ModeVerifyOnly] so as not to hold the G1 points, and [Context.MemoryFootprint] reflects the
+// mode. The default is [ModeProveAndVerify].
+func WithMode(mode ContextMode) ContextOption {
+	return func(config *contextConfig) {
+		config.mode = mode
+	}
+}
+
+// WithoutMonomialSRS tells the [Context] to not retain the monomial G1 points from the trusted setup.
+//
+// This roughly halves the memory needed for the G1 points in the [Context]. The only method which needs the
+// monomial points is [Context.CommitToMonomialPolynomial], which will return [ErrMonomialSRSUnavailable].
+func WithoutMonomialSRS() ContextOption {
+	return func(config *contextConfig) {
+		config.skipMonomialSRS = true
+	}
+}
+
+// WithPrecomputedSRS tells the [Context] to precompute a fixed base table for the lagrange G1 points, which is then
+// used to compute commitments and proofs instead of a generic multi exponentiation.
+//
+// windowBits must be between 4 and 8. The table for 4096 points takes ~12.6MB with a window size of 8 and ~25MB with
+// a window size of 4, and computing it adds roughly half a second to the creation of the [Context] on a single core.
+// Window sizes of 7 and 8 make committing faster than without the table; smaller window sizes are slower and are only
+// useful for comparison.
+func WithPrecomputedSRS(windowBits int) ContextOption {
+	return func(config *contextConfig) {
+		config.precomputeSRS = true
+		config.precomputedSRSWindowBits = windowBits
+	}
+}
+
+// WithNumGoRoutines sets the number of go routines used by [Context] methods which do not take it as a parameter,
+// such as [Context.BlobsToKZGCommitments], and by the methods which take it as a parameter when they are passed a
+// negative number or 0. Setting this value to a negative number or 0, which is the default, will make it default to
+// runtime.GOMAXPROCS(0), so that the library runs serially when GOMAXPROCS is 1, as on js/wasm and wasip1.
+//
+// It also bounds the number of go routines used to create the [Context]: to parse and check the trusted setup, and
+// to compute the table of [WithPrecomputedSRS]. On small machines, or in WASM, setting it to 1 avoids the overhead of
+// scheduling the go routines. Building with the gokzg_serial tag has the same effect for every Context.
+//
+// The multi exponentiations of gnark-crypto still start a go routine per window of the scalars, but they use
+// numGoRoutines to limit how many of them do work at the same time.
+func WithNumGoRoutines(numGoRoutines int) ContextOption {
+	return func(config *contextConfig) {
+		config.numGoRoutines = numGoRoutines
+	}
+}
+
+// WithSerialMode is WithNumGoRoutines(1): the batch methods process the blobs one after the other from the calling
+// go routine, and the multi exponentiations use a single task. With [WithPrecomputedSRS], computing commitments and
+// proofs does not start any go routine. This bounds the CPU usage of the [Context] to roughly a single core and
+// makes profiles easier to read.
+func WithSerialMode() ContextOption {
+	return WithNumGoRoutines(1)
+}
+
+// WithSubgroupChecks sets whether the points of the trusted setup are checked to be in the correct subgroup when the
+// [Context] is created, which is needed when the trusted setup comes from an untrusted source. This roughly doubles
+// the time needed to parse the trusted setup.
+//
+// The checks are enabled by default for [NewContext4096FromReader], [NewContext4096FromTextReader] and
+// [NewContext4096FromFile], and disabled by default for the other constructors, such as [NewContext4096Secure] which
+// uses the embedded trusted setup.
+func WithSubgroupChecks(enabled bool) ContextOption {
+	return func(config *contextConfig) {
+		config.subgroupChecks = enabled
+	}
+}
+
+// withDefaultSubgroupChecks enables the subgroup checks of the trusted setup before applying opts,
+// so that they can still be disabled using [WithSubgroupChecks].
+func withDefaultSubgroupChecks(opts []ContextOption) []ContextOption {
+	return append([]ContextOption{WithSubgroupChecks(true)}, opts...)
+}
+
+// WithExhaustiveSetupCheck tells [NewContextFromJSONChecked] to check that the lagrange G1 points match the
+// monomial G1 points by converting them with an FFT, instead of the default randomized check which only needs two
+// multi exponentiations. The exhaustive check is deterministic, but takes several seconds for 4096 points.
+func WithExhaustiveSetupCheck() ContextOption {
+	return func(config *contextConfig) {
+		config.exhaustiveSetupCheck = true
+	}
+}
+
+// WithTrustedCommitments tells the verification methods of the [Context] to skip the subgroup check when deserializing
+// commitments, which is the most expensive part of the deserialization. Commitments are still checked to be valid
+// encodings of points on the curve.
+//
+// This MUST only be used if every commitment passed to the context has already been validated, for example because
+// the commitments were checked when they were first received and are then cached. Verifying a proof against a
+// commitment which is not in the subgroup is not sound.
+func WithTrustedCommitments() ContextOption {
+	return func(config *contextConfig) {
+		config.trustedCommitments = true
+	}
+}
+
+// WithRejectInfinityCommitments tells the verification methods of the [Context] to return [ErrPointAtInfinity] when
+// given a commitment which is the point at infinity.
+//
+// The specs allow this, since the commitment to the blob holding only zeros is the point at infinity. This option
+// should only be used by protocols where such a blob can never be valid.
+func WithRejectInfinityCommitments() ContextOption {
+	return func(config *contextConfig) {
+		config.rejectInfinityCommitments = true
+	}
+}
+
+// WithRejectInfinityProofs tells the verification methods of the [Context] to return [ErrPointAtInfinity] when
+// given a proof which is the point at infinity.
+//
+// The specs allow this, since the proof for a blob whose polynomial is constant, for example a blob holding only
+// zeros, is the point at infinity. This option should only be used by protocols where such blobs can never be valid.
+func WithRejectInfinityProofs() ContextOption {
+	return func(config *contextConfig) {
+		config.rejectInfinityProofs = true
+	}
+}
+
+// WithRandomSource sets the source of the random scalars which are used to combine proofs in the batch verification
+// methods, such as [Context.VerifyBlobKZGProofBatch], and to check the structure of the trusted setup in
+// [NewContextFromJSONChecked]. The default is crypto/rand.Reader.
+//
+// The soundness of batch verification relies on these scalars being unpredictable, so this MUST be a
+// cryptographically secure source outside of tests and deterministic replays. If reading from it fails, the methods
+// return an error wrapping [ErrRandomSource] instead of falling back to another source.
+//
+// The reads are serialized by the [Context], so the reader does not need to be safe for concurrent use.
+func WithRandomSource(randomSource io.Reader) ContextOption {
+	return func(config *contextConfig) {
+		config.randomSource = &lockedReader{r: randomSource}
+	}
+}
+
+// WithObserver sets an [Observer] which receives the duration of [Context.BlobToKZGCommitment],
+// [Context.ComputeBlobKZGProof], [Context.VerifyBlobKZGProofBatch], and of the multi exponentiations and pairings of
+// the batch verification methods. Without it, the [Context] does not read the clock.
+func WithObserver(observer Observer) ContextOption {
+	return func(config *contextConfig) {
+		config.observer = observer
+	}
+}
+
+// WithCommitmentCache tells the [Context] to cache the commitments computed by [Context.BlobToKZGCommitment] and
+// [Context.BlobsToKZGCommitments], so that committing to the same blob again does not need a multi exponentiation.
+//
+// The cache holds at most maxEntries commitments and evicts the least recently used one when it is full. Its keys are
+// the SHA-256 digests of the blobs, which need to be computed for every lookup, and it does not hold references to the
+// blobs. Each entry takes about 150 bytes. A value of maxEntries <= 0 disables the cache, which is the default.
+//
+// If an [Observer] is set using [WithObserver], every lookup is reported as [OperationCommitmentCacheHit] or
+// [OperationCommitmentCacheMiss].
+func WithCommitmentCache(maxEntries int) ContextOption {
+	return func(config *contextConfig) {
+		config.commitmentCacheSize = maxEntries
+	}
+}
+
+// WithChallengeHasher tells the [Context] to derive its Fiat-Shamir challenges with the hash function returned by
+// newHasher instead of SHA-256, for deployments outside of Ethereum which standardize on another hash function. For
+// instance, BLAKE2b can be used with a function calling blake2b.New256(nil) from golang.org/x/crypto/blake2b.
+//
+// WARNING: this breaks interoperability with EIP-4844. Proofs computed by a [Context] using this option are rejected
+// by every implementation of the specs, including a [Context] created without it, and the other way around. Only use
+// it if every prover and verifier of the deployment uses the same hash function.
+//
+// The challenges are those of [ComputeChallenge] and [Context.ComputeEquivalenceChallenge], with SHA-256 replaced: the
+// digest, of any size, is interpreted as a big endian integer and reduced modulo the scalar field order. The hashers
+// returned by newHasher are not shared between go routines. The random scalars of batch verification do not come from a
+// hash function and are not affected.
+func WithChallengeHasher(newHasher func() hash.Hash) ContextOption {
+	return func(config *contextConfig) {
+		config.newChallengeHasher = newHasher
+	}
+}
+
+// WithChallengeDomainSeparator tells the [Context] to start the transcripts of its Fiat-Shamir challenges with
+// domainSeparator instead of [DomSepProtocol], so that the challenges of a deployment differ from those of any other
+// protocol using this package. The transcripts of [Context.ComputeEquivalenceChallenge] start with domainSeparator
+// followed by [DomSepEquivalence].
+//
+// WARNING: like [WithChallengeHasher], this breaks interoperability with EIP-4844.
+func WithChallengeDomainSeparator(domainSeparator string) ContextOption {
+	return func(config *contextConfig) {
+		config.challengeDomainSeparator = domainSeparator
+	}
+}
+
+// lockedReader serializes the reads from a reader which is shared by the go routines using a [Context].
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Read(p)
+}
+
+// BatchOption configures how [Context.VerifyBlobKZGProofBatchPar] verifies the proofs of a batch and reports the
+// invalid ones.
+type BatchOption func(*batchConfig)
+
+// batchConfig holds the settings which can be modified by passing a [BatchOption]
+// to [Context.VerifyBlobKZGProofBatchPar].
+type batchConfig struct {
+	// failFast indicates that the proofs should be verified one by one, stopping
+	// at the first invalid one.
+	failFast bool
+
+	// allFailures indicates that the proofs should be verified one by one, and
+	// that every invalid one should be reported.
+	allFailures bool
+
+	// bisect indicates that the proofs should be verified together, and that
+	// every invalid one should be found by bisection if the check fails.
+	bisect bool
+}
+
+// newBatchConfig returns the default configuration with the given options applied.
+func newBatchConfig(opts []BatchOption) *batchConfig {
+	config := &batchConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// WithFailFast tells [Context.VerifyBlobKZGProofBatchPar] to verify the proofs one by one and to return as soon as
+// one of them is invalid, with a [*BlobError] holding its index. Since the proofs are verified concurrently, this is
+// not necessarily the first invalid proof of the batch.
+//
+// This needs one pairing check per proof instead of one for the whole batch, so it is slower when all the proofs
+// are valid.
+func WithFailFast() BatchOption {
+	return func(config *batchConfig) {
+		config.failFast = true
+		config.allFailures = false
+		config.bisect = false
+	}
+}
+
+// WithAllFailures tells [Context.VerifyBlobKZGProofBatchPar] to verify the proofs one by one and to report every
+// invalid blob, commitment or proof of the batch in a [*BatchError].
+//
+// This needs one pairing check per proof instead of one for the whole batch, so it is slower when all the proofs
+// are valid.
+func WithAllFailures() BatchOption {
+	return func(config *batchConfig) {
+		config.allFailures = true
+		config.failFast = false
+		config.bisect = false
+	}
+}
+
+// WithBisection tells [Context.VerifyBlobKZGProofBatchPar] to verify the proofs together like by default, and, if the
+// check fails, to find the invalid proofs by splitting the batch in halves and checking them recursively. Every
+// invalid blob, commitment or proof of the batch is reported in a [*BatchError], like with [WithAllFailures].
+//
+// When all the proofs are valid, this needs a single pairing check. With k invalid proofs in a batch of n, it needs
+// about 2k*log2(n) checks, each of them on the blobs, commitments and proofs which were deserialized at the start.
+func WithBisection() BatchOption {
+	return func(config *batchConfig) {
+		config.bisect = true
+		config.failFast = false
+		config.allFailures = false
+	}
+}
@@ -0,0 +1,11 @@
+package gokzg4844
+
+// goRoutines returns the number of go routines to use for a method taking numGoRoutines as a parameter: the
+// parameter if it is positive, and otherwise the number configured by [WithNumGoRoutines], which is 0 by default
+// to let the callee choose.
+func (c *Context) goRoutines(numGoRoutines int) int {
+	if numGoRoutines > 0 {
+		return numGoRoutines
+	}
+	return c.numGoRoutines
+}
@@ -0,0 +1,155 @@
+package gokzg4844
+
+import (
+	"fmt"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/multiexp"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// PartialCommitment is the part of the commitment to a blob which comes from the scalars at a fixed set of indices,
+// as returned by [Context.PrecomputePartialCommitment]. Committing to blobs which always hold the same scalars at
+// those indices, such as padding, using [Context.BlobToKZGCommitmentWithPartial] only runs the multi exponentiation
+// over the other indices. The gain is small when the covered scalars are small, such as zero padding, since the
+// multi exponentiation already handles those cheaply.
+//
+// A PartialCommitment holds the lagrange G1 points of the indices it does not cover, so it should only be used with
+// the context which created it. The zero value is not a valid partial commitment: the methods return
+// [ErrPartialCommitmentUnset] for it.
+type PartialCommitment struct {
+	// point is the commitment to the polynomial which is zero outside of the covered indices
+	point bls12381.G1Affine
+	// indices and values are the covered indices and the scalars at those indices
+	indices []uint64
+	values  []fr.Element
+	// remainingIndices are the indices which are not covered, and remainingPoints their lagrange G1 points
+	remainingIndices []int
+	remainingPoints  []bls12381.G1Affine
+	set              bool
+}
+
+// Indices returns the indices of the scalars of a blob covered by the partial commitment, in the order they were
+// given to [Context.PrecomputePartialCommitment].
+func (p PartialCommitment) Indices() []uint64 {
+	return append([]uint64(nil), p.indices...)
+}
+
+// PrecomputePartialCommitment computes the part of the commitment to a blob which comes from the scalars at the
+// given indices, where values[i] is the scalar at indices[i]. The indices are positions of scalars in a [Blob], so
+// they must be distinct and less than [ScalarsPerBlob].
+//
+// It returns a [*BatchLengthError] if the numbers of indices and values differ, and an error wrapping
+// [ErrInvalidPartialIndex] or [ErrDuplicatePartialIndex] for an invalid index.
+func (c *Context) PrecomputePartialCommitment(indices []uint64, values []fr.Element) (PartialCommitment, error) {
+	if !c.calls.acquire() {
+		return PartialCommitment{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return PartialCommitment{}, err
+	}
+	if c.domain.Cardinality != ScalarsPerBlob {
+		return PartialCommitment{}, ErrContextSizeMismatch
+	}
+	if err := checkBatchLengths([]string{"indices", "values"}, len(indices), len(values)); err != nil {
+		return PartialCommitment{}, err
+	}
+
+	// 1. Validation of the indices
+	//
+	var covered [ScalarsPerBlob]bool
+	coveredPoints := make([]bls12381.G1Affine, len(indices))
+	for i, index := range indices {
+		if index >= ScalarsPerBlob {
+			return PartialCommitment{}, fmt.Errorf("%w: got %d at position %d", ErrInvalidPartialIndex, index, i)
+		}
+		if covered[index] {
+			return PartialCommitment{}, fmt.Errorf("%w: %d is repeated at position %d", ErrDuplicatePartialIndex, index, i)
+		}
+		covered[index] = true
+		coveredPoints[i] = c.commitKey.G1[index]
+	}
+
+	// 2. Commit to the covered scalars
+	//
+	point, err := multiexp.MultiExp(values, coveredPoints, c.numGoRoutines)
+	if err != nil {
+		return PartialCommitment{}, err
+	}
+
+	remainingIndices := make([]int, 0, ScalarsPerBlob-len(indices))
+	remainingPoints := make([]bls12381.G1Affine, 0, ScalarsPerBlob-len(indices))
+	for i := range covered {
+		if !covered[i] {
+			remainingIndices = append(remainingIndices, i)
+			remainingPoints = append(remainingPoints, c.commitKey.G1[i])
+		}
+	}
+
+	return PartialCommitment{
+		point:            *point,
+		indices:          append([]uint64(nil), indices...),
+		values:           append([]fr.Element(nil), values...),
+		remainingIndices: remainingIndices,
+		remainingPoints:  remainingPoints,
+		set:              true,
+	}, nil
+}
+
+// BlobToKZGCommitmentWithPartial is [Context.BlobToKZGCommitment] for a blob which holds the scalars of the partial
+// commitment at the indices it covers. Only the scalars at the other indices are committed to, and the precomputed
+// part is added to the result, which is the same commitment as the one returned by [Context.BlobToKZGCommitment].
+//
+// The whole blob is deserialized and checked, and an error wrapping [ErrPartialCommitmentMismatch] is returned if a
+// scalar at a covered index differs from the one of the partial commitment. The multi exponentiation over the
+// remaining indices does not use the precomputations of [WithPrecomputedSRS], nor the cache of
+// [WithCommitmentCache].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
+func (c *Context) BlobToKZGCommitmentWithPartial(blob *Blob, partial PartialCommitment, numGoRoutines int) (KZGCommitment, error) {
+	if !c.calls.acquire() {
+		return KZGCommitment{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+	if !partial.set {
+		return KZGCommitment{}, ErrPartialCommitmentUnset
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlobParallel(blob, numGoRoutines)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	for i, index := range partial.indices {
+		if !polynomial[index].Equal(&partial.values[i]) {
+			return KZGCommitment{}, fmt.Errorf("%w: scalar at index %d", ErrPartialCommitmentMismatch, index)
+		}
+	}
+
+	// 2. Commit to the remaining scalars, and add the precomputed part
+	//
+	remainingScalars := make([]fr.Element, len(partial.remainingIndices))
+	for i, index := range partial.remainingIndices {
+		remainingScalars[i] = polynomial[index]
+	}
+	remaining, err := multiexp.MultiExp(remainingScalars, partial.remainingPoints, c.goRoutines(numGoRoutines))
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	var commitment bls12381.G1Jac
+	commitment.FromAffine(remaining)
+	commitment.AddMixed(&partial.point)
+
+	// 3. Serialization
+	//
+	var commitmentAffine bls12381.G1Affine
+	commitmentAffine.FromJacobian(&commitment)
+	return KZGCommitment(SerializeG1Point(commitmentAffine)), nil
+}
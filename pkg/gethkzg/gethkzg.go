@@ -0,0 +1,104 @@
+// Package gethkzg exposes this library with the types and function signatures of the crypto/kzg4844 package of
+// go-ethereum, so that a fork of go-ethereum can back that package with this library by forwarding its functions.
+//
+// The types have the same layout as those of go-ethereum, so a value of one can be converted to the other, and a
+// *Blob converts to a *[gokzg4844.Blob] without copying the blob.
+//
+// The functions use a [gokzg4844.Context] created from the mainnet trusted setup on the first call to any of them, see
+// [Context].
+package gethkzg
+
+import (
+	"sync"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+)
+
+// Blob represents a 4844 data blob.
+type Blob [gokzg4844.ScalarsPerBlob * gokzg4844.SerializedScalarSize]byte
+
+// Commitment is a serialized commitment to a polynomial.
+type Commitment [48]byte
+
+// Proof is a serialized commitment to the quotient polynomial.
+type Proof [48]byte
+
+// Point is a BLS field element.
+type Point [32]byte
+
+// Claim is a claimed evaluation value in a specific point.
+type Claim [32]byte
+
+var (
+	contextOnce      sync.Once
+	sharedContext    *gokzg4844.Context
+	sharedContextErr error
+
+	// newContext creates the context on the first call to [Context]. It is replaced by the tests.
+	newContext = func() (*gokzg4844.Context, error) {
+		return gokzg4844.NewContext4096Secure()
+	}
+)
+
+// Context returns the context used by the functions of this package, which is created from the mainnet trusted setup
+// on the first call and shared by all of the following ones, including concurrent ones. It is safe for concurrent use.
+func Context() (*gokzg4844.Context, error) {
+	contextOnce.Do(func() {
+		sharedContext, sharedContextErr = newContext()
+	})
+	return sharedContext, sharedContextErr
+}
+
+// BlobToCommitment creates a small commitment out of a data blob.
+func BlobToCommitment(blob *Blob) (Commitment, error) {
+	ctx, err := Context()
+	if err != nil {
+		return Commitment{}, err
+	}
+	commitment, err := ctx.BlobToKZGCommitment((*gokzg4844.Blob)(blob), 0)
+	return Commitment(commitment), err
+}
+
+// ComputeProof computes the KZG proof at the given point for the polynomial represented by the blob, along with the
+// claimed value of the polynomial at that point.
+func ComputeProof(blob *Blob, point Point) (Proof, Claim, error) {
+	ctx, err := Context()
+	if err != nil {
+		return Proof{}, Claim{}, err
+	}
+	proof, claim, err := ctx.ComputeKZGProof((*gokzg4844.Blob)(blob), gokzg4844.Scalar(point), 0)
+	return Proof(proof), Claim(claim), err
+}
+
+// VerifyProof verifies the KZG proof that the polynomial represented by the commitment evaluates to the claimed value
+// at the given point. It returns nil if the proof is valid, see [gokzg4844.Context.VerifyKZGProof].
+func VerifyProof(commitment Commitment, point Point, claim Claim, proof Proof) error {
+	ctx, err := Context()
+	if err != nil {
+		return err
+	}
+	return ctx.VerifyKZGProof(gokzg4844.KZGCommitment(commitment), gokzg4844.Scalar(point), gokzg4844.Scalar(claim),
+		gokzg4844.KZGProof(proof))
+}
+
+// ComputeBlobProof returns the KZG proof that is used to verify the blob against the commitment.
+//
+// This method does not verify that the commitment is correct with respect to the blob.
+func ComputeBlobProof(blob *Blob, commitment Commitment) (Proof, error) {
+	ctx, err := Context()
+	if err != nil {
+		return Proof{}, err
+	}
+	proof, err := ctx.ComputeBlobKZGProof((*gokzg4844.Blob)(blob), gokzg4844.KZGCommitment(commitment), 0)
+	return Proof(proof), err
+}
+
+// VerifyBlobProof verifies that the blob data corresponds to the provided commitment. It returns nil if the proof is
+// valid, see [gokzg4844.Context.VerifyBlobKZGProof].
+func VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error {
+	ctx, err := Context()
+	if err != nil {
+		return err
+	}
+	return ctx.VerifyBlobKZGProof((*gokzg4844.Blob)(blob), gokzg4844.KZGCommitment(commitment), gokzg4844.KZGProof(proof))
+}
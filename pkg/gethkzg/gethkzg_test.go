@@ -0,0 +1,104 @@
+package gethkzg
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdapterMatchesContext(t *testing.T) {
+	ctx, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+	fixture, err := testutil.GenerateProofFixture(ctx, 1)
+	require.NoError(t, err)
+	blob := (*Blob)(fixture.Blob)
+
+	commitment, err := BlobToCommitment(blob)
+	require.NoError(t, err)
+	require.Equal(t, fixture.Commitment, gokzg4844.KZGCommitment(commitment))
+
+	blobProof, err := ComputeBlobProof(blob, commitment)
+	require.NoError(t, err)
+	require.Equal(t, fixture.Proof, gokzg4844.KZGProof(blobProof))
+	require.NoError(t, VerifyBlobProof(blob, commitment, blobProof))
+	require.ErrorIs(t, VerifyBlobProof(blob, commitment, Proof(commitment)), gokzg4844.ErrProofInvalid)
+
+	point := Point(testutil.GenerateScalar(2))
+	proof, claim, err := ComputeProof(blob, point)
+	require.NoError(t, err)
+	expectedProof, expectedClaim, err := ctx.ComputeKZGProof(fixture.Blob, gokzg4844.Scalar(point), 0)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, gokzg4844.KZGProof(proof))
+	require.Equal(t, expectedClaim, gokzg4844.Scalar(claim))
+	require.NoError(t, VerifyProof(commitment, point, claim, proof))
+	require.ErrorIs(t, VerifyProof(commitment, point, Claim(point), proof), gokzg4844.ErrProofInvalid)
+
+	// The errors of the context are returned as they are
+	invalidBlob := (*Blob)(testutil.GenerateInvalidBlob(1, 0))
+	_, err = BlobToCommitment(invalidBlob)
+	_, expectedErr := ctx.BlobToKZGCommitment(testutil.GenerateInvalidBlob(1, 0), 0)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
+	require.Equal(t, expectedErr.Error(), err.Error())
+}
+
+// resetContext makes the next call to Context create the context using create, and restores the default afterwards.
+func resetContext(t *testing.T, create func() (*gokzg4844.Context, error)) {
+	defaultNewContext := newContext
+	contextOnce = sync.Once{}
+	newContext = create
+	t.Cleanup(func() {
+		contextOnce = sync.Once{}
+		newContext = defaultNewContext
+		sharedContext, sharedContextErr = nil, nil
+	})
+}
+
+func TestContextCreatedOnce(t *testing.T) {
+	var calls atomic.Int32
+	resetContext(t, func() (*gokzg4844.Context, error) {
+		calls.Add(1)
+		return gokzg4844.NewContext4096Secure()
+	})
+
+	const numGoRoutines = 16
+	contexts := make([]*gokzg4844.Context, numGoRoutines)
+	var blob Blob
+	var wg sync.WaitGroup
+	for i := range contexts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			contexts[i], err = Context()
+			if err == nil {
+				_, err = BlobToCommitment(&blob)
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+	for _, ctx := range contexts {
+		require.Same(t, contexts[0], ctx)
+	}
+}
+
+func TestContextError(t *testing.T) {
+	errSetup := errors.New("could not load the trusted setup")
+	resetContext(t, func() (*gokzg4844.Context, error) {
+		return nil, errSetup
+	})
+
+	var blob Blob
+	_, err := BlobToCommitment(&blob)
+	require.ErrorIs(t, err, errSetup)
+	require.ErrorIs(t, VerifyBlobProof(&blob, Commitment{}, Proof{}), errSetup)
+}
@@ -0,0 +1,49 @@
+package kzg_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/RiemaLabs/go-kzg-4844/pkg/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// This example commits to a polynomial with 16 evaluations and proves its value at a point.
+// The setup is created from a known secret, so it must not be used in production.
+func Example() {
+	domain := kzg.NewDomain(16)
+	srs, err := kzg.NewLagrangeSRSInsecure(domain, big.NewInt(1234))
+	if err != nil {
+		panic(err)
+	}
+
+	// The polynomial takes the value i at the i'th element of the domain
+	poly := make(kzg.Polynomial, 16)
+	for i := range poly {
+		poly[i].SetUint64(uint64(i))
+	}
+
+	commitment, err := kzg.Commit(poly, &srs.CommitKey, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	// Evaluating at a point in the domain returns the corresponding value of the polynomial
+	proof, err := kzg.Open(domain, poly, domain.Roots[5], &srs.CommitKey, 0)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("claimed value:", proof.ClaimedValue.String())
+
+	err = kzg.Verify(commitment, &proof, &srs.OpeningKey)
+	fmt.Println("valid proof:", err == nil)
+
+	proof.ClaimedValue = fr.NewElement(6)
+	err = kzg.Verify(commitment, &proof, &srs.OpeningKey)
+	fmt.Println("valid proof with wrong value:", err == nil)
+
+	// Output:
+	// claimed value: 5
+	// valid proof: true
+	// valid proof with wrong value: false
+}
@@ -0,0 +1,90 @@
+// Package kzg exposes the KZG polynomial commitment scheme over bls12-381 used by this library,
+// without the blob framing of EIP-4844.
+//
+// Polynomials are given in lagrange form over a [Domain] of any power of two size, and the
+// keys are passed explicitly, so that the functions in this package work for any polynomial
+// size supported by the trusted setup.
+package kzg
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Polynomial is a polynomial in lagrange form, that is its evaluations over a [Domain].
+type Polynomial = kzg.Polynomial
+
+// Commitment is a commitment to a polynomial.
+type Commitment = kzg.Commitment
+
+// OpeningProof is a proof that a polynomial, committed to in a [Commitment],
+// evaluates to OpeningProof.ClaimedValue at OpeningProof.InputPoint.
+type OpeningProof = kzg.OpeningProof
+
+// CommitKey holds the G1 points of the trusted setup in lagrange form, used to commit to
+// polynomials and to compute opening proofs.
+type CommitKey = kzg.CommitKey
+
+// OpeningKey holds the points of the trusted setup needed to verify opening proofs.
+type OpeningKey = kzg.OpeningKey
+
+// SRS holds the commit key and the opening key of a trusted setup.
+type SRS = kzg.SRS
+
+// Domain is a multiplicative subgroup of the scalar field, whose size is a power of two.
+// Polynomials are represented by their evaluations over the elements of the domain.
+type Domain = kzg.Domain
+
+var (
+	ErrInvalidNumDigests              = kzg.ErrInvalidNumDigests
+	ErrInvalidPolynomialSize          = kzg.ErrInvalidPolynomialSize
+	ErrVerifyOpeningProof             = kzg.ErrVerifyOpeningProof
+	ErrPolynomialMismatchedSizeDomain = kzg.ErrPolynomialMismatchedSizeDomain
+	ErrMinSRSSize                     = kzg.ErrMinSRSSize
+	ErrRandomSource                   = kzg.ErrRandomSource
+)
+
+// NewDomain creates a domain of size `x`, which must be a power of two.
+func NewDomain(x uint64) *Domain {
+	return kzg.NewDomain(x)
+}
+
+// NewLagrangeSRSInsecure creates an SRS in lagrange form over `domain` from the secret `bAlpha`.
+//
+// This method should not be used in production because the secret is supplied as input,
+// so anyone who knows it can create proofs for false statements.
+func NewLagrangeSRSInsecure(domain *Domain, bAlpha *big.Int) (*SRS, error) {
+	return kzg.NewSRSInsecure(*domain, bAlpha, true)
+}
+
+// Commit commits to the polynomial `p` using the commit key `ck`.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func Commit(p Polynomial, ck *CommitKey, numGoRoutines int) (*Commitment, error) {
+	return kzg.Commit(p, ck, numGoRoutines)
+}
+
+// Open computes an opening proof of the polynomial `p` at the point `evaluationPoint`,
+// which may or may not be in the domain.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKey, numGoRoutines int) (OpeningProof, error) {
+	return kzg.Open(domain, p, evaluationPoint, ck, numGoRoutines)
+}
+
+// Verify checks that `proof` is a valid opening proof for the polynomial committed to in `commitment`.
+// It returns [ErrVerifyOpeningProof] if the proof is invalid.
+func Verify(commitment *Commitment, proof *OpeningProof, openKey *OpeningKey) error {
+	return kzg.Verify(commitment, proof, openKey)
+}
+
+// BatchVerifyMultiPoints verifies multiple opening proofs, each for a different commitment,
+// faster than verifying them one by one. The proofs are combined using randomness from crypto/rand.
+func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) error {
+	return kzg.BatchVerifyMultiPoints(commitments, proofs, openKey, 0, rand.Reader, nil)
+}
@@ -0,0 +1,97 @@
+package kzg_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/RiemaLabs/go-kzg-4844/pkg/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+const polySize = 16
+
+func newTestSetup(t *testing.T) (*kzg.Domain, *kzg.SRS) {
+	domain := kzg.NewDomain(polySize)
+	srs, err := kzg.NewLagrangeSRSInsecure(domain, big.NewInt(1234))
+	require.NoError(t, err)
+	return domain, srs
+}
+
+func randPoly(t *testing.T) kzg.Polynomial {
+	poly := make(kzg.Polynomial, polySize)
+	for i := 0; i < polySize; i++ {
+		_, err := poly[i].SetRandom()
+		require.NoError(t, err)
+	}
+	return poly
+}
+
+func TestOpenVerify(t *testing.T) {
+	domain, srs := newTestSetup(t)
+	poly := randPoly(t)
+	polyCopy := append(kzg.Polynomial{}, poly...)
+
+	commitment, err := kzg.Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	// Open at a point outside of the domain and at a point inside of it
+	var outside fr.Element
+	outside.SetUint64(987654321)
+	for _, point := range []fr.Element{outside, domain.Roots[3]} {
+		proof, err := kzg.Open(domain, poly, point, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.NoError(t, kzg.Verify(commitment, &proof, &srs.OpeningKey))
+
+		// Changing the claimed value should make the proof invalid
+		proof.ClaimedValue.Add(&proof.ClaimedValue, new(fr.Element).SetOne())
+		require.ErrorIs(t, kzg.Verify(commitment, &proof, &srs.OpeningKey), kzg.ErrVerifyOpeningProof)
+	}
+	proof, err := kzg.Open(domain, poly, domain.Roots[3], &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.Equal(t, poly[3], proof.ClaimedValue)
+
+	// The polynomial is not modified by committing to it or opening it
+	require.Equal(t, polyCopy, poly)
+}
+
+func TestBatchVerify(t *testing.T) {
+	domain, srs := newTestSetup(t)
+
+	var commitments []kzg.Commitment
+	var proofs []kzg.OpeningProof
+	for i := 0; i < 4; i++ {
+		poly := randPoly(t)
+		commitment, err := kzg.Commit(poly, &srs.CommitKey, 0)
+		require.NoError(t, err)
+
+		var point fr.Element
+		point.SetUint64(uint64(1000 + i))
+		proof, err := kzg.Open(domain, poly, point, &srs.CommitKey, 0)
+		require.NoError(t, err)
+
+		commitments = append(commitments, *commitment)
+		proofs = append(proofs, proof)
+	}
+	commitmentsCopy := append([]kzg.Commitment{}, commitments...)
+	proofsCopy := append([]kzg.OpeningProof{}, proofs...)
+	require.NoError(t, kzg.BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey))
+	require.Equal(t, commitmentsCopy, commitments)
+	require.Equal(t, proofsCopy, proofs)
+
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	require.ErrorIs(t, kzg.BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey), kzg.ErrVerifyOpeningProof)
+}
+
+func TestInvalidSizes(t *testing.T) {
+	domain, srs := newTestSetup(t)
+
+	_, err := kzg.Commit(make(kzg.Polynomial, polySize+1), &srs.CommitKey, 0)
+	require.ErrorIs(t, err, kzg.ErrInvalidPolynomialSize)
+
+	_, err = kzg.Open(domain, make(kzg.Polynomial, polySize/2), fr.One(), &srs.CommitKey, 0)
+	require.ErrorIs(t, err, kzg.ErrPolynomialMismatchedSizeDomain)
+
+	_, err = kzg.NewLagrangeSRSInsecure(kzg.NewDomain(1), big.NewInt(1234))
+	require.ErrorIs(t, err, kzg.ErrMinSRSSize)
+}
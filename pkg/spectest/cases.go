@@ -0,0 +1,157 @@
+// Package spectest runs the KZG test vectors of the [consensus-spec-tests] against a [gokzg4844.Context], so that
+// the clients using this library do not need to decode the fixtures themselves.
+//
+// The fixtures are laid out as <handler>/<suite>/<case>/data.yaml, for instance
+// verify_blob_kzg_proof/kzg-mainnet/verify_blob_kzg_proof_case_correct_proof_19b3f3f8c98ea31e/data.yaml, either
+// directly or anywhere below the directory given to [RunAll], which is the case in the consensus-spec-tests
+// archives. The cases can also be given as data.json, with the same fields.
+//
+// Every case has an input and an output. Following the specs, an output of null means that the input is invalid and
+// that the function must raise an exception, which for this library is an error wrapping [gokzg4844.ErrInvalidInput].
+//
+// [consensus-spec-tests]: https://github.com/ethereum/consensus-spec-tests
+package spectest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The handlers of the KZG test vectors supported by [RunAll], which are the names of the directories holding their
+// cases.
+const (
+	HandlerBlobToKZGCommitment     = "blob_to_kzg_commitment"
+	HandlerComputeKZGProof         = "compute_kzg_proof"
+	HandlerComputeBlobKZGProof     = "compute_blob_kzg_proof"
+	HandlerVerifyKZGProof          = "verify_kzg_proof"
+	HandlerVerifyBlobKZGProof      = "verify_blob_kzg_proof"
+	HandlerVerifyBlobKZGProofBatch = "verify_blob_kzg_proof_batch"
+
+	HandlerComputeCells             = "compute_cells"
+	HandlerComputeCellsAndKZGProofs = "compute_cells_and_kzg_proofs"
+	HandlerVerifyCellKZGProofBatch  = "verify_cell_kzg_proof_batch"
+	HandlerRecoverCellsAndKZGProofs = "recover_cells_and_kzg_proofs"
+)
+
+// The cases of each handler hold the inputs and outputs as they are written in the fixtures: the blobs, scalars,
+// points and cells are hex-strings with the 0x prefix, which are only decoded when the case is run, since decoding
+// them is part of what an invalid case checks. A nil output means that the input is invalid.
+
+// BlobToKZGCommitmentCase is a case of the blob_to_kzg_commitment handler.
+type BlobToKZGCommitmentCase struct {
+	Input struct {
+		Blob string `yaml:"blob" json:"blob"`
+	} `yaml:"input" json:"input"`
+	Output *string `yaml:"output" json:"output"`
+}
+
+// ComputeKZGProofCase is a case of the compute_kzg_proof handler, whose output is the proof and the evaluation of the
+// blob at z.
+type ComputeKZGProofCase struct {
+	Input struct {
+		Blob string `yaml:"blob" json:"blob"`
+		Z    string `yaml:"z" json:"z"`
+	} `yaml:"input" json:"input"`
+	Output *[2]string `yaml:"output" json:"output"`
+}
+
+// ComputeBlobKZGProofCase is a case of the compute_blob_kzg_proof handler.
+type ComputeBlobKZGProofCase struct {
+	Input struct {
+		Blob       string `yaml:"blob" json:"blob"`
+		Commitment string `yaml:"commitment" json:"commitment"`
+	} `yaml:"input" json:"input"`
+	Output *string `yaml:"output" json:"output"`
+}
+
+// VerifyKZGProofCase is a case of the verify_kzg_proof handler.
+type VerifyKZGProofCase struct {
+	Input struct {
+		Commitment string `yaml:"commitment" json:"commitment"`
+		Z          string `yaml:"z" json:"z"`
+		Y          string `yaml:"y" json:"y"`
+		Proof      string `yaml:"proof" json:"proof"`
+	} `yaml:"input" json:"input"`
+	Output *bool `yaml:"output" json:"output"`
+}
+
+// VerifyBlobKZGProofCase is a case of the verify_blob_kzg_proof handler.
+type VerifyBlobKZGProofCase struct {
+	Input struct {
+		Blob       string `yaml:"blob" json:"blob"`
+		Commitment string `yaml:"commitment" json:"commitment"`
+		Proof      string `yaml:"proof" json:"proof"`
+	} `yaml:"input" json:"input"`
+	Output *bool `yaml:"output" json:"output"`
+}
+
+// VerifyBlobKZGProofBatchCase is a case of the verify_blob_kzg_proof_batch handler.
+type VerifyBlobKZGProofBatchCase struct {
+	Input struct {
+		Blobs       []string `yaml:"blobs" json:"blobs"`
+		Commitments []string `yaml:"commitments" json:"commitments"`
+		Proofs      []string `yaml:"proofs" json:"proofs"`
+	} `yaml:"input" json:"input"`
+	Output *bool `yaml:"output" json:"output"`
+}
+
+// ComputeCellsCase is a case of the compute_cells handler.
+type ComputeCellsCase struct {
+	Input struct {
+		Blob string `yaml:"blob" json:"blob"`
+	} `yaml:"input" json:"input"`
+	Output *[]string `yaml:"output" json:"output"`
+}
+
+// ComputeCellsAndKZGProofsCase is a case of the compute_cells_and_kzg_proofs handler, whose output is the cells and
+// their proofs.
+type ComputeCellsAndKZGProofsCase struct {
+	Input struct {
+		Blob string `yaml:"blob" json:"blob"`
+	} `yaml:"input" json:"input"`
+	Output *[2][]string `yaml:"output" json:"output"`
+}
+
+// VerifyCellKZGProofBatchCase is a case of the verify_cell_kzg_proof_batch handler.
+type VerifyCellKZGProofBatchCase struct {
+	Input struct {
+		Commitments []string `yaml:"commitments" json:"commitments"`
+		CellIndices []uint64 `yaml:"cell_indices" json:"cell_indices"`
+		Cells       []string `yaml:"cells" json:"cells"`
+		Proofs      []string `yaml:"proofs" json:"proofs"`
+	} `yaml:"input" json:"input"`
+	Output *bool `yaml:"output" json:"output"`
+}
+
+// RecoverCellsAndKZGProofsCase is a case of the recover_cells_and_kzg_proofs handler, whose output is all of the
+// cells of the extended blob and their proofs.
+type RecoverCellsAndKZGProofsCase struct {
+	Input struct {
+		CellIndices []uint64 `yaml:"cell_indices" json:"cell_indices"`
+		Cells       []string `yaml:"cells" json:"cells"`
+	} `yaml:"input" json:"input"`
+	Output *[2][]string `yaml:"output" json:"output"`
+}
+
+// LoadCase decodes the case in the file at path into testCase, which must be a pointer to one of the case types of
+// this package. The file is decoded as JSON if its name ends with .json, and as YAML otherwise.
+func LoadCase(path string, testCase any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, testCase)
+	} else {
+		err = yaml.Unmarshal(data, testCase)
+	}
+	if err != nil {
+		return fmt.Errorf("could not decode the case %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,526 @@
+package spectest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"gopkg.in/yaml.v2"
+)
+
+// Suite is the name of the directory holding the cases of a handler written by [Generate], which is the one used by
+// the consensus-spec-tests for the mainnet preset.
+const Suite = "kzg-mainnet"
+
+// generatedCase is a case written by [Generate], whose directory is named after name.
+type generatedCase struct {
+	name     string
+	testCase any
+}
+
+// generators maps each supported handler to the function generating its cases from a seed.
+var generators = map[string]func(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error){
+	HandlerBlobToKZGCommitment:     generateBlobToKZGCommitment,
+	HandlerComputeKZGProof:         generateComputeKZGProof,
+	HandlerComputeBlobKZGProof:     generateComputeBlobKZGProof,
+	HandlerVerifyKZGProof:          generateVerifyKZGProof,
+	HandlerVerifyBlobKZGProof:      generateVerifyBlobKZGProof,
+	HandlerVerifyBlobKZGProofBatch: generateVerifyBlobKZGProofBatch,
+
+	HandlerComputeCells:             generateComputeCells,
+	HandlerComputeCellsAndKZGProofs: generateComputeCellsAndKZGProofs,
+	HandlerVerifyCellKZGProofBatch:  generateVerifyCellKZGProofBatch,
+	HandlerRecoverCellsAndKZGProofs: generateRecoverCellsAndKZGProofs,
+}
+
+// Handlers returns the handlers supported by [Generate], sorted.
+func Handlers() []string {
+	handlers := make([]string, 0, len(generators))
+	for handler := range generators {
+		handlers = append(handlers, handler)
+	}
+	sort.Strings(handlers)
+	return handlers
+}
+
+// Generate writes cases of the handler derived from the seed below dir, in the layout of the consensus-spec-tests:
+// each case is written to dir/<handler>/[Suite]/<handler>_case_<name>_<hash>/data.yaml, where hash is the start of
+// the SHA-256 hash of the file. The inputs are generated using [testutil], and include invalid ones, such as
+// non-canonical scalars or invalid point encodings.
+//
+// The outputs are those of ctx, so the cases are only as correct as this library: they are meant for the features
+// which do not have official test vectors yet, so that other implementations can be checked against this one. The
+// cell handlers need the monomial G1 points of the trusted setup, see [RunAll].
+func Generate(ctx *gokzg4844.Context, handler string, seed int64, dir string) error {
+	generate, ok := generators[handler]
+	if !ok {
+		return fmt.Errorf("the handler %q is not supported", handler)
+	}
+	cases, err := generate(ctx, seed)
+	if err != nil {
+		return fmt.Errorf("could not generate the cases of %s: %w", handler, err)
+	}
+
+	for _, generated := range cases {
+		data, err := yaml.Marshal(generated.testCase)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(data)
+		caseDir := filepath.Join(dir, handler, Suite, fmt.Sprintf("%s_case_%s_%x", handler, generated.name, hash[:8]))
+		if err := os.MkdirAll(caseDir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(caseDir, "data.yaml"), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateAll calls [Generate] for every handler returned by [Handlers].
+func GenerateAll(ctx *gokzg4844.Context, seed int64, dir string) error {
+	for _, handler := range Handlers() {
+		if err := Generate(ctx, handler, seed, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The inputs shared by the generators. The invalid point has the infinity flag and a non-zero coordinate, so it is not
+// a valid encoding.
+var (
+	zeroBlob     gokzg4844.Blob
+	invalidPoint = [48]byte{0: 0xc0, 47: 1}
+)
+
+// invalidBlob returns a blob derived from the seed whose scalar at an index derived from the seed is not canonical.
+func invalidBlob(seed int64) *gokzg4844.Blob {
+	return testutil.GenerateInvalidBlob(seed, int(uint64(seed)%gokzg4844.ScalarsPerBlob))
+}
+
+func generateBlobToKZGCommitment(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	blobs := []struct {
+		name string
+		blob *gokzg4844.Blob
+	}{
+		{"valid_blob", testutil.GenerateBlob(seed)},
+		{"zero_blob", &zeroBlob},
+		{"invalid_blob_non_canonical_scalar", invalidBlob(seed)},
+	}
+
+	var cases []generatedCase
+	for _, input := range blobs {
+		var testCase BlobToKZGCommitmentCase
+		testCase.Input.Blob = hexString(input.blob[:])
+		commitment, err := ctx.BlobToKZGCommitment(input.blob, 0)
+		if testCase.Output, err = hexOutput(commitment[:], err); err != nil {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+
+	// A blob which is one byte short cannot be decoded
+	var testCase BlobToKZGCommitmentCase
+	testCase.Input.Blob = hexString(testutil.GenerateBlob(seed)[1:])
+	cases = append(cases, generatedCase{"invalid_blob_length", testCase})
+	return cases, nil
+}
+
+func generateComputeKZGProof(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	blob := testutil.GenerateBlob(seed)
+	z := testutil.GenerateScalar(seed)
+	inputs := []struct {
+		name string
+		blob *gokzg4844.Blob
+		z    gokzg4844.Scalar
+	}{
+		{"valid", blob, z},
+		{"zero_z", blob, gokzg4844.Scalar{}},
+		{"zero_blob", &zeroBlob, z},
+		{"invalid_blob", invalidBlob(seed), z},
+		{"invalid_z_non_canonical", blob, testutil.NonCanonicalScalar(z)},
+	}
+
+	var cases []generatedCase
+	for _, input := range inputs {
+		var testCase ComputeKZGProofCase
+		testCase.Input.Blob = hexString(input.blob[:])
+		testCase.Input.Z = hexString(input.z[:])
+		proof, y, err := ctx.ComputeKZGProof(input.blob, input.z, 0)
+		if err == nil {
+			testCase.Output = &[2]string{hexString(proof[:]), hexString(y[:])}
+		} else if !errors.Is(err, gokzg4844.ErrInvalidInput) {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+func generateComputeBlobKZGProof(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	fixture, other, err := twoFixtures(ctx, seed)
+	if err != nil {
+		return nil, err
+	}
+	inputs := []struct {
+		name       string
+		blob       *gokzg4844.Blob
+		commitment gokzg4844.KZGCommitment
+	}{
+		{"valid", fixture.Blob, fixture.Commitment},
+		{"commitment_of_another_blob", fixture.Blob, other.Commitment},
+		{"invalid_blob", invalidBlob(seed), fixture.Commitment},
+		{"invalid_commitment", fixture.Blob, invalidPoint},
+	}
+
+	var cases []generatedCase
+	for _, input := range inputs {
+		var testCase ComputeBlobKZGProofCase
+		testCase.Input.Blob = hexString(input.blob[:])
+		testCase.Input.Commitment = hexString(input.commitment[:])
+		proof, err := ctx.ComputeBlobKZGProof(input.blob, input.commitment, 0)
+		if testCase.Output, err = hexOutput(proof[:], err); err != nil {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+func generateVerifyKZGProof(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	blob := testutil.GenerateBlob(seed)
+	commitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	if err != nil {
+		return nil, err
+	}
+	z := testutil.GenerateScalar(seed)
+	proof, y, err := ctx.ComputeKZGProof(blob, z, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := []struct {
+		name       string
+		commitment gokzg4844.KZGCommitment
+		z, y       gokzg4844.Scalar
+		proof      gokzg4844.KZGProof
+	}{
+		{"correct_proof", commitment, z, y, proof},
+		{"incorrect_y", commitment, z, testutil.GenerateScalar(seed + 1), proof},
+		{"invalid_commitment", invalidPoint, z, y, proof},
+		{"invalid_z_non_canonical", commitment, testutil.NonCanonicalScalar(z), y, proof},
+		{"invalid_proof", commitment, z, y, invalidPoint},
+	}
+
+	var cases []generatedCase
+	for _, input := range inputs {
+		var testCase VerifyKZGProofCase
+		testCase.Input.Commitment = hexString(input.commitment[:])
+		testCase.Input.Z = hexString(input.z[:])
+		testCase.Input.Y = hexString(input.y[:])
+		testCase.Input.Proof = hexString(input.proof[:])
+		err := ctx.VerifyKZGProof(input.commitment, input.z, input.y, input.proof)
+		if testCase.Output, err = verificationOutput(err); err != nil {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+func generateVerifyBlobKZGProof(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	fixture, other, err := twoFixtures(ctx, seed)
+	if err != nil {
+		return nil, err
+	}
+	inputs := []struct {
+		name       string
+		blob       *gokzg4844.Blob
+		commitment gokzg4844.KZGCommitment
+		proof      gokzg4844.KZGProof
+	}{
+		{"correct_proof", fixture.Blob, fixture.Commitment, fixture.Proof},
+		{"incorrect_proof", fixture.Blob, fixture.Commitment, other.Proof},
+		{"invalid_blob", invalidBlob(seed), fixture.Commitment, fixture.Proof},
+		{"invalid_commitment", fixture.Blob, invalidPoint, fixture.Proof},
+		{"invalid_proof", fixture.Blob, fixture.Commitment, invalidPoint},
+	}
+
+	var cases []generatedCase
+	for _, input := range inputs {
+		var testCase VerifyBlobKZGProofCase
+		testCase.Input.Blob = hexString(input.blob[:])
+		testCase.Input.Commitment = hexString(input.commitment[:])
+		testCase.Input.Proof = hexString(input.proof[:])
+		err := ctx.VerifyBlobKZGProof(input.blob, input.commitment, input.proof)
+		if testCase.Output, err = verificationOutput(err); err != nil {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+func generateVerifyBlobKZGProofBatch(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	const batchSize = 3
+	var blobs []gokzg4844.Blob
+	var commitments []gokzg4844.KZGCommitment
+	var proofs []gokzg4844.KZGProof
+	for i := int64(0); i < batchSize; i++ {
+		fixture, err := testutil.GenerateProofFixture(ctx, seed+i)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, *fixture.Blob)
+		commitments = append(commitments, fixture.Commitment)
+		proofs = append(proofs, fixture.Proof)
+	}
+	swappedProofs := append([]gokzg4844.KZGProof{proofs[1], proofs[0]}, proofs[2:]...)
+	invalidBlobs := append([]gokzg4844.Blob{*invalidBlob(seed)}, blobs[1:]...)
+
+	inputs := []struct {
+		name        string
+		blobs       []gokzg4844.Blob
+		commitments []gokzg4844.KZGCommitment
+		proofs      []gokzg4844.KZGProof
+	}{
+		{"correct_proofs", blobs, commitments, proofs},
+		{"empty_batch", nil, nil, nil},
+		{"incorrect_proofs", blobs, commitments, swappedProofs},
+		{"invalid_blob", invalidBlobs, commitments, proofs},
+		{"invalid_length", blobs, commitments, proofs[1:]},
+	}
+
+	var cases []generatedCase
+	for _, input := range inputs {
+		var testCase VerifyBlobKZGProofBatchCase
+		testCase.Input.Blobs = hexStrings(input.blobs, func(blob *gokzg4844.Blob) []byte { return blob[:] })
+		testCase.Input.Commitments = hexStrings(input.commitments, func(c *gokzg4844.KZGCommitment) []byte { return c[:] })
+		testCase.Input.Proofs = hexStrings(input.proofs, proofBytes)
+		err := ctx.VerifyBlobKZGProofBatch(input.blobs, input.commitments, input.proofs)
+		if testCase.Output, err = verificationOutput(err); err != nil {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+func generateComputeCells(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	var cases []generatedCase
+	for _, input := range cellBlobs(seed) {
+		var testCase ComputeCellsCase
+		testCase.Input.Blob = hexString(input.blob[:])
+		cells, err := ctx.ComputeCells(input.blob)
+		if err == nil {
+			cellStrs := hexStrings(cells[:], cellBytes)
+			testCase.Output = &cellStrs
+		} else if !errors.Is(err, gokzg4844.ErrInvalidInput) {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+func generateComputeCellsAndKZGProofs(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	var cases []generatedCase
+	for _, input := range cellBlobs(seed) {
+		var testCase ComputeCellsAndKZGProofsCase
+		testCase.Input.Blob = hexString(input.blob[:])
+		cells, proofs, err := ctx.ComputeCellsAndKZGProofs(input.blob)
+		if err == nil {
+			testCase.Output = &[2][]string{hexStrings(cells[:], cellBytes), hexStrings(proofs[:], proofBytes)}
+		} else if !errors.Is(err, gokzg4844.ErrInvalidInput) {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+// cellBlobs returns the blobs given to the cell handlers computing the cells of a blob.
+func cellBlobs(seed int64) []struct {
+	name string
+	blob *gokzg4844.Blob
+} {
+	return []struct {
+		name string
+		blob *gokzg4844.Blob
+	}{
+		{"valid_blob", testutil.GenerateBlob(seed)},
+		{"zero_blob", &zeroBlob},
+		{"invalid_blob", invalidBlob(seed)},
+	}
+}
+
+func generateVerifyCellKZGProofBatch(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	blob := testutil.GenerateBlob(seed)
+	commitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	if err != nil {
+		return nil, err
+	}
+	allCells, allProofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first, a middle and the last cell of the extended blob
+	indices := []uint64{0, gokzg4844.CellsPerExtBlob / 2, gokzg4844.CellsPerExtBlob - 1}
+	var commitments []gokzg4844.KZGCommitment
+	var cells []gokzg4844.Cell
+	var proofs []gokzg4844.KZGProof
+	for _, index := range indices {
+		commitments = append(commitments, commitment)
+		cells = append(cells, allCells[index])
+		proofs = append(proofs, allProofs[index])
+	}
+	swappedProofs := append([]gokzg4844.KZGProof{proofs[1], proofs[0]}, proofs[2:]...)
+	invalidProofs := append([]gokzg4844.KZGProof{invalidPoint}, proofs[1:]...)
+	invalidIndices := append([]uint64{gokzg4844.CellsPerExtBlob}, indices[1:]...)
+
+	inputs := []struct {
+		name        string
+		commitments []gokzg4844.KZGCommitment
+		indices     []uint64
+		cells       []gokzg4844.Cell
+		proofs      []gokzg4844.KZGProof
+	}{
+		{"correct_proofs", commitments, indices, cells, proofs},
+		{"empty_batch", nil, nil, nil, nil},
+		{"incorrect_proofs", commitments, indices, cells, swappedProofs},
+		{"invalid_cell_index", commitments, invalidIndices, cells, proofs},
+		{"invalid_proof", commitments, indices, cells, invalidProofs},
+		{"invalid_length", commitments[1:], indices, cells, proofs},
+	}
+
+	var cases []generatedCase
+	for _, input := range inputs {
+		var testCase VerifyCellKZGProofBatchCase
+		testCase.Input.Commitments = hexStrings(input.commitments, func(c *gokzg4844.KZGCommitment) []byte { return c[:] })
+		testCase.Input.CellIndices = append([]uint64{}, input.indices...)
+		testCase.Input.Cells = hexStrings(input.cells, cellBytes)
+		testCase.Input.Proofs = hexStrings(input.proofs, proofBytes)
+		err := ctx.VerifyCellKZGProofBatch(input.commitments, input.indices, input.cells, input.proofs)
+		if testCase.Output, err = verificationOutput(err); err != nil {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+func generateRecoverCellsAndKZGProofs(ctx *gokzg4844.Context, seed int64) ([]generatedCase, error) {
+	allCells, _, err := ctx.ComputeCellsAndKZGProofs(testutil.GenerateBlob(seed))
+	if err != nil {
+		return nil, err
+	}
+
+	// Half of the cells, which is the least number of cells needed to recover the others
+	var indices []uint64
+	var cells []gokzg4844.Cell
+	for i := uint64(0); i < gokzg4844.CellsPerExtBlob; i += 2 {
+		indices = append(indices, i)
+		cells = append(cells, allCells[i])
+	}
+	duplicateIndices := append([]uint64{indices[1]}, indices[1:]...)
+
+	inputs := []struct {
+		name    string
+		indices []uint64
+		cells   []gokzg4844.Cell
+	}{
+		{"half_of_the_cells", indices, cells},
+		{"not_enough_cells", indices[1:], cells[1:]},
+		{"duplicate_cell_index", duplicateIndices, cells},
+		{"invalid_length", indices, cells[1:]},
+	}
+
+	var cases []generatedCase
+	for _, input := range inputs {
+		var testCase RecoverCellsAndKZGProofsCase
+		testCase.Input.CellIndices = append([]uint64{}, input.indices...)
+		testCase.Input.Cells = hexStrings(input.cells, cellBytes)
+		recoveredCells, recoveredProofs, err := ctx.RecoverCellsAndKZGProofs(input.indices, input.cells)
+		if err == nil {
+			testCase.Output = &[2][]string{hexStrings(recoveredCells[:], cellBytes), hexStrings(recoveredProofs[:], proofBytes)}
+		} else if !errors.Is(err, gokzg4844.ErrInvalidInput) {
+			return nil, err
+		}
+		cases = append(cases, generatedCase{input.name, testCase})
+	}
+	return cases, nil
+}
+
+// twoFixtures returns the proof fixtures of the seed and of the next one.
+func twoFixtures(ctx *gokzg4844.Context, seed int64) (*testutil.ProofFixture, *testutil.ProofFixture, error) {
+	fixture, err := testutil.GenerateProofFixture(ctx, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	other, err := testutil.GenerateProofFixture(ctx, seed+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fixture, other, nil
+}
+
+// hexOutput returns the output of a case whose function returned b and err: nil if err wraps
+// [gokzg4844.ErrInvalidInput], and b as a hex-string if err is nil. Any other error is returned.
+func hexOutput(b []byte, err error) (*string, error) {
+	if err != nil {
+		if errors.Is(err, gokzg4844.ErrInvalidInput) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	s := hexString(b)
+	return &s, nil
+}
+
+// verificationOutput returns the output of a case whose verification method returned err, see [checkVerification].
+// Any error which is not classified is returned.
+func verificationOutput(err error) (*bool, error) {
+	var valid bool
+	switch {
+	case err == nil:
+		valid = true
+	case errors.Is(err, gokzg4844.ErrProofInvalid):
+		valid = false
+	case errors.Is(err, gokzg4844.ErrInvalidInput):
+		return nil, nil
+	default:
+		return nil, err
+	}
+	return &valid, nil
+}
+
+func hexString(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// hexStrings returns the hex-strings of the values, whose bytes are returned by toBytes. It returns an empty slice
+// rather than nil for no values, so that the inputs of the empty cases are written as empty lists.
+func hexStrings[T any](values []T, toBytes func(*T) []byte) []string {
+	strs := make([]string, len(values))
+	for i := range values {
+		strs[i] = hexString(toBytes(&values[i]))
+	}
+	return strs
+}
+
+func cellBytes(cell *gokzg4844.Cell) []byte {
+	return cell[:]
+}
+
+func proofBytes(proof *gokzg4844.KZGProof) []byte {
+	return proof[:]
+}
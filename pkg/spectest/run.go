@@ -0,0 +1,441 @@
+package spectest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+)
+
+// runners maps each supported handler to the function running one of its cases.
+var runners = map[string]func(t *testing.T, ctx *gokzg4844.Context, path string){
+	HandlerBlobToKZGCommitment:     runBlobToKZGCommitment,
+	HandlerComputeKZGProof:         runComputeKZGProof,
+	HandlerComputeBlobKZGProof:     runComputeBlobKZGProof,
+	HandlerVerifyKZGProof:          runVerifyKZGProof,
+	HandlerVerifyBlobKZGProof:      runVerifyBlobKZGProof,
+	HandlerVerifyBlobKZGProofBatch: runVerifyBlobKZGProofBatch,
+
+	HandlerComputeCells:             runComputeCells,
+	HandlerComputeCellsAndKZGProofs: runComputeCellsAndKZGProofs,
+	HandlerVerifyCellKZGProofBatch:  runVerifyCellKZGProofBatch,
+	HandlerRecoverCellsAndKZGProofs: runRecoverCellsAndKZGProofs,
+}
+
+// RunAll runs every case found below dir as a subtest of t, named after the path of the case relative to dir.
+//
+// The handler of a case is the name of the directory two levels above the directory of the case, see the package
+// documentation. The cases of the handlers which are not supported are skipped, and t fails if no case is found, so
+// that a wrong directory is not mistaken for a passing run. The cell handlers need the monomial G1 points of the
+// trusted setup, so ctx must not be created using [gokzg4844.WithoutMonomialSRS] if dir holds such cases.
+func RunAll(t *testing.T, ctx *gokzg4844.Context, dir string) {
+	t.Helper()
+
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && (entry.Name() == "data.yaml" || entry.Name() == "data.json") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not walk %s: %v", dir, err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no test case found below %s", dir)
+	}
+
+	for _, path := range paths {
+		path := path
+		name, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			name = path
+		}
+		t.Run(filepath.ToSlash(name), func(t *testing.T) {
+			RunCase(t, ctx, Handler(path), path)
+		})
+	}
+}
+
+// Handler returns the handler of the case in the file at path, which is the name of the directory two levels above
+// the directory of the case.
+func Handler(path string) string {
+	return filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(path))))
+}
+
+// RunCase runs the case of the given handler in the file at path, and fails t if the output of ctx does not match
+// the output of the case. It skips the case if the handler is not supported.
+func RunCase(t *testing.T, ctx *gokzg4844.Context, handler, path string) {
+	t.Helper()
+
+	run, ok := runners[handler]
+	if !ok {
+		t.Skipf("the handler %q is not supported", handler)
+	}
+	run(t, ctx, path)
+}
+
+func runBlobToKZGCommitment(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase BlobToKZGCommitmentCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	blob, err := decodeBlob(testCase.Input.Blob)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	commitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	if outputError(t, invalid, err) {
+		return
+	}
+	checkEqual(t, "commitment", *testCase.Output, commitment[:])
+}
+
+func runComputeKZGProof(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase ComputeKZGProofCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	blob, err := decodeBlob(testCase.Input.Blob)
+	if inputError(t, invalid, err) {
+		return
+	}
+	z, err := decodeScalar(testCase.Input.Z)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	proof, y, err := ctx.ComputeKZGProof(blob, z, 0)
+	if outputError(t, invalid, err) {
+		return
+	}
+	checkEqual(t, "proof", testCase.Output[0], proof[:])
+	checkEqual(t, "y", testCase.Output[1], y[:])
+}
+
+func runComputeBlobKZGProof(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase ComputeBlobKZGProofCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	blob, err := decodeBlob(testCase.Input.Blob)
+	if inputError(t, invalid, err) {
+		return
+	}
+	commitment, err := decodeCommitment(testCase.Input.Commitment)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, 0)
+	if outputError(t, invalid, err) {
+		return
+	}
+	checkEqual(t, "proof", *testCase.Output, proof[:])
+}
+
+func runVerifyKZGProof(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase VerifyKZGProofCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	commitment, err := decodeCommitment(testCase.Input.Commitment)
+	if inputError(t, invalid, err) {
+		return
+	}
+	z, err := decodeScalar(testCase.Input.Z)
+	if inputError(t, invalid, err) {
+		return
+	}
+	y, err := decodeScalar(testCase.Input.Y)
+	if inputError(t, invalid, err) {
+		return
+	}
+	proof, err := decodeProof(testCase.Input.Proof)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	checkVerification(t, testCase.Output, ctx.VerifyKZGProof(commitment, z, y, proof))
+}
+
+func runVerifyBlobKZGProof(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase VerifyBlobKZGProofCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	blob, err := decodeBlob(testCase.Input.Blob)
+	if inputError(t, invalid, err) {
+		return
+	}
+	commitment, err := decodeCommitment(testCase.Input.Commitment)
+	if inputError(t, invalid, err) {
+		return
+	}
+	proof, err := decodeProof(testCase.Input.Proof)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	checkVerification(t, testCase.Output, ctx.VerifyBlobKZGProof(blob, commitment, proof))
+}
+
+func runVerifyBlobKZGProofBatch(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase VerifyBlobKZGProofBatchCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	blobs, err := decodeAll(testCase.Input.Blobs, decodeBlobValue)
+	if inputError(t, invalid, err) {
+		return
+	}
+	commitments, err := decodeAll(testCase.Input.Commitments, decodeCommitment)
+	if inputError(t, invalid, err) {
+		return
+	}
+	proofs, err := decodeAll(testCase.Input.Proofs, decodeProof)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	checkVerification(t, testCase.Output, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+}
+
+func runComputeCells(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase ComputeCellsCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	blob, err := decodeBlob(testCase.Input.Blob)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	cells, err := ctx.ComputeCells(blob)
+	if outputError(t, invalid, err) {
+		return
+	}
+	checkCells(t, *testCase.Output, cells[:])
+}
+
+func runComputeCellsAndKZGProofs(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase ComputeCellsAndKZGProofsCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	blob, err := decodeBlob(testCase.Input.Blob)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	if outputError(t, invalid, err) {
+		return
+	}
+	checkCells(t, testCase.Output[0], cells[:])
+	checkProofs(t, testCase.Output[1], proofs[:])
+}
+
+func runVerifyCellKZGProofBatch(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase VerifyCellKZGProofBatchCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	commitments, err := decodeAll(testCase.Input.Commitments, decodeCommitment)
+	if inputError(t, invalid, err) {
+		return
+	}
+	cells, err := decodeAll(testCase.Input.Cells, decodeCell)
+	if inputError(t, invalid, err) {
+		return
+	}
+	proofs, err := decodeAll(testCase.Input.Proofs, decodeProof)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	checkVerification(t, testCase.Output, ctx.VerifyCellKZGProofBatch(commitments, testCase.Input.CellIndices, cells, proofs))
+}
+
+func runRecoverCellsAndKZGProofs(t *testing.T, ctx *gokzg4844.Context, path string) {
+	var testCase RecoverCellsAndKZGProofsCase
+	loadCase(t, path, &testCase)
+	invalid := testCase.Output == nil
+
+	cells, err := decodeAll(testCase.Input.Cells, decodeCell)
+	if inputError(t, invalid, err) {
+		return
+	}
+
+	recoveredCells, recoveredProofs, err := ctx.RecoverCellsAndKZGProofs(testCase.Input.CellIndices, cells)
+	if outputError(t, invalid, err) {
+		return
+	}
+	checkCells(t, testCase.Output[0], recoveredCells[:])
+	checkProofs(t, testCase.Output[1], recoveredProofs[:])
+}
+
+// loadCase is [LoadCase] which fails t if the case cannot be decoded.
+func loadCase(t *testing.T, path string, testCase any) {
+	t.Helper()
+	if err := LoadCase(path, testCase); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// inputError reports whether an input of the case could not be decoded, in which case the case is done. This is
+// only expected for invalid cases, such as those holding a blob of the wrong length.
+func inputError(t *testing.T, invalid bool, err error) bool {
+	t.Helper()
+	if err == nil {
+		return false
+	}
+	if !invalid {
+		t.Fatalf("could not decode an input of a valid case: %v", err)
+	}
+	return true
+}
+
+// outputError checks the error returned by a function computing the output of a case, and reports whether the case
+// is done: an invalid case must fail with an error wrapping [gokzg4844.ErrInvalidInput], and a valid one must not
+// fail.
+func outputError(t *testing.T, invalid bool, err error) bool {
+	t.Helper()
+	if invalid {
+		if !errors.Is(err, gokzg4844.ErrInvalidInput) {
+			t.Fatalf("expected an error wrapping ErrInvalidInput for an invalid case, got %v", err)
+		}
+		return true
+	}
+	if err != nil {
+		t.Fatalf("unexpected error for a valid case: %v", err)
+	}
+	return false
+}
+
+// checkVerification checks the error returned by a verification method against the output of the case: nil for an
+// invalid input, true for a valid proof or false for a proof which does not verify.
+func checkVerification(t *testing.T, output *bool, err error) {
+	t.Helper()
+	switch {
+	case output == nil:
+		if !errors.Is(err, gokzg4844.ErrInvalidInput) {
+			t.Fatalf("expected an error wrapping ErrInvalidInput for an invalid case, got %v", err)
+		}
+	case *output:
+		if err != nil {
+			t.Fatalf("expected the proof to verify, got %v", err)
+		}
+	default:
+		if !errors.Is(err, gokzg4844.ErrProofInvalid) {
+			t.Fatalf("expected an error wrapping ErrProofInvalid, got %v", err)
+		}
+	}
+}
+
+// checkEqual fails t if got is not the bytes of the hex-string expected.
+func checkEqual(t *testing.T, name, expected string, got []byte) {
+	t.Helper()
+	want, err := hex.DecodeString(strings.TrimPrefix(expected, "0x"))
+	if err != nil {
+		t.Fatalf("could not decode the expected %s: %v", name, err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Fatalf("expected %s %s, got 0x%x", name, expected, got)
+	}
+}
+
+func checkCells(t *testing.T, expected []string, cells []gokzg4844.Cell) {
+	t.Helper()
+	if len(expected) != len(cells) {
+		t.Fatalf("expected %d cells, got %d", len(expected), len(cells))
+	}
+	for i := range cells {
+		checkEqual(t, fmt.Sprintf("cell %d", i), expected[i], cells[i][:])
+	}
+}
+
+func checkProofs(t *testing.T, expected []string, proofs []gokzg4844.KZGProof) {
+	t.Helper()
+	if len(expected) != len(proofs) {
+		t.Fatalf("expected %d proofs, got %d", len(expected), len(proofs))
+	}
+	for i := range proofs {
+		checkEqual(t, fmt.Sprintf("proof %d", i), expected[i], proofs[i][:])
+	}
+}
+
+// decodeHex decodes the hex-string s, with the 0x prefix, into dst. The hex-string must encode exactly len(dst)
+// bytes.
+func decodeHex(dst []byte, s string) error {
+	if !strings.HasPrefix(s, "0x") {
+		return fmt.Errorf("the hex-string %.10q is not prefixed with 0x", s)
+	}
+	s = s[2:]
+	if len(s) != hex.EncodedLen(len(dst)) {
+		return fmt.Errorf("expected %d hex characters, got %d", hex.EncodedLen(len(dst)), len(s))
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+func decodeBlob(s string) (*gokzg4844.Blob, error) {
+	var blob gokzg4844.Blob
+	if err := decodeHex(blob[:], s); err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+func decodeBlobValue(s string) (gokzg4844.Blob, error) {
+	var blob gokzg4844.Blob
+	err := decodeHex(blob[:], s)
+	return blob, err
+}
+
+func decodeScalar(s string) (gokzg4844.Scalar, error) {
+	var scalar gokzg4844.Scalar
+	err := decodeHex(scalar[:], s)
+	return scalar, err
+}
+
+func decodeCommitment(s string) (gokzg4844.KZGCommitment, error) {
+	var commitment gokzg4844.KZGCommitment
+	err := decodeHex(commitment[:], s)
+	return commitment, err
+}
+
+func decodeProof(s string) (gokzg4844.KZGProof, error) {
+	var proof gokzg4844.KZGProof
+	err := decodeHex(proof[:], s)
+	return proof, err
+}
+
+func decodeCell(s string) (gokzg4844.Cell, error) {
+	var cell gokzg4844.Cell
+	err := decodeHex(cell[:], s)
+	return cell, err
+}
+
+// decodeAll decodes every hex-string of strs using decode.
+func decodeAll[T any](strs []string, decode func(string) (T, error)) ([]T, error) {
+	values := make([]T, len(strs))
+	for i, s := range strs {
+		var err error
+		values[i], err = decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return values, nil
+}
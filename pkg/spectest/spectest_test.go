@@ -0,0 +1,155 @@
+package spectest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/spectest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// The cases of the consensus specs vendored at the root of the repository
+const testDir = "../../tests"
+
+var ctx *gokzg4844.Context
+
+func TestMain(m *testing.M) {
+	var err error
+	ctx, err = gokzg4844.NewContext4096Secure()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not create the context:", err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestRunAll(t *testing.T) {
+	spectest.RunAll(t, ctx, testDir)
+}
+
+func TestHandler(t *testing.T) {
+	path := filepath.Join("tests", "mainnet", "deneb", "kzg", "verify_kzg_proof", "kzg-mainnet", "case", "data.yaml")
+	require.Equal(t, spectest.HandlerVerifyKZGProof, spectest.Handler(path))
+}
+
+func TestLoadCase(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(testDir, spectest.HandlerVerifyKZGProof, "*", "*", "data.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	var testCase spectest.VerifyKZGProofCase
+	require.NoError(t, spectest.LoadCase(paths[0], &testCase))
+	require.NotEmpty(t, testCase.Input.Commitment)
+
+	// The same case written as JSON decodes to the same value
+	data, err := json.Marshal(testCase)
+	require.NoError(t, err)
+	jsonPath := filepath.Join(t.TempDir(), "data.json")
+	require.NoError(t, os.WriteFile(jsonPath, data, 0o600))
+	var jsonCase spectest.VerifyKZGProofCase
+	require.NoError(t, spectest.LoadCase(jsonPath, &jsonCase))
+	require.Equal(t, testCase, jsonCase)
+}
+
+// No cases of the cell handlers are vendored, so these are written from the outputs of the context. This checks that
+// the cases are decoded and run, not that the outputs match the specs.
+func TestRunAllCells(t *testing.T) {
+	dir := t.TempDir()
+	writeCase := func(handler, name string, testCase any) {
+		caseDir := filepath.Join(dir, handler, "kzg-mainnet", name)
+		require.NoError(t, os.MkdirAll(caseDir, 0o700))
+		data, err := yaml.Marshal(testCase)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(caseDir, "data.yaml"), data, 0o600))
+	}
+
+	var blob gokzg4844.Blob
+	for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+		blob[i*gokzg4844.SerializedScalarSize+31] = byte(i)
+	}
+	commitment, err := ctx.BlobToKZGCommitment(&blob, 0)
+	require.NoError(t, err)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(&blob)
+	require.NoError(t, err)
+	cellStrs := make([]string, len(cells))
+	proofStrs := make([]string, len(proofs))
+	for i := range cells {
+		cellStrs[i] = fmt.Sprintf("0x%x", cells[i][:])
+		proofStrs[i] = fmt.Sprintf("0x%x", proofs[i][:])
+	}
+
+	var computeCells spectest.ComputeCellsCase
+	computeCells.Input.Blob = fmt.Sprintf("0x%x", blob[:])
+	computeCells.Output = &cellStrs
+	writeCase(spectest.HandlerComputeCells, "valid", computeCells)
+
+	var computeCellsAndProofs spectest.ComputeCellsAndKZGProofsCase
+	computeCellsAndProofs.Input.Blob = computeCells.Input.Blob
+	computeCellsAndProofs.Output = &[2][]string{cellStrs, proofStrs}
+	writeCase(spectest.HandlerComputeCellsAndKZGProofs, "valid", computeCellsAndProofs)
+	computeCellsAndProofs.Input.Blob = computeCells.Input.Blob[:len(computeCells.Input.Blob)-2]
+	computeCellsAndProofs.Output = nil
+	writeCase(spectest.HandlerComputeCellsAndKZGProofs, "invalid_blob_length", computeCellsAndProofs)
+
+	var recoverCells spectest.RecoverCellsAndKZGProofsCase
+	for i := 0; i < gokzg4844.CellsPerExtBlob; i += 2 {
+		recoverCells.Input.CellIndices = append(recoverCells.Input.CellIndices, uint64(i))
+		recoverCells.Input.Cells = append(recoverCells.Input.Cells, cellStrs[i])
+	}
+	recoverCells.Output = &[2][]string{cellStrs, proofStrs}
+	writeCase(spectest.HandlerRecoverCellsAndKZGProofs, "valid", recoverCells)
+	recoverCells.Input.CellIndices = recoverCells.Input.CellIndices[1:]
+	recoverCells.Input.Cells = recoverCells.Input.Cells[1:]
+	recoverCells.Output = nil
+	writeCase(spectest.HandlerRecoverCellsAndKZGProofs, "not_enough_cells", recoverCells)
+
+	var verifyCells spectest.VerifyCellKZGProofBatchCase
+	verifyCells.Input.Commitments = []string{fmt.Sprintf("0x%x", commitment[:])}
+	verifyCells.Input.CellIndices = []uint64{1}
+	verifyCells.Input.Cells = []string{cellStrs[1]}
+	verifyCells.Input.Proofs = []string{proofStrs[1]}
+	valid, invalid := true, false
+	verifyCells.Output = &valid
+	writeCase(spectest.HandlerVerifyCellKZGProofBatch, "valid", verifyCells)
+	verifyCells.Input.Proofs = []string{proofStrs[2]}
+	verifyCells.Output = &invalid
+	writeCase(spectest.HandlerVerifyCellKZGProofBatch, "incorrect_proof", verifyCells)
+	verifyCells.Input.CellIndices = []uint64{gokzg4844.CellsPerExtBlob}
+	verifyCells.Output = nil
+	writeCase(spectest.HandlerVerifyCellKZGProofBatch, "invalid_cell_index", verifyCells)
+
+	spectest.RunAll(t, ctx, dir)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, spectest.GenerateAll(ctx, 1, dir))
+
+	// Every handler has valid and invalid cases, in the layout read by RunAll
+	for _, handler := range spectest.Handlers() {
+		paths, err := filepath.Glob(filepath.Join(dir, handler, spectest.Suite, handler+"_case_*", "data.yaml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, paths, handler)
+		require.Equal(t, handler, spectest.Handler(paths[0]))
+	}
+	spectest.RunAll(t, ctx, dir)
+
+	// The cases only depend on the seed
+	again := t.TempDir()
+	require.NoError(t, spectest.Generate(ctx, spectest.HandlerVerifyBlobKZGProof, 1, again))
+	paths, err := filepath.Glob(filepath.Join(again, spectest.HandlerVerifyBlobKZGProof, spectest.Suite, "*", "data.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	for _, path := range paths {
+		relPath, err := filepath.Rel(again, path)
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(dir, relPath))
+	}
+
+	require.Error(t, spectest.Generate(ctx, "unknown_handler", 1, dir))
+}
@@ -0,0 +1,97 @@
+// Package testutil generates deterministic blobs, scalars and proofs from a seed, so that the tests of this library
+// and of the projects using it can build reproducible fixtures, including invalid ones.
+//
+// The outputs for a given seed are part of the API: they are the same in every release, and changing them is a
+// breaking change, since downstream tests may compare against values computed from them.
+package testutil
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// GenerateScalar returns the canonical scalar derived from the seed: the SHA-256 digest of the seed, as an 8 byte big
+// endian integer, interpreted as a big endian integer and reduced modulo the scalar field order.
+func GenerateScalar(seed int64) gokzg4844.Scalar {
+	var seedBytes [8]byte
+	binary.BigEndian.PutUint64(seedBytes[:], uint64(seed))
+	digest := sha256.Sum256(seedBytes[:])
+
+	var scalar fr.Element
+	scalar.SetBytes(digest[:])
+	return gokzg4844.SerializeScalar(scalar)
+}
+
+// GenerateBlob returns the canonical blob derived from the seed, whose scalar at index i is
+// GenerateScalar(seed + 32*i).
+//
+// The scalars of the blobs derived from seeds which differ by a multiple of 32 overlap, so the seeds of the blobs of
+// a fixture should be consecutive integers.
+func GenerateBlob(seed int64) *gokzg4844.Blob {
+	var blob gokzg4844.Blob
+	for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+		offset := i * gokzg4844.SerializedScalarSize
+		scalar := GenerateScalar(seed + int64(offset))
+		copy(blob[offset:offset+gokzg4844.SerializedScalarSize], scalar[:])
+	}
+	return &blob
+}
+
+// NonCanonicalScalar returns the non-canonical encoding of the canonical scalar s, which is s plus the scalar field
+// order. It represents the same field element as s, but it is rejected by [gokzg4844.DeserializeScalar]. This panics
+// if s is not canonical, since the sum may not fit in 32 bytes.
+func NonCanonicalScalar(s gokzg4844.Scalar) gokzg4844.Scalar {
+	if _, err := gokzg4844.DeserializeScalar(s); err != nil {
+		panic("testutil: the scalar is not canonical")
+	}
+
+	var value big.Int
+	value.SetBytes(s[:])
+	value.Add(&value, fr.Modulus())
+
+	var nonCanonical gokzg4844.Scalar
+	value.FillBytes(nonCanonical[:])
+	return nonCanonical
+}
+
+// GenerateNonCanonicalScalar returns NonCanonicalScalar(GenerateScalar(seed)).
+func GenerateNonCanonicalScalar(seed int64) gokzg4844.Scalar {
+	return NonCanonicalScalar(GenerateScalar(seed))
+}
+
+// GenerateInvalidBlob returns GenerateBlob(seed) with the scalar at the given index replaced by its non-canonical
+// encoding, see [NonCanonicalScalar]. Deserializing the blob fails with a [*gokzg4844.ScalarError] holding the index.
+// This panics if the index is not less than [gokzg4844.ScalarsPerBlob].
+func GenerateInvalidBlob(seed int64, index int) *gokzg4844.Blob {
+	blob := GenerateBlob(seed)
+	scalar := blob[index*gokzg4844.SerializedScalarSize : (index+1)*gokzg4844.SerializedScalarSize]
+	nonCanonical := NonCanonicalScalar(gokzg4844.Scalar(scalar))
+	copy(scalar, nonCanonical[:])
+	return blob
+}
+
+// ProofFixture is a blob along with its commitment and the proof returned by [gokzg4844.Context.ComputeBlobKZGProof].
+type ProofFixture struct {
+	Blob       *gokzg4844.Blob
+	Commitment gokzg4844.KZGCommitment
+	Proof      gokzg4844.KZGProof
+}
+
+// GenerateProofFixture returns the blob GenerateBlob(seed) with its commitment and proof, computed using ctx. The
+// fixture only depends on the seed and on the trusted setup of ctx.
+func GenerateProofFixture(ctx *gokzg4844.Context, seed int64) (*ProofFixture, error) {
+	blob := GenerateBlob(seed)
+	commitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &ProofFixture{Blob: blob, Commitment: commitment, Proof: proof}, nil
+}
@@ -0,0 +1,68 @@
+package testutil_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// The expected values were computed independently by hashing the seeds as documented. They must
+// not change, see the package documentation.
+func TestGenerateStable(t *testing.T) {
+	scalar := testutil.GenerateScalar(0)
+	require.Equal(t, "3b67c9a277e38e32c452d743bd688e09ba377a3fbafac14ee5b2328ee0e83dfb", hex.EncodeToString(scalar[:]))
+	scalar = testutil.GenerateScalar(-1)
+	require.Equal(t, "12a3ae445661ce5dee78d0650d33362dec29c4f82af05e7e57fb595bbbacf0ca", hex.EncodeToString(scalar[:]))
+
+	blobDigest := sha256.Sum256(testutil.GenerateBlob(7)[:])
+	require.Equal(t, "4ff072c67da5245800e6cefcec7bc6d6b1160136bb1c0a66147fb31f2a2b5538", hex.EncodeToString(blobDigest[:]))
+}
+
+func TestNonCanonicalScalar(t *testing.T) {
+	scalar := testutil.GenerateScalar(13)
+	expected, err := gokzg4844.DeserializeScalar(scalar)
+	require.NoError(t, err)
+
+	nonCanonical := testutil.NonCanonicalScalar(scalar)
+	_, err = gokzg4844.DeserializeScalar(nonCanonical)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.Equal(t, nonCanonical, testutil.GenerateNonCanonicalScalar(13))
+
+	// The non-canonical encoding reduces to the same field element
+	var reduced fr.Element
+	reduced.SetBytes(nonCanonical[:])
+	require.Equal(t, expected, reduced)
+
+	require.Panics(t, func() { testutil.NonCanonicalScalar(nonCanonical) })
+}
+
+func TestGenerateInvalidBlob(t *testing.T) {
+	ctx, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+
+	for _, index := range []int{0, 77, gokzg4844.ScalarsPerBlob - 1} {
+		_, err := ctx.BlobToKZGCommitment(testutil.GenerateInvalidBlob(3, index), 0)
+		var scalarErr *gokzg4844.ScalarError
+		require.ErrorAs(t, err, &scalarErr)
+		require.Equal(t, index, scalarErr.Index)
+	}
+}
+
+func TestGenerateProofFixture(t *testing.T) {
+	ctx, err := gokzg4844.NewContext4096Secure()
+	require.NoError(t, err)
+
+	fixture, err := testutil.GenerateProofFixture(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, testutil.GenerateBlob(1), fixture.Blob)
+	require.NoError(t, ctx.VerifyBlobKZGProof(fixture.Blob, fixture.Commitment, fixture.Proof))
+
+	again, err := testutil.GenerateProofFixture(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, fixture, again)
+}
@@ -0,0 +1,98 @@
+package gokzg4844
+
+import (
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+)
+
+// PointEvaluationInputSize is the number of bytes in the input of the point evaluation precompile: the versioned
+// hash, the input point z, the claimed value y, the commitment and the proof.
+const PointEvaluationInputSize = 32 + 2*SerializedScalarSize + 2*CompressedG1Size
+
+// PointEvaluationOutputSize is the number of bytes in the output of the point evaluation precompile.
+const PointEvaluationOutputSize = 2 * SerializedScalarSize
+
+// pointEvaluationOutput is the output of the point evaluation precompile on success: [ScalarsPerBlob] followed by
+// the modulus of the scalar field, both as 32 byte big endian integers.
+var pointEvaluationOutput = func() [PointEvaluationOutputSize]byte {
+	var output [PointEvaluationOutputSize]byte
+	output[SerializedScalarSize-2] = byte(ScalarsPerBlob >> 8)
+	output[SerializedScalarSize-1] = byte(ScalarsPerBlob & 0xff)
+	copy(output[SerializedScalarSize:], scalarModulus[:])
+	return output
+}()
+
+// PointEvaluation implements [point_evaluation_precompile], the precompile at address 0x0A introduced by EIP-4844.
+//
+// It returns an error if the input is not exactly [PointEvaluationInputSize] bytes long (a [*LengthError]), if the
+// versioned hash is not the one of the commitment ([ErrVersionedHashMismatch]), if z or y is not canonical, if the
+// commitment or the proof is invalid, or if the proof does not verify. Otherwise, it returns the
+// [PointEvaluationOutputSize] bytes of the output of the precompile.
+//
+// The commitment and the proof are checked exactly as in the spec, regardless of the options the context was created
+// with. The context must hold the trusted setup from the Ethereum KZG ceremony for the result to match the one of the
+// precompile.
+//
+// [point_evaluation_precompile]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func (c *Context) PointEvaluation(input []byte) ([]byte, error) {
+	if !c.calls.acquire() {
+		return nil, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Split the input
+	//
+	if len(input) != PointEvaluationInputSize {
+		return nil, &LengthError{Got: len(input), Want: PointEvaluationInputSize}
+	}
+	var (
+		versionedHash [32]byte
+		serInputPoint Scalar
+		serClaimed    Scalar
+		serCommitment KZGCommitment
+		serProof      KZGProof
+	)
+	offset := 0
+	for _, field := range [][]byte{versionedHash[:], serInputPoint[:], serClaimed[:], serCommitment[:], serProof[:]} {
+		offset += copy(field, input[offset:])
+	}
+
+	// 2. Check the versioned hash
+	if KZGToVersionedHash(serCommitment) != versionedHash {
+		return nil, ErrVersionedHashMismatch
+	}
+
+	// 3. Deserialize
+	//
+	inputPoint, err := DeserializeScalar(serInputPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	claimedValue, err := DeserializeScalar(serClaimed)
+	if err != nil {
+		return nil, err
+	}
+
+	commitment, err := DeserializeKZGCommitment(serCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	quotientCommitment, err := DeserializeKZGProof(serProof)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Verify opening proof
+	proof := kzg.OpeningProof{
+		QuotientCommitment: quotientCommitment,
+		InputPoint:         inputPoint,
+		ClaimedValue:       claimedValue,
+	}
+	if err := kzg.Verify(&commitment, &proof, c.openKey); err != nil {
+		return nil, err
+	}
+
+	output := pointEvaluationOutput
+	return output[:], nil
+}
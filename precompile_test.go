@@ -0,0 +1,147 @@
+package gokzg4844_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// pointEvaluationOutput is the output of the precompile on success.
+const pointEvaluationOutput = "0000000000000000000000000000000000000000000000000000000000001000" +
+	"73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"
+
+// pointEvaluationInput is the test vector of the precompile in go-ethereum.
+const pointEvaluationInput = "01e798154708fe7789429634053cbf9f99b619f9f084048927333fce637f549b" +
+	"564c0a11a0f704f4fc3e8acfe0f8245f0ad1347b378fbf96e206da11a5d36306" +
+	"24d25032e67a7e6a4910df5834b8fe70e6bcfeeac0352434196bdf4b2485d5a1" +
+	"8f59a8d2a1a625a17f3fea0fe5eb8c896db3764f3185481bc22f91b4aaffcca25f26936857bc3a7c2539ea8ec3a952b787" +
+	"3033e038326e87ed3e1276fd140253fa08e9fc25fb2d9a98527fc22a2c9612fbeafdad446cbc7bcdbdcd780af2c16a"
+
+func TestPointEvaluation(t *testing.T) {
+	input, err := hex.DecodeString(pointEvaluationInput)
+	require.NoError(t, err)
+	require.Len(t, input, gokzg4844.PointEvaluationInputSize)
+
+	output, err := ctx.PointEvaluation(input)
+	require.NoError(t, err)
+	require.Equal(t, pointEvaluationOutput, hex.EncodeToString(output))
+	require.Len(t, output, gokzg4844.PointEvaluationOutputSize)
+
+	// The output is not shared between calls
+	output[0] = 1
+	output, err = ctx.PointEvaluation(input)
+	require.NoError(t, err)
+	require.Equal(t, pointEvaluationOutput, hex.EncodeToString(output))
+
+	for _, length := range []int{0, gokzg4844.PointEvaluationInputSize - 1, gokzg4844.PointEvaluationInputSize + 1} {
+		paddedInput := append(append([]byte{}, input...), make([]byte, 1)...)
+		_, err := ctx.PointEvaluation(paddedInput[:length])
+		var lengthErr *gokzg4844.LengthError
+		require.ErrorAs(t, err, &lengthErr)
+		require.Equal(t, gokzg4844.LengthError{Got: length, Want: gokzg4844.PointEvaluationInputSize}, *lengthErr)
+	}
+
+	// The versioned hash must match the commitment, including the version byte
+	modifiedInput := append([]byte{}, input...)
+	modifiedInput[0] = 0
+	_, err = ctx.PointEvaluation(modifiedInput)
+	require.ErrorIs(t, err, gokzg4844.ErrVersionedHashMismatch)
+
+	// z must be canonical: adding the modulus to it gives the same field element but is rejected
+	z := new(big.Int).SetBytes(input[32:64])
+	z.Add(z, fr.Modulus())
+	modifiedInput = append([]byte{}, input...)
+	z.FillBytes(modifiedInput[32:64])
+	_, err = ctx.PointEvaluation(modifiedInput)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+
+	// A different y fails the pairing check
+	modifiedInput = append([]byte{}, input...)
+	modifiedInput[95] ^= 1
+	_, err = ctx.PointEvaluation(modifiedInput)
+	require.ErrorIs(t, err, kzg.ErrVerifyOpeningProof)
+}
+
+// TestPointEvaluationConsensusSpecs runs the precompile on the inputs of the verify_kzg_proof tests of the consensus
+// specs, from which the precompile tests of the execution specs are derived.
+func TestPointEvaluationConsensusSpecs(t *testing.T) {
+	type Test struct {
+		Input struct {
+			Commitment  string `yaml:"commitment"`
+			InputPoint  string `yaml:"z"`
+			OutputPoint string `yaml:"y"`
+			Proof       string `yaml:"proof"`
+		}
+		ProofIsValid *bool `yaml:"output"`
+	}
+
+	tests, err := filepath.Glob(verifyKZGProofTests)
+	require.NoError(t, err)
+	require.True(t, len(tests) > 0)
+
+	for _, testPath := range tests {
+		t.Run(testPath, func(t *testing.T) {
+			data, err := os.ReadFile(testPath)
+			require.NoError(t, err)
+			test := Test{}
+			require.NoError(t, yaml.Unmarshal(data, &test))
+
+			commitment, err := hexStrToBytes(test.Input.Commitment)
+			require.NoError(t, err)
+			var serCommitment gokzg4844.KZGCommitment
+			copy(serCommitment[:], commitment)
+			versionedHash := gokzg4844.KZGToVersionedHash(serCommitment)
+			var input []byte
+			input = append(input, versionedHash[:]...)
+			for _, field := range []string{test.Input.InputPoint, test.Input.OutputPoint, test.Input.Commitment, test.Input.Proof} {
+				fieldBytes, err := hexStrToBytes(field)
+				require.NoError(t, err)
+				input = append(input, fieldBytes...)
+			}
+
+			output, err := ctx.PointEvaluation(input)
+			if test.ProofIsValid != nil && *test.ProofIsValid {
+				require.NoError(t, err)
+				require.Equal(t, pointEvaluationOutput, hex.EncodeToString(output))
+				return
+			}
+			require.Error(t, err)
+			require.Nil(t, output)
+			// Valid inputs with an incorrect proof fail the pairing check
+			require.Equal(t, test.ProofIsValid != nil, errors.Is(err, kzg.ErrVerifyOpeningProof))
+		})
+	}
+}
+
+// FuzzPointEvaluationPrecompile checks that the precompile never panics, that it returns its fixed output whenever it
+// succeeds, and that every failure is either an invalid input or a proof which does not verify.
+func FuzzPointEvaluationPrecompile(f *testing.F) {
+	input, err := hex.DecodeString(pointEvaluationInput)
+	require.NoError(f, err)
+	f.Add(input)
+	f.Add(input[:gokzg4844.PointEvaluationInputSize-1])
+	f.Add(make([]byte, gokzg4844.PointEvaluationInputSize))
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		output, err := ctx.PointEvaluation(input)
+		if err == nil {
+			require.Equal(t, pointEvaluationOutput, hex.EncodeToString(output))
+			return
+		}
+		require.Nil(t, output)
+		if len(input) != gokzg4844.PointEvaluationInputSize {
+			var lengthErr *gokzg4844.LengthError
+			require.ErrorAs(t, err, &lengthErr)
+		}
+		require.True(t, errors.Is(err, gokzg4844.ErrInvalidInput) != errors.Is(err, gokzg4844.ErrProofInvalid), "unclassified error: %v", err)
+	})
+}
@@ -1,26 +1,63 @@
 package gokzg4844
 
 import (
+	"context"
+	"time"
+
 	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
 // BlobToKZGCommitment implements [blob_to_kzg_commitment].
 //
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
-// value to a negative number or 0 will make it default to the number of CPUs.
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
 //
 // [blob_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_kzg_commitment
 func (c *Context) BlobToKZGCommitment(blob *Blob, numGoRoutines int) (KZGCommitment, error) {
+	if !c.calls.acquire() {
+		return KZGCommitment{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+
+	if c.observer != nil {
+		defer c.observeSince(OperationBlobToKZGCommitment, 1, time.Now())
+	}
+
+	// 0. Look up the commitment in the cache
+	//
+	var cacheKey [32]byte
+	if c.commitmentCache != nil {
+		start := time.Now()
+		cacheKey = commitmentCacheKey(blob)
+		cached, ok := c.commitmentCache.get(cacheKey)
+		if c.observer != nil {
+			if ok {
+				c.observeSince(OperationCommitmentCacheHit, 1, start)
+			} else {
+				c.observeSince(OperationCommitmentCacheMiss, 1, start)
+			}
+		}
+		if ok {
+			return cached, nil
+		}
+	}
+
 	// 1. Deserialization
 	//
 	// Deserialize blob into polynomial
-	polynomial, err := DeserializeBlob(blob)
+	polynomial, err := c.deserializeBlobParallel(blob, numGoRoutines)
 	if err != nil {
 		return KZGCommitment{}, err
 	}
 
 	// 2. Commit to polynomial
-	commitment, err := kzg.Commit(polynomial, c.commitKey, numGoRoutines)
+	commitment, err := kzg.Commit(polynomial, c.commitKey, c.goRoutines(numGoRoutines))
 	if err != nil {
 		return KZGCommitment{}, err
 	}
@@ -28,9 +65,178 @@ func (c *Context) BlobToKZGCommitment(blob *Blob, numGoRoutines int) (KZGCommitm
 	// 3. Serialization
 	//
 	// Serialize commitment
-	serComm := SerializeG1Point(*commitment)
+	serComm := KZGCommitment(SerializeG1Point(*commitment))
+	if c.commitmentCache != nil {
+		c.commitmentCache.add(cacheKey, serComm)
+	}
+
+	return serComm, nil
+}
+
+// BlobsToKZGCommitments computes the commitments to many blobs at once, as if calling [Context.BlobToKZGCommitment]
+// for each of them. The commitments are returned in the same order as the blobs.
+//
+// The blobs are committed to concurrently using the number of go routines configured by [WithNumGoRoutines]. If the
+// number of blobs is smaller than that, the remaining go routines are shared by the multi exponentiations.
+//
+// If any of the blobs is invalid, a [*BlobError] is returned holding the index of the first invalid blob.
+func (c *Context) BlobsToKZGCommitments(blobs []Blob) ([]KZGCommitment, error) {
+	return c.BlobsToKZGCommitmentsCtx(context.Background(), blobs)
+}
+
+// BlobsToKZGCommitmentsCtx is [Context.BlobsToKZGCommitments] which stops early and returns ctx.Err() if ctx is done
+// before every blob has been committed to.
+//
+// The context is checked before committing to each blob. A commitment which has started is a single multi
+// exponentiation which cannot be interrupted, so the call returns once the blobs being committed to are done.
+func (c *Context) BlobsToKZGCommitmentsCtx(ctx context.Context, blobs []Blob) ([]KZGCommitment, error) {
+	if !c.calls.acquire() {
+		return nil, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return nil, err
+	}
+
+	numBlobs := len(blobs)
+	commitments := make([]KZGCommitment, numBlobs)
+	if numBlobs == 0 {
+		return commitments, nil
+	}
+
+	err := c.forEachBlob(ctx, numBlobs, func(i, numMSMGoRoutines int) (err error) {
+		commitments[i], err = c.BlobToKZGCommitment(&blobs[i], numMSMGoRoutines)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commitments, nil
+}
+
+// ComputeBlobKZGProofBatch computes the proofs for many blobs at once, as if calling [Context.ComputeBlobKZGProof]
+// for each blob and the commitment at the same index. The proofs are returned in the same order as the blobs.
+//
+// The proofs are computed concurrently using the number of go routines configured by [WithNumGoRoutines]. If the
+// number of blobs is smaller than that, the remaining go routines are shared by the multi exponentiations.
+//
+// If the number of blobs and commitments differ, [ErrBatchLengthCheck] is returned. If any of the blobs or
+// commitments is invalid, a [*BlobError] is returned holding the index of the first invalid one.
+func (c *Context) ComputeBlobKZGProofBatch(blobs []Blob, commitments []KZGCommitment) ([]KZGProof, error) {
+	return c.ComputeBlobKZGProofBatchCtx(context.Background(), blobs, commitments)
+}
+
+// ComputeBlobKZGProofBatchCtx is [Context.ComputeBlobKZGProofBatch] which stops early and returns ctx.Err() if ctx is
+// done before every proof has been computed.
+//
+// The context is checked before computing the proof of each blob. A proof which has started cannot be interrupted,
+// so the call returns once the proofs being computed are done.
+func (c *Context) ComputeBlobKZGProofBatchCtx(ctx context.Context, blobs []Blob, commitments []KZGCommitment) ([]KZGProof, error) {
+	if !c.calls.acquire() {
+		return nil, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return nil, err
+	}
+
+	numBlobs := len(blobs)
+	if err := checkBatchLengths([]string{"blobs", "commitments"}, numBlobs, len(commitments)); err != nil {
+		return nil, err
+	}
+	proofs := make([]KZGProof, numBlobs)
+	if numBlobs == 0 {
+		return proofs, nil
+	}
+
+	// The challenges are computed in a first pass over the blobs, which only hashes them
+	evaluationChallenges, err := c.computeChallenges(ctx, blobs, commitments, c.numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.forEachBlob(ctx, numBlobs, func(i, numMSMGoRoutines int) error {
+		if c.observer != nil {
+			defer c.observeSince(OperationComputeBlobKZGProof, 1, time.Now())
+		}
+
+		polynomial, err := c.deserializeBlobAndCommitment(&blobs[i], commitments[i], numMSMGoRoutines)
+		if err != nil {
+			return err
+		}
+		proofs[i], err = c.openBlob(polynomial, evaluationChallenges[i], numMSMGoRoutines)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proofs, nil
+}
+
+// forEachBlob calls f(i, numMSMGoRoutines) for every i in [0, numBlobs) concurrently, using the number of go routines
+// configured by [WithNumGoRoutines]. If the number of blobs is smaller than that, the remaining go routines are
+// split between the calls and passed as numMSMGoRoutines.
+//
+// All the calls are made, and if any of them fails, a [*BlobError] is returned holding the index of the first one.
+// If ctx is done, the calls which have not started are skipped and ctx.Err() is returned.
+func (c *Context) forEachBlob(ctx context.Context, numBlobs int, f func(i, numMSMGoRoutines int) error) error {
+	numWorkers := utils.NumGoRoutines(c.numGoRoutines)
+	numMSMGoRoutines := 1
+	if numWorkers > numBlobs {
+		numMSMGoRoutines = numWorkers / numBlobs
+		numWorkers = numBlobs
+	}
+
+	// Record the error for every blob, so that we can report the first
+	// invalid blob in the order of the input rather than in the order
+	// that the go routines finish.
+	errs := make([]error, numBlobs)
+
+	err := utils.ParallelForCtx(ctx, numBlobs, numWorkers, func(i int) error {
+		errs[i] = f(i, numMSMGoRoutines)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-	return KZGCommitment(serComm), nil
+	return firstBlobError(errs)
+}
+
+// firstBlobError returns a [*BlobError] for the first non-nil error of errs, which holds the error of every blob of a
+// batch, or nil if there is none.
+func firstBlobError(errs []error) error {
+	for i, err := range errs {
+		if err != nil {
+			return &BlobError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// CommitToMonomialPolynomial commits to a polynomial given by its coefficients, where coeffs[i] is the coefficient of
+// X^i. The resulting commitment is the same as the commitment to the blob holding the evaluations of this polynomial.
+//
+// Returns [ErrMonomialSRSUnavailable] if the [Context] does not hold the monomial G1 points from the trusted setup and
+// an error if there are more coefficients than there are points in the trusted setup.
+func (c *Context) CommitToMonomialPolynomial(coeffs []fr.Element) (KZGCommitment, error) {
+	if !c.calls.acquire() {
+		return KZGCommitment{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	if c.monomialCommitKey == nil {
+		return KZGCommitment{}, ErrMonomialSRSUnavailable
+	}
+
+	commitment, err := kzg.Commit(coeffs, c.monomialCommitKey, c.numGoRoutines)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	return KZGCommitment(SerializeG1Point(*commitment)), nil
 }
 
 // ComputeBlobKZGProof implements [compute_blob_kzg_proof]. It takes a blob and returns the KZG proof that is used to
@@ -40,35 +246,74 @@ func (c *Context) BlobToKZGCommitment(blob *Blob, numGoRoutines int) (KZGCommitm
 // commitment is a valid commitment. One should check this externally or call [Context.BlobToKZGCommitment].
 //
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
-// value to a negative number or 0 will make it default to the number of CPUs.
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
 //
 // [compute_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
 func (c *Context) ComputeBlobKZGProof(blob *Blob, blobCommitment KZGCommitment, numGoRoutines int) (KZGProof, error) {
+	if !c.calls.acquire() {
+		return KZGProof{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, err
+	}
+
+	if c.observer != nil {
+		defer c.observeSince(OperationComputeBlobKZGProof, 1, time.Now())
+	}
+
 	// 1. Deserialization
 	//
-	polynomial, err := DeserializeBlob(blob)
+	polynomial, err := c.deserializeBlobAndCommitment(blob, blobCommitment, numGoRoutines)
 	if err != nil {
 		return KZGProof{}, err
 	}
 
+	// 2. Compute Fiat-Shamir challenge
+	evaluationChallenge := c.computeChallenge(blob, blobCommitment)
+
+	// 3. Create opening proof
+	return c.openBlob(polynomial, evaluationChallenge, numGoRoutines)
+}
+
+// checkCanProve returns [ErrVerifyOnlyContext] if the context does not hold the lagrange G1 points needed to commit
+// to polynomials and compute proofs, see [ModeVerifyOnly].
+func (c *Context) checkCanProve() error {
+	if c.commitKey == nil {
+		return ErrVerifyOnlyContext
+	}
+	return nil
+}
+
+// deserializeBlobAndCommitment returns the polynomial of the blob, deserialized using up to numGoRoutines go routines,
+// after checking that the commitment is valid.
+func (c *Context) deserializeBlobAndCommitment(blob *Blob, blobCommitment KZGCommitment, numGoRoutines int) (kzg.Polynomial, error) {
+	polynomial, err := c.deserializeBlobParallel(blob, numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
 	// Deserialize commitment
 	//
 	// We only do this to check if it is in the correct subgroup
 	_, err = DeserializeKZGCommitment(blobCommitment)
 	if err != nil {
-		return KZGProof{}, err
+		return nil, err
 	}
 
-	// 2. Compute Fiat-Shamir challenge
-	evaluationChallenge := computeChallenge(blob, blobCommitment)
+	return polynomial, nil
+}
 
-	// 3. Create opening proof
-	openingProof, err := kzg.Open(c.domain, polynomial, evaluationChallenge, c.commitKey, numGoRoutines)
+// openBlob returns the serialized proof of [Context.ComputeBlobKZGProof] for the polynomial of a blob, given its
+// evaluation challenge.
+func (c *Context) openBlob(polynomial kzg.Polynomial, evaluationChallenge fr.Element, numGoRoutines int) (KZGProof, error) {
+	openingProof, err := kzg.Open(c.domain, polynomial, evaluationChallenge, c.commitKey, c.goRoutines(numGoRoutines))
 	if err != nil {
 		return KZGProof{}, err
 	}
 
-	// 4. Serialization
+	// Serialization
 	//
 	// Quotient commitment
 	kzgProof := SerializeG1Point(openingProof.QuotientCommitment)
@@ -76,36 +321,284 @@ func (c *Context) ComputeBlobKZGProof(blob *Blob, blobCommitment KZGCommitment,
 	return KZGProof(kzgProof), nil
 }
 
+// BlobToKZGCommitmentBytes is [Context.BlobToKZGCommitment] for a blob held in a byte slice, which is not copied. It
+// returns a [*LengthError] if the slice does not hold exactly the number of bytes in a [Blob].
+func (c *Context) BlobToKZGCommitmentBytes(blob []byte, numGoRoutines int) (KZGCommitment, error) {
+	if !c.calls.acquire() {
+		return KZGCommitment{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	serBlob, err := blobFromBytes(blob)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	return c.BlobToKZGCommitment(serBlob, numGoRoutines)
+}
+
+// ComputeBlobKZGProofBytes is [Context.ComputeBlobKZGProof] for a blob and a commitment held in byte slices. The
+// lengths of the slices are checked before any other work: a [*LengthError] is returned for the blob and a
+// [*PointError] wrapping a [*LengthError] for the commitment.
+func (c *Context) ComputeBlobKZGProofBytes(blob, blobCommitment []byte, numGoRoutines int) (KZGProof, error) {
+	if !c.calls.acquire() {
+		return KZGProof{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	serBlob, err := blobFromBytes(blob)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	serCommitment, err := g1PointFromBytes(commitmentKind, blobCommitment)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	return c.ComputeBlobKZGProof(serBlob, KZGCommitment(serCommitment), numGoRoutines)
+}
+
 // ComputeKZGProof implements [compute_kzg_proof].
 //
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
-// value to a negative number or 0 will make it default to the number of CPUs.
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
 //
 // [compute_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof
 func (c *Context) ComputeKZGProof(blob *Blob, inputPointBytes Scalar, numGoRoutines int) (KZGProof, Scalar, error) {
+	if !c.calls.acquire() {
+		return KZGProof{}, Scalar{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+
 	// 1. Deserialization
 	//
-	polynomial, err := DeserializeBlob(blob)
+	inputPoint, err := DeserializeScalar(inputPointBytes)
 	if err != nil {
 		return KZGProof{}, [32]byte{}, err
 	}
 
-	inputPoint, err := DeserializeScalar(inputPointBytes)
+	kzgProof, claimedValue, err := c.computeKZGProof(blob, inputPoint, numGoRoutines)
 	if err != nil {
 		return KZGProof{}, [32]byte{}, err
 	}
 
+	// 2. Serialization
+	//
+	claimedValueBytes := SerializeScalar(claimedValue)
+
+	return kzgProof, claimedValueBytes, nil
+}
+
+// ComputeKZGProofFr is [Context.ComputeKZGProof] for an input point which is already a field element, such as a
+// challenge computed by another protocol. It returns the claimed value as a field element too.
+//
+// It uses the number of go routines configured by [WithNumGoRoutines].
+func (c *Context) ComputeKZGProofFr(blob *Blob, inputPoint fr.Element) (KZGProof, fr.Element, error) {
+	if !c.calls.acquire() {
+		return KZGProof{}, fr.Element{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, fr.Element{}, err
+	}
+
+	return c.computeKZGProof(blob, inputPoint, c.numGoRoutines)
+}
+
+// computeKZGProof implements [Context.ComputeKZGProof] for an input point which has already been deserialized.
+func (c *Context) computeKZGProof(blob *Blob, inputPoint fr.Element, numGoRoutines int) (KZGProof, fr.Element, error) {
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlobParallel(blob, numGoRoutines)
+	if err != nil {
+		return KZGProof{}, fr.Element{}, err
+	}
+
 	// 2. Create opening proof
-	openingProof, err := kzg.Open(c.domain, polynomial, inputPoint, c.commitKey, numGoRoutines)
+	openingProof, err := kzg.Open(c.domain, polynomial, inputPoint, c.commitKey, c.goRoutines(numGoRoutines))
 	if err != nil {
-		return KZGProof{}, [32]byte{}, err
+		return KZGProof{}, fr.Element{}, err
 	}
 
 	// 3. Serialization
 	//
 	kzgProof := SerializeG1Point(openingProof.QuotientCommitment)
 
+	return KZGProof(kzgProof), openingProof.ClaimedValue, nil
+}
+
+// CommitToPolynomial commits to the polynomial with the given evaluations, which are ordered in the same way as the
+// scalars in a [Blob]. The number of evaluations must be the size of the [Context].
+//
+// This is the counterpart of [Context.BlobToKZGCommitment] for a [Context] created using [NewContextFromSetup].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
+func (c *Context) CommitToPolynomial(evaluations []fr.Element, numGoRoutines int) (KZGCommitment, error) {
+	if !c.calls.acquire() {
+		return KZGCommitment{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+
+	if uint64(len(evaluations)) != c.domain.Cardinality {
+		return KZGCommitment{}, ErrContextSizeMismatch
+	}
+
+	commitment, err := kzg.Commit(evaluations, c.commitKey, c.goRoutines(numGoRoutines))
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	return KZGCommitment(SerializeG1Point(*commitment)), nil
+}
+
+// ComputePolynomialKZGProof computes the proof that the polynomial with the given evaluations evaluates to the
+// returned claimed value at the input point. The evaluations are ordered in the same way as the scalars in a [Blob]
+// and their number must be the size of the [Context]. The proof can be verified using [Context.VerifyKZGProof].
+//
+// This is the counterpart of [Context.ComputeKZGProof] for a [Context] created using [NewContextFromSetup].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
+func (c *Context) ComputePolynomialKZGProof(evaluations []fr.Element, inputPointBytes Scalar, numGoRoutines int) (KZGProof, Scalar, error) {
+	if !c.calls.acquire() {
+		return KZGProof{}, Scalar{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+
+	if uint64(len(evaluations)) != c.domain.Cardinality {
+		return KZGProof{}, [32]byte{}, ErrContextSizeMismatch
+	}
+
+	inputPoint, err := DeserializeScalar(inputPointBytes)
+	if err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
+	openingProof, err := kzg.Open(c.domain, evaluations, inputPoint, c.commitKey, c.goRoutines(numGoRoutines))
+	if err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
+	kzgProof := SerializeG1Point(openingProof.QuotientCommitment)
 	claimedValueBytes := SerializeScalar(openingProof.ClaimedValue)
 
 	return KZGProof(kzgProof), claimedValueBytes, nil
 }
+
+// ComputeDegreeBoundProof computes a proof that the polynomial represented by `blob` has degree less than
+// `degreeBound`, for example to show that its top coefficients are zero.
+//
+// The proof can only be verified if the trusted setup contains the G2 power α^(ScalarsPerBlob - degreeBound).
+// With the Ethereum trusted setup, this means that degreeBound must be at least ScalarsPerBlob - 64.
+//
+// Returns [ErrMonomialSRSUnavailable] if the [Context] does not hold the monomial G1 points from the trusted setup and
+// an error if the polynomial does not have degree less than `degreeBound`.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
+func (c *Context) ComputeDegreeBoundProof(blob *Blob, degreeBound uint64, numGoRoutines int) (KZGProof, error) {
+	if !c.calls.acquire() {
+		return KZGProof{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	if c.monomialCommitKey == nil {
+		return KZGProof{}, ErrMonomialSRSUnavailable
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return KZGProof{}, err
+	}
+
+	// 2. Create degree bound proof
+	proof, err := kzg.OpenDegreeBound(c.domain, polynomial, degreeBound, c.monomialCommitKey, c.goRoutines(numGoRoutines))
+	if err != nil {
+		return KZGProof{}, err
+	}
+
+	// 3. Serialization
+	//
+	return KZGProof(SerializeG1Point(proof)), nil
+}
+
+// ExternalEvaluationFn evaluates the data behind an external commitment, interpreted as a polynomial in the same way
+// as a blob, at `challenge`. It is used by [Context.ComputeEquivalenceProof] to obtain the evaluation claimed by the
+// other commitment scheme, for example by producing an opening proof in that scheme.
+type ExternalEvaluationFn func(challenge Scalar) (Scalar, error)
+
+// ComputeEquivalenceProof shows that `blobCommitment` and `externalCommitment`, a commitment to the same data in another
+// scheme, are commitments to the same polynomial by evaluating both at a Fiat-Shamir challenge derived from the two
+// commitments. See [Context.ComputeEquivalenceChallenge] for the transcript.
+//
+// It returns the challenge, the evaluation of the blob at the challenge, and the KZG proof for that evaluation, which
+// can be checked using [Context.VerifyEquivalenceProof]. If the evaluation returned by `externalEval` differs from the
+// evaluation of the blob, [ErrEquivalenceMismatch] is returned.
+//
+// Note: As in [Context.ComputeBlobKZGProof], this method does not check that the commitment corresponds to the `blob`.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number configured by
+// [WithNumGoRoutines], or to runtime.GOMAXPROCS(0).
+func (c *Context) ComputeEquivalenceProof(blob *Blob, blobCommitment KZGCommitment, externalCommitment []byte, externalEval ExternalEvaluationFn, numGoRoutines int) (Scalar, Scalar, KZGProof, error) {
+	if !c.calls.acquire() {
+		return Scalar{}, Scalar{}, KZGProof{}, ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return Scalar{}, Scalar{}, KZGProof{}, err
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return Scalar{}, Scalar{}, KZGProof{}, err
+	}
+
+	// We only do this to check if it is in the correct subgroup
+	_, err = DeserializeKZGCommitment(blobCommitment)
+	if err != nil {
+		return Scalar{}, Scalar{}, KZGProof{}, err
+	}
+
+	// 2. Compute Fiat-Shamir challenge
+	evaluationChallenge := c.computeEquivalenceChallenge(blobCommitment, externalCommitment)
+	serChallenge := SerializeScalar(evaluationChallenge)
+
+	// 3. Create opening proof
+	openingProof, err := kzg.Open(c.domain, polynomial, evaluationChallenge, c.commitKey, c.goRoutines(numGoRoutines))
+	if err != nil {
+		return Scalar{}, Scalar{}, KZGProof{}, err
+	}
+	claimedValue := SerializeScalar(openingProof.ClaimedValue)
+
+	// 4. Check the evaluation of the external commitment
+	externalValue, err := externalEval(serChallenge)
+	if err != nil {
+		return Scalar{}, Scalar{}, KZGProof{}, err
+	}
+	if externalValue != claimedValue {
+		return Scalar{}, Scalar{}, KZGProof{}, ErrEquivalenceMismatch
+	}
+
+	// 5. Serialization
+	//
+	kzgProof := SerializeG1Point(openingProof.QuotientCommitment)
+
+	return serChallenge, claimedValue, KZGProof(kzgProof), nil
+}
@@ -0,0 +1,105 @@
+package gokzg4844
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// ContextRegistry holds a [Context] for each power of two size up to the size of a trusted setup, so that an
+// application can commit to and open polynomials of several sizes using the same trusted setup.
+//
+// The contexts are derived from the monomial G1 points of the trusted setup: the context for polynomials with `size`
+// evaluations uses the first `size` monomial points, and its lagrange G1 points are computed from them using an inverse
+// FFT. Commitments and proofs computed by a context of the registry are therefore the same as those computed by a
+// context created directly from a trusted setup of that size with the same secret.
+//
+// A ContextRegistry is safe for concurrent use.
+type ContextRegistry struct {
+	config     *contextConfig
+	genG1      bls12381.G1Affine
+	monomialG1 []bls12381.G1Affine
+	g2         []bls12381.G2Affine
+
+	mu      sync.Mutex
+	entries map[uint64]*registryEntry
+}
+
+// registryEntry holds the context of a given size, which is created by the first call to [ContextRegistry.Get].
+type registryEntry struct {
+	once    sync.Once
+	context *Context
+	err     error
+}
+
+// NewContextRegistry creates a registry from the trusted setup held by `c`, which must hold the monomial G1 points,
+// see [WithoutMonomialSRS]. It returns [ErrMonomialSRSUnavailable] otherwise.
+//
+// The registry returns `c` itself for the size of `c`. The contexts of other sizes are created using `opts`.
+func NewContextRegistry(c *Context, opts ...ContextOption) (*ContextRegistry, error) {
+	if !c.calls.acquire() {
+		return nil, ErrContextClosed
+	}
+	defer c.calls.release()
+	if c.monomialCommitKey == nil {
+		return nil, ErrMonomialSRSUnavailable
+	}
+
+	// The entry for the size of c is marked as done, so that Get returns c for it
+	entry := &registryEntry{context: c}
+	entry.once.Do(func() {})
+
+	return &ContextRegistry{
+		config:     newContextConfig(opts),
+		genG1:      c.openKey.GenG1,
+		monomialG1: c.monomialCommitKey.G1,
+		g2:         c.openKey.G2,
+		entries:    map[uint64]*registryEntry{c.domain.Cardinality: entry},
+	}, nil
+}
+
+// MaxSize returns the largest size of the contexts of the registry, which is the number of monomial G1 points of the
+// trusted setup.
+func (r *ContextRegistry) MaxSize() uint64 {
+	return uint64(len(r.monomialG1))
+}
+
+// Get returns the context for polynomials with `size` evaluations, which must be a power of two no larger than
+// [ContextRegistry.MaxSize]. It returns an error wrapping [ErrInvalidContextSize] otherwise.
+//
+// The context is created on the first call for each size and cached, so the first call for a large size can take
+// a while, but it does not block the calls for other sizes.
+func (r *ContextRegistry) Get(size uint64) (*Context, error) {
+	if err := checkContextSize(size); err != nil {
+		return nil, err
+	}
+	if size > r.MaxSize() {
+		return nil, fmt.Errorf("%w: the trusted setup only has %d monomial G1 points, got size %d", ErrInvalidContextSize, r.MaxSize(), size)
+	}
+
+	r.mu.Lock()
+	entry, ok := r.entries[size]
+	if !ok {
+		entry = &registryEntry{}
+		r.entries[size] = entry
+	}
+	r.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.context, entry.err = r.newContext(size)
+	})
+	return entry.context, entry.err
+}
+
+// newContext creates the context for polynomials with `size` evaluations from the first `size` monomial G1 points.
+func (r *ContextRegistry) newContext(size uint64) (*Context, error) {
+	monomialG1 := r.monomialG1[:size:size]
+	lagrangeG1 := kzg.NewDomain(size).IfftG1(monomialG1)
+	if !r.config.retainMonomialSRS() {
+		monomialG1 = nil
+	}
+
+	return newContextFromPoints(r.config, r.genG1, monomialG1, lagrangeG1, r.g2)
+}
@@ -0,0 +1,78 @@
+package gokzg4844_test
+
+import (
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextRegistry(t *testing.T) {
+	var tau fr.Element
+	tau.SetUint64(1337)
+	largeCtx, err := gokzg4844.NewInsecureContextWithSecret(tau, gokzg4844.ScalarsPerBlob)
+	require.NoError(t, err)
+	registry, err := gokzg4844.NewContextRegistry(largeCtx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(gokzg4844.ScalarsPerBlob), registry.MaxSize())
+
+	got, err := registry.Get(gokzg4844.ScalarsPerBlob)
+	require.NoError(t, err)
+	require.Same(t, largeCtx, got)
+
+	// A context derived from the larger setup agrees with one created directly for the same secret
+	for _, size := range []uint64{2, 1024} {
+		derivedCtx, err := registry.Get(size)
+		require.NoError(t, err)
+		again, err := registry.Get(size)
+		require.NoError(t, err)
+		require.Same(t, derivedCtx, again)
+
+		directCtx, err := gokzg4844.NewInsecureContextWithSecret(tau, size)
+		require.NoError(t, err)
+
+		evaluations := make([]fr.Element, size)
+		for i := range evaluations {
+			_, _ = evaluations[i].SetRandom()
+		}
+		inputPoint := testutil.GenerateScalar(int64(size))
+
+		commitment, err := derivedCtx.CommitToPolynomial(evaluations, NumGoRoutines)
+		require.NoError(t, err)
+		directCommitment, err := directCtx.CommitToPolynomial(evaluations, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, directCommitment, commitment)
+
+		// The proofs of each context verify with the other one
+		proof, claimedValue, err := derivedCtx.ComputePolynomialKZGProof(evaluations, inputPoint, NumGoRoutines)
+		require.NoError(t, err)
+		directProof, directClaimedValue, err := directCtx.ComputePolynomialKZGProof(evaluations, inputPoint, NumGoRoutines)
+		require.NoError(t, err)
+		require.NoError(t, directCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+		require.NoError(t, derivedCtx.VerifyKZGProof(directCommitment, inputPoint, directClaimedValue, directProof))
+
+		// The derived context does not accept proofs for another value
+		require.ErrorIs(t, derivedCtx.VerifyKZGProof(commitment, inputPoint, testutil.GenerateScalar(1), proof), gokzg4844.ErrProofInvalid)
+	}
+
+	for _, size := range []uint64{0, 1, 1000, 2 * gokzg4844.ScalarsPerBlob} {
+		_, err := registry.Get(size)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidContextSize, "size %d", size)
+	}
+
+	// The options apply to the derived contexts
+	registry, err = gokzg4844.NewContextRegistry(largeCtx, gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+	derivedCtx, err := registry.Get(16)
+	require.NoError(t, err)
+	_, err = derivedCtx.CommitToMonomialPolynomial(make([]fr.Element, 16))
+	require.ErrorIs(t, err, gokzg4844.ErrMonomialSRSUnavailable)
+
+	// The monomial G1 points are needed to derive the contexts
+	ctxNoMonomial, err := gokzg4844.NewInsecureContextWithSecret(tau, 16, gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+	_, err = gokzg4844.NewContextRegistry(ctxNoMonomial)
+	require.ErrorIs(t, err, gokzg4844.ErrMonomialSRSUnavailable)
+}
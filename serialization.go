@@ -1,6 +1,15 @@
 package gokzg4844
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
 	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
 	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
@@ -13,6 +22,13 @@ const CompressedG1Size = 48
 // CompressedG2Size is the number of bytes needed to represent a group element in G2 when compressed.
 const CompressedG2Size = 96
 
+// UncompressedG1Size is the number of bytes needed to represent a group element in G1 when uncompressed.
+const UncompressedG1Size = 96
+
+// compressedFlag is the most significant bit of the first byte of a serialized point, which is set if and only if
+// the point is compressed.
+const compressedFlag = 0x80
+
 // SerializedScalarSize is the number of bytes needed to represent a field element corresponding to the order of the G1
 // group.
 //
@@ -32,6 +48,21 @@ const SerializedScalarSize = 32
 // [FIELD_ELEMENTS_PER_BLOB]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob
 const ScalarsPerBlob = 4096
 
+// FieldElementsPerCell is the number of scalars in a [Cell].
+//
+// It matches [FIELD_ELEMENTS_PER_CELL] in the EIP-7594 spec.
+//
+// [FIELD_ELEMENTS_PER_CELL]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#cells
+const FieldElementsPerCell = 64
+
+// CellsPerExtBlob is the number of cells of a blob extended to twice its size, which is what [Context.ComputeCells]
+// returns.
+//
+// It matches [CELLS_PER_EXT_BLOB] in the EIP-7594 spec.
+//
+// [CELLS_PER_EXT_BLOB]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#cells
+const CellsPerExtBlob = 2 * ScalarsPerBlob / FieldElementsPerCell
+
 type (
 	// G1Point matches [G1Point] in the spec.
 	//
@@ -68,57 +99,554 @@ type (
 	//
 	// [KZGCommitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#custom-types
 	KZGCommitment G1Point
+
+	// Cell is a serialized chunk of [FieldElementsPerCell] consecutive evaluations of the extended blob.
+	//
+	// It matches [Cell] in the EIP-7594 spec.
+	//
+	// [Cell]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#custom-types
+	Cell [FieldElementsPerCell * SerializedScalarSize]byte
+
+	// G1PointUncompressed is the uncompressed encoding of a G1 point, which holds both coordinates of the point so
+	// that it can be deserialized without computing a square root. It is not part of the spec.
+	G1PointUncompressed [UncompressedG1Size]byte
+
+	// KZGProofUncompressed is the uncompressed form of a [KZGProof].
+	KZGProofUncompressed G1PointUncompressed
+
+	// KZGCommitmentUncompressed is the uncompressed form of a [KZGCommitment].
+	KZGCommitmentUncompressed G1PointUncompressed
 )
 
+// VersionedHashVersionKZG is the version byte of the versioned hash of a [KZGCommitment].
+//
+// It matches [VERSIONED_HASH_VERSION_KZG] in EIP-4844.
+//
+// [VERSIONED_HASH_VERSION_KZG]: https://eips.ethereum.org/EIPS/eip-4844#parameters
+const VersionedHashVersionKZG = 0x01
+
+// KZGToVersionedHash implements [kzg_to_versioned_hash]: it returns the sha256 hash of the commitment with its first
+// byte replaced by [VersionedHashVersionKZG].
+//
+// [kzg_to_versioned_hash]: https://eips.ethereum.org/EIPS/eip-4844#helpers
+func KZGToVersionedHash(commitment KZGCommitment) [32]byte {
+	versionedHash := sha256.Sum256(commitment[:])
+	versionedHash[0] = VersionedHashVersionKZG
+	return versionedHash
+}
+
 // SerializeG1Point converts a [bls12381.G1Affine] to [G1Point].
 func SerializeG1Point(affine bls12381.G1Affine) G1Point {
 	return affine.Bytes()
 }
 
-// deserializeG1Point converts a [G1Point] to the internal [bls12381.G1Affine] type. It will return an error if the
-// point is not on the group or if the point is not in the correct subgroup.
+// decodeG1Point converts a compressed [G1Point] or an uncompressed [G1PointUncompressed], depending on the length of
+// serPoint, to the internal [bls12381.G1Affine] type. It will return an error if the point is not on the group or, if
+// subgroupCheck is true, if the point is not in the correct subgroup.
 //
-// It implements [validate_kzg_g1].
+// With the subgroup check, it implements [validate_kzg_g1].
+//
+// The error wraps [ErrInvalidPointEncoding] if the point could not be decoded and [ErrPointNotInSubgroup] if it
+// is not in the correct subgroup.
 //
 // [validate_kzg_g1]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#validate_kzg_g1
-func deserializeG1Point(serPoint G1Point) (bls12381.G1Affine, error) {
-	var point bls12381.G1Affine
-	_, err := point.SetBytes(serPoint[:])
-	if err != nil {
-		return bls12381.G1Affine{}, err
+func decodeG1Point(serPoint []byte, subgroupCheck bool) (bls12381.G1Affine, error) {
+	// The decoder would only read the first half of an uncompressed point with the compression flag set
+	if len(serPoint) == UncompressedG1Size && serPoint[0]&compressedFlag != 0 {
+		return bls12381.G1Affine{}, fmt.Errorf("%w: the compression flag is set on an uncompressed point", ErrInvalidPointEncoding)
+	}
+
+	d := pointDecoderPool.Get().(*pointDecoder)
+	defer pointDecoderPool.Put(d)
+	// The point is copied, so that serPoint is not retained by the pool
+	d.reader.Reset(d.buf[:copy(d.buf[:], serPoint)])
+	if err := d.decoder.Decode(&d.point); err != nil {
+		return bls12381.G1Affine{}, fmt.Errorf("%w: %v", ErrInvalidPointEncoding, err)
+	}
+	point := d.point
+	// Decompressed points are always on the curve, but uncompressed points are only
+	// checked to be on the curve by the subgroup check
+	if len(serPoint) == UncompressedG1Size && !point.IsOnCurve() {
+		return bls12381.G1Affine{}, fmt.Errorf("%w: the point is not on the curve", ErrInvalidPointEncoding)
+	}
+	if subgroupCheck && !point.IsInSubGroup() {
+		return bls12381.G1Affine{}, ErrPointNotInSubgroup
 	}
 	return point, nil
 }
 
+// pointDecoder holds a decoder reading from a buffer, along with the point it decodes. It is pooled by
+// [decodeG1Point], since the decoder and the point would otherwise be allocated for every point.
+type pointDecoder struct {
+	buf     [UncompressedG1Size]byte
+	reader  bytes.Reader
+	decoder *bls12381.Decoder
+	point   bls12381.G1Affine
+}
+
+var pointDecoderPool = sync.Pool{
+	New: func() any {
+		d := &pointDecoder{}
+		d.decoder = bls12381.NewDecoder(&d.reader, bls12381.NoSubgroupChecks())
+		return d
+	},
+}
+
 // DeserializeKZGCommitment implements [bytes_to_kzg_commitment].
 //
+// The error is a [*PointError] holding the reason the commitment is invalid.
+//
 // [bytes_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bytes_to_kzg_commitment
 func DeserializeKZGCommitment(commitment KZGCommitment) (bls12381.G1Affine, error) {
-	return deserializeG1Point(G1Point(commitment))
+	return decodeKZGPoint(commitmentKind, commitment[:], true, false)
 }
 
 // DeserializeKZGProof implements [bytes_to_kzg_proof].
 //
+// The error is a [*PointError] holding the reason the proof is invalid.
+//
 // [bytes_to_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bytes_to_kzg_proof
 func DeserializeKZGProof(proof KZGProof) (bls12381.G1Affine, error) {
-	return deserializeG1Point(G1Point(proof))
+	return decodeKZGPoint(proofKind, proof[:], true, false)
+}
+
+// DeserializeKZGCommitmentBytes is [DeserializeKZGCommitment] for a commitment held in a byte slice. If the slice
+// does not hold exactly [CompressedG1Size] bytes, the [*PointError] wraps a [*LengthError].
+func DeserializeKZGCommitmentBytes(commitment []byte) (bls12381.G1Affine, error) {
+	serCommitment, err := g1PointFromBytes(commitmentKind, commitment)
+	if err != nil {
+		return bls12381.G1Affine{}, err
+	}
+	return DeserializeKZGCommitment(KZGCommitment(serCommitment))
+}
+
+// DeserializeKZGProofBytes is [DeserializeKZGProof] for a proof held in a byte slice. If the slice does not hold
+// exactly [CompressedG1Size] bytes, the [*PointError] wraps a [*LengthError].
+func DeserializeKZGProofBytes(proof []byte) (bls12381.G1Affine, error) {
+	serProof, err := g1PointFromBytes(proofKind, proof)
+	if err != nil {
+		return bls12381.G1Affine{}, err
+	}
+	return DeserializeKZGProof(KZGProof(serProof))
+}
+
+// g1PointFromBytes converts a byte slice holding a compressed point to [G1Point]. It returns a [*PointError] for the
+// given kind wrapping a [*LengthError] if the slice does not hold exactly [CompressedG1Size] bytes.
+func g1PointFromBytes(kind string, serPoint []byte) (G1Point, error) {
+	if len(serPoint) != CompressedG1Size {
+		return G1Point{}, &PointError{Kind: kind, Err: &LengthError{Got: len(serPoint), Want: CompressedG1Size}}
+	}
+	return G1Point(serPoint), nil
+}
+
+// The kinds of points reported in a [PointError].
+const (
+	commitmentKind = "commitment"
+	proofKind      = "proof"
+)
+
+// decodeKZGPoint decodes a compressed or uncompressed commitment or proof using [decodeG1Point], additionally
+// rejecting the point at infinity if rejectInfinity is true. Errors are returned as a [*PointError] of the given kind.
+func decodeKZGPoint(kind string, serPoint []byte, subgroupCheck, rejectInfinity bool) (bls12381.G1Affine, error) {
+	point, err := decodeG1Point(serPoint, subgroupCheck)
+	if err != nil {
+		return bls12381.G1Affine{}, &PointError{Kind: kind, Err: err}
+	}
+	if rejectInfinity && point.IsInfinity() {
+		return bls12381.G1Affine{}, &PointError{Kind: kind, Err: ErrPointAtInfinity}
+	}
+	return point, nil
+}
+
+// SerializeG1PointUncompressed converts a [bls12381.G1Affine] to [G1PointUncompressed].
+func SerializeG1PointUncompressed(affine bls12381.G1Affine) G1PointUncompressed {
+	return affine.RawBytes()
+}
+
+// SerializeKZGCommitmentUncompressed converts a [KZGCommitment] to its uncompressed form. The commitment is checked
+// like in [DeserializeKZGCommitment].
+func SerializeKZGCommitmentUncompressed(commitment KZGCommitment) (KZGCommitmentUncompressed, error) {
+	point, err := DeserializeKZGCommitment(commitment)
+	if err != nil {
+		return KZGCommitmentUncompressed{}, err
+	}
+	return KZGCommitmentUncompressed(SerializeG1PointUncompressed(point)), nil
+}
+
+// SerializeKZGProofUncompressed converts a [KZGProof] to its uncompressed form. The proof is checked like in
+// [DeserializeKZGProof].
+func SerializeKZGProofUncompressed(proof KZGProof) (KZGProofUncompressed, error) {
+	point, err := DeserializeKZGProof(proof)
+	if err != nil {
+		return KZGProofUncompressed{}, err
+	}
+	return KZGProofUncompressed(SerializeG1PointUncompressed(point)), nil
+}
+
+// DeserializeKZGCommitmentUncompressed is the counterpart of [DeserializeKZGCommitment] for the uncompressed form of
+// a commitment. The point is checked to be on the curve and in the correct subgroup, but no square root is computed.
+func DeserializeKZGCommitmentUncompressed(commitment KZGCommitmentUncompressed) (bls12381.G1Affine, error) {
+	return decodeKZGPoint(commitmentKind, commitment[:], true, false)
+}
+
+// DeserializeKZGProofUncompressed is the counterpart of [DeserializeKZGProof] for the uncompressed form of a proof.
+// The point is checked to be on the curve and in the correct subgroup, but no square root is computed.
+func DeserializeKZGProofUncompressed(proof KZGProofUncompressed) (bls12381.G1Affine, error) {
+	return decodeKZGPoint(proofKind, proof[:], true, false)
+}
+
+// deserializeKZGCommitment is [DeserializeKZGCommitment] with the additional check for the point at infinity
+// configured using [WithRejectInfinityCommitments]. The subgroup check is skipped if the context was created using
+// [WithTrustedCommitments].
+func (c *Context) deserializeKZGCommitment(commitment KZGCommitment) (bls12381.G1Affine, error) {
+	return c.decodeKZGCommitment(commitment[:])
+}
+
+// decodeKZGCommitment implements [Context.deserializeKZGCommitment] for compressed and uncompressed commitments.
+func (c *Context) decodeKZGCommitment(serCommitment []byte) (bls12381.G1Affine, error) {
+	return decodeKZGPoint(commitmentKind, serCommitment, !c.trustedCommitments, c.rejectInfinityCommitments)
+}
+
+// deserializeBlob is [DeserializeBlob] with the additional check that the context was created for polynomials with
+// [ScalarsPerBlob] evaluations. See [NewContextFromSetup].
+func (c *Context) deserializeBlob(blob *Blob) (kzg.Polynomial, error) {
+	if c.domain.Cardinality != ScalarsPerBlob {
+		return nil, ErrContextSizeMismatch
+	}
+	return DeserializeBlob(blob)
+}
+
+// blobDeserializeChunkScalars is the number of scalars checked at once by [Context.deserializeBlobParallel].
+const blobDeserializeChunkScalars = 512
+
+// deserializeBlobParallel is [Context.deserializeBlob] which checks the scalars of the blob in chunks using up to
+// numGoRoutines go routines, resolved like in [WithNumGoRoutines], so that the deserialization of a single blob
+// does not run on one core before the multi exponentiation uses all of them. With one go routine, it is
+// [Context.deserializeBlob].
+//
+// The chunks are all checked, so that the error holds the index of the first invalid scalar like [DeserializeBlob].
+func (c *Context) deserializeBlobParallel(blob *Blob, numGoRoutines int) (kzg.Polynomial, error) {
+	numWorkers := utils.NumGoRoutines(c.goRoutines(numGoRoutines))
+	if numWorkers == 1 {
+		return c.deserializeBlob(blob)
+	}
+	if c.domain.Cardinality != ScalarsPerBlob {
+		return nil, ErrContextSizeMismatch
+	}
+
+	const numChunks = ScalarsPerBlob / blobDeserializeChunkScalars
+	poly := make(kzg.Polynomial, ScalarsPerBlob)
+	var invalidIndices [numChunks]int
+	err := utils.ParallelFor(numChunks, numWorkers, func(chunk int) error {
+		start, end := chunk*blobDeserializeChunkScalars, (chunk+1)*blobDeserializeChunkScalars
+		invalidIndices[chunk], _ = utils.ReduceCanonicalBatch(poly[start:end], blob[start*SerializedScalarSize:end*SerializedScalarSize])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for chunk, index := range invalidIndices {
+		if index != -1 {
+			return nil, &ScalarError{Index: chunk*blobDeserializeChunkScalars + index}
+		}
+	}
+	return poly, nil
+}
+
+// polynomialPool holds the polynomials of [ScalarsPerBlob] evaluations returned by [Context.deserializeBlobPooled].
+var polynomialPool = sync.Pool{
+	New: func() any {
+		polynomial := make(kzg.Polynomial, ScalarsPerBlob)
+		return &polynomial
+	},
+}
+
+// deserializeBlobPooled is [Context.deserializeBlob] for the verification methods, which only use the polynomial
+// until they return: it is taken from polynomialPool rather than allocated, and the caller gives it back with
+// polynomialPool.Put once it is no longer used.
+func (c *Context) deserializeBlobPooled(blob *Blob) (*kzg.Polynomial, error) {
+	if c.domain.Cardinality != ScalarsPerBlob {
+		return nil, ErrContextSizeMismatch
+	}
+	polynomial := polynomialPool.Get().(*kzg.Polynomial)
+	if i, err := utils.ReduceCanonicalBatch(*polynomial, blob[:]); err != nil {
+		polynomialPool.Put(polynomial)
+		return nil, &ScalarError{Index: i}
+	}
+	return polynomial, nil
+}
+
+// deserializeBlobs calls [Context.deserializeBlob] on each of the blobs, using the number of go routines configured
+// by [WithNumGoRoutines]. The polynomials are returned in the same order as the blobs.
+//
+// If any of the blobs is invalid, a [*BlobError] is returned holding the index of the first invalid blob, regardless
+// of the order in which the go routines process them. If ctx is done before every blob is processed, ctx.Err() is
+// returned.
+func (c *Context) deserializeBlobs(ctx context.Context, blobs []Blob) ([]kzg.Polynomial, error) {
+	polynomials := make([]kzg.Polynomial, len(blobs))
+	errs := make([]error, len(blobs))
+	// The errors are recorded rather than returned, so that every blob is processed
+	// and the first invalid blob can be found afterwards.
+	err := utils.ParallelForCtx(ctx, len(blobs), c.numGoRoutines, func(i int) error {
+		polynomials[i], errs[i] = c.deserializeBlob(&blobs[i])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, &BlobError{Index: i, Err: err}
+		}
+	}
+	return polynomials, nil
+}
+
+// deserializeKZGCommitments calls [Context.deserializeKZGCommitment] on each of the commitments, using the number of
+// go routines configured by [WithNumGoRoutines]. The points are returned in the same order as the commitments.
+//
+// Each distinct commitment is only deserialized once, see [Context.deserializeUniqueKZGCommitments]. If any of the
+// commitments is invalid, a [*BlobError] is returned holding the index of the first invalid commitment, regardless of
+// the order in which the go routines process them. If ctx is done before every commitment is processed, ctx.Err() is
+// returned.
+func (c *Context) deserializeKZGCommitments(ctx context.Context, commitments []KZGCommitment) ([]bls12381.G1Affine, error) {
+	return expandUnique(c.deserializeUniqueKZGCommitments(ctx, commitments))
+}
+
+// deserializeUniqueKZGCommitments deserializes each distinct commitment once, which saves the subgroup checks of the
+// repeated commitments in batches where a commitment comes with several proofs. It returns the points of the distinct
+// commitments in the order of their first occurrence, and for each commitment the index of its point.
+//
+// Errors are reported as by [Context.deserializeKZGCommitments], at the first index of the invalid commitment.
+func (c *Context) deserializeUniqueKZGCommitments(ctx context.Context, commitments []KZGCommitment) ([]bls12381.G1Affine, []uint64, error) {
+	return decodeUniqueKZGPoints(ctx, c, commitments, func(commitment *KZGCommitment) (bls12381.G1Affine, error) {
+		return c.decodeKZGCommitment(commitment[:])
+	})
+}
+
+// decodeUniqueKZGPoints implements [Context.deserializeUniqueKZGCommitments] for compressed or uncompressed
+// commitments, where decode deserializes a single commitment.
+func decodeUniqueKZGPoints[T comparable](ctx context.Context, c *Context, encoded []T, decode func(*T) (bls12381.G1Affine, error)) ([]bls12381.G1Affine, []uint64, error) {
+	unique, indices, firstIndices := deduplicate(encoded)
+	points, err := c.decodeKZGPoints(ctx, len(unique), func(i int) (bls12381.G1Affine, error) {
+		return decode(&unique[i])
+	})
+	if err != nil {
+		var blobErr *BlobError
+		if errors.As(err, &blobErr) {
+			blobErr.Index = firstIndices[blobErr.Index]
+		}
+		return nil, nil, err
+	}
+	return points, indices, nil
+}
+
+// deduplicate returns the distinct elements of s in the order of their first occurrence, the index in that list of
+// each element of s, and the index in s of the first occurrence of each distinct element.
+func deduplicate[T comparable](s []T) (unique []T, indices []uint64, firstIndices []int) {
+	uniqueIndices := make(map[T]uint64)
+	indices = make([]uint64, len(s))
+	for i, element := range s {
+		index, ok := uniqueIndices[element]
+		if !ok {
+			index = uint64(len(unique))
+			uniqueIndices[element] = index
+			unique = append(unique, element)
+			firstIndices = append(firstIndices, i)
+		}
+		indices[i] = index
+	}
+	return unique, indices, firstIndices
+}
+
+// expandUnique returns the points at the given indices of the distinct points, which undoes [deduplicate].
+func expandUnique(points []bls12381.G1Affine, indices []uint64, err error) ([]bls12381.G1Affine, error) {
+	if err != nil {
+		return nil, err
+	}
+	expanded := make([]bls12381.G1Affine, len(indices))
+	for i, index := range indices {
+		expanded[i] = points[index]
+	}
+	return expanded, nil
+}
+
+// deserializeKZGProofs is [Context.deserializeKZGCommitments] for proofs.
+func (c *Context) deserializeKZGProofs(ctx context.Context, proofs []KZGProof) ([]bls12381.G1Affine, error) {
+	return c.decodeKZGPoints(ctx, len(proofs), func(i int) (bls12381.G1Affine, error) {
+		return c.decodeKZGProof(proofs[i][:])
+	})
+}
+
+// decodeKZGPoints implements [Context.deserializeKZGCommitments] for n commitments or proofs, where decode(i)
+// deserializes the i'th point.
+func (c *Context) decodeKZGPoints(ctx context.Context, n int, decode func(i int) (bls12381.G1Affine, error)) ([]bls12381.G1Affine, error) {
+	points := make([]bls12381.G1Affine, n)
+	errs := make([]error, n)
+	// The errors are recorded rather than returned, see [Context.deserializeBlobs]
+	err := utils.ParallelForCtx(ctx, n, c.numGoRoutines, func(i int) error {
+		points[i], errs[i] = decode(i)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, &BlobError{Index: i, Err: err}
+		}
+	}
+	return points, nil
+}
+
+// deserializeKZGProof is [DeserializeKZGProof] with the additional check for the point at infinity
+// configured using [WithRejectInfinityProofs].
+func (c *Context) deserializeKZGProof(proof KZGProof) (bls12381.G1Affine, error) {
+	return c.decodeKZGProof(proof[:])
+}
+
+// decodeKZGProof implements [Context.deserializeKZGProof] for compressed and uncompressed proofs.
+func (c *Context) decodeKZGProof(serProof []byte) (bls12381.G1Affine, error) {
+	return decodeKZGPoint(proofKind, serProof, true, c.rejectInfinityProofs)
+}
+
+// scalarModulus is the big-endian encoding of the modulus of the scalar field, against which serialized scalars are
+// compared to check that they are canonical.
+var scalarModulus = func() Scalar {
+	var modulus Scalar
+	fr.Modulus().FillBytes(modulus[:])
+	return modulus
+}()
+
+// ValidateBlob checks that every scalar in the blob is canonical, ie that [DeserializeBlob] would accept it, without
+// converting the scalars to field elements.
+//
+// If a scalar is not canonical, a [*ScalarError] is returned holding the index of the first such scalar.
+func ValidateBlob(blob *Blob) error {
+	for i := 0; i < ScalarsPerBlob; i++ {
+		chunk := blob[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
+		if bytes.Compare(chunk, scalarModulus[:]) >= 0 {
+			return &ScalarError{Index: i}
+		}
+	}
+	return nil
 }
 
 // DeserializeBlob implements [blob_to_polynomial].
 //
+// If a scalar is not canonical, a [*ScalarError] is returned holding the index of the first such scalar.
+//
 // [blob_to_polynomial]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_polynomial
 func DeserializeBlob(blob *Blob) (kzg.Polynomial, error) {
 	poly := make(kzg.Polynomial, ScalarsPerBlob)
-	for i := 0; i < ScalarsPerBlob; i++ {
+	if i, err := utils.ReduceCanonicalBatch(poly, blob[:]); err != nil {
+		return nil, &ScalarError{Index: i}
+	}
+	return poly, nil
+}
+
+// MaxCollectedScalarErrors is the maximum number of invalid scalars reported by [DeserializeBlobCollectErrors].
+const MaxCollectedScalarErrors = 64
+
+// DeserializeBlobCollectErrors is [DeserializeBlob] for debugging blobs: instead of stopping at the first scalar which
+// is not canonical, it returns the indices of all of them, up to [MaxCollectedScalarErrors], in increasing order.
+//
+// If a scalar is not canonical, the polynomial is nil and the error is a [*ScalarError] holding the index of the
+// first such scalar, as returned by [DeserializeBlob].
+func DeserializeBlobCollectErrors(blob *Blob) (kzg.Polynomial, []int, error) {
+	poly := make(kzg.Polynomial, ScalarsPerBlob)
+	var invalidIndices []int
+	for i := 0; i < ScalarsPerBlob && len(invalidIndices) < MaxCollectedScalarErrors; i++ {
 		chunk := blob[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
 		if err := poly[i].SetBytesCanonical(chunk); err != nil {
-			return nil, ErrNonCanonicalScalar
+			invalidIndices = append(invalidIndices, i)
+		}
+	}
+	if len(invalidIndices) > 0 {
+		return nil, invalidIndices, &ScalarError{Index: invalidIndices[0]}
+	}
+	return poly, nil, nil
+}
+
+// blobReadChunkScalars is the number of scalars read at once by [DeserializeBlobFromReader].
+const blobReadChunkScalars = 64
+
+// DeserializeBlobBytes is [DeserializeBlob] for a blob held in a byte slice. It returns a [*LengthError] if the slice
+// does not hold exactly the number of bytes in a [Blob]. The slice is not copied.
+func DeserializeBlobBytes(blob []byte) (kzg.Polynomial, error) {
+	serBlob, err := blobFromBytes(blob)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeBlob(serBlob)
+}
+
+// blobFromBytes returns a [*Blob] pointing to the bytes of the slice, so that it is not copied, or a [*LengthError]
+// if the slice does not hold exactly the number of bytes in a [Blob].
+func blobFromBytes(blob []byte) (*Blob, error) {
+	if len(blob) != len(Blob{}) {
+		return nil, &LengthError{Got: len(blob), Want: len(Blob{})}
+	}
+	return (*Blob)(blob), nil
+}
+
+// DeserializeBlobFromReader reads a serialized [Blob] from `r` and deserializes it like [DeserializeBlob], without
+// first reading the whole blob into memory.
+//
+// The scalars are read in small chunks and checked as soon as they are read, so that an invalid blob is rejected
+// without waiting for the rest of it. Exactly the size of a blob is read from `r`, so several blobs can be read from
+// the same stream.
+//
+// It returns a [*ScalarError] holding the index of the first invalid scalar, or an error wrapping
+// [io.ErrUnexpectedEOF] if `r` ends before the end of the blob. Errors returned by `r` are wrapped as well.
+func DeserializeBlobFromReader(r io.Reader) (kzg.Polynomial, error) {
+	poly := make(kzg.Polynomial, ScalarsPerBlob)
+
+	var buf [blobReadChunkScalars * SerializedScalarSize]byte
+	for start := 0; start < ScalarsPerBlob; start += blobReadChunkScalars {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("reading scalars %d to %d: %w", start, start+blobReadChunkScalars-1, err)
+		}
+		for i := 0; i < blobReadChunkScalars; i++ {
+			chunk := buf[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
+			if err := poly[start+i].SetBytesCanonical(chunk); err != nil {
+				return nil, &ScalarError{Index: start + i}
+			}
 		}
 	}
 	return poly, nil
 }
 
-// DeserializeScalar implements [bytes_to_bls_field].
+// WriteBlobTo writes the polynomial `poly` to `w` in the serialized form of a [Blob], like [SerializePoly], without
+// first serializing the whole blob into memory.
+//
+// It returns [ErrContextSizeMismatch] if the polynomial does not have [ScalarsPerBlob] evaluations.
+func WriteBlobTo(w io.Writer, poly kzg.Polynomial) error {
+	if len(poly) != ScalarsPerBlob {
+		return ErrContextSizeMismatch
+	}
+
+	var buf [blobReadChunkScalars * SerializedScalarSize]byte
+	for start := 0; start < ScalarsPerBlob; start += blobReadChunkScalars {
+		for i := 0; i < blobReadChunkScalars; i++ {
+			serScalar := SerializeScalar(poly[start+i])
+			copy(buf[i*SerializedScalarSize:], serScalar[:])
+		}
+		if _, err := w.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeserializeScalar implements [bytes_to_bls_field]: the scalar is a 32 byte big endian integer, as everywhere in
+// the specs. See [DeserializeScalarLE] for little endian scalars.
 //
 // Note: Returns an error if the scalar is not in the range [0, p-1] (inclusive) where `p` is the prime associated with the scalar field.
 //
@@ -131,11 +659,50 @@ func DeserializeScalar(serScalar Scalar) (fr.Element, error) {
 	return scalar, nil
 }
 
-// SerializeScalar converts a [fr.Element] to [Scalar].
+// SerializeScalar converts a [fr.Element] to [Scalar], as a 32 byte big endian integer. See [SerializeScalarLE] for
+// little endian scalars.
 func SerializeScalar(element fr.Element) Scalar {
 	return element.Bytes()
 }
 
+// DeserializeScalarLE is [DeserializeScalar] for a scalar serialized as a 32 byte little endian integer, which is the
+// byte order of the early EIP-4844 specs and of some tools which predate the current specs.
+//
+// Returns [ErrNonCanonicalScalar] if the scalar is not in the range [0, p-1] (inclusive).
+func DeserializeScalarLE(serScalar Scalar) (fr.Element, error) {
+	var scalar fr.Element
+	if !utils.SetFrLittleEndian(&scalar, serScalar[:]) {
+		return fr.Element{}, ErrNonCanonicalScalar
+	}
+	return scalar, nil
+}
+
+// SerializeScalarLE is [SerializeScalar] for a scalar serialized as a 32 byte little endian integer.
+func SerializeScalarLE(element fr.Element) Scalar {
+	var serScalar Scalar
+	fr.LittleEndian.PutElement((*[fr.Bytes]byte)(&serScalar), element)
+	return serScalar
+}
+
+// ReverseScalarEndianness converts a serialized scalar from big endian to little endian, or from little endian to
+// big endian, by reversing its bytes. The result is canonical in the new byte order if and only if the input is
+// canonical in the old one.
+func ReverseScalarEndianness(serScalar Scalar) Scalar {
+	utils.ReverseSlice(serScalar[:])
+	return serScalar
+}
+
+// ReverseBlobEndianness applies [ReverseScalarEndianness] to each of the scalars of the blob, so that a blob
+// serialized using little endian scalars can be passed to the methods of [Context], which expect big endian scalars
+// as in the specs.
+func ReverseBlobEndianness(blob *Blob) *Blob {
+	var reversed Blob
+	for i := 0; i < ScalarsPerBlob; i++ {
+		utils.ReverseBytesCopy(reversed[i*SerializedScalarSize:(i+1)*SerializedScalarSize], blob[i*SerializedScalarSize:(i+1)*SerializedScalarSize])
+	}
+	return &reversed
+}
+
 // SerializePoly converts a [kzg.Polynomial] to [Blob].
 //
 // Note: This method is never used in the API because we always expect a byte array and will never receive deserialized
@@ -149,3 +716,69 @@ func SerializePoly(poly kzg.Polynomial) *Blob {
 	}
 	return &blob
 }
+
+// MarshalText implements [encoding.TextMarshaler], which is also used by encoding/json. The blob is encoded as a
+// hex-string with the 0x prefix.
+//
+// The receiver is a pointer so that the blob is not copied. encoding/json therefore only uses this method for an
+// addressable blob, such as a *Blob or a Blob field of a struct which is marshalled through a pointer.
+func (blob *Blob) MarshalText() ([]byte, error) {
+	return encodeHex(blob[:]), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], which is also used by encoding/json. The 0x prefix is
+// optional and the hex-string must encode exactly the number of bytes in a blob.
+//
+// The scalars are not checked to be canonical, this happens when the blob is deserialized.
+func (blob *Blob) UnmarshalText(text []byte) error {
+	return decodeHex(blob[:], text)
+}
+
+// MarshalText implements [encoding.TextMarshaler], which is also used by encoding/json. The commitment is encoded as
+// a hex-string with the 0x prefix.
+func (commitment KZGCommitment) MarshalText() ([]byte, error) {
+	return encodeHex(commitment[:]), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], which is also used by encoding/json. The 0x prefix is
+// optional and the hex-string must encode exactly [CompressedG1Size] bytes.
+//
+// The point is not checked to be valid, this happens when the commitment is deserialized.
+func (commitment *KZGCommitment) UnmarshalText(text []byte) error {
+	return decodeHex(commitment[:], text)
+}
+
+// MarshalText implements [encoding.TextMarshaler], which is also used by encoding/json. The proof is encoded as a
+// hex-string with the 0x prefix.
+func (proof KZGProof) MarshalText() ([]byte, error) {
+	return encodeHex(proof[:]), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], which is also used by encoding/json. The 0x prefix is
+// optional and the hex-string must encode exactly [CompressedG1Size] bytes.
+//
+// The point is not checked to be valid, this happens when the proof is deserialized.
+func (proof *KZGProof) UnmarshalText(text []byte) error {
+	return decodeHex(proof[:], text)
+}
+
+// encodeHex returns the hex-string of src with the 0x prefix.
+func encodeHex(src []byte) []byte {
+	text := make([]byte, 2+hex.EncodedLen(len(src)))
+	copy(text, "0x")
+	hex.Encode(text[2:], src)
+	return text
+}
+
+// decodeHex decodes the hex-string text, with an optional 0x prefix, into dst. The hex-string must encode exactly
+// len(dst) bytes.
+func decodeHex(dst, text []byte) error {
+	text = bytes.TrimPrefix(text, []byte("0x"))
+	if len(text) != hex.EncodedLen(len(dst)) {
+		return fmt.Errorf("%w: expected %d hex characters, got %d", ErrInvalidHexString, hex.EncodedLen(len(dst)), len(text))
+	}
+	if _, err := hex.Decode(dst, text); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHexString, err)
+	}
+	return nil
+}
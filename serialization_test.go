@@ -2,13 +2,24 @@ package gokzg4844_test
 
 import (
 	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
 	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/RiemaLabs/go-kzg-4844/pkg/testutil"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestG1RoundTripSmoke(t *testing.T) {
@@ -58,6 +69,458 @@ func TestSerializePolyRoundTrip(t *testing.T) {
 	assertPolyNotEqual(t, expectedPolyA, gotPolyB)
 }
 
+func TestScalarEndianness(t *testing.T) {
+	modulus := fr.Modulus()
+	var modulusMinusOne, modulusPlusOne big.Int
+	modulusMinusOne.Sub(modulus, big.NewInt(1))
+	modulusPlusOne.Add(modulus, big.NewInt(1))
+	allOnes := new(big.Int).SetBytes(bytes.Repeat([]byte{0xff}, gokzg4844.SerializedScalarSize))
+
+	tests := []struct {
+		value     *big.Int
+		canonical bool
+	}{
+		{big.NewInt(0), true},
+		{big.NewInt(1), true},
+		{big.NewInt(0x0102), true},
+		{&modulusMinusOne, true},
+		{modulus, false},
+		{&modulusPlusOne, false},
+		{allOnes, false},
+	}
+	for _, test := range tests {
+		var serScalarBE gokzg4844.Scalar
+		test.value.FillBytes(serScalarBE[:])
+		serScalarLE := gokzg4844.ReverseScalarEndianness(serScalarBE)
+		require.Equal(t, serScalarBE, gokzg4844.ReverseScalarEndianness(serScalarLE))
+
+		scalarBE, errBE := gokzg4844.DeserializeScalar(serScalarBE)
+		scalarLE, errLE := gokzg4844.DeserializeScalarLE(serScalarLE)
+		if !test.canonical {
+			require.ErrorIs(t, errBE, gokzg4844.ErrNonCanonicalScalar, test.value)
+			require.ErrorIs(t, errLE, gokzg4844.ErrNonCanonicalScalar, test.value)
+			continue
+		}
+		require.NoError(t, errBE)
+		require.NoError(t, errLE)
+		require.True(t, scalarBE.Equal(&scalarLE), test.value)
+		require.Equal(t, 0, test.value.Cmp(scalarLE.BigInt(new(big.Int))))
+
+		// Round trips
+		require.Equal(t, serScalarBE, gokzg4844.SerializeScalar(scalarBE))
+		require.Equal(t, serScalarLE, gokzg4844.SerializeScalarLE(scalarLE))
+	}
+
+	// The byte order is that of the integer
+	serScalarLE := gokzg4844.SerializeScalarLE(fr.NewElement(0x0102))
+	require.Equal(t, []byte{0x02, 0x01}, serScalarLE[:2])
+}
+
+func TestReverseBlobEndianness(t *testing.T) {
+	poly := randPoly4096()
+	var blobLE gokzg4844.Blob
+	for i := range poly {
+		serScalar := gokzg4844.SerializeScalarLE(poly[i])
+		copy(blobLE[i*gokzg4844.SerializedScalarSize:], serScalar[:])
+	}
+
+	blobBE := gokzg4844.ReverseBlobEndianness(&blobLE)
+	require.Equal(t, gokzg4844.SerializePoly(poly), blobBE)
+	require.Equal(t, &blobLE, gokzg4844.ReverseBlobEndianness(blobBE))
+
+	gotPoly, err := gokzg4844.DeserializeBlob(blobBE)
+	require.NoError(t, err)
+	assertPolyEqual(t, poly, gotPoly)
+}
+
+func TestDeserializeBlobAllocs(t *testing.T) {
+	blob := gokzg4844.SerializePoly(randPoly4096())
+
+	// The scalars are read directly from the blob, so the only allocation is the polynomial itself
+	allocs := testing.AllocsPerRun(10, func() {
+		_, err := gokzg4844.DeserializeBlob(blob)
+		require.NoError(t, err)
+	})
+	require.LessOrEqual(t, allocs, 1.0)
+}
+
+func TestValidateBlob(t *testing.T) {
+	blob := gokzg4844.SerializePoly(randPoly4096())
+	require.NoError(t, gokzg4844.ValidateBlob(blob))
+	allocs := testing.AllocsPerRun(10, func() {
+		require.NoError(t, gokzg4844.ValidateBlob(blob))
+	})
+	require.Zero(t, allocs)
+
+	// The modulus is the smallest non-canonical scalar
+	modulus := fr.Modulus()
+	var serModulus gokzg4844.Scalar
+	modulus.FillBytes(serModulus[:])
+	copy(blob[gokzg4844.SerializedScalarSize*2000:], serModulus[:])
+	copy(blob[gokzg4844.SerializedScalarSize*3000:], serModulus[:])
+
+	err := gokzg4844.ValidateBlob(blob)
+	var scalarErr *gokzg4844.ScalarError
+	require.ErrorAs(t, err, &scalarErr)
+	require.Equal(t, 2000, scalarErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+// FuzzValidateBlob checks that [gokzg4844.ValidateBlob] accepts exactly the blobs that [gokzg4844.DeserializeBlob]
+// accepts, and reports the same invalid scalar.
+func FuzzValidateBlob(f *testing.F) {
+	modulus := fr.Modulus()
+	var modulusMinusOne, modulusPlusOne big.Int
+	modulusMinusOne.Sub(modulus, big.NewInt(1))
+	modulusPlusOne.Add(modulus, big.NewInt(1))
+	for _, seed := range []*big.Int{big.NewInt(0), &modulusMinusOne, modulus, &modulusPlusOne} {
+		var serScalar gokzg4844.Scalar
+		seed.FillBytes(serScalar[:])
+		f.Add(uint16(0), serScalar[:])
+		f.Add(uint16(4095), append(serScalar[:], serScalar[:]...))
+	}
+	f.Add(uint16(100), bytes.Repeat([]byte{0xff}, 3*gokzg4844.SerializedScalarSize))
+
+	f.Fuzz(func(t *testing.T, index uint16, data []byte) {
+		var blob gokzg4844.Blob
+		copy(blob[int(index)%gokzg4844.ScalarsPerBlob*gokzg4844.SerializedScalarSize:], data)
+
+		_, expectedErr := gokzg4844.DeserializeBlob(&blob)
+		require.Equal(t, expectedErr, gokzg4844.ValidateBlob(&blob))
+	})
+}
+
+// FuzzDeserializeBlob checks that the entry points deserializing a blob all accept and reject the same blobs, that
+// they agree with [gokzg4844.ValidateBlob], and that a byte slice of any length is rejected without panicking unless
+// it holds exactly a blob.
+func FuzzDeserializeBlob(f *testing.F) {
+	var serModulus gokzg4844.Scalar
+	fr.Modulus().FillBytes(serModulus[:])
+	f.Add(uint16(0), []byte{})
+	f.Add(uint16(4095), serModulus[:])
+	f.Add(uint16(17), bytes.Repeat([]byte{0xff}, 2*gokzg4844.SerializedScalarSize))
+	f.Add(uint16(64), bytes.Repeat([]byte{0x73}, 5*gokzg4844.SerializedScalarSize+3))
+
+	f.Fuzz(func(t *testing.T, index uint16, data []byte) {
+		// data is also checked on its own, since its length is arbitrary
+		_, err := gokzg4844.DeserializeBlobBytes(data)
+		if len(data) != len(gokzg4844.Blob{}) {
+			var lengthErr *gokzg4844.LengthError
+			require.ErrorAs(t, err, &lengthErr)
+		}
+
+		var blob gokzg4844.Blob
+		copy(blob[int(index)%gokzg4844.ScalarsPerBlob*gokzg4844.SerializedScalarSize:], data)
+
+		poly, err := gokzg4844.DeserializeBlob(&blob)
+		require.Equal(t, err, gokzg4844.ValidateBlob(&blob))
+
+		polyBytes, errBytes := gokzg4844.DeserializeBlobBytes(blob[:])
+		require.Equal(t, err, errBytes)
+		require.Equal(t, poly, polyBytes)
+
+		polyReader, errReader := gokzg4844.DeserializeBlobFromReader(bytes.NewReader(blob[:]))
+		require.Equal(t, err, errReader)
+		require.Equal(t, poly, polyReader)
+
+		polyCollected, invalidIndices, errCollected := gokzg4844.DeserializeBlobCollectErrors(&blob)
+		require.Equal(t, err, errCollected)
+		require.Equal(t, poly, polyCollected)
+		if err != nil {
+			var scalarErr *gokzg4844.ScalarError
+			require.ErrorAs(t, err, &scalarErr)
+			require.Equal(t, scalarErr.Index, invalidIndices[0])
+		}
+	})
+}
+
+// FuzzDeserializeG1 checks that decoding a commitment or a proof from arbitrary bytes never panics, that the errors
+// are classified as invalid inputs, and that a compressed point which is accepted is the canonical encoding of the
+// decoded point.
+func FuzzDeserializeG1(f *testing.F) {
+	_, _, generator, _ := bls12381.Generators()
+	compressedGenerator := gokzg4844.SerializeG1Point(generator)
+	uncompressedGenerator := gokzg4844.SerializeG1PointUncompressed(generator)
+	f.Add(compressedGenerator[:])
+	f.Add(uncompressedGenerator[:])
+	f.Add(gokzg4844.PointAtInfinity[:])
+	f.Add(bytes.Repeat([]byte{0xff}, gokzg4844.CompressedG1Size))
+	f.Add(bytes.Repeat([]byte{0xff}, gokzg4844.UncompressedG1Size))
+	f.Add([]byte{0xc0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		commitment, err := gokzg4844.DeserializeKZGCommitmentBytes(data)
+		proof, proofErr := gokzg4844.DeserializeKZGProofBytes(data)
+		require.Equal(t, err == nil, proofErr == nil)
+		if err != nil {
+			var pointErr *gokzg4844.PointError
+			require.ErrorAs(t, err, &pointErr)
+			require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
+			require.ErrorIs(t, proofErr, gokzg4844.ErrInvalidInput)
+		} else {
+			require.Equal(t, commitment, proof)
+			reserialized := gokzg4844.SerializeG1Point(commitment)
+			require.Equal(t, data, reserialized[:])
+		}
+
+		if len(data) == gokzg4844.UncompressedG1Size {
+			point, err := gokzg4844.DeserializeKZGCommitmentUncompressed(gokzg4844.KZGCommitmentUncompressed(data))
+			if err != nil {
+				require.ErrorIs(t, err, gokzg4844.ErrInvalidInput)
+			} else {
+				reserialized := gokzg4844.SerializeG1PointUncompressed(point)
+				require.Equal(t, data, reserialized[:])
+			}
+		}
+	})
+}
+
+// FuzzDeserializeScalar checks [gokzg4844.DeserializeScalar] against a reference implementation of
+// bytes_to_bls_field using big integers, and that [gokzg4844.DeserializeBlob] agrees with it.
+func FuzzDeserializeScalar(f *testing.F) {
+	modulus := fr.Modulus()
+	var modulusMinusOne big.Int
+	modulusMinusOne.Sub(modulus, big.NewInt(1))
+	for _, seed := range []*big.Int{big.NewInt(0), big.NewInt(1), &modulusMinusOne, modulus} {
+		var serScalar gokzg4844.Scalar
+		seed.FillBytes(serScalar[:])
+		f.Add(serScalar[:])
+	}
+	f.Add(bytes.Repeat([]byte{0xff}, gokzg4844.SerializedScalarSize))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var serScalar gokzg4844.Scalar
+		copy(serScalar[:], data)
+
+		expected := new(big.Int).SetBytes(serScalar[:])
+		isCanonical := expected.Cmp(modulus) < 0
+
+		scalar, err := gokzg4844.DeserializeScalar(serScalar)
+		if !isCanonical {
+			require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, 0, expected.Cmp(scalar.BigInt(new(big.Int))))
+		}
+
+		var blob gokzg4844.Blob
+		copy(blob[gokzg4844.SerializedScalarSize*4095:], serScalar[:])
+		poly, err := gokzg4844.DeserializeBlob(&blob)
+		if !isCanonical {
+			require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+		} else {
+			require.NoError(t, err)
+			require.True(t, poly[4095].Equal(&scalar))
+		}
+	})
+}
+
+func TestUncompressedG1RoundTrip(t *testing.T) {
+	_, _, genG1, _ := bls12381.Generators()
+	var infinity bls12381.G1Affine
+
+	for _, point := range []bls12381.G1Affine{genG1, *new(bls12381.G1Affine).Double(&genG1), infinity} {
+		compressed := gokzg4844.SerializeG1Point(point)
+
+		commitment, err := gokzg4844.SerializeKZGCommitmentUncompressed(gokzg4844.KZGCommitment(compressed))
+		require.NoError(t, err)
+		require.Equal(t, gokzg4844.KZGCommitmentUncompressed(gokzg4844.SerializeG1PointUncompressed(point)), commitment)
+		proof, err := gokzg4844.SerializeKZGProofUncompressed(gokzg4844.KZGProof(compressed))
+		require.NoError(t, err)
+		require.Equal(t, gokzg4844.G1PointUncompressed(commitment), gokzg4844.G1PointUncompressed(proof))
+
+		// Both forms decode to the same point
+		fromCompressed, err := gokzg4844.DeserializeKZGCommitment(gokzg4844.KZGCommitment(compressed))
+		require.NoError(t, err)
+		fromUncompressed, err := gokzg4844.DeserializeKZGCommitmentUncompressed(commitment)
+		require.NoError(t, err)
+		require.True(t, fromCompressed.Equal(&fromUncompressed))
+		require.True(t, point.Equal(&fromUncompressed))
+		fromUncompressed, err = gokzg4844.DeserializeKZGProofUncompressed(proof)
+		require.NoError(t, err)
+		require.True(t, point.Equal(&fromUncompressed))
+	}
+}
+
+func TestUncompressedG1Invalid(t *testing.T) {
+	_, _, genG1, _ := bls12381.Generators()
+	valid := gokzg4844.SerializeG1PointUncompressed(genG1)
+
+	notOnCurve := valid
+	notOnCurve[gokzg4844.UncompressedG1Size-1] ^= 1
+
+	compressedFlag := valid
+	compressedFlag[0] |= 0x80
+
+	xTooLarge := valid
+	copy(xTooLarge[:], bytes.Repeat([]byte{0xff}, gokzg4844.CompressedG1Size))
+	xTooLarge[0] = 0x1f
+
+	for name, point := range map[string]gokzg4844.G1PointUncompressed{
+		"not on curve":    notOnCurve,
+		"compressed flag": compressedFlag,
+		"x too large":     xTooLarge,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := gokzg4844.DeserializeKZGCommitmentUncompressed(gokzg4844.KZGCommitmentUncompressed(point))
+			require.ErrorIs(t, err, gokzg4844.ErrInvalidPointEncoding)
+			_, err = gokzg4844.DeserializeKZGProofUncompressed(gokzg4844.KZGProofUncompressed(point))
+			require.ErrorIs(t, err, gokzg4844.ErrInvalidPointEncoding)
+		})
+	}
+}
+
+// chunkReader returns the data of r in reads of at most n bytes.
+type chunkReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > c.n {
+		p = p[:c.n]
+	}
+	return c.r.Read(p)
+}
+
+func TestDeserializeBlobCollectErrors(t *testing.T) {
+	modulus := fr.Modulus()
+	var serModulus, serModulusMinusOne gokzg4844.Scalar
+	modulus.FillBytes(serModulus[:])
+	new(big.Int).Sub(modulus, big.NewInt(1)).FillBytes(serModulusMinusOne[:])
+	setScalar := func(blob *gokzg4844.Blob, index int, scalar gokzg4844.Scalar) {
+		copy(blob[index*gokzg4844.SerializedScalarSize:], scalar[:])
+	}
+
+	// The modulus minus one is the largest canonical scalar
+	blob := gokzg4844.SerializePoly(randPoly4096())
+	setScalar(blob, 10, serModulusMinusOne)
+	expectedPoly, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	poly, invalidIndices, err := gokzg4844.DeserializeBlobCollectErrors(blob)
+	require.NoError(t, err)
+	require.Empty(t, invalidIndices)
+	require.Equal(t, expectedPoly, poly)
+
+	tests := []struct {
+		name            string
+		invalidIndices  []int
+		expectedIndices []int
+	}{
+		{"one", []int{4095}, []int{4095}},
+		{"several", []int{3, 10, 11, 2000}, []int{3, 10, 11, 2000}},
+		{"many", makeRange(100, 4096, 3), makeRange(100, 100+3*gokzg4844.MaxCollectedScalarErrors, 3)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blob := gokzg4844.SerializePoly(randPoly4096())
+			for _, index := range test.invalidIndices {
+				setScalar(blob, index, serModulus)
+			}
+
+			poly, invalidIndices, err := gokzg4844.DeserializeBlobCollectErrors(blob)
+			require.Nil(t, poly)
+			require.Equal(t, test.expectedIndices, invalidIndices)
+
+			// The error is the one returned by DeserializeBlob
+			_, expectedErr := gokzg4844.DeserializeBlob(blob)
+			require.Equal(t, expectedErr, err)
+			var scalarErr *gokzg4844.ScalarError
+			require.ErrorAs(t, err, &scalarErr)
+			require.Equal(t, test.invalidIndices[0], scalarErr.Index)
+		})
+	}
+}
+
+// makeRange returns the integers in [start, end) with the given step.
+func makeRange(start, end, step int) []int {
+	var values []int
+	for i := start; i < end; i += step {
+		values = append(values, i)
+	}
+	return values
+}
+
+func TestDeserializeBytes(t *testing.T) {
+	blob := testutil.GenerateBlob(3)
+	expectedPoly, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	poly, err := gokzg4844.DeserializeBlobBytes(blob[:])
+	require.NoError(t, err)
+	require.Equal(t, expectedPoly, poly)
+
+	_, _, g1Aff, _ := bls12381.Generators()
+	serPoint := gokzg4844.SerializeG1Point(g1Aff)
+	commitment, err := gokzg4844.DeserializeKZGCommitmentBytes(serPoint[:])
+	require.NoError(t, err)
+	require.True(t, commitment.Equal(&g1Aff))
+	proof, err := gokzg4844.DeserializeKZGProofBytes(serPoint[:])
+	require.NoError(t, err)
+	require.True(t, proof.Equal(&g1Aff))
+
+	// The slices are checked with the same rules as the arrays
+	invalidPoint := append([]byte{0x9f}, bytes.Repeat([]byte{0xff}, gokzg4844.CompressedG1Size-1)...)
+	_, err = gokzg4844.DeserializeKZGCommitmentBytes(invalidPoint)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPointEncoding)
+}
+
+func TestDeserializeBlobFromReader(t *testing.T) {
+	poly := randPoly4096()
+	blob := gokzg4844.SerializePoly(poly)
+
+	// Two blobs are written to the same stream to check that only one blob is read at a time
+	var stream bytes.Buffer
+	require.NoError(t, gokzg4844.WriteBlobTo(&stream, poly))
+	require.Equal(t, blob[:], stream.Bytes())
+	stream.Write(blob[:])
+
+	readers := map[string]func([]byte) io.Reader{
+		"whole":    func(b []byte) io.Reader { return bytes.NewReader(b) },
+		"1 byte":   func(b []byte) io.Reader { return iotest.OneByteReader(bytes.NewReader(b)) },
+		"33 bytes": func(b []byte) io.Reader { return &chunkReader{bytes.NewReader(b), 33} },
+		"half":     func(b []byte) io.Reader { return iotest.HalfReader(bytes.NewReader(b)) },
+	}
+	for name, newReader := range readers {
+		t.Run(name, func(t *testing.T) {
+			r := newReader(stream.Bytes())
+			for n := 0; n < 2; n++ {
+				got, err := gokzg4844.DeserializeBlobFromReader(r)
+				require.NoError(t, err)
+				assertPolyEqual(t, poly, got)
+			}
+			_, err := gokzg4844.DeserializeBlobFromReader(r)
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+
+	// The index of the first invalid scalar is reported
+	invalid := *blob
+	copy(invalid[1000*gokzg4844.SerializedScalarSize:], gokzg4844.BlsModulus[:])
+	copy(invalid[2000*gokzg4844.SerializedScalarSize:], gokzg4844.BlsModulus[:])
+	_, err := gokzg4844.DeserializeBlobFromReader(&chunkReader{bytes.NewReader(invalid[:]), 33})
+	var scalarErr *gokzg4844.ScalarError
+	require.ErrorAs(t, err, &scalarErr)
+	require.Equal(t, 1000, scalarErr.Index)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+
+	// The blob is rejected as soon as the invalid scalar is read, so the reader failing later is not reached
+	readErr := errors.New("connection reset")
+	failing := io.MultiReader(bytes.NewReader(invalid[:1100*gokzg4844.SerializedScalarSize]), iotest.ErrReader(readErr))
+	_, err = gokzg4844.DeserializeBlobFromReader(failing)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+
+	// Errors and short reads are reported
+	failing = io.MultiReader(bytes.NewReader(blob[:12345]), iotest.ErrReader(readErr))
+	_, err = gokzg4844.DeserializeBlobFromReader(failing)
+	require.ErrorIs(t, err, readErr)
+	_, err = gokzg4844.DeserializeBlobFromReader(bytes.NewReader(blob[:len(blob)-1]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, err = gokzg4844.DeserializeBlobFromReader(bytes.NewReader(nil))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	require.ErrorIs(t, gokzg4844.WriteBlobTo(io.Discard, poly[:100]), gokzg4844.ErrContextSizeMismatch)
+}
+
 // Check element-wise that each evaluation in the polynomial is the same
 func assertPolyEqual(t *testing.T, lhs, rhs kzg.Polynomial) {
 	t.Helper()
@@ -119,3 +582,105 @@ func randPoly4096() kzg.Polynomial {
 	}
 	return poly
 }
+
+func TestKZGToVersionedHash(t *testing.T) {
+	tests := []struct {
+		commitment    string
+		versionedHash string
+	}{
+		// The commitment to the zero blob is the point at infinity
+		{"c0" + strings.Repeat("00", 47), "010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014"},
+		{strings.Repeat("00", 48), "01b0761f87b081d5cf10757ccc89f12be355c70e2e29df288b65b30710dcbcd1"},
+	}
+	for _, test := range tests {
+		var commitment gokzg4844.KZGCommitment
+		require.NoError(t, commitment.UnmarshalText([]byte(test.commitment)))
+		versionedHash := gokzg4844.KZGToVersionedHash(commitment)
+		require.Equal(t, test.versionedHash, hex.EncodeToString(versionedHash[:]))
+		require.Equal(t, byte(gokzg4844.VersionedHashVersionKZG), versionedHash[0])
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	blob := testutil.GenerateBlob(7)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+
+	type encoded struct {
+		Blob       *gokzg4844.Blob         `json:"blob"`
+		Commitment gokzg4844.KZGCommitment `json:"commitment"`
+		Proof      gokzg4844.KZGProof      `json:"proof"`
+	}
+	data, err := json.Marshal(encoded{blob, commitment, proof})
+	require.NoError(t, err)
+	require.Equal(t, `{"blob":"0x`+hex.EncodeToString(blob[:])+`","commitment":"0x`+hex.EncodeToString(commitment[:])+
+		`","proof":"0x`+hex.EncodeToString(proof[:])+`"}`, string(data))
+
+	var decoded encoded
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, encoded{blob, commitment, proof}, decoded)
+
+	// A Blob value is encoded the same way when it is addressable
+	valueData, err := json.Marshal(&struct {
+		Blob gokzg4844.Blob `json:"blob"`
+	}{*blob})
+	require.NoError(t, err)
+	require.Equal(t, `{"blob":"0x`+hex.EncodeToString(blob[:])+`"}`, string(valueData))
+
+	// The 0x prefix is optional
+	var decodedProof gokzg4844.KZGProof
+	require.NoError(t, decodedProof.UnmarshalText([]byte(hex.EncodeToString(proof[:]))))
+	require.Equal(t, proof, decodedProof)
+
+	hexProof := hex.EncodeToString(proof[:])
+	for _, text := range []string{"", "0x", "0x" + hexProof[2:], "0x" + hexProof + "00", "0x" + hexProof[2:] + "zz", "0X" + hexProof} {
+		var commitment gokzg4844.KZGCommitment
+		require.ErrorIs(t, commitment.UnmarshalText([]byte(text)), gokzg4844.ErrInvalidHexString, text)
+	}
+	err = json.Unmarshal([]byte(`{"blob":"0x00"}`), &decoded)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidHexString)
+}
+
+func TestUnmarshalConsensusSpecsTestCase(t *testing.T) {
+	type Test struct {
+		Input struct {
+			Blob       gokzg4844.Blob          `yaml:"blob"`
+			Commitment gokzg4844.KZGCommitment `yaml:"commitment"`
+			Proof      gokzg4844.KZGProof      `yaml:"proof"`
+		}
+		Output bool `yaml:"output"`
+	}
+
+	data, err := os.ReadFile(filepath.Join(testDir, "verify_blob_kzg_proof/kzg-mainnet/verify_blob_kzg_proof_case_correct_proof_19b3f3f8c98ea31e/data.yaml"))
+	require.NoError(t, err)
+	var test Test
+	require.NoError(t, yaml.Unmarshal(data, &test))
+	require.True(t, test.Output)
+	require.NoError(t, ctx.VerifyBlobKZGProof(&test.Input.Blob, test.Input.Commitment, test.Input.Proof))
+
+	// The values match the ones decoded by the consensus-specs tests
+	var hexTest struct {
+		Input struct {
+			Blob       string `yaml:"blob"`
+			Commitment string `yaml:"commitment"`
+			Proof      string `yaml:"proof"`
+		}
+	}
+	require.NoError(t, yaml.Unmarshal(data, &hexTest))
+	blob, err := hexStrToBlob(hexTest.Input.Blob)
+	require.NoError(t, err)
+	commitment, err := hexStrToCommitment(hexTest.Input.Commitment)
+	require.NoError(t, err)
+	proof, err := hexStrToProof(hexTest.Input.Proof)
+	require.NoError(t, err)
+	require.Equal(t, *blob, test.Input.Blob)
+	require.Equal(t, commitment, test.Input.Commitment)
+	require.Equal(t, proof, test.Input.Proof)
+
+	// Encoding the values gives back the hex-strings of the test case
+	text, err := test.Input.Proof.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, hexTest.Input.Proof, string(text))
+}
@@ -0,0 +1,190 @@
+package gokzg4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// setupCacheVersion is the version of the format written by [Context.SaveSetupCache].
+// It must be incremented whenever the format changes.
+const setupCacheVersion uint32 = 1
+
+// The setup cache holds the points of the trusted setup in uncompressed form, so that
+// creating a [Context] from it does not need to decompress them. It is laid out as follows:
+//
+//   - the format version as a big endian uint32
+//   - the SHA-256 digest of the trusted setup, see [computeSetupDigest]
+//   - the G1 generator, the monomial G1 points, the lagrange G1 points and the G2 points
+//     encoded using gnark-crypto's raw encoding. Slices are prefixed by their length.
+//
+// The lagrange G1 points are stored in the order of the trusted setup, not bit-reversed.
+
+// SaveSetupCache writes the trusted setup held by the context to `w` in a binary format which can be loaded
+// much faster than the JSON or text formats using [NewContextFromSetupCache].
+//
+// The cache should be stored somewhere that only trusted parties can write to, since the points are not
+// subgroup checked when it is loaded. It returns [ErrVerifyOnlyContext] if the context was created using
+// [ModeVerifyOnly], since it does not hold the G1 points.
+func (c *Context) SaveSetupCache(w io.Writer) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return err
+	}
+
+	var monomialG1 []bls12381.G1Affine
+	if c.monomialCommitKey != nil {
+		monomialG1 = c.monomialCommitKey.G1
+	}
+
+	lagrangeG1 := c.setupLagrangeG1()
+
+	if err := binary.Write(w, binary.BigEndian, setupCacheVersion); err != nil {
+		return err
+	}
+	if _, err := w.Write(c.setupDigest[:]); err != nil {
+		return err
+	}
+
+	enc := bls12381.NewEncoder(w, bls12381.RawEncoding())
+	for _, v := range []interface{}{&c.openKey.GenG1, monomialG1, lagrangeG1, c.openKey.G2} {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewContextFromSetupCache creates a new context object from a trusted setup cache written by
+// [Context.SaveSetupCache].
+//
+// This skips the decompression and the subgroup checks of the points, which makes it several times faster than
+// [NewContext4096]. The points are still checked to be on the curve, and the digest of the setup stored in the
+// cache is checked against the points. The context has the same size as the context the cache was written from.
+//
+// It returns an error wrapping [ErrSetupCacheVersion] if the cache was written using a different format version,
+// [ErrSetupCacheChecksum] if the points do not match the digest and [ErrInvalidSetupCache] if the cache is
+// otherwise malformed.
+//
+// Options can be passed to modify the behavior of the [Context], see [ContextOption].
+func NewContextFromSetupCache(r io.Reader, opts ...ContextOption) (*Context, error) {
+	config := newContextConfig(opts)
+
+	var version uint32
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSetupCache, err)
+	}
+	if version != setupCacheVersion {
+		return nil, fmt.Errorf("%w: got version %d, expected %d", ErrSetupCacheVersion, version, setupCacheVersion)
+	}
+
+	var digest [32]byte
+	if _, err := io.ReadFull(r, digest[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSetupCache, err)
+	}
+
+	var (
+		genG1      bls12381.G1Affine
+		monomialG1 []bls12381.G1Affine
+		lagrangeG1 []bls12381.G1Affine
+		g2         []bls12381.G2Affine
+	)
+	dec := bls12381.NewDecoder(r, bls12381.NoSubgroupChecks())
+	for _, v := range []interface{}{&genG1, &monomialG1, &lagrangeG1, &g2} {
+		if err := dec.Decode(v); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSetupCache, err)
+		}
+	}
+
+	size := uint64(len(lagrangeG1))
+	if size < 2 || !utils.IsPowerOfTwo(size) || (len(monomialG1) != 0 && len(monomialG1) != len(lagrangeG1)) || len(g2) < 2 {
+		return nil, fmt.Errorf("%w: unexpected number of points", ErrInvalidSetupCache)
+	}
+
+	// The raw encoding holds both coordinates, which are not checked by the decoder
+	if !genG1.IsOnCurve() {
+		return nil, fmt.Errorf("%w: G1 generator is not on the curve", ErrInvalidSetupCache)
+	}
+	for i := range monomialG1 {
+		if !monomialG1[i].IsOnCurve() {
+			return nil, fmt.Errorf("%w: monomial G1 point %d is not on the curve", ErrInvalidSetupCache, i)
+		}
+	}
+	for i := range lagrangeG1 {
+		if !lagrangeG1[i].IsOnCurve() {
+			return nil, fmt.Errorf("%w: lagrange G1 point %d is not on the curve", ErrInvalidSetupCache, i)
+		}
+	}
+	for i := range g2 {
+		if !g2[i].IsOnCurve() {
+			return nil, fmt.Errorf("%w: G2 point %d is not on the curve", ErrInvalidSetupCache, i)
+		}
+	}
+
+	if len(monomialG1) == 0 {
+		monomialG1 = nil
+	}
+	if computeSetupDigest(monomialG1, lagrangeG1, g2) != digest {
+		return nil, ErrSetupCacheChecksum
+	}
+
+	if !config.retainMonomialSRS() {
+		monomialG1 = nil
+	}
+
+	return newContextFromPoints(config, genG1, monomialG1, lagrangeG1, g2)
+}
+
+// setupLagrangeG1 returns a copy of the lagrange G1 points held by the context in the order of the trusted setup,
+// undoing the bit-reversal applied when the context was created.
+func (c *Context) setupLagrangeG1() []bls12381.G1Affine {
+	lagrangeG1 := kzg.CommitKey{G1: make([]bls12381.G1Affine, len(c.commitKey.G1))}
+	copy(lagrangeG1.G1, c.commitKey.G1)
+	lagrangeG1.ReversePoints()
+	return lagrangeG1.G1
+}
+
+// computeSetupDigest computes the SHA-256 digest of the compressed points of a trusted setup, with each group
+// of points prefixed by its length as a big endian uint32. The lagrange G1 points must be in the order of the
+// trusted setup, and the monomial G1 points may be nil.
+//
+// Since the compressed encoding determines a point on the curve, two setups with the same digest hold
+// the same points.
+func computeSetupDigest(monomialG1, lagrangeG1 []bls12381.G1Affine, g2 []bls12381.G2Affine) [32]byte {
+	h := sha256.New()
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(monomialG1)))
+	h.Write(length[:])
+	for i := range monomialG1 {
+		pointBytes := monomialG1[i].Bytes()
+		h.Write(pointBytes[:])
+	}
+
+	binary.BigEndian.PutUint32(length[:], uint32(len(lagrangeG1)))
+	h.Write(length[:])
+	for i := range lagrangeG1 {
+		pointBytes := lagrangeG1[i].Bytes()
+		h.Write(pointBytes[:])
+	}
+
+	binary.BigEndian.PutUint32(length[:], uint32(len(g2)))
+	h.Write(length[:])
+	for i := range g2 {
+		pointBytes := g2[i].Bytes()
+		h.Write(pointBytes[:])
+	}
+
+	var digest [32]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
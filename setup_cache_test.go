@@ -0,0 +1,110 @@
+package gokzg4844
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupCacheRoundTrip(t *testing.T) {
+	ctx, err := NewContext4096Secure()
+	require.NoError(t, err)
+
+	var cache bytes.Buffer
+	require.NoError(t, ctx.SaveSetupCache(&cache))
+
+	ctxFromCache, err := NewContextFromSetupCache(bytes.NewReader(cache.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, ctx.setupDigest, ctxFromCache.setupDigest)
+
+	blob := &Blob{}
+	for i := 0; i < ScalarsPerBlob; i += 7 {
+		blob[i*SerializedScalarSize+31] = byte(i)
+	}
+	blobCommitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	got, err := ctxFromCache.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	require.Equal(t, blobCommitment, got)
+
+	proof, err := ctxFromCache.ComputeBlobKZGProof(blob, got, 0)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyBlobKZGProof(blob, got, proof))
+	require.NoError(t, ctxFromCache.VerifyBlobKZGProof(blob, got, proof))
+
+	poly := []fr.Element{fr.One(), fr.One()}
+	expected, err := ctx.CommitToMonomialPolynomial(poly)
+	require.NoError(t, err)
+	got, err = ctxFromCache.CommitToMonomialPolynomial(poly)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// Options are applied to the loaded context
+	ctxNoMonomial, err := NewContextFromSetupCache(bytes.NewReader(cache.Bytes()), WithoutMonomialSRS())
+	require.NoError(t, err)
+	_, err = ctxNoMonomial.CommitToMonomialPolynomial(poly)
+	require.ErrorIs(t, err, ErrMonomialSRSUnavailable)
+
+	// A context without the monomial points can be cached as well
+	var cacheNoMonomial bytes.Buffer
+	require.NoError(t, ctxNoMonomial.SaveSetupCache(&cacheNoMonomial))
+	require.Less(t, cacheNoMonomial.Len(), cache.Len())
+	ctxFromCache, err = NewContextFromSetupCache(&cacheNoMonomial)
+	require.NoError(t, err)
+	got, err = ctxFromCache.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	require.Equal(t, blobCommitment, got)
+}
+
+func TestSetupCacheInvalid(t *testing.T) {
+	ctx, err := NewContext4096Secure()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ctx.SaveSetupCache(&buf))
+	cache := buf.Bytes()
+
+	// modify returns a copy of the cache after applying `f` to it
+	modify := func(f func([]byte) []byte) []byte {
+		return f(append([]byte{}, cache...))
+	}
+
+	tests := []struct {
+		name     string
+		cache    []byte
+		expected error
+	}{
+		{"empty", nil, ErrInvalidSetupCache},
+		{"truncated", cache[:len(cache)/2], ErrInvalidSetupCache},
+		{"version", modify(func(c []byte) []byte {
+			c[3]++
+			return c
+		}), ErrSetupCacheVersion},
+		{"digest", modify(func(c []byte) []byte {
+			c[4] ^= 1
+			return c
+		}), ErrSetupCacheChecksum},
+		{"point not on the curve", modify(func(c []byte) []byte {
+			c[len(c)-1] ^= 1
+			return c
+		}), ErrInvalidSetupCache},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewContextFromSetupCache(bytes.NewReader(test.cache))
+			require.ErrorIs(t, err, test.expected)
+		})
+	}
+
+	// A cache for a different trusted setup has a valid digest, which does not match the points
+	ctxInsecure, err := NewContext4096Insecure1337()
+	require.NoError(t, err)
+	var insecureCache bytes.Buffer
+	require.NoError(t, ctxInsecure.SaveSetupCache(&insecureCache))
+	mixed := append([]byte{}, insecureCache.Bytes()...)
+	copy(mixed[4:36], cache[4:36])
+	_, err = NewContextFromSetupCache(bytes.NewReader(mixed))
+	require.ErrorIs(t, err, ErrSetupCacheChecksum)
+}
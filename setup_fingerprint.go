@@ -0,0 +1,53 @@
+package gokzg4844
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// MainnetSetupFingerprint is the fingerprint of the trusted setup from the Ethereum KZG ceremony, which is used by
+// [NewContext4096Secure]. See [Context.SetupFingerprint].
+var MainnetSetupFingerprint = [32]byte{
+	0x02, 0xe9, 0x0a, 0xb3, 0x32, 0xf5, 0x17, 0xeb,
+	0x0b, 0x2c, 0x1d, 0x61, 0x6d, 0xa1, 0x58, 0x87,
+	0xe6, 0xdd, 0x54, 0xc7, 0xbb, 0xf8, 0x35, 0x1f,
+	0x63, 0x44, 0x4b, 0x9f, 0x33, 0x14, 0x24, 0x4d,
+}
+
+// SetupFingerprint returns a fingerprint identifying the trusted setup held by the context, which can be logged or
+// compared to check which trusted setup is in use.
+//
+// The fingerprint is the SHA-256 digest of the following, in order:
+//   - 4 zero bytes
+//   - the number of lagrange G1 points as a big endian uint32, followed by the compressed lagrange G1 points in the
+//     order of the trusted setup
+//   - the number of G2 points as a big endian uint32, followed by the compressed G2 points
+//
+// The monomial G1 points are not part of the fingerprint, so it does not depend on whether the trusted setup contained
+// them or on the format the trusted setup was loaded from. It is computed on the first call and cached.
+func (c *Context) SetupFingerprint() [32]byte {
+	if !c.calls.acquire() {
+		return [32]byte{}
+	}
+	defer c.calls.release()
+	c.setupFingerprintOnce.Do(func() {
+		c.setupFingerprint = computeSetupDigest(nil, c.setupLagrangeG1(), c.openKey.G2)
+	})
+	return c.setupFingerprint
+}
+
+// CheckSetupFingerprint returns an error wrapping [ErrSetupFingerprintMismatch] if the fingerprint of the trusted
+// setup held by the context is not `expected`. Use [MainnetSetupFingerprint] to check that the context holds the
+// trusted setup from the Ethereum KZG ceremony.
+func (c *Context) CheckSetupFingerprint(expected [32]byte) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	fingerprint := c.SetupFingerprint()
+	if fingerprint != expected {
+		return fmt.Errorf("%w: got %s, expected %s", ErrSetupFingerprintMismatch, hex.EncodeToString(fingerprint[:]), hex.EncodeToString(expected[:]))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package gokzg4844
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupFingerprint(t *testing.T) {
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
+
+	// Compute the fingerprint from the hex-strings of the trusted setup, following the documented format
+	h := sha256.New()
+	h.Write(make([]byte, 4))
+	h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(setup.SetupG1Lagrange))))
+	for _, point := range setup.SetupG1Lagrange {
+		pointBytes, err := point.Bytes()
+		require.NoError(t, err)
+		h.Write(pointBytes)
+	}
+	h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(setup.SetupG2))))
+	for _, point := range setup.SetupG2 {
+		pointBytes, err := point.Bytes()
+		require.NoError(t, err)
+		h.Write(pointBytes)
+	}
+	require.Equal(t, MainnetSetupFingerprint[:], h.Sum(nil))
+
+	ctx, err := NewContext4096Secure()
+	require.NoError(t, err)
+	require.Equal(t, MainnetSetupFingerprint, ctx.SetupFingerprint())
+	require.NoError(t, ctx.CheckSetupFingerprint(MainnetSetupFingerprint))
+
+	// The fingerprint does not depend on how the trusted setup was loaded
+	ctxFromJSON, err := NewContext4096FromReader(strings.NewReader(mainnetTrustedSetupJSON(t)))
+	require.NoError(t, err)
+	ctxFromText, err := NewContext4096FromTextReader(strings.NewReader(readTrustedSetupText(t, false)), WithSubgroupChecks(false))
+	require.NoError(t, err)
+	var cache bytes.Buffer
+	require.NoError(t, ctx.SaveSetupCache(&cache))
+	ctxFromCache, err := NewContextFromSetupCache(&cache)
+	require.NoError(t, err)
+	ctxNoMonomial, err := NewContext4096Secure(WithoutMonomialSRS())
+	require.NoError(t, err)
+	for _, other := range []*Context{ctxFromJSON, ctxFromText, ctxFromCache, ctxNoMonomial} {
+		require.Equal(t, MainnetSetupFingerprint, other.SetupFingerprint())
+	}
+
+	// Changing a single point changes the fingerprint
+	setup.SetupG1Lagrange[100], setup.SetupG1Lagrange[101] = setup.SetupG1Lagrange[101], setup.SetupG1Lagrange[100]
+	ctxModified, err := NewContext4096(setup)
+	require.NoError(t, err)
+	require.NotEqual(t, MainnetSetupFingerprint, ctxModified.SetupFingerprint())
+	require.ErrorIs(t, ctxModified.CheckSetupFingerprint(MainnetSetupFingerprint), ErrSetupFingerprintMismatch)
+
+	ctxInsecure, err := NewContext4096Insecure1337()
+	require.NoError(t, err)
+	require.ErrorIs(t, ctxInsecure.CheckSetupFingerprint(MainnetSetupFingerprint), ErrSetupFingerprintMismatch)
+}
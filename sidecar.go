@@ -0,0 +1,99 @@
+package gokzg4844
+
+import "context"
+
+// VerifyBlobSidecar checks the KZG parts of a blob sidecar in one call: that the blob is canonical, that the
+// commitment is a valid point whose versioned hash, see [KZGToVersionedHash], is `expectedVersionedHash`, and that
+// `proof` is a valid proof for the blob and the commitment, as checked by [Context.VerifyBlobKZGProof].
+//
+// The checks are done in this order and the first failure is returned, so that the faulty component can be told from
+// the error:
+//   - a [*ScalarError] for a non-canonical blob,
+//   - a [*PointError] of kind "commitment" for an invalid commitment,
+//   - [ErrVersionedHashMismatch] if the commitment does not match the versioned hash,
+//   - a [*PointError] of kind "proof" for an invalid proof,
+//   - [ErrProofInvalid] if the proof does not verify.
+func (c *Context) VerifyBlobSidecar(blob *Blob, commitment KZGCommitment, expectedVersionedHash [32]byte, proof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization and versioned hash check
+	//
+	polynomial, err := c.deserializeBlobPooled(blob)
+	if err != nil {
+		return err
+	}
+	defer polynomialPool.Put(polynomial)
+
+	polynomialCommitment, err := c.deserializeKZGCommitment(commitment)
+	if err != nil {
+		return err
+	}
+
+	if KZGToVersionedHash(commitment) != expectedVersionedHash {
+		return ErrVersionedHashMismatch
+	}
+
+	quotientCommitment, err := c.deserializeKZGProof(proof)
+	if err != nil {
+		return err
+	}
+
+	// 2. Verify the proof
+	//
+	return c.verifyBlobKZGProof(blob, *polynomial, commitment, polynomialCommitment, quotientCommitment)
+}
+
+// VerifyBlobSidecarBatch is [Context.VerifyBlobSidecar] for many sidecars at once, where the i'th sidecar is made of
+// blobs[i], commitments[i], expectedVersionedHashes[i] and proofs[i].
+//
+// The components of all the sidecars are deserialized and checked first, like by [Context.VerifyBlobKZGProofBatch],
+// and the proofs are then verified together with a single pairing check. If a component is invalid, a [*BlobError]
+// holding the index of its sidecar is returned, wrapping the error that [Context.VerifyBlobSidecar] would return. The
+// blobs are checked first, then the commitments, the versioned hashes and the proofs, so the first invalid component
+// of each kind is reported. If the number of elements in the slices differ, [ErrBatchLengthCheck] is returned.
+func (c *Context) VerifyBlobSidecarBatch(blobs []Blob, commitments []KZGCommitment, expectedVersionedHashes [][32]byte, proofs []KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Check that all components in the batch have the same size
+	//
+	batchSize := len(blobs)
+	if err := checkBatchLengths([]string{"blobs", "commitments", "versioned hashes", "proofs"}, batchSize, len(commitments), len(expectedVersionedHashes), len(proofs)); err != nil {
+		return err
+	}
+	if batchSize == 0 {
+		return nil
+	}
+
+	// 2. Deserialization and versioned hash checks
+	//
+	polynomials, err := c.deserializeBlobs(context.Background(), blobs)
+	if err != nil {
+		return err
+	}
+
+	polynomialCommitments, err := c.deserializeKZGCommitments(context.Background(), commitments)
+	if err != nil {
+		return err
+	}
+
+	for i := range commitments {
+		if KZGToVersionedHash(commitments[i]) != expectedVersionedHashes[i] {
+			return &BlobError{Index: i, Err: ErrVersionedHashMismatch}
+		}
+	}
+
+	quotientCommitments, err := c.deserializeKZGProofs(context.Background(), proofs)
+	if err != nil {
+		return err
+	}
+
+	// 3. Verify the proofs together
+	//
+	return c.verifyBlobKZGProofBatch(context.Background(), blobs, polynomials, commitments, polynomialCommitments, quotientCommitments, 1)
+}
@@ -0,0 +1,103 @@
+package gokzg4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Transcript is a Fiat-Shamir transcript for protocols built on top of this package, which absorbs labelled
+// messages and derives challenges from all of the messages absorbed so far.
+//
+// The transcript is the SHA-256 hash of a sequence of frames, where frame(x) is the length of x in bytes as an 8
+// byte big endian integer followed by x:
+//   - [NewTranscript] writes frame(domainSeparator).
+//   - [Transcript.AppendBytes] writes frame(label) || frame(b). [Transcript.AppendG1] and [Transcript.AppendScalar]
+//     are AppendBytes with the 48 byte compressed point and the 32 byte big endian scalar.
+//   - [Transcript.ChallengeScalar] writes frame(label), returns c = hash_to_bls_field(bytes written so far), see
+//     [utils.HashToBLSField], and then writes frame(c) with c as a 32 byte big endian scalar, so that the
+//     following challenges depend on it.
+//
+// The frames make the transcript unambiguous: two different sequences of calls never hash the same bytes.
+//
+// A Transcript is not safe for concurrent use.
+type Transcript struct {
+	hasher hash.Hash
+	// Buffers which are reused so that appending does not allocate
+	frameLength [8]byte
+	label       []byte
+	point       KZGCommitment
+	scalar      Scalar
+	digest      [sha256.Size]byte
+}
+
+// NewTranscript returns a transcript whose first frame is the domain separator, which should identify the protocol
+// and its version.
+func NewTranscript(domainSeparator string) *Transcript {
+	t := &Transcript{hasher: sha256.New()}
+	t.appendLabel(domainSeparator)
+	return t
+}
+
+// AppendBytes absorbs the message `b` under the given label.
+func (t *Transcript) AppendBytes(label string, b []byte) {
+	t.appendLabel(label)
+	t.appendFrame(b)
+}
+
+// AppendG1 absorbs the compressed point `p` under the given label.
+func (t *Transcript) AppendG1(label string, p KZGCommitment) {
+	// p is copied, since slicing the parameter would make it escape to the heap
+	t.point = p
+	t.AppendBytes(label, t.point[:])
+}
+
+// AppendScalar absorbs the big endian serialization of `s` under the given label.
+func (t *Transcript) AppendScalar(label string, s fr.Element) {
+	t.scalar = SerializeScalar(s)
+	t.AppendBytes(label, t.scalar[:])
+}
+
+// ChallengeScalar returns a challenge derived from the messages absorbed so far and the label, and absorbs it.
+func (t *Transcript) ChallengeScalar(label string) fr.Element {
+	t.appendLabel(label)
+	challenge := t.challengeUnframed()
+	t.scalar = SerializeScalar(challenge)
+	t.appendFrame(t.scalar[:])
+	return challenge
+}
+
+// appendLabel writes frame(label). The label is copied into a reused buffer, since converting it to a byte slice
+// for the hasher would allocate.
+func (t *Transcript) appendLabel(label string) {
+	t.label = append(t.label[:0], label...)
+	t.appendFrame(t.label)
+}
+
+// appendFrame writes the length of b as an 8 byte big endian integer, followed by b.
+func (t *Transcript) appendFrame(b []byte) {
+	binary.BigEndian.PutUint64(t.frameLength[:], uint64(len(b)))
+	t.hasher.Write(t.frameLength[:])
+	t.hasher.Write(b)
+}
+
+// The transcripts of the specs, like that of [ComputeChallenge], are plain concatenations of their components,
+// so they are written without frames using the methods below.
+
+// resetUnframed empties the transcript, including the domain separator.
+func (t *Transcript) resetUnframed() {
+	t.hasher.Reset()
+}
+
+// appendUnframed writes b as is.
+func (t *Transcript) appendUnframed(b []byte) {
+	t.hasher.Write(b)
+}
+
+// challengeUnframed returns hash_to_bls_field of the bytes written so far, without absorbing it.
+func (t *Transcript) challengeUnframed() fr.Element {
+	return utils.HashToBLSFieldFromHash(t.hasher, t.digest[:0])
+}
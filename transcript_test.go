@@ -0,0 +1,52 @@
+package gokzg4844
+
+import (
+	"encoding/hex"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// The expected values were computed independently by hashing the framed transcript documented
+// in Transcript and reducing the digests modulo the field order.
+func TestTranscriptVectors(t *testing.T) {
+	transcript := NewTranscript("test-protocol-v1")
+	transcript.AppendBytes("msg", []byte("hello"))
+	transcript.AppendG1("point", KZGCommitment(SerializeG1Point(bls12381.G1Affine{})))
+	transcript.AppendScalar("scalar", fr.NewElement(7))
+
+	c1 := SerializeScalar(transcript.ChallengeScalar("c1"))
+	require.Equal(t, "73c4e09ee0452756d65e355785237305f6798f11a53b1a3ddf360f0592a617a6", hex.EncodeToString(c1[:]))
+
+	// The second challenge depends on the first one
+	c2 := SerializeScalar(transcript.ChallengeScalar("c2"))
+	require.Equal(t, "1a360b12a43b40ae0d86ad2ba0db8866ed7b4336ad8cc42bd868e403b55a0c46", hex.EncodeToString(c2[:]))
+}
+
+func TestTranscriptFraming(t *testing.T) {
+	// Moving bytes between the domain separator, the label and the message changes the challenge
+	a := NewTranscript("d")
+	a.AppendBytes("c", nil)
+	b := NewTranscript("dc")
+	b.AppendBytes("", nil)
+	c := NewTranscript("d")
+	c.AppendBytes("", []byte("c"))
+
+	challengeA := a.ChallengeScalar("")
+	require.NotEqual(t, challengeA, b.ChallengeScalar(""))
+	require.NotEqual(t, challengeA, c.ChallengeScalar(""))
+}
+
+func TestTranscriptAllocs(t *testing.T) {
+	transcript := NewTranscript("test-protocol-v1")
+	commitment := KZGCommitment(SerializeG1Point(bls12381.G1Affine{}))
+	scalar := fr.NewElement(7)
+	allocs := testing.AllocsPerRun(100, func() {
+		transcript.AppendG1("point", commitment)
+		transcript.AppendScalar("scalar", scalar)
+		transcript.ChallengeScalar("challenge")
+	})
+	require.Zero(t, allocs)
+}
@@ -0,0 +1,128 @@
+package gokzg4844
+
+import (
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// TrustedCommitment is a commitment which has already been deserialized and checked, as returned by
+// [Context.ValidateCommitment]. Verifying many proofs against the same commitment using the methods taking a
+// TrustedCommitment, such as [Context.VerifyCellKZGProofTrusted], skips decompressing the commitment and checking that
+// it is in the correct subgroup for every proof.
+//
+// The zero value is not a valid commitment: the methods return [ErrTrustedCommitmentUnset] for it.
+type TrustedCommitment struct {
+	commitment KZGCommitment
+	point      bls12381.G1Affine
+	set        bool
+}
+
+// ValidateCommitment deserializes the commitment and runs the checks the verification methods of the context run on
+// commitments, so that the returned [TrustedCommitment] can be used to verify many proofs without repeating them.
+//
+// Like the verification methods, it returns a [*PointError] if the commitment is invalid. The checks depend on the
+// options of the context, such as [WithRejectInfinityCommitments], so a TrustedCommitment should only be used with
+// contexts created using the same options as the one which validated it.
+func (c *Context) ValidateCommitment(commitment KZGCommitment) (TrustedCommitment, error) {
+	if !c.calls.acquire() {
+		return TrustedCommitment{}, ErrContextClosed
+	}
+	defer c.calls.release()
+
+	point, err := c.deserializeKZGCommitment(commitment)
+	if err != nil {
+		return TrustedCommitment{}, err
+	}
+	return TrustedCommitment{commitment: commitment, point: point, set: true}, nil
+}
+
+// UnsafeTrustedCommitmentFromPoint returns a [TrustedCommitment] for a point without checking it.
+//
+// The point MUST be on the curve and in the correct subgroup, for example because it was computed by this library or
+// deserialized with [DeserializeKZGCommitment]. Verifying proofs against a point which is not in the correct subgroup
+// is not sound.
+func UnsafeTrustedCommitmentFromPoint(point bls12381.G1Affine) TrustedCommitment {
+	return TrustedCommitment{commitment: KZGCommitment(SerializeG1Point(point)), point: point, set: true}
+}
+
+// Commitment returns the serialized commitment.
+func (t TrustedCommitment) Commitment() KZGCommitment {
+	return t.commitment
+}
+
+// Point returns the deserialized commitment.
+func (t TrustedCommitment) Point() bls12381.G1Affine {
+	return t.point
+}
+
+// VerifyKZGProofTrusted is [Context.VerifyKZGProof] for a commitment which has already been checked.
+func (c *Context) VerifyKZGProofTrusted(commitment TrustedCommitment, inputPointBytes, claimedValueBytes Scalar, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+	if !commitment.set {
+		return ErrTrustedCommitmentUnset
+	}
+
+	// 1. Deserialization
+	//
+	claimedValue, err := DeserializeScalar(claimedValueBytes)
+	if err != nil {
+		return err
+	}
+
+	inputPoint, err := DeserializeScalar(inputPointBytes)
+	if err != nil {
+		return err
+	}
+
+	return c.verifyKZGProof(&commitment.point, inputPoint, claimedValue, kzgProof)
+}
+
+// VerifyBlobKZGProofTrusted is [Context.VerifyBlobKZGProof] for a commitment which has already been checked.
+func (c *Context) VerifyBlobKZGProofTrusted(blob *Blob, commitment TrustedCommitment, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+	if !commitment.set {
+		return ErrTrustedCommitmentUnset
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := c.deserializeBlobPooled(blob)
+	if err != nil {
+		return err
+	}
+	defer polynomialPool.Put(polynomial)
+
+	quotientCommitment, err := c.deserializeKZGProof(kzgProof)
+	if err != nil {
+		return err
+	}
+
+	return c.verifyBlobKZGProof(blob, *polynomial, commitment.commitment, commitment.point, quotientCommitment)
+}
+
+// VerifyCellKZGProofTrusted is [Context.VerifyCellKZGProof] for a commitment which has already been checked. This is
+// useful to verify the proofs of the cells of a blob one by one as they are received.
+func (c *Context) VerifyCellKZGProofTrusted(commitment TrustedCommitment, cellIndex uint64, cell *Cell, proof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+	if !commitment.set {
+		return ErrTrustedCommitmentUnset
+	}
+
+	// 1. Deserialization
+	//
+	if cellIndex >= CellsPerExtBlob {
+		return fmt.Errorf("%w: got %d", ErrInvalidCellIndex, cellIndex)
+	}
+
+	return c.verifyCellKZGProof(&commitment.point, cellIndex, cell, proof)
+}
@@ -1,12 +1,24 @@
 package gokzg4844
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/rand"
 	_ "embed"
 	"encoding/hex"
-	"sync"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
 
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/RiemaLabs/go-kzg-4844/internal/multiexp"
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
 // This library will not :
@@ -14,37 +26,484 @@ import (
 // - Check that setupG1Lagrange is the lagrange version of setupG1.
 //
 // Note: There is an embedded (via a //go:embed - compiler instruction) setup
-// testKzgSetupStr, to which we do check those properties in a test function.
+// mainnetTrustedSetupText, to which we do check those properties in a test function.
 
 // JSONTrustedSetup is a struct used for serializing the trusted setup from/to JSON format.
 //
 // The intended use-case is that library users store the trusted setup in a JSON file and we provide such a file
 // as part of the package.
+//
+// SetupG1Monomial is optional. If it is empty, the [Context] will not be able to commit to polynomials in monomial
+// form.
+//
+// Decoding a JSONTrustedSetup from JSON checks the number of points and the encoding of each point, in the same way
+// as [NewContext4096FromReader].
 type JSONTrustedSetup struct {
-	SetupG2         []G2CompressedHexStr               `json:"g2_monomial"`
-	SetupG1Lagrange [ScalarsPerBlob]G1CompressedHexStr `json:"g1_lagrange"`
+	SetupG2         []G2Hex               `json:"g2_monomial"`
+	SetupG1Monomial []G1Hex               `json:"g1_monomial,omitempty"`
+	SetupG1Lagrange [ScalarsPerBlob]G1Hex `json:"g1_lagrange"`
+}
+
+// JSONTrustedSetupFlexible has the same JSON format as [JSONTrustedSetup], but holds the points in slices so that
+// it can hold a trusted setup of any size. It is used to create a [Context] for polynomials of any power of two
+// size using [NewContextFromSetup].
+//
+// Unlike [JSONTrustedSetup], decoding it from JSON does not check the number of points.
+type JSONTrustedSetupFlexible struct {
+	SetupG2         []G2Hex `json:"g2_monomial"`
+	SetupG1Monomial []G1Hex `json:"g1_monomial,omitempty"`
+	SetupG1Lagrange []G1Hex `json:"g1_lagrange"`
 }
 
+// flexible returns the trusted setup as a [JSONTrustedSetupFlexible], sharing the points.
+func (trustedSetup *JSONTrustedSetup) flexible() *JSONTrustedSetupFlexible {
+	return &JSONTrustedSetupFlexible{
+		SetupG2:         trustedSetup.SetupG2,
+		SetupG1Monomial: trustedSetup.SetupG1Monomial,
+		SetupG1Lagrange: trustedSetup.SetupG1Lagrange[:],
+	}
+}
+
+// G1Hex is a hex-string (with the 0x prefix) of a compressed G1 point.
+//
+// When decoded from JSON, the 0x prefix is optional and the hex-string is checked to encode the right number of
+// bytes. It is always encoded with the 0x prefix.
+type G1Hex string
+
+// G2Hex is a hex-string (with the 0x prefix) of a compressed G2 point.
+//
+// When decoded from JSON, the 0x prefix is optional and the hex-string is checked to encode the right number of
+// bytes. It is always encoded with the 0x prefix.
+type G2Hex string
+
 // G1CompressedHexStr is a hex-string (with the 0x prefix) of a compressed G1 point.
-type G1CompressedHexStr = string
+//
+// Deprecated: Use [G1Hex] instead.
+type G1CompressedHexStr = G1Hex
 
 // G2CompressedHexStr is a hex-string (with the 0x prefix) of a compressed G2 point.
-type G2CompressedHexStr = string
+//
+// Deprecated: Use [G2Hex] instead.
+type G2CompressedHexStr = G2Hex
+
+// Bytes returns the compressed G1 point encoded by the hex-string.
+func (h G1Hex) Bytes() ([]byte, error) {
+	return decodeHexString(string(h))
+}
+
+// Bytes returns the compressed G2 point encoded by the hex-string.
+func (h G2Hex) Bytes() ([]byte, error) {
+	return decodeHexString(string(h))
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (h G1Hex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(add0xPrefix(string(h)))
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (h G2Hex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(add0xPrefix(string(h)))
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (h *G1Hex) UnmarshalJSON(data []byte) error {
+	var hexString string
+	if err := json.Unmarshal(data, &hexString); err != nil {
+		return err
+	}
+	normalized, err := normalizeHexPoint(hexString, bls12381.SizeOfG1AffineCompressed)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTrustedSetupPoint, err)
+	}
+	*h = G1Hex(normalized)
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (h *G2Hex) UnmarshalJSON(data []byte) error {
+	var hexString string
+	if err := json.Unmarshal(data, &hexString); err != nil {
+		return err
+	}
+	normalized, err := normalizeHexPoint(hexString, bls12381.SizeOfG2AffineCompressed)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTrustedSetupPoint, err)
+	}
+	*h = G2Hex(normalized)
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+//
+// It returns an error wrapping [ErrInvalidTrustedSetupSize] if the setup has the wrong number of points and
+// [ErrInvalidTrustedSetupPoint], naming the field and the index of the point, if a point is not a hex-string of
+// the right length.
+func (trustedSetup *JSONTrustedSetup) UnmarshalJSON(data []byte) error {
+	var decoded jsonTrustedSetupSlices
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	validated, err := decoded.validate()
+	if err != nil {
+		return err
+	}
+	*trustedSetup = *validated
+	return nil
+}
+
+// mainnetTrustedSetupText is the trusted setup generated in the Ethereum KZG ceremony, which is used by
+// [NewContext4096Secure]. It is stored in the text format of [ParseTrustedSetupText], which is more compact than
+// the JSON format.
+//
+//go:embed trusted_setup.txt
+var mainnetTrustedSetupText string
+
+// insecureTrustedSetup creates a trusted setup for polynomials with `size` evaluations using the known secret
+// `secret`. Like the trusted setup from the Ethereum KZG ceremony, it has 65 G2 points.
+//
+// This SHOULD NOT BE USED IN PRODUCTION, since anyone knowing the secret can create proofs for false statements.
+func insecureTrustedSetup(secret int64, size uint64) *JSONTrustedSetupFlexible {
+	var alpha fr.Element
+	alpha.SetInt64(secret)
+	monomialG1, lagrangeG1, g2 := insecureSetupPoints(alpha, size)
+	utils.ZeroizeFr(&alpha)
+
+	trustedSetup := &JSONTrustedSetupFlexible{
+		SetupG1Monomial: make([]G1Hex, size),
+		SetupG1Lagrange: make([]G1Hex, size),
+		SetupG2:         make([]G2Hex, len(g2)),
+	}
+	for i := uint64(0); i < size; i++ {
+		monomialBytes := monomialG1[i].Bytes()
+		trustedSetup.SetupG1Monomial[i] = G1Hex("0x" + hex.EncodeToString(monomialBytes[:]))
+		lagrangeBytes := lagrangeG1[i].Bytes()
+		trustedSetup.SetupG1Lagrange[i] = G1Hex("0x" + hex.EncodeToString(lagrangeBytes[:]))
+	}
+	for i := range g2 {
+		pointBytes := g2[i].Bytes()
+		trustedSetup.SetupG2[i] = G2Hex("0x" + hex.EncodeToString(pointBytes[:]))
+	}
+
+	return trustedSetup
+}
+
+// insecureSetupPoints computes the points of a trusted setup for polynomials with `size` evaluations using the
+// known secret α: the monomial G1 points [α^i]₁, the lagrange G1 points [L_i(α)]₁ in the order of the trusted
+// setup and 65 G2 points [α^i]₂. `size` must be a power of two.
+//
+// The powers of α and the other scalars derived from it are overwritten with zeros before returning, see
+// [utils.ZeroizeFr]. The caller owns `alpha`.
+//
+// This SHOULD NOT BE USED IN PRODUCTION, since anyone knowing the secret can create proofs for false statements.
+func insecureSetupPoints(alpha fr.Element, size uint64) ([]bls12381.G1Affine, []bls12381.G1Affine, []bls12381.G2Affine) {
+	const numG2Points = 65
+
+	_, _, genG1, genG2 := bls12381.Generators()
+
+	powers := make([]fr.Element, size)
+	powers[0].SetOne()
+	for i := 1; i < len(powers); i++ {
+		powers[i].Mul(&powers[i-1], &alpha)
+	}
+
+	// The i'th lagrange point is L_i(α) * G where L_i(α) = (ω^i / n) * (α^n - 1) / (α - ω^i).
+	// Computing the scalars first is much faster than an inverse FFT over the monomial points.
+	domain := kzg.NewDomain(size)
+	var vanishing fr.Element
+	vanishing.Mul(&powers[size-1], &alpha)
+	vanishing.Sub(&vanishing, new(fr.Element).SetOne())
+
+	lagrangeScalars := make([]fr.Element, size)
+	if vanishing.IsZero() {
+		// α is one of the roots of unity ω^j, so L_i(α) is 1 if i == j and 0 otherwise
+		for i := range lagrangeScalars {
+			if domain.Roots[i].Equal(&alpha) {
+				lagrangeScalars[i].SetOne()
+			}
+		}
+	} else {
+		vanishing.Mul(&vanishing, &domain.CardinalityInv)
+		for i := range lagrangeScalars {
+			lagrangeScalars[i].Sub(&alpha, &domain.Roots[i])
+		}
+		inverses := fr.BatchInvert(lagrangeScalars)
+		utils.ZeroizeFrSlice(lagrangeScalars)
+		lagrangeScalars = inverses
+		for i := range lagrangeScalars {
+			lagrangeScalars[i].Mul(&lagrangeScalars[i], &domain.Roots[i])
+			lagrangeScalars[i].Mul(&lagrangeScalars[i], &vanishing)
+		}
+	}
+
+	monomialG1 := bls12381.BatchScalarMultiplicationG1(&genG1, powers)
+	lagrangeG1 := bls12381.BatchScalarMultiplicationG1(&genG1, lagrangeScalars)
 
-// This is the test trusted setup, which SHOULD NOT BE USED IN PRODUCTION.
-// The secret for this 1337.
+	g2 := make([]bls12381.G2Affine, numG2Points)
+	var alphaPower fr.Element
+	alphaPower.SetOne()
+	for i := range g2 {
+		var power big.Int
+		g2[i].ScalarMultiplication(&genG2, alphaPower.BigInt(&power))
+		alphaPower.Mul(&alphaPower, &alpha)
+
+		words := power.Bits()
+		for j := range words {
+			words[j] = 0
+		}
+	}
+
+	utils.ZeroizeFrSlice(powers)
+	utils.ZeroizeFrSlice(lagrangeScalars)
+	utils.ZeroizeFr(&alpha, &alphaPower, &vanishing)
+
+	return monomialG1, lagrangeG1, g2
+}
+
+// jsonTrustedSetupSlices has the same JSON format as [JSONTrustedSetup], but holds the lagrange
+// points in a slice, so that we can detect when the JSON has the wrong number of points.
+// Decoding into an array silently ignores extra elements and leaves missing elements empty.
 //
-//go:embed trusted_setup.json
-var testKzgSetupStr string
+// The points are held as plain strings, so that [jsonTrustedSetupSlices.validate] can report
+// the index of an invalid point.
+type jsonTrustedSetupSlices struct {
+	SetupG2         []string `json:"g2_monomial"`
+	SetupG1Monomial []string `json:"g1_monomial"`
+	SetupG1Lagrange []string `json:"g1_lagrange"`
+}
+
+// decodeTrustedSetup reads a trusted setup in JSON format from `r`.
+//
+// It checks the number of points and that each point is a hex-string
+// of the right length, but not that the points are valid group elements.
+//
+// The document is decoded one point at a time rather than being read in full first, so that only the
+// hex-strings of the points and a small read buffer are held in memory. This keeps the memory allocated
+// while decoding to about twice the size of the document, which matters in WASM.
+func decodeTrustedSetup(r io.Reader) (*JSONTrustedSetup, error) {
+	decoder := json.NewDecoder(r)
+
+	var decoded jsonTrustedSetupSlices
+	if err := decoded.decode(decoder); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrustedSetupJSON, err)
+	}
+	trustedSetup, err := decoded.validate()
+	if err != nil {
+		return nil, err
+	}
+	// Check that there is nothing after the JSON object
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after the trusted setup", ErrInvalidTrustedSetupJSON)
+	}
+
+	return trustedSetup, nil
+}
+
+// decode reads the JSON object of a trusted setup from the decoder, like [json.Decoder.Decode] would, but
+// decodes the arrays of points element by element. As with encoding/json, the keys are matched case-insensitively,
+// unknown keys are skipped and null leaves a field empty.
+func (decoded *jsonTrustedSetupSlices) decode(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected an object, got %v", token)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		// Inside an object, the decoder only returns strings as keys
+		key := token.(string)
+
+		var field *[]string
+		switch {
+		case strings.EqualFold(key, "g1_lagrange"):
+			field = &decoded.SetupG1Lagrange
+		case strings.EqualFold(key, "g1_monomial"):
+			field = &decoded.SetupG1Monomial
+		case strings.EqualFold(key, "g2_monomial"):
+			field = &decoded.SetupG2
+		default:
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+			continue
+		}
+		if *field, err = decodeJSONStrings(decoder); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	// Consume the closing brace
+	_, err = decoder.Token()
+	return err
+}
+
+// decodeJSONStrings reads a JSON array of strings, or null, from the decoder one element at a time.
+func decodeJSONStrings(decoder *json.Decoder) ([]string, error) {
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return nil, err
+	}
+	if token != json.Delim('[') {
+		return nil, fmt.Errorf("expected an array, got %v", token)
+	}
+	var values []string
+	for decoder.More() {
+		var value string
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("index %d: %w", len(values), err)
+		}
+		values = append(values, value)
+	}
+	// Consume the closing bracket
+	_, err = decoder.Token()
+	return values, err
+}
+
+// validate checks the number of points and that each point is a hex-string of the right length, with an
+// optional 0x prefix. It returns the points as a [JSONTrustedSetup], with the 0x prefix.
+//
+// Errors name the JSON field and the index of the invalid point.
+func (decoded *jsonTrustedSetupSlices) validate() (*JSONTrustedSetup, error) {
+	if len(decoded.SetupG1Lagrange) != ScalarsPerBlob {
+		return nil, fmt.Errorf("%w: expected %d lagrange G1 points, got %d", ErrInvalidTrustedSetupSize, ScalarsPerBlob, len(decoded.SetupG1Lagrange))
+	}
+	if len(decoded.SetupG1Monomial) != 0 && len(decoded.SetupG1Monomial) != ScalarsPerBlob {
+		return nil, fmt.Errorf("%w: expected %d monomial G1 points, got %d", ErrInvalidTrustedSetupSize, ScalarsPerBlob, len(decoded.SetupG1Monomial))
+	}
+	if len(decoded.SetupG2) < 2 {
+		return nil, fmt.Errorf("%w: expected at least 2 G2 points, got %d", ErrInvalidTrustedSetupSize, len(decoded.SetupG2))
+	}
+
+	trustedSetup := &JSONTrustedSetup{}
+	for i, point := range decoded.SetupG1Lagrange {
+		normalized, err := normalizeHexPoint(point, bls12381.SizeOfG1AffineCompressed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: g1_lagrange[%d]: %v", ErrInvalidTrustedSetupPoint, i, err)
+		}
+		trustedSetup.SetupG1Lagrange[i] = G1Hex(normalized)
+	}
+	if len(decoded.SetupG1Monomial) != 0 {
+		trustedSetup.SetupG1Monomial = make([]G1Hex, len(decoded.SetupG1Monomial))
+	}
+	for i, point := range decoded.SetupG1Monomial {
+		normalized, err := normalizeHexPoint(point, bls12381.SizeOfG1AffineCompressed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: g1_monomial[%d]: %v", ErrInvalidTrustedSetupPoint, i, err)
+		}
+		trustedSetup.SetupG1Monomial[i] = G1Hex(normalized)
+	}
+	trustedSetup.SetupG2 = make([]G2Hex, len(decoded.SetupG2))
+	for i, point := range decoded.SetupG2 {
+		normalized, err := normalizeHexPoint(point, bls12381.SizeOfG2AffineCompressed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: g2_monomial[%d]: %v", ErrInvalidTrustedSetupPoint, i, err)
+		}
+		trustedSetup.SetupG2[i] = G2Hex(normalized)
+	}
+
+	return trustedSetup, nil
+}
+
+// ParseTrustedSetupText parses a trusted setup in the plain-text format used by [c-kzg-4844].
+//
+// The format consists of whitespace separated values: the number of G1 points and the number of G2 points,
+// followed by the lagrange G1 points and then the G2 points. Newer versions of the file also contain the monomial
+// G1 points after the G2 points. All points are hex-strings without the 0x prefix.
+//
+// The returned setup has the same checks applied as in [NewContext4096FromReader].
+//
+// [c-kzg-4844]: https://github.com/ethereum/c-kzg-4844/blob/main/src/trusted_setup.txt
+func ParseTrustedSetupText(r io.Reader) (*JSONTrustedSetup, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+
+	// readCount reads one of the numbers at the start of the file
+	readCount := func(name string) (int, error) {
+		if !scanner.Scan() {
+			return 0, fmt.Errorf("%w: missing the number of %s points", ErrInvalidTrustedSetupText, name)
+		}
+		count, err := strconv.Atoi(scanner.Text())
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("%w: invalid number of %s points %q", ErrInvalidTrustedSetupText, name, scanner.Text())
+		}
+		return count, nil
+	}
+	numG1, err := readCount("G1")
+	if err != nil {
+		return nil, err
+	}
+	numG2, err := readCount("G2")
+	if err != nil {
+		return nil, err
+	}
+	if numG1 != ScalarsPerBlob {
+		return nil, fmt.Errorf("%w: expected %d G1 points, got %d", ErrInvalidTrustedSetupSize, ScalarsPerBlob, numG1)
+	}
+
+	// The points are stored with the 0x prefix, as in the JSON format
+	var points []string
+	for scanner.Scan() {
+		points = append(points, "0x"+scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The monomial G1 points are optional
+	numPoints := len(points)
+	if numPoints != numG1+numG2 && numPoints != 2*numG1+numG2 {
+		return nil, fmt.Errorf("%w: expected %d or %d points, got %d", ErrInvalidTrustedSetupSize, numG1+numG2, 2*numG1+numG2, numPoints)
+	}
+	decoded := jsonTrustedSetupSlices{
+		SetupG1Lagrange: points[:numG1],
+		SetupG2:         points[numG1 : numG1+numG2],
+		SetupG1Monomial: points[numG1+numG2:],
+	}
+
+	return decoded.validate()
+}
+
+// normalizeHexPoint checks that `hexString` is a hex-string encoding `numBytes` bytes, with an optional
+// 0x prefix. It returns the hex-string with the 0x prefix.
+func normalizeHexPoint(hexString string, numBytes int) (string, error) {
+	hexString = add0xPrefix(hexString)
+	if len(hexString) != 2+2*numBytes {
+		return "", fmt.Errorf("expected %d hex characters, got %d", 2*numBytes, len(hexString)-2)
+	}
+	// Decode into a buffer on the stack, since only the validity of the hex-string is checked
+	var decoded [bls12381.SizeOfG2AffineCompressed]byte
+	if _, err := hex.Decode(decoded[:numBytes], []byte(hexString[2:])); err != nil {
+		return "", err
+	}
+	return hexString, nil
+}
+
+// add0xPrefix adds the 0x prefix to `hexString` if it does not have it already.
+func add0xPrefix(hexString string) string {
+	if strings.HasPrefix(hexString, "0x") {
+		return hexString
+	}
+	return "0x" + hexString
+}
 
 // CheckTrustedSetupIsWellFormed checks whether the trusted setup is well-formed.
 //
 // To be specific, this checks that:
 //   - All elements are in the correct subgroup.
+//   - The first G2 point is the canonical BLS12-381 G2 generator.
+//   - No lagrange G1 point is the point at infinity.
+//   - The lagrange G1 points are distinct.
+//
+// Each of the last three checks has its own error, see [checkSetupPointsNonDegenerate].
 func CheckTrustedSetupIsWellFormed(trustedSetup *JSONTrustedSetup) error {
-	for i := 0; i < len(trustedSetup.SetupG1Lagrange); i++ {
+	for i := 0; i < len(trustedSetup.SetupG1Monomial); i++ {
 		var point bls12381.G1Affine
-		byts, err := hex.DecodeString(trim0xPrefix(trustedSetup.SetupG1Lagrange[i]))
+		byts, err := trustedSetup.SetupG1Monomial[i].Bytes()
 		if err != nil {
 			return err
 		}
@@ -54,134 +513,365 @@ func CheckTrustedSetupIsWellFormed(trustedSetup *JSONTrustedSetup) error {
 		}
 	}
 
+	lagrangeG1 := make([]bls12381.G1Affine, len(trustedSetup.SetupG1Lagrange))
+	for i := 0; i < len(trustedSetup.SetupG1Lagrange); i++ {
+		byts, err := trustedSetup.SetupG1Lagrange[i].Bytes()
+		if err != nil {
+			return err
+		}
+		_, err = lagrangeG1[i].SetBytes(byts)
+		if err != nil {
+			return err
+		}
+	}
+
+	g2 := make([]bls12381.G2Affine, len(trustedSetup.SetupG2))
 	for i := 0; i < len(trustedSetup.SetupG2); i++ {
-		var point bls12381.G2Affine
-		byts, err := hex.DecodeString(trim0xPrefix(trustedSetup.SetupG2[i]))
+		byts, err := trustedSetup.SetupG2[i].Bytes()
 		if err != nil {
 			return err
 		}
-		_, err = point.SetBytes(byts)
+		_, err = g2[i].SetBytes(byts)
 		if err != nil {
 			return err
 		}
 	}
 
+	return checkSetupPointsNonDegenerate(lagrangeG1, g2)
+}
+
+// checkSetupPointsNonDegenerate checks that the parsed points of a trusted setup are not degenerate: it returns
+//   - [ErrTrustedSetupG2Generator] if the first G2 point is not the canonical G2 generator,
+//   - [ErrTrustedSetupPointAtInfinity] if a lagrange G1 point is the point at infinity,
+//   - [ErrTrustedSetupDuplicatePoint] if two lagrange G1 points are equal.
+//
+// A setup failing these checks can be used to create proofs which do not verify, or which verify for the wrong
+// values, while passing the subgroup checks.
+func checkSetupPointsNonDegenerate(lagrangeG1 []bls12381.G1Affine, g2 []bls12381.G2Affine) error {
+	_, _, _, genG2 := bls12381.Generators()
+	if len(g2) == 0 {
+		return fmt.Errorf("%w: no G2 points", ErrInvalidTrustedSetupSize)
+	}
+	if !g2[0].Equal(&genG2) {
+		return ErrTrustedSetupG2Generator
+	}
+
+	// The points are compared by their compressed encoding, which is unique for points on the curve
+	seen := make(map[[bls12381.SizeOfG1AffineCompressed]byte]int, len(lagrangeG1))
+	for i := range lagrangeG1 {
+		if lagrangeG1[i].IsInfinity() {
+			return fmt.Errorf("%w: lagrange G1 point %d", ErrTrustedSetupPointAtInfinity, i)
+		}
+		compressed := lagrangeG1[i].Bytes()
+		if j, ok := seen[compressed]; ok {
+			return fmt.Errorf("%w: lagrange G1 points %d and %d", ErrTrustedSetupDuplicatePoint, j, i)
+		}
+		seen[compressed] = i
+	}
+
 	return nil
 }
 
-// parseTrustedSetup parses the trusted setup in `JSONTrustedSetup` format
+// CheckTrustedSetupStructure checks that the trusted setup is made of successive powers of the same secret τ.
+// This is intended for users loading a trusted setup from an untrusted source; see [NewContextFromJSONChecked].
+//
+// To be specific, in addition to the checks of [CheckTrustedSetupIsWellFormed], this checks that:
+//   - The monomial G1 points are [τ^i]₁ and the G2 points are [τ^i]₂ for the same τ and generators, which are not
+//     the point at infinity.
+//   - The lagrange G1 points are the lagrange form of the monomial G1 points.
+//
+// If the monomial G1 points are not part of the setup, they are computed from the lagrange G1 points.
+//
+// The checks are randomized so that each of them only needs a couple of pairings, and the lagrange G1 points are
+// checked against the monomial G1 points using two multi exponentiations, see [checkLagrangeMatchesMonomial].
+// An invalid setup passes the checks with negligible probability.
+func CheckTrustedSetupStructure(trustedSetup *JSONTrustedSetup) error {
+	return checkTrustedSetupStructure(trustedSetup, false, 0, rand.Reader)
+}
+
+// checkTrustedSetupStructure implements [CheckTrustedSetupStructure]. If exhaustive is true, the lagrange G1 points
+// are checked against the monomial G1 points by computing the monomial form of the lagrange G1 points with an FFT,
+// which is deterministic but takes several seconds for 4096 points.
+//
+// numGoRoutines bounds the number of go routines used to parse the points and by the multi exponentiations. Setting
+// it to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+func checkTrustedSetupStructure(trustedSetup *JSONTrustedSetup, exhaustive bool, numGoRoutines int, randReader io.Reader) error {
+	if len(trustedSetup.SetupG2) < 2 {
+		return kzg.ErrMinSRSSize
+	}
+	if len(trustedSetup.SetupG1Monomial) != 0 && len(trustedSetup.SetupG1Monomial) != len(trustedSetup.SetupG1Lagrange) {
+		return ErrInvalidMonomialSRSSize
+	}
+	// This performs the checks of [CheckTrustedSetupIsWellFormed]
+	_, monomialG1, lagrangeG1, g2, err := parseTrustedSetup(trustedSetup.flexible(), true, true, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	if err := checkSetupPointsNonDegenerate(lagrangeG1, g2); err != nil {
+		return err
+	}
+
+	domain := kzg.NewDomain(ScalarsPerBlob)
+	switch {
+	case monomialG1 == nil:
+		monomialG1 = domain.FftG1(lagrangeG1)
+	case exhaustive:
+		fromLagrange := domain.FftG1(lagrangeG1)
+		for i := range monomialG1 {
+			if !monomialG1[i].Equal(&fromLagrange[i]) {
+				return fmt.Errorf("%w: the lagrange G1 points do not match the monomial G1 point %d", ErrInvalidTrustedSetupStructure, i)
+			}
+		}
+	default:
+		if err := checkLagrangeMatchesMonomial(domain, monomialG1, lagrangeG1, numGoRoutines, randReader); err != nil {
+			return err
+		}
+	}
+
+	if monomialG1[0].IsInfinity() || monomialG1[1].IsInfinity() || g2[0].IsInfinity() {
+		return fmt.Errorf("%w: the generators or [τ]₁ are the point at infinity", ErrInvalidTrustedSetupStructure)
+	}
+
+	// Check that G1[i+1] = τ * G1[i] using e(Σ r^i G1[i+1], [1]₂) == e(Σ r^i G1[i], [τ]₂)
+	r, err := randomPowers(len(monomialG1)-1, randReader)
+	if err != nil {
+		return err
+	}
+	shiftedG1, err := multiexp.MultiExp(r, monomialG1[1:], numGoRoutines)
+	if err != nil {
+		return err
+	}
+	unshiftedG1, err := multiexp.MultiExp(r, monomialG1[:len(monomialG1)-1], numGoRoutines)
+	if err != nil {
+		return err
+	}
+	unshiftedG1.Neg(unshiftedG1)
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{*shiftedG1, *unshiftedG1}, []bls12381.G2Affine{g2[0], g2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the G1 points are not successive powers of [τ]₂", ErrInvalidTrustedSetupStructure)
+	}
+
+	// Check that G2[i+1] = τ * G2[i] using e([1]₁, Σ r^i G2[i+1]) == e([τ]₁, Σ r^i G2[i])
+	r, err = randomPowers(len(g2)-1, randReader)
+	if err != nil {
+		return err
+	}
+	shiftedG2, err := multiexp.MultiExpG2(r, g2[1:], numGoRoutines)
+	if err != nil {
+		return err
+	}
+	unshiftedG2, err := multiexp.MultiExpG2(r, g2[:len(g2)-1], numGoRoutines)
+	if err != nil {
+		return err
+	}
+	var negTauG1 bls12381.G1Affine
+	negTauG1.Neg(&monomialG1[1])
+	ok, err = bls12381.PairingCheck([]bls12381.G1Affine{monomialG1[0], negTauG1}, []bls12381.G2Affine{*shiftedG2, *unshiftedG2})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the G2 points are not successive powers of [τ]₁", ErrInvalidTrustedSetupStructure)
+	}
+
+	return nil
+}
+
+// setupMultiExpError translates an error from a multi exponentiation over the points of the trusted setup: a length
+// mismatch means that the monomial and lagrange G1 points differ in number.
+func setupMultiExpError(err error) error {
+	if errors.Is(err, multiexp.ErrLengthMismatch) {
+		return fmt.Errorf("%w: %v", ErrInvalidTrustedSetupSize, err)
+	}
+	return err
+}
+
+// checkLagrangeMatchesMonomial checks that the lagrange G1 points are the lagrange form of the monomial G1 points.
+// Both are in the order of the trusted setup.
+//
+// For a random polynomial p(X) with coefficients c_i, we check that Σ c_i G1[i] == Σ p(ω^j) L[j], as both sides
+// are equal to [p(τ)]₁ for a valid setup.
+func checkLagrangeMatchesMonomial(domain *kzg.Domain, monomialG1, lagrangeG1 []bls12381.G1Affine, numGoRoutines int, randReader io.Reader) error {
+	coeffs, err := randomPowers(len(monomialG1), randReader)
+	if err != nil {
+		return err
+	}
+	evaluations := domain.FftFr(coeffs)
+
+	fromMonomial, err := multiexp.MultiExp(coeffs, monomialG1, numGoRoutines)
+	if err != nil {
+		return setupMultiExpError(err)
+	}
+	fromLagrange, err := multiexp.MultiExp(evaluations, lagrangeG1, numGoRoutines)
+	if err != nil {
+		return setupMultiExpError(err)
+	}
+	if !fromMonomial.Equal(fromLagrange) {
+		return fmt.Errorf("%w: the lagrange G1 points do not match the monomial G1 points", ErrInvalidTrustedSetupStructure)
+	}
+
+	return nil
+}
+
+// randomPowers returns the first n powers of a scalar sampled from `randReader`, starting at 1.
+func randomPowers(n int, randReader io.Reader) ([]fr.Element, error) {
+	r, err := kzg.RandomScalar(randReader)
+	if err != nil {
+		return nil, err
+	}
+
+	powers := make([]fr.Element, n)
+	powers[0].SetOne()
+	for i := 1; i < n; i++ {
+		powers[i].Mul(&powers[i-1], &r)
+	}
+	return powers, nil
+}
+
+// parseTrustedSetup parses the trusted setup in `JSONTrustedSetupFlexible` format
 // which contains hex encoded strings to corresponding group elements.
-// Elements are assumed to be well-formed.
 //
-// This method wil panic if the points have not been serialized correctly.
-func parseTrustedSetup(trustedSetup *JSONTrustedSetup) (bls12381.G1Affine, []bls12381.G1Affine, []bls12381.G2Affine) {
+// If subgroupChecks is false, the elements are assumed to be in the correct subgroup,
+// which should only be done for trusted inputs such as the embedded trusted setup.
+//
+// An error naming the offending point is returned if a point has not been serialized correctly.
+//
+// The monomial G1 points are only parsed if parseMonomial is true, otherwise
+// the returned slice is nil. The points are parsed using numGoRoutines go routines,
+// see [parseG1Points].
+func parseTrustedSetup(trustedSetup *JSONTrustedSetupFlexible, parseMonomial, subgroupChecks bool, numGoRoutines int) (bls12381.G1Affine, []bls12381.G1Affine, []bls12381.G1Affine, []bls12381.G2Affine, error) {
 	// The G1 generator is the first element of the monomial G1 points.
-	// We do not have that and so we use the fact that the setup started at
+	// If we do not have those, we use the fact that the setup started at
 	// the canonical generator point.
 	_, _, genG1, _ := bls12381.Generators()
 
-	setupLagrangeG1Points := parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1Lagrange[:])
-	g2Points := parseG2PointsNoSubgroupCheck(trustedSetup.SetupG2)
-	return genG1, setupLagrangeG1Points, g2Points
+	var setupMonomialG1Points []bls12381.G1Affine
+	if parseMonomial && len(trustedSetup.SetupG1Monomial) > 0 {
+		var err error
+		setupMonomialG1Points, err = parseG1Points(trustedSetup.SetupG1Monomial, subgroupChecks, numGoRoutines)
+		if err != nil {
+			return bls12381.G1Affine{}, nil, nil, nil, fmt.Errorf("%w: monomial G1 %v", ErrInvalidTrustedSetupPoint, err)
+		}
+		genG1 = setupMonomialG1Points[0]
+	}
+
+	setupLagrangeG1Points, err := parseG1Points(trustedSetup.SetupG1Lagrange, subgroupChecks, numGoRoutines)
+	if err != nil {
+		return bls12381.G1Affine{}, nil, nil, nil, fmt.Errorf("%w: lagrange G1 %v", ErrInvalidTrustedSetupPoint, err)
+	}
+	g2Points, err := parseG2Points(trustedSetup.SetupG2, subgroupChecks, numGoRoutines)
+	if err != nil {
+		return bls12381.G1Affine{}, nil, nil, nil, fmt.Errorf("%w: G2 %v", ErrInvalidTrustedSetupPoint, err)
+	}
+	return genG1, setupMonomialG1Points, setupLagrangeG1Points, g2Points, nil
 }
 
-// parseG1PointNoSubgroupCheck parses a hex-string (with the 0x prefix) into a G1 point.
+// parseG1Point parses a hex-string (with the 0x prefix) into a G1 point.
 //
-// This function performs no (expensive) subgroup checks, and should only be used
-// for trusted inputs.
-func parseG1PointNoSubgroupCheck(hexString string) (bls12381.G1Affine, error) {
-	byts, err := hex.DecodeString(trim0xPrefix(hexString))
+// If subgroupCheck is false, this function performs no (expensive) subgroup checks,
+// and should only be used for trusted inputs.
+func parseG1Point(hexString G1Hex, subgroupCheck bool) (bls12381.G1Affine, error) {
+	byts, err := hexString.Bytes()
 	if err != nil {
 		return bls12381.G1Affine{}, err
 	}
 
 	var point bls12381.G1Affine
-	noSubgroupCheck := bls12381.NoSubgroupChecks()
-	d := bls12381.NewDecoder(bytes.NewReader(byts), noSubgroupCheck)
+	d := bls12381.NewDecoder(bytes.NewReader(byts), decoderOptions(subgroupCheck)...)
 
 	return point, d.Decode(&point)
 }
 
-// parseG2PointNoSubgroupCheck parses a hex-string (with the 0x prefix) into a G2 point.
+// parseG2Point parses a hex-string (with the 0x prefix) into a G2 point.
 //
-// This function performs no (expensive) subgroup checks, and should only be used
-// for trusted inputs.
-func parseG2PointNoSubgroupCheck(hexString string) (bls12381.G2Affine, error) {
-	byts, err := hex.DecodeString(trim0xPrefix(hexString))
+// If subgroupCheck is false, this function performs no (expensive) subgroup checks,
+// and should only be used for trusted inputs.
+func parseG2Point(hexString G2Hex, subgroupCheck bool) (bls12381.G2Affine, error) {
+	byts, err := hexString.Bytes()
 	if err != nil {
 		return bls12381.G2Affine{}, err
 	}
 
 	var point bls12381.G2Affine
-	noSubgroupCheck := bls12381.NoSubgroupChecks()
-	d := bls12381.NewDecoder(bytes.NewReader(byts), noSubgroupCheck)
+	d := bls12381.NewDecoder(bytes.NewReader(byts), decoderOptions(subgroupCheck)...)
 
 	return point, d.Decode(&point)
 }
 
-// parseG1PointsNoSubgroupCheck parses a slice hex-string (with the 0x prefix) into a
+// decoderOptions returns the options for a gnark-crypto decoder which only performs
+// subgroup checks if subgroupCheck is true.
+func decoderOptions(subgroupCheck bool) []func(*bls12381.Decoder) {
+	if subgroupCheck {
+		return nil
+	}
+	return []func(*bls12381.Decoder){bls12381.NoSubgroupChecks()}
+}
+
+// parseG1Points parses a slice hex-string (with the 0x prefix) into a
 // slice of G1 points.
 //
-// This is essentially a parallelized version of calling [parseG1PointNoSubgroupCheck]
-// on each element of the slice individually.
-//
-// This function performs no (expensive) subgroup checks, and should only be used
-// for trusted inputs.
-func parseG1PointsNoSubgroupCheck(hexStrings []string) []bls12381.G1Affine {
-	numG1 := len(hexStrings)
-	g1Points := make([]bls12381.G1Affine, numG1)
-
-	var wg sync.WaitGroup
-	wg.Add(numG1)
-	for i := 0; i < numG1; i++ {
-		go func(j int) {
-			g1Point, err := parseG1PointNoSubgroupCheck(hexStrings[j])
-			if err != nil {
-				panic(err)
-			}
-			g1Points[j] = g1Point
-			wg.Done()
-		}(i)
+// This is essentially a parallelized version of calling [parseG1Point]
+// on each element of the slice individually, using a pool of numGoRoutines go routines.
+// Setting numGoRoutines to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+// If any of the points fails to parse, the remaining go routines stop early and an error with
+// the index of that point is returned.
+//
+// If subgroupCheck is false, this function performs no (expensive) subgroup checks,
+// and should only be used for trusted inputs.
+func parseG1Points(hexStrings []G1Hex, subgroupCheck bool, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	g1Points := make([]bls12381.G1Affine, len(hexStrings))
+
+	err := utils.ParallelFor(len(hexStrings), numGoRoutines, func(i int) error {
+		g1Point, err := parseG1Point(hexStrings[i], subgroupCheck)
+		if err != nil {
+			return fmt.Errorf("point %d: %w", i, err)
+		}
+		g1Points[i] = g1Point
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	wg.Wait()
 
-	return g1Points
+	return g1Points, nil
 }
 
-// parseG2PointsNoSubgroupCheck parses a slice hex-string (with the 0x prefix) into a
+// parseG2Points parses a slice hex-string (with the 0x prefix) into a
 // slice of G2 points.
 //
-// This is essentially a parallelized version of calling [parseG2PointNoSubgroupCheck]
-// on each element of the slice individually.
-//
-// This function performs no (expensive) subgroup checks, and should only be used
-// for trusted inputs.
-func parseG2PointsNoSubgroupCheck(hexStrings []string) []bls12381.G2Affine {
-	numG2 := len(hexStrings)
-	g2Points := make([]bls12381.G2Affine, numG2)
-
-	var wg sync.WaitGroup
-	wg.Add(numG2)
-	for i := 0; i < numG2; i++ {
-		go func(_i int) {
-			g2Point, err := parseG2PointNoSubgroupCheck(hexStrings[_i])
-			if err != nil {
-				panic(err)
-			}
-			g2Points[_i] = g2Point
-			wg.Done()
-		}(i)
+// This is essentially a parallelized version of calling [parseG2Point]
+// on each element of the slice individually, using a pool of numGoRoutines go routines.
+// Setting numGoRoutines to a negative number or 0 will make it default to runtime.GOMAXPROCS(0).
+// If any of the points fails to parse, the remaining go routines stop early and an error with
+// the index of that point is returned.
+//
+// If subgroupCheck is false, this function performs no (expensive) subgroup checks,
+// and should only be used for trusted inputs.
+func parseG2Points(hexStrings []G2Hex, subgroupCheck bool, numGoRoutines int) ([]bls12381.G2Affine, error) {
+	g2Points := make([]bls12381.G2Affine, len(hexStrings))
+
+	err := utils.ParallelFor(len(hexStrings), numGoRoutines, func(i int) error {
+		g2Point, err := parseG2Point(hexStrings[i], subgroupCheck)
+		if err != nil {
+			return fmt.Errorf("point %d: %w", i, err)
+		}
+		g2Points[i] = g2Point
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	wg.Wait()
 
-	return g2Points
+	return g2Points, nil
 }
 
-// trim0xPrefix removes the "0x" from a hex-string.
-func trim0xPrefix(hexString string) string {
-	// Check that we are trimming off 0x
-	if hexString[0:2] != "0x" {
-		panic("hex string is not prefixed with 0x")
+// decodeHexString decodes a hex-string with the 0x prefix.
+func decodeHexString(hexString string) ([]byte, error) {
+	if !strings.HasPrefix(hexString, "0x") {
+		return nil, errors.New("hex string is not prefixed with 0x")
 	}
-	return hexString[2:]
+	return hex.DecodeString(hexString[2:])
 }
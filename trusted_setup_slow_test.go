@@ -0,0 +1,22 @@
+//go:build slow
+
+package gokzg4844
+
+// The tests in this file check the whole embedded mainnet trusted setup, parsing and checking every point. They are
+// opt-in:
+//
+//	make test-slow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformTrustedSetup(t *testing.T) {
+	parsedSetup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
+	err = CheckTrustedSetupIsWellFormed(parsedSetup)
+	require.NoError(t, err)
+}
@@ -1,17 +1,704 @@
 package gokzg4844
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/stretchr/testify/require"
 )
 
-func TestTransformTrustedSetup(t *testing.T) {
-	parsedSetup := JSONTrustedSetup{}
+// mainnetTrustedSetupJSON returns the embedded trusted setup in the JSON format of [JSONTrustedSetup].
+func mainnetTrustedSetupJSON(t *testing.T) string {
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
+	encoded, err := json.Marshal(setup)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestCheckTrustedSetupIsWellFormedDegenerate(t *testing.T) {
+	var infinity bls12381.G1Affine
+	infinityBytes := infinity.Bytes()
+
+	tests := []struct {
+		name     string
+		modify   func(setup *JSONTrustedSetup)
+		expected error
+	}{
+		{"G2 generator", func(setup *JSONTrustedSetup) {
+			setup.SetupG2[0] = setup.SetupG2[1]
+		}, ErrTrustedSetupG2Generator},
+		{"lagrange G1 point at infinity", func(setup *JSONTrustedSetup) {
+			setup.SetupG1Lagrange[7] = G1Hex("0x" + hex.EncodeToString(infinityBytes[:]))
+		}, ErrTrustedSetupPointAtInfinity},
+		{"duplicate lagrange G1 points", func(setup *JSONTrustedSetup) {
+			setup.SetupG1Lagrange[4000] = setup.SetupG1Lagrange[12]
+		}, ErrTrustedSetupDuplicatePoint},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modified, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+			require.NoError(t, err)
+			test.modify(modified)
+			require.ErrorIs(t, CheckTrustedSetupIsWellFormed(modified), test.expected)
+			require.ErrorIs(t, CheckTrustedSetupStructure(modified), test.expected)
+		})
+	}
+}
+
+func TestNewContext4096Insecure1337(t *testing.T) {
+	ctxInsecure, err := NewContext4096Insecure1337()
+	require.NoError(t, err)
+	ctx, err := NewContext4096Secure()
+	require.NoError(t, err)
+
+	blob := &Blob{}
+	for i := 0; i < ScalarsPerBlob; i += 5 {
+		blob[i*SerializedScalarSize+31] = byte(i)
+	}
+	commitment, err := ctxInsecure.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	proof, err := ctxInsecure.ComputeBlobKZGProof(blob, commitment, 0)
+	require.NoError(t, err)
+	require.NoError(t, ctxInsecure.VerifyBlobKZGProof(blob, commitment, proof))
+
+	// The lagrange and monomial points must describe the same setup
+	monomialCommitment, err := ctxInsecure.CommitToMonomialPolynomial([]fr.Element{fr.One()})
+	require.NoError(t, err)
+	var ones Blob
+	for i := 0; i < ScalarsPerBlob; i++ {
+		ones[i*SerializedScalarSize+31] = 1
+	}
+	lagrangeCommitment, err := ctxInsecure.BlobToKZGCommitment(&ones, 0)
+	require.NoError(t, err)
+	require.Equal(t, lagrangeCommitment, monomialCommitment)
+
+	// The setups are different, so the proof does not verify against the mainnet setup
+	secureCommitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	require.NotEqual(t, secureCommitment, commitment)
+	require.Error(t, ctx.VerifyBlobKZGProof(blob, commitment, proof))
+}
+
+func TestNewInsecureContextWithSecret(t *testing.T) {
+	var tau fr.Element
+	tau.SetUint64(1337)
+	ctx, err := NewInsecureContextWithSecret(tau, ScalarsPerBlob)
+	require.NoError(t, err)
+	ctx1337, err := NewContext4096Insecure1337()
+	require.NoError(t, err)
+
+	// Both contexts must hold exactly the same trusted setup
+	var cache, cache1337 bytes.Buffer
+	require.NoError(t, ctx.SaveSetupCache(&cache))
+	require.NoError(t, ctx1337.SaveSetupCache(&cache1337))
+	require.Equal(t, cache1337.Bytes(), cache.Bytes())
+
+	// Since the secret is known, commitments can be checked against the evaluation of the polynomial at the secret,
+	// including when the secret is one of the roots of unity of the domain.
+	const size = 16
+	var randomTau fr.Element
+	_, err = randomTau.SetRandom()
+	require.NoError(t, err)
+	for _, tau := range []fr.Element{randomTau, kzg.NewDomain(size).Roots[3]} {
+		ctx, err := NewInsecureContextWithSecret(tau, size)
+		require.NoError(t, err)
+
+		evaluations := make([]fr.Element, size)
+		for i := range evaluations {
+			_, err := evaluations[i].SetRandom()
+			require.NoError(t, err)
+		}
+		commitment, err := ctx.CommitToPolynomial(evaluations, 0)
+		require.NoError(t, err)
+
+		evaluation, err := ctx.domain.EvaluateLagrangePolynomial(evaluations, tau)
+		require.NoError(t, err)
+		var expected bls12381.G1Affine
+		expected.ScalarMultiplication(&ctx.openKey.GenG1, evaluation.BigInt(new(big.Int)))
+		require.Equal(t, KZGCommitment(SerializeG1Point(expected)), commitment)
+	}
+
+	for _, size := range []uint64{0, 1, 3, 4095, kzg.MaxDomainSize * 2} {
+		_, err := NewInsecureContextWithSecret(tau, size)
+		require.ErrorIs(t, err, ErrInvalidContextSize)
+	}
+}
+
+func TestNewContext4096FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trusted_setup.json")
+	require.NoError(t, os.WriteFile(path, []byte(mainnetTrustedSetupJSON(t)), 0o600))
+
+	ctxFromFile, err := NewContext4096FromFile(path)
+	require.NoError(t, err)
+	ctx, err := NewContext4096Secure()
+	require.NoError(t, err)
+
+	blob := &Blob{}
+	blob[31] = 1
+	expected, err := ctx.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	got, err := ctxFromFile.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	_, err = NewContext4096FromFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewContext4096FromReaderInvalidSetup(t *testing.T) {
+	setupJSON := mainnetTrustedSetupJSON(t)
+	var setup jsonTrustedSetupSlices
+	require.NoError(t, json.Unmarshal([]byte(setupJSON), &setup))
+
+	// encode returns the JSON for the setup after applying `modify` to a copy of it
+	encode := func(modify func(*jsonTrustedSetupSlices)) string {
+		modified := setup
+		modified.SetupG1Lagrange = append([]string{}, setup.SetupG1Lagrange...)
+		modified.SetupG2 = append([]string{}, setup.SetupG2...)
+		modify(&modified)
+		encoded, err := json.Marshal(modified)
+		require.NoError(t, err)
+		return string(encoded)
+	}
+
+	tests := []struct {
+		name     string
+		json     string
+		expected error
+	}{
+		{"truncated", setupJSON[:len(setupJSON)/2], ErrInvalidTrustedSetupJSON},
+		{"trailing data", setupJSON + "{}", ErrInvalidTrustedSetupJSON},
+		{"not an object", "[" + setupJSON + "]", ErrInvalidTrustedSetupJSON},
+		{"points which are not strings", `{"g1_lagrange": [1, 2], "g2_monomial": []}`, ErrInvalidTrustedSetupJSON},
+		{"points which are not in an array", `{"g1_lagrange": "0x00"}`, ErrInvalidTrustedSetupJSON},
+		{"null", "null", ErrInvalidTrustedSetupSize},
+		{"4095 lagrange points", encode(func(s *jsonTrustedSetupSlices) {
+			s.SetupG1Lagrange = s.SetupG1Lagrange[:ScalarsPerBlob-1]
+		}), ErrInvalidTrustedSetupSize},
+		{"4097 lagrange points", encode(func(s *jsonTrustedSetupSlices) {
+			s.SetupG1Lagrange = append(s.SetupG1Lagrange, s.SetupG1Lagrange[0])
+		}), ErrInvalidTrustedSetupSize},
+		{"4095 monomial points", encode(func(s *jsonTrustedSetupSlices) {
+			s.SetupG1Monomial = s.SetupG1Monomial[:ScalarsPerBlob-1]
+		}), ErrInvalidTrustedSetupSize},
+		{"one G2 point", encode(func(s *jsonTrustedSetupSlices) {
+			s.SetupG2 = s.SetupG2[:1]
+		}), ErrInvalidTrustedSetupSize},
+		{"invalid 0x prefix", encode(func(s *jsonTrustedSetupSlices) {
+			s.SetupG1Lagrange[7] = "1x" + s.SetupG1Lagrange[7][2:]
+		}), ErrInvalidTrustedSetupPoint},
+		{"invalid hex", encode(func(s *jsonTrustedSetupSlices) {
+			s.SetupG2[1] = s.SetupG2[1][:10] + "zz" + s.SetupG2[1][12:]
+		}), ErrInvalidTrustedSetupPoint},
+		{"G1 point with the size of a G2 point", encode(func(s *jsonTrustedSetupSlices) {
+			s.SetupG1Lagrange[0] = s.SetupG2[0]
+		}), ErrInvalidTrustedSetupPoint},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewContext4096FromReader(strings.NewReader(test.json))
+			require.ErrorIs(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecodeTrustedSetupStreaming(t *testing.T) {
+	setupJSON := mainnetTrustedSetupJSON(t)
+	expected, err := decodeTrustedSetup(strings.NewReader(setupJSON))
+	require.NoError(t, err)
+
+	// The keys are matched case-insensitively and the unknown keys are skipped, as with encoding/json
+	modifiedJSON := `{"comment": {"g1_lagrange": []}, "G1_LAGRANGE": null, ` + setupJSON[1:]
+	modifiedJSON = strings.Replace(modifiedJSON, `"g2_monomial"`, `"G2_Monomial"`, 1)
+	decoded, err := decodeTrustedSetup(strings.NewReader(modifiedJSON))
+	require.NoError(t, err)
+	require.Equal(t, expected, decoded)
+
+	// The document is not read in full before being decoded, so the memory allocated is about the size of the
+	// hex-strings of the points, which make up most of the document
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err = decodeTrustedSetup(strings.NewReader(setupJSON))
+	runtime.ReadMemStats(&after)
+	require.NoError(t, err)
+	allocated := after.TotalAlloc - before.TotalAlloc
+	require.Less(t, allocated, uint64(len(setupJSON))*5/2, "%d bytes allocated to decode %d bytes", allocated, len(setupJSON))
+}
+
+func TestNewContext4096CorruptedPoint(t *testing.T) {
+	// Valid hex strings with the compression flag set, whose x coordinate is larger than the field modulus.
+	corruptedG1 := G1Hex("0x9f" + strings.Repeat("ff", 47))
+	corruptedG2 := G2Hex("0x9f" + strings.Repeat("ff", 95))
+
+	tests := []struct {
+		name     string
+		corrupt  func(setup *JSONTrustedSetup)
+		expected string
+	}{
+		{"lagrange G1", func(setup *JSONTrustedSetup) { setup.SetupG1Lagrange[1234] = corruptedG1 }, "lagrange G1 point 1234"},
+		{"monomial G1", func(setup *JSONTrustedSetup) { setup.SetupG1Monomial[4095] = corruptedG1 }, "monomial G1 point 4095"},
+		{"G2", func(setup *JSONTrustedSetup) { setup.SetupG2[42] = corruptedG2 }, "G2 point 42"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+			require.NoError(t, err)
+			test.corrupt(setup)
+
+			require.NotPanics(t, func() {
+				_, err := NewContext4096(setup)
+				require.ErrorIs(t, err, ErrInvalidTrustedSetupPoint)
+				require.ErrorContains(t, err, test.expected)
+			})
+		})
+	}
+}
+
+func TestNewContext4096SubgroupChecks(t *testing.T) {
+	// Find a point on the G2 curve y^2 = x^3 + 4(1+u) which is not in the prime order subgroup.
+	// Since the cofactor of G2 is large, this is the case for almost all points on the curve.
+	var notInSubgroup bls12381.G2Affine
+	for i := uint64(1); ; i++ {
+		notInSubgroup.X.SetZero()
+		notInSubgroup.X.A0.SetUint64(i)
+
+		var b, rhs = notInSubgroup.X, notInSubgroup.X
+		b.A0.SetUint64(4)
+		b.A1.SetUint64(4)
+		rhs.Square(&rhs).Mul(&rhs, &notInSubgroup.X).Add(&rhs, &b)
+		if rhs.Legendre() == 1 {
+			notInSubgroup.Y.Sqrt(&rhs)
+			break
+		}
+	}
+	require.True(t, notInSubgroup.IsOnCurve())
+	require.False(t, notInSubgroup.IsInSubGroup())
+
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
+	pointBytes := notInSubgroup.Bytes()
+	setup.SetupG2[3] = G2Hex(add0xPrefix(hex.EncodeToString(pointBytes[:])))
+
+	_, err = NewContext4096(setup)
+	require.NoError(t, err)
+
+	_, err = NewContext4096(setup, WithSubgroupChecks(true))
+	require.ErrorIs(t, err, ErrInvalidTrustedSetupPoint)
+	require.ErrorContains(t, err, "G2 point 3")
+
+	setupJSON, err := json.Marshal(setup)
+	require.NoError(t, err)
+
+	_, err = NewContext4096FromReader(bytes.NewReader(setupJSON))
+	require.ErrorIs(t, err, ErrInvalidTrustedSetupPoint)
+
+	_, err = NewContext4096FromReader(bytes.NewReader(setupJSON), WithSubgroupChecks(false))
+	require.NoError(t, err)
+}
+
+// The embedded trusted setup is a copy of the file distributed with c-kzg-4844 v2, which includes the monomial points.
+// The older version of the file is the same, without the monomial points at the end.
+func readTrustedSetupText(t *testing.T, withMonomial bool) string {
+	t.Helper()
+	if withMonomial {
+		return mainnetTrustedSetupText
+	}
+	lines := strings.SplitAfter(mainnetTrustedSetupText, "\n")
+	return strings.Join(lines[:2+ScalarsPerBlob+65], "")
+}
+
+func TestParseTrustedSetupTextGolden(t *testing.T) {
+	_, _, genG1, genG2 := bls12381.Generators()
+	genG1Bytes := genG1.Bytes()
+	genG2Bytes := genG2.Bytes()
+
+	setup, err := ParseTrustedSetupText(strings.NewReader(readTrustedSetupText(t, true)))
+	require.NoError(t, err)
+	require.Equal(t, G1Hex("0x"+hex.EncodeToString(genG1Bytes[:])), setup.SetupG1Monomial[0])
+	require.Equal(t, G2Hex("0x"+hex.EncodeToString(genG2Bytes[:])), setup.SetupG2[0])
+	require.Equal(t, G1Hex("0xa0413c0dcafec6dbc9f47d66785cf1e8c981044f7d13cfe3e4fcbb71b5408dfde6312493cb3c1d30516cb3ca88c03654"), setup.SetupG1Lagrange[0])
+
+	setupNoMonomial, err := ParseTrustedSetupText(strings.NewReader(readTrustedSetupText(t, false)))
+	require.NoError(t, err)
+	require.Equal(t, setup.SetupG1Lagrange, setupNoMonomial.SetupG1Lagrange)
+	require.Empty(t, setupNoMonomial.SetupG1Monomial)
+	require.Equal(t, setup.SetupG2, setupNoMonomial.SetupG2)
+}
+
+func TestNewContext4096FromTextReader(t *testing.T) {
+	ctx, err := NewContext4096Secure()
+	require.NoError(t, err)
+
+	blob := &Blob{}
+	for i := 0; i < ScalarsPerBlob; i += 3 {
+		blob[i*SerializedScalarSize+31] = byte(i)
+	}
+	expected, err := ctx.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+
+	for _, withMonomial := range []bool{true, false} {
+		ctxFromText, err := NewContext4096FromTextReader(strings.NewReader(readTrustedSetupText(t, withMonomial)))
+		require.NoError(t, err)
+
+		got, err := ctxFromText.BlobToKZGCommitment(blob, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+
+		_, err = ctxFromText.CommitToMonomialPolynomial([]fr.Element{fr.One()})
+		if withMonomial {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, ErrMonomialSRSUnavailable)
+		}
+	}
+}
+
+func TestNewContextFromLagrangeSetup(t *testing.T) {
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
 
-	err := json.Unmarshal([]byte(testKzgSetupStr), &parsedSetup)
+	ctx, err := NewContext4096(setup)
 	require.NoError(t, err)
-	err = CheckTrustedSetupIsWellFormed(&parsedSetup)
+	ctxLagrange, err := NewContextFromLagrangeSetup(setup.SetupG1Lagrange[:], setup.SetupG2[:2])
 	require.NoError(t, err)
+	require.Equal(t, ctx.openKey.GenG1, ctxLagrange.openKey.GenG1)
+
+	blob := &Blob{}
+	for i := 0; i < ScalarsPerBlob; i++ {
+		blob[i*SerializedScalarSize+31] = byte(i)
+	}
+	var inputPoint Scalar
+	inputPoint[31] = 42
+
+	commitment, err := ctx.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	gotCommitment, err := ctxLagrange.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	require.Equal(t, commitment, gotCommitment)
+
+	proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, 0)
+	require.NoError(t, err)
+	gotProof, gotClaimedValue, err := ctxLagrange.ComputeKZGProof(blob, inputPoint, 0)
+	require.NoError(t, err)
+	require.Equal(t, proof, gotProof)
+	require.Equal(t, claimedValue, gotClaimedValue)
+	require.NoError(t, ctxLagrange.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, 0)
+	require.NoError(t, err)
+	gotBlobProof, err := ctxLagrange.ComputeBlobKZGProof(blob, commitment, 0)
+	require.NoError(t, err)
+	require.Equal(t, blobProof, gotBlobProof)
+	require.NoError(t, ctxLagrange.VerifyBlobKZGProof(blob, commitment, blobProof))
+
+	_, err = ctxLagrange.CommitToMonomialPolynomial([]fr.Element{fr.One()})
+	require.ErrorIs(t, err, ErrMonomialSRSUnavailable)
+	_, err = ctxLagrange.ComputeDegreeBoundProof(blob, ScalarsPerBlob, 0)
+	require.ErrorIs(t, err, ErrMonomialSRSUnavailable)
+
+	_, err = NewContextFromLagrangeSetup(setup.SetupG1Lagrange[:100], setup.SetupG2)
+	require.ErrorIs(t, err, ErrInvalidContextSize)
+}
+
+func TestParseTrustedSetupTextInvalid(t *testing.T) {
+	text := readTrustedSetupText(t, false)
+	lines := strings.SplitAfter(text, "\n")
+
+	tests := []struct {
+		name     string
+		text     string
+		expected error
+	}{
+		{"empty", "", ErrInvalidTrustedSetupText},
+		{"invalid count", "4096\nabc\n", ErrInvalidTrustedSetupText},
+		{"4095 G1 points", "4095\n65\n" + strings.Join(lines[3:], ""), ErrInvalidTrustedSetupSize},
+		{"missing point", strings.Join(lines[:len(lines)-2], ""), ErrInvalidTrustedSetupSize},
+		{"too many G2 points", "4096\n64\n" + strings.Join(lines[2:], ""), ErrInvalidTrustedSetupSize},
+		{"invalid hex", strings.Replace(text, "a0413c0d", "a0413c0z", 1), ErrInvalidTrustedSetupPoint},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseTrustedSetupText(strings.NewReader(test.text))
+			require.ErrorIs(t, err, test.expected)
+		})
+	}
+}
+
+func TestCheckTrustedSetupStructure(t *testing.T) {
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
+	require.NoError(t, CheckTrustedSetupStructure(setup))
+
+	insecureSetup := insecureTrustedSetup(42, ScalarsPerBlob)
+	tests := []struct {
+		name   string
+		modify func(setup *JSONTrustedSetup)
+	}{
+		{"swapped monomial G1 points", func(setup *JSONTrustedSetup) {
+			setup.SetupG1Monomial[10], setup.SetupG1Monomial[11] = setup.SetupG1Monomial[11], setup.SetupG1Monomial[10]
+		}},
+		{"swapped lagrange G1 points", func(setup *JSONTrustedSetup) {
+			setup.SetupG1Lagrange[0], setup.SetupG1Lagrange[1] = setup.SetupG1Lagrange[1], setup.SetupG1Lagrange[0]
+		}},
+		{"swapped lagrange G1 points without monomial points", func(setup *JSONTrustedSetup) {
+			setup.SetupG1Monomial = nil
+			setup.SetupG1Lagrange[0], setup.SetupG1Lagrange[1] = setup.SetupG1Lagrange[1], setup.SetupG1Lagrange[0]
+		}},
+		{"swapped G2 points", func(setup *JSONTrustedSetup) {
+			setup.SetupG2[5], setup.SetupG2[6] = setup.SetupG2[6], setup.SetupG2[5]
+		}},
+		{"G2 points for a different secret", func(setup *JSONTrustedSetup) {
+			setup.SetupG2 = insecureSetup.SetupG2
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modified, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+			require.NoError(t, err)
+			test.modify(modified)
+			require.ErrorIs(t, CheckTrustedSetupStructure(modified), ErrInvalidTrustedSetupStructure)
+		})
+	}
+}
+
+func TestCheckTrustedSetupStructureExhaustive(t *testing.T) {
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
+	require.NoError(t, checkTrustedSetupStructure(setup, true, 0, rand.Reader))
+
+	// Swapping a single lagrange point with its neighbour must be caught by both checks.
+	setup.SetupG1Lagrange[1000], setup.SetupG1Lagrange[1001] = setup.SetupG1Lagrange[1001], setup.SetupG1Lagrange[1000]
+	require.ErrorIs(t, checkTrustedSetupStructure(setup, false, 0, rand.Reader), ErrInvalidTrustedSetupStructure)
+	require.ErrorIs(t, checkTrustedSetupStructure(setup, true, 0, rand.Reader), ErrInvalidTrustedSetupStructure)
+}
+
+func TestCheckLagrangeMatchesMonomialSize(t *testing.T) {
+	domain := kzg.NewDomain(4)
+	_, _, genG1, _ := bls12381.Generators()
+	points := []bls12381.G1Affine{genG1, genG1, genG1, genG1}
+
+	err := checkLagrangeMatchesMonomial(domain, points, points[:3], 0, rand.Reader)
+	require.ErrorIs(t, err, ErrInvalidTrustedSetupSize)
+	require.Contains(t, err.Error(), "got 4 scalars for 3 points")
+}
+
+func TestNewContextFromJSONChecked(t *testing.T) {
+	setupJSON := mainnetTrustedSetupJSON(t)
+
+	ctxChecked, err := NewContextFromJSONChecked(strings.NewReader(setupJSON))
+	require.NoError(t, err)
+	ctx, err := NewContext4096Secure()
+	require.NoError(t, err)
+
+	blob := &Blob{}
+	blob[31] = 1
+	expected, err := ctx.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	got, err := ctxChecked.BlobToKZGCommitment(blob, 0)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	var setup JSONTrustedSetup
+	require.NoError(t, json.Unmarshal([]byte(setupJSON), &setup))
+	setup.SetupG1Monomial[1], setup.SetupG1Monomial[2] = setup.SetupG1Monomial[2], setup.SetupG1Monomial[1]
+	modifiedJSON, err := json.Marshal(&setup)
+	require.NoError(t, err)
+	_, err = NewContextFromJSONChecked(bytes.NewReader(modifiedJSON))
+	require.ErrorIs(t, err, ErrInvalidTrustedSetupStructure)
+}
+
+func TestHexPointJSON(t *testing.T) {
+	g1 := strings.Repeat("ab", bls12381.SizeOfG1AffineCompressed)
+	g2 := strings.Repeat("cd", bls12381.SizeOfG2AffineCompressed)
+
+	tests := []struct {
+		name  string
+		json  string
+		valid bool
+	}{
+		{"prefixed", `"0x` + g1 + `"`, true},
+		{"not prefixed", `"` + g1 + `"`, true},
+		{"too short", `"0x` + g1[2:] + `"`, false},
+		{"too long", `"0x` + g1 + `00"`, false},
+		{"G2 length", `"0x` + g2 + `"`, false},
+		{"not hex", `"0x` + g1[:94] + `zz"`, false},
+		{"not a string", `42`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var point G1Hex
+			err := json.Unmarshal([]byte(test.json), &point)
+			if !test.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, G1Hex("0x"+g1), point)
+		})
+	}
+
+	var point G2Hex
+	require.NoError(t, json.Unmarshal([]byte(`"`+g2+`"`), &point))
+	require.Equal(t, G2Hex("0x"+g2), point)
+	require.ErrorIs(t, json.Unmarshal([]byte(`"0x`+g1+`"`), &point), ErrInvalidTrustedSetupPoint)
+
+	// Points are always encoded with the prefix
+	encoded, err := json.Marshal([]G1Hex{G1Hex(g1), G1Hex("0x" + g1)})
+	require.NoError(t, err)
+	require.Equal(t, `["0x`+g1+`","0x`+g1+`"]`, string(encoded))
+}
+
+func TestJSONTrustedSetupUnmarshalInvalidPoint(t *testing.T) {
+	var setup jsonTrustedSetupSlices
+	require.NoError(t, json.Unmarshal([]byte(mainnetTrustedSetupJSON(t)), &setup))
+
+	// A G2 point in the lagrange G1 points is rejected when decoding
+	setup.SetupG1Lagrange[17] = setup.SetupG2[0]
+	encoded, err := json.Marshal(setup)
+	require.NoError(t, err)
+
+	var trustedSetup JSONTrustedSetup
+	err = json.Unmarshal(encoded, &trustedSetup)
+	require.ErrorIs(t, err, ErrInvalidTrustedSetupPoint)
+	require.ErrorContains(t, err, "g1_lagrange[17]")
+
+	// The 0x prefix is optional
+	setup.SetupG1Lagrange[17] = setup.SetupG1Lagrange[16][2:]
+	setup.SetupG2[3] = setup.SetupG2[3][2:]
+	encoded, err = json.Marshal(setup)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &trustedSetup))
+	require.Equal(t, trustedSetup.SetupG1Lagrange[16], trustedSetup.SetupG1Lagrange[17])
+	require.Equal(t, G2Hex(setup.SetupG2[2][:2]+setup.SetupG2[3]), trustedSetup.SetupG2[3])
+}
+
+func TestNewContextFromSetup(t *testing.T) {
+	const size = 256
+	setup := insecureTrustedSetup(1337, size)
+
+	// The monomial points of a larger setup with the same secret are truncated
+	setup.SetupG1Monomial = insecureTrustedSetup(1337, 2*size).SetupG1Monomial
+
+	ctx, err := NewContextFromSetup(setup, size)
+	require.NoError(t, err)
+
+	// f(X) = 1 + 2X
+	coeffs := []fr.Element{fr.NewElement(1), fr.NewElement(2)}
+	evaluations := make([]fr.Element, size)
+	for i := range evaluations {
+		root, err := ctx.DomainByIndex(i)
+		require.NoError(t, err)
+		evaluations[i].Mul(root, &coeffs[1])
+		evaluations[i].Add(&evaluations[i], &coeffs[0])
+	}
+
+	commitment, err := ctx.CommitToPolynomial(evaluations, 0)
+	require.NoError(t, err)
+	monomialCommitment, err := ctx.CommitToMonomialPolynomial(coeffs)
+	require.NoError(t, err)
+	require.Equal(t, monomialCommitment, commitment)
+
+	// Open at a point outside of the domain and at a point in the domain
+	root, err := ctx.DomainByIndex(17)
+	require.NoError(t, err)
+	for _, inputPoint := range []fr.Element{fr.NewElement(12345), *root} {
+		inputPointBytes := SerializeScalar(inputPoint)
+		proof, claimedValueBytes, err := ctx.ComputePolynomialKZGProof(evaluations, inputPointBytes, 0)
+		require.NoError(t, err)
+
+		var expected fr.Element
+		expected.Mul(&inputPoint, &coeffs[1])
+		expected.Add(&expected, &coeffs[0])
+		require.Equal(t, SerializeScalar(expected), claimedValueBytes)
+		require.NoError(t, ctx.VerifyKZGProof(commitment, inputPointBytes, claimedValueBytes, proof))
+
+		expected.Add(&expected, &coeffs[0])
+		require.Error(t, ctx.VerifyKZGProof(commitment, inputPointBytes, SerializeScalar(expected), proof))
+	}
+
+	_, err = ctx.CommitToPolynomial(evaluations[:size/2], 0)
+	require.ErrorIs(t, err, ErrContextSizeMismatch)
+	_, _, err = ctx.ComputePolynomialKZGProof(append(evaluations, fr.One()), Scalar{}, 0)
+	require.ErrorIs(t, err, ErrContextSizeMismatch)
+
+	// The methods taking a blob need a context with ScalarsPerBlob evaluations
+	_, err = ctx.BlobToKZGCommitment(&Blob{}, 0)
+	require.ErrorIs(t, err, ErrContextSizeMismatch)
+	require.ErrorIs(t, ctx.VerifyBlobKZGProof(&Blob{}, commitment, KZGProof{}), ErrContextSizeMismatch)
+
+	// The setup cache keeps the size of the context
+	var cache bytes.Buffer
+	require.NoError(t, ctx.SaveSetupCache(&cache))
+	ctxFromCache, err := NewContextFromSetupCache(&cache)
+	require.NoError(t, err)
+	got, err := ctxFromCache.CommitToPolynomial(evaluations, 0)
+	require.NoError(t, err)
+	require.Equal(t, commitment, got)
+}
+
+func TestNewContextFromSetupInvalid(t *testing.T) {
+	setup := insecureTrustedSetup(1337, 8)
+
+	for _, size := range []uint64{0, 1, 6} {
+		_, err := NewContextFromSetup(setup, size)
+		require.ErrorIs(t, err, ErrInvalidContextSize)
+	}
+
+	_, err := NewContextFromSetup(setup, 4)
+	require.ErrorIs(t, err, ErrInvalidTrustedSetupSize)
+	_, err = NewContextFromSetup(setup, 16)
+	require.ErrorIs(t, err, ErrInvalidTrustedSetupSize)
+
+	setup.SetupG1Monomial = setup.SetupG1Monomial[:4]
+	_, err = NewContextFromSetup(setup, 8)
+	require.ErrorIs(t, err, ErrInvalidMonomialSRSSize)
+}
+
+func TestParseTrustedSetupNumGoRoutines(t *testing.T) {
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(t, err)
+
+	genG1, monomialG1, lagrangeG1, g2, err := parseTrustedSetup(setup.flexible(), true, false, 1)
+	require.NoError(t, err)
+
+	for _, numGoRoutines := range []int{0, 3, 4, runtime.NumCPU(), 2 * ScalarsPerBlob} {
+		gotGenG1, gotMonomialG1, gotLagrangeG1, gotG2, err := parseTrustedSetup(setup.flexible(), true, false, numGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, genG1, gotGenG1)
+		require.Equal(t, monomialG1, gotMonomialG1)
+		require.Equal(t, lagrangeG1, gotLagrangeG1)
+		require.Equal(t, g2, gotG2)
+	}
+
+	ctx, err := NewContext4096(setup, WithNumGoRoutines(1))
+	require.NoError(t, err)
+	require.Equal(t, lagrangeG1[0], ctx.commitKey.G1[0])
+}
+
+func BenchmarkParseTrustedSetup(b *testing.B) {
+	setup, err := ParseTrustedSetupText(strings.NewReader(mainnetTrustedSetupText))
+	require.NoError(b, err)
+
+	for _, numGoRoutines := range []int{1, 4, runtime.NumCPU()} {
+		b.Run(fmt.Sprintf("numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _, _, _, err := parseTrustedSetup(setup.flexible(), true, false, numGoRoutines)
+				require.NoError(b, err)
+			}
+		})
+	}
 }
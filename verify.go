@@ -1,15 +1,25 @@
 package gokzg4844
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/RiemaLabs/go-kzg-4844/internal/kzg"
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
-	"golang.org/x/sync/errgroup"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
 // VerifyKZGProof implements [verify_kzg_proof].
 //
 // [verify_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof
 func (c *Context) VerifyKZGProof(blobCommitment KZGCommitment, inputPointBytes, claimedValueBytes Scalar, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
 	// 1. Deserialization
 	//
 	claimedValue, err := DeserializeScalar(claimedValueBytes)
@@ -22,12 +32,30 @@ func (c *Context) VerifyKZGProof(blobCommitment KZGCommitment, inputPointBytes,
 		return err
 	}
 
-	polynomialCommitment, err := DeserializeKZGCommitment(blobCommitment)
+	return c.VerifyKZGProofFr(blobCommitment, inputPoint, claimedValue, kzgProof)
+}
+
+// VerifyKZGProofFr is [Context.VerifyKZGProof] for an input point and a claimed value which are already field
+// elements, as returned by [Context.ComputeKZGProofFr].
+func (c *Context) VerifyKZGProofFr(blobCommitment KZGCommitment, inputPoint, claimedValue fr.Element, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization
+	//
+	polynomialCommitment, err := c.deserializeKZGCommitment(blobCommitment)
 	if err != nil {
 		return err
 	}
 
-	quotientCommitment, err := DeserializeKZGProof(kzgProof)
+	return c.verifyKZGProof(&polynomialCommitment, inputPoint, claimedValue, kzgProof)
+}
+
+// verifyKZGProof implements [Context.VerifyKZGProofFr] for a commitment which has already been deserialized.
+func (c *Context) verifyKZGProof(polynomialCommitment *bls12381.G1Affine, inputPoint, claimedValue fr.Element, kzgProof KZGProof) error {
+	quotientCommitment, err := c.deserializeKZGProof(kzgProof)
 	if err != nil {
 		return err
 	}
@@ -39,32 +67,164 @@ func (c *Context) VerifyKZGProof(blobCommitment KZGCommitment, inputPointBytes,
 		ClaimedValue:       claimedValue,
 	}
 
-	return kzg.Verify(&polynomialCommitment, &proof, c.openKey)
+	return kzg.Verify(polynomialCommitment, &proof, c.openKey)
+}
+
+// VerifyDegreeBoundProof verifies a proof, created by [Context.ComputeDegreeBoundProof], that the polynomial committed
+// to in `blobCommitment` has degree less than `degreeBound`.
+//
+// Returns an error if the trusted setup does not contain the G2 power α^(ScalarsPerBlob - degreeBound).
+func (c *Context) VerifyDegreeBoundProof(blobCommitment KZGCommitment, degreeBound uint64, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialization
+	//
+	polynomialCommitment, err := c.deserializeKZGCommitment(blobCommitment)
+	if err != nil {
+		return err
+	}
+
+	proof, err := c.deserializeKZGProof(kzgProof)
+	if err != nil {
+		return err
+	}
+
+	// 2. Verify degree bound proof
+	return kzg.VerifyDegreeBound(&polynomialCommitment, &proof, degreeBound, c.domain.Cardinality, c.openKey)
 }
 
 // VerifyBlobKZGProof implements [verify_blob_kzg_proof].
 //
 // [verify_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
 func (c *Context) VerifyBlobKZGProof(blob *Blob, blobCommitment KZGCommitment, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
 	// 1. Deserialize
 	//
-	polynomial, err := DeserializeBlob(blob)
+	polynomial, err := c.deserializeBlobPooled(blob)
 	if err != nil {
 		return err
 	}
+	defer polynomialPool.Put(polynomial)
 
-	polynomialCommitment, err := DeserializeKZGCommitment(blobCommitment)
+	polynomialCommitment, err := c.deserializeKZGCommitment(blobCommitment)
 	if err != nil {
 		return err
 	}
 
-	quotientCommitment, err := DeserializeKZGProof(kzgProof)
+	quotientCommitment, err := c.deserializeKZGProof(kzgProof)
 	if err != nil {
 		return err
 	}
 
+	return c.verifyBlobKZGProof(blob, *polynomial, blobCommitment, polynomialCommitment, quotientCommitment)
+}
+
+// VerifyBlobKZGProofBytes is [Context.VerifyBlobKZGProof] for a blob, a commitment and a proof held in byte slices.
+// The lengths of the slices are checked before any other work: a [*LengthError] is returned for the blob and a
+// [*PointError] wrapping a [*LengthError] for the commitment or the proof.
+func (c *Context) VerifyBlobKZGProofBytes(blob, blobCommitment, kzgProof []byte) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	serBlob, err := blobFromBytes(blob)
+	if err != nil {
+		return err
+	}
+	serCommitment, err := g1PointFromBytes(commitmentKind, blobCommitment)
+	if err != nil {
+		return err
+	}
+	serProof, err := g1PointFromBytes(proofKind, kzgProof)
+	if err != nil {
+		return err
+	}
+	return c.VerifyBlobKZGProof(serBlob, KZGCommitment(serCommitment), KZGProof(serProof))
+}
+
+// VerifyBlobKZGProofUncompressed is [Context.VerifyBlobKZGProof] for a commitment and a proof in uncompressed form,
+// which avoids decompressing them. See [G1PointUncompressed].
+func (c *Context) VerifyBlobKZGProofUncompressed(blob *Blob, blobCommitment KZGCommitmentUncompressed, kzgProof KZGProofUncompressed) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	// 1. Deserialize
+	//
+	polynomial, err := c.deserializeBlobPooled(blob)
+	if err != nil {
+		return err
+	}
+	defer polynomialPool.Put(polynomial)
+
+	polynomialCommitment, err := c.decodeKZGCommitment(blobCommitment[:])
+	if err != nil {
+		return err
+	}
+
+	quotientCommitment, err := c.decodeKZGProof(kzgProof[:])
+	if err != nil {
+		return err
+	}
+
+	// The challenge is computed from the compressed commitment
+	serCommitment := KZGCommitment(SerializeG1Point(polynomialCommitment))
+	return c.verifyBlobKZGProof(blob, *polynomial, serCommitment, polynomialCommitment, quotientCommitment)
+}
+
+// VerifyBlobKZGProofAgainstVersionedHash is [Context.VerifyBlobKZGProof] for a blob which is only known by the
+// versioned hash of its commitment, as in a blob transaction. The commitment is recomputed from the blob using the
+// number of go routines configured by [WithNumGoRoutines], and [ErrVersionedHashMismatch] is returned if its
+// versioned hash, see [KZGToVersionedHash], is not `versionedHash`.
+func (c *Context) VerifyBlobKZGProofAgainstVersionedHash(blob *Blob, versionedHash [32]byte, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+	if err := c.checkCanProve(); err != nil {
+		return err
+	}
+
+	// 1. Deserialize
+	//
+	polynomial, err := c.deserializeBlobPooled(blob)
+	if err != nil {
+		return err
+	}
+	defer polynomialPool.Put(polynomial)
+
+	quotientCommitment, err := c.deserializeKZGProof(kzgProof)
+	if err != nil {
+		return err
+	}
+
+	// 2. Recompute the commitment and check its versioned hash
+	polynomialCommitment, err := kzg.Commit(*polynomial, c.commitKey, c.numGoRoutines)
+	if err != nil {
+		return err
+	}
+	blobCommitment := KZGCommitment(SerializeG1Point(*polynomialCommitment))
+	if KZGToVersionedHash(blobCommitment) != versionedHash {
+		return ErrVersionedHashMismatch
+	}
+
+	return c.verifyBlobKZGProof(blob, *polynomial, blobCommitment, *polynomialCommitment, quotientCommitment)
+}
+
+// verifyBlobKZGProof implements [Context.VerifyBlobKZGProof] for a blob, a commitment and a proof which have already
+// been deserialized into `polynomial`, `polynomialCommitment` and `quotientCommitment`.
+func (c *Context) verifyBlobKZGProof(blob *Blob, polynomial kzg.Polynomial, blobCommitment KZGCommitment, polynomialCommitment, quotientCommitment bls12381.G1Affine) error {
 	// 2. Compute the evaluation challenge
-	evaluationChallenge := computeChallenge(blob, blobCommitment)
+	evaluationChallenge := c.computeChallenge(blob, blobCommitment)
 
 	// 3. Compute output point/ claimed value
 	outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
@@ -82,88 +242,329 @@ func (c *Context) VerifyBlobKZGProof(blob *Blob, blobCommitment KZGCommitment, k
 	return kzg.Verify(&polynomialCommitment, &openingProof, c.openKey)
 }
 
+// VerifyEquivalenceProof verifies a proof created by [Context.ComputeEquivalenceProof]. It checks that the polynomial
+// committed to in `blobCommitment` evaluates to `claimedValue` at the challenge derived from `blobCommitment` and
+// `externalCommitment`.
+//
+// The caller is responsible for checking that the external commitment opens to `claimedValue` at the same challenge,
+// which can be computed using [Context.ComputeEquivalenceChallenge].
+func (c *Context) VerifyEquivalenceProof(blobCommitment KZGCommitment, externalCommitment []byte, claimedValue Scalar, kzgProof KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	challenge := c.ComputeEquivalenceChallenge(blobCommitment, externalCommitment)
+	return c.VerifyKZGProof(blobCommitment, challenge, claimedValue, kzgProof)
+}
+
+// blobBatchNames names the slices of the batches of blobs, commitments and proofs in a [*BatchLengthError].
+var blobBatchNames = []string{"blobs", "commitments", "proofs"}
+
 // VerifyBlobKZGProofBatch implements [verify_blob_kzg_proof_batch].
 //
+// The blobs, the commitments and the proofs are deserialized concurrently using the number of go routines configured
+// by [WithNumGoRoutines], the rest of the verification is single-threaded. If any of them is invalid, a [*BlobError]
+// is returned holding the index of the first invalid one. It wraps a [*ScalarError] for an invalid blob and a
+// [*PointError] for an invalid commitment or proof.
+//
 // [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
 func (c *Context) VerifyBlobKZGProofBatch(blobs []Blob, polynomialCommitments []KZGCommitment, kzgProofs []KZGProof) error {
+	return c.VerifyBlobKZGProofBatchCtx(context.Background(), blobs, polynomialCommitments, kzgProofs)
+}
+
+// VerifyBlobKZGProofBatchCtx is [Context.VerifyBlobKZGProofBatch] which stops early and returns ctx.Err() if ctx is
+// done before the verification completes.
+//
+// The context is checked before deserializing each blob, commitment and proof, and before the proofs are verified
+// together. The multi exponentiations and the pairing check of the final step cannot be interrupted, so once they
+// have started the verification runs to completion.
+func (c *Context) VerifyBlobKZGProofBatchCtx(ctx context.Context, blobs []Blob, polynomialCommitments []KZGCommitment, kzgProofs []KZGProof) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
+	if c.observer != nil {
+		defer c.observeSince(OperationVerifyBlobKZGProofBatch, len(blobs), time.Now())
+	}
+
 	// 1. Check that all components in the batch have the same size
 	//
-	blobsLen := len(blobs)
-	lengthsAreEqual := blobsLen == len(polynomialCommitments) && blobsLen == len(kzgProofs)
-	if !lengthsAreEqual {
-		return ErrBatchLengthCheck
+	batchSize := len(blobs)
+	if err := checkBatchLengths(blobBatchNames, batchSize, len(polynomialCommitments), len(kzgProofs)); err != nil {
+		return err
+	}
+	if batchSize == 0 {
+		return nil
 	}
-	batchSize := blobsLen
 
-	// 2. Collect opening proofs
+	// 2. Deserialize
 	//
-	openingProofs := make([]kzg.OpeningProof, batchSize)
-	commitments := make([]bls12381.G1Affine, batchSize)
-	for i := 0; i < batchSize; i++ {
-		// 2a. Deserialize
-		//
-		serComm := polynomialCommitments[i]
-		polynomialCommitment, err := DeserializeKZGCommitment(serComm)
-		if err != nil {
-			return err
-		}
+	// The blobs, commitments and proofs are deserialized first, so that this can be done concurrently
+	polynomials, err := c.deserializeBlobs(ctx, blobs)
+	if err != nil {
+		return err
+	}
+	commitments, err := c.deserializeKZGCommitments(ctx, polynomialCommitments)
+	if err != nil {
+		return err
+	}
 
-		kzgProof := kzgProofs[i]
-		quotientCommitment, err := DeserializeKZGProof(kzgProof)
-		if err != nil {
-			return err
-		}
+	quotientCommitments, err := c.deserializeKZGProofs(ctx, kzgProofs)
+	if err != nil {
+		return err
+	}
 
-		blob := &blobs[i]
-		polynomial, err := DeserializeBlob(blob)
-		if err != nil {
-			return err
-		}
+	return c.verifyBlobKZGProofBatch(ctx, blobs, polynomials, polynomialCommitments, commitments, quotientCommitments, 1)
+}
 
-		// 2b. Compute the evaluation challenge
-		evaluationChallenge := computeChallenge(blob, serComm)
+// VerifyBlobKZGProofBatchUncompressed is [Context.VerifyBlobKZGProofBatch] for commitments and proofs in uncompressed
+// form, which avoids decompressing them. See [G1PointUncompressed].
+func (c *Context) VerifyBlobKZGProofBatchUncompressed(blobs []Blob, polynomialCommitments []KZGCommitmentUncompressed, kzgProofs []KZGProofUncompressed) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
 
-		// 2c. Compute output point/ claimed value
-		outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
-		if err != nil {
-			return err
-		}
+	// 1. Check that all components in the batch have the same size
+	//
+	batchSize := len(blobs)
+	if err := checkBatchLengths(blobBatchNames, batchSize, len(polynomialCommitments), len(kzgProofs)); err != nil {
+		return err
+	}
+	if batchSize == 0 {
+		return nil
+	}
 
-		// 2d. Append opening proof to list
-		openingProof := kzg.OpeningProof{
-			QuotientCommitment: quotientCommitment,
-			InputPoint:         evaluationChallenge,
-			ClaimedValue:       *outputPoint,
-		}
-		openingProofs[i] = openingProof
-		commitments[i] = polynomialCommitment
+	// 2. Deserialize
+	//
+	polynomials, err := c.deserializeBlobs(context.Background(), blobs)
+	if err != nil {
+		return err
+	}
+	commitments, err := expandUnique(decodeUniqueKZGPoints(context.Background(), c, polynomialCommitments, func(commitment *KZGCommitmentUncompressed) (bls12381.G1Affine, error) {
+		return c.decodeKZGCommitment(commitment[:])
+	}))
+	if err != nil {
+		return err
+	}
+	quotientCommitments, err := c.decodeKZGPoints(context.Background(), batchSize, func(i int) (bls12381.G1Affine, error) {
+		return c.decodeKZGProof(kzgProofs[i][:])
+	})
+	if err != nil {
+		return err
+	}
+
+	// The challenges are computed from the compressed commitments
+	serCommitments := make([]KZGCommitment, batchSize)
+	for i := range commitments {
+		serCommitments[i] = KZGCommitment(SerializeG1Point(commitments[i]))
+	}
+
+	return c.verifyBlobKZGProofBatch(context.Background(), blobs, polynomials, serCommitments, commitments, quotientCommitments, 1)
+}
+
+// verifyBlobKZGProofBatch implements [Context.VerifyBlobKZGProofBatch] for blobs, commitments and proofs which have
+// already been deserialized. The evaluation challenges and claimed values are computed using numGoRoutines go
+// routines, and the proofs are then verified together with a random linear combination.
+//
+// ctx is checked before computing each evaluation challenge and claimed value, and before verifying the proofs.
+func (c *Context) verifyBlobKZGProofBatch(ctx context.Context, blobs []Blob, polynomials []kzg.Polynomial, serCommitments []KZGCommitment, commitments, quotientCommitments []bls12381.G1Affine, numGoRoutines int) error {
+	// 2a. Compute the evaluation challenges
+	evaluationChallenges, err := c.computeChallenges(ctx, blobs, serCommitments, numGoRoutines)
+	if err != nil {
+		return err
+	}
+
+	openingProofs := make([]kzg.OpeningProof, len(blobs))
+	err = utils.ParallelForCtx(ctx, len(blobs), numGoRoutines, func(i int) (err error) {
+		openingProofs[i], err = c.blobOpeningProof(polynomials[i], evaluationChallenges[i], quotientCommitments[i])
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	// 3. Verify opening proofs
-	return kzg.BatchVerifyMultiPoints(commitments, openingProofs, c.openKey)
+	return kzg.BatchVerifyMultiPoints(commitments, openingProofs, c.openKey, numGoRoutines, c.randomSource, c.observer)
+}
+
+// blobOpeningProof returns the opening proof checked by [Context.VerifyBlobKZGProof] for a blob which has already
+// been deserialized, given its evaluation challenge.
+func (c *Context) blobOpeningProof(polynomial kzg.Polynomial, evaluationChallenge fr.Element, quotientCommitment bls12381.G1Affine) (kzg.OpeningProof, error) {
+	// 2b. Compute output point/ claimed value
+	outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
+	if err != nil {
+		return kzg.OpeningProof{}, err
+	}
+
+	return kzg.OpeningProof{
+		QuotientCommitment: quotientCommitment,
+		InputPoint:         evaluationChallenge,
+		ClaimedValue:       *outputPoint,
+	}, nil
 }
 
 // VerifyBlobKZGProofBatchPar implements [verify_blob_kzg_proof_batch]. This is the parallelized version of
-// [Context.VerifyBlobKZGProofBatch], which is single-threaded. This function uses go-routines to process each proof in
-// parallel. If you are worried about resource starvation on large batches, it is advised to schedule your own
-// go-routines in a more intricate way than done below for large batches.
+// [Context.VerifyBlobKZGProofBatch]: every step of the verification uses the number of go routines configured by
+// [WithNumGoRoutines].
+//
+// By default, like [Context.VerifyBlobKZGProofBatch], the blobs, commitments and proofs are deserialized first and a
+// [*BlobError] holding the index of the first invalid one is returned if any of them is invalid. The proofs are then
+// verified together with a random linear combination, which needs a single pairing check but does not tell which
+// proof is invalid. This can be changed with a [BatchOption]:
+//   - [WithFailFast] verifies the triples one by one and stops at the first invalid one, which is reported in a
+//     [*BlobError].
+//   - [WithAllFailures] verifies all the triples one by one and reports every invalid one in a [*BatchError].
+//   - [WithBisection] verifies the proofs together, then bisects the batch if the check fails to report every
+//     invalid triple in a [*BatchError].
 //
 // [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
-func (c *Context) VerifyBlobKZGProofBatchPar(blobs []Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+func (c *Context) VerifyBlobKZGProofBatchPar(blobs []Blob, commitments []KZGCommitment, proofs []KZGProof, opts ...BatchOption) error {
+	return c.VerifyBlobKZGProofBatchParCtx(context.Background(), blobs, commitments, proofs, opts...)
+}
+
+// VerifyBlobKZGProofBatchParCtx is [Context.VerifyBlobKZGProofBatchPar] which stops early and returns ctx.Err() if
+// ctx is done before the verification completes.
+//
+// The context is checked before each step which is split between the go routines: deserializing a blob, a commitment
+// or a proof, verifying a single triple, and each batched check of the bisection. A multi exponentiation or a pairing
+// check which has started cannot be interrupted.
+func (c *Context) VerifyBlobKZGProofBatchParCtx(ctx context.Context, blobs []Blob, commitments []KZGCommitment, proofs []KZGProof, opts ...BatchOption) error {
+	if !c.calls.acquire() {
+		return ErrContextClosed
+	}
+	defer c.calls.release()
+
 	// 1. Check that all components in the batch have the same size
-	if len(commitments) != len(blobs) || len(proofs) != len(blobs) {
-		return ErrBatchLengthCheck
+	if err := checkBatchLengths(blobBatchNames, len(blobs), len(commitments), len(proofs)); err != nil {
+		return err
+	}
+	if len(blobs) == 0 {
+		return nil
 	}
 
-	// 2. Verify each opening proof using green threads
-	var errG errgroup.Group
-	for i := range blobs {
-		j := i // Capture the value of the loop variable
-		errG.Go(func() error {
-			return c.VerifyBlobKZGProof(&blobs[j], commitments[j], proofs[j])
+	config := newBatchConfig(opts)
+	switch {
+	case config.failFast:
+		// 2. Verify the triples one by one, stopping at the first invalid one
+		return utils.ParallelForCtx(ctx, len(blobs), c.numGoRoutines, func(i int) error {
+			if err := c.VerifyBlobKZGProof(&blobs[i], commitments[i], proofs[i]); err != nil {
+				return &BlobError{Index: i, Err: err}
+			}
+			return nil
+		})
+
+	case config.allFailures:
+		// 2. Verify all the triples one by one, recording the error of every triple
+		errs := make([]error, len(blobs))
+		err := utils.ParallelForCtx(ctx, len(blobs), c.numGoRoutines, func(i int) error {
+			errs[i] = c.VerifyBlobKZGProof(&blobs[i], commitments[i], proofs[i])
+			return nil
 		})
+		if err != nil {
+			return err
+		}
+
+		return newBatchError(errs)
+
+	case config.bisect:
+		return c.verifyBlobKZGProofBatchBisect(ctx, blobs, commitments, proofs)
+	}
+
+	// 2. Deserialize the blobs, commitments and proofs
+	polynomials, err := c.deserializeBlobs(ctx, blobs)
+	if err != nil {
+		return err
+	}
+	commitmentPoints, err := c.deserializeKZGCommitments(ctx, commitments)
+	if err != nil {
+		return err
+	}
+	quotientCommitments, err := c.deserializeKZGProofs(ctx, proofs)
+	if err != nil {
+		return err
+	}
+
+	// 3. Verify the opening proofs together
+	return c.verifyBlobKZGProofBatch(ctx, blobs, polynomials, commitments, commitmentPoints, quotientCommitments, c.numGoRoutines)
+}
+
+// verifyBlobKZGProofBatchBisect implements [Context.VerifyBlobKZGProofBatchPar] with [WithBisection].
+func (c *Context) verifyBlobKZGProofBatchBisect(ctx context.Context, blobs []Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+	// 2. Deserialize the triples, recording the error of every invalid one
+	batchSize := len(blobs)
+	errs := make([]error, batchSize)
+	openingProofs := make([]kzg.OpeningProof, batchSize)
+	commitmentPoints := make([]bls12381.G1Affine, batchSize)
+	err := utils.ParallelForCtx(ctx, batchSize, c.numGoRoutines, func(i int) error {
+		polynomial, err := c.deserializeBlob(&blobs[i])
+		if err != nil {
+			errs[i] = err
+			return nil
+		}
+		commitmentPoints[i], err = c.deserializeKZGCommitment(commitments[i])
+		if err != nil {
+			errs[i] = err
+			return nil
+		}
+		quotientCommitment, err := c.deserializeKZGProof(proofs[i])
+		if err != nil {
+			errs[i] = err
+			return nil
+		}
+		evaluationChallenge := c.computeChallenge(&blobs[i], commitments[i])
+		openingProofs[i], errs[i] = c.blobOpeningProof(polynomial, evaluationChallenge, quotientCommitment)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The valid triples are moved to the front, so that the halves checked by the bisection are contiguous
+	var indices []int
+	for i, err := range errs {
+		if err == nil {
+			commitmentPoints[len(indices)] = commitmentPoints[i]
+			openingProofs[len(indices)] = openingProofs[i]
+			indices = append(indices, i)
+		}
+	}
+
+	// 3. Verify the opening proofs together, and bisect the ones which fail
+	//
+	// If a range fails and its first half passes, the second half is known to fail and is not checked again.
+	var bisect func(start, end int, knownInvalid bool) (bool, error)
+	bisect = func(start, end int, knownInvalid bool) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if !knownInvalid {
+			err := kzg.BatchVerifyMultiPoints(commitmentPoints[start:end], openingProofs[start:end], c.openKey, c.numGoRoutines, c.randomSource, c.observer)
+			if !errors.Is(err, ErrProofInvalid) {
+				return false, err
+			}
+		}
+		if end-start == 1 {
+			errs[indices[start]] = ErrProofInvalid
+			return true, nil
+		}
+
+		mid := (start + end) / 2
+		firstHalfInvalid, err := bisect(start, mid, false)
+		if err != nil {
+			return false, err
+		}
+		_, err = bisect(mid, end, !firstHalfInvalid)
+		return true, err
+	}
+	if _, err := bisect(0, len(indices), false); err != nil {
+		return err
 	}
 
-	// 3. Wait for all go routines to complete and check if any returned an error
-	return errG.Wait()
+	return newBatchError(errs)
 }
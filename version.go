@@ -0,0 +1,123 @@
+package gokzg4844
+
+import (
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/RiemaLabs/go-kzg-4844/internal/utils"
+)
+
+// modulePath is the path of the module holding this package, as it appears in the build information.
+const modulePath = "github.com/RiemaLabs/go-kzg-4844"
+
+// version is the version of the library, which is bumped on every release. It is returned by [Version] when the
+// build information does not hold the version of the module.
+const version = "v1.0.0-dev"
+
+// Version returns the version of the library. When the binary was built with module support and the version of the
+// module is known, this is the version from the build information, such as "v1.0.0" or a pseudo-version. Otherwise,
+// for instance when the module is replaced by a local directory, it is the version of this source tree.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	module := &info.Main
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			module = dep
+			break
+		}
+	}
+	if module.Path != modulePath {
+		return version
+	}
+	if module.Replace != nil {
+		module = module.Replace
+	}
+	if module.Version == "" || module.Version == "(devel)" {
+		return version
+	}
+	return module.Version
+}
+
+// Features reports which optional capabilities a [Context] has, as returned by [Context.Features].
+type Features struct {
+	// CellsSupported is true if the EIP-7594 cell methods, such as [Context.ComputeCellsAndKZGProofs], can be used.
+	// This needs the monomial G1 points and a context for blobs of [ScalarsPerBlob] scalars.
+	CellsSupported bool
+	// MonomialCommitments is true if the context holds the monomial G1 points of the trusted setup, which are used by
+	// the cell methods and [Context.ComputeDegreeBoundProof]. See [WithoutMonomialSRS].
+	MonomialCommitments bool
+	// PrecomputedSRS is true if the commitments are computed using the fixed base table of [WithPrecomputedSRS].
+	PrecomputedSRS bool
+}
+
+// Features returns the optional capabilities of the context. All of them are false once the context is closed.
+func (c *Context) Features() Features {
+	if !c.calls.acquire() {
+		return Features{}
+	}
+	defer c.calls.release()
+	return Features{
+		CellsSupported:      c.monomialCommitKey != nil && c.domain.Cardinality == ScalarsPerBlob,
+		MonomialCommitments: c.monomialCommitKey != nil,
+		PrecomputedSRS:      c.precomputedSRSWindowBits > 0,
+	}
+}
+
+// String summarizes the context for logging: the version of the library, the fingerprint of the trusted setup, the
+// size of the domain, the number of go routines, the features and the options which differ from the defaults.
+//
+// It computes the fingerprint on the first call, see [Context.SetupFingerprint].
+func (c *Context) String() string {
+	if !c.calls.acquire() {
+		return "gokzg4844.Context{closed}"
+	}
+	defer c.calls.release()
+
+	features := c.Features()
+	var enabled []string
+	if features.CellsSupported {
+		enabled = append(enabled, "cells")
+	}
+	if features.MonomialCommitments {
+		enabled = append(enabled, "monomial commitments")
+	}
+	if features.PrecomputedSRS {
+		enabled = append(enabled, fmt.Sprintf("precomputed SRS (%d bits)", c.precomputedSRSWindowBits))
+	}
+
+	var options []string
+	if c.mode != ModeProveAndVerify {
+		options = append(options, c.mode.String())
+	}
+	if c.commitmentCache != nil {
+		options = append(options, fmt.Sprintf("commitment cache (%d entries)", c.commitmentCache.maxEntries))
+	}
+	if c.trustedCommitments {
+		options = append(options, "trusted commitments")
+	}
+	if c.rejectInfinityCommitments {
+		options = append(options, "reject infinity commitments")
+	}
+	if c.rejectInfinityProofs {
+		options = append(options, "reject infinity proofs")
+	}
+	if c.newChallengeHasher != nil {
+		options = append(options, "custom challenge hasher")
+	}
+	if c.challengeDomainSeparator != DomSepProtocol {
+		options = append(options, fmt.Sprintf("challenge domain separator %q", c.challengeDomainSeparator))
+	}
+	if c.observer != nil {
+		options = append(options, "observer")
+	}
+
+	fingerprint := c.SetupFingerprint()
+	return fmt.Sprintf("gokzg4844.Context{version: %s, setup fingerprint: 0x%s, domain size: %d, go routines: %d, features: [%s], options: [%s]}",
+		Version(), hex.EncodeToString(fingerprint[:]), c.domain.Cardinality, utils.NumGoRoutines(c.numGoRoutines),
+		strings.Join(enabled, ", "), strings.Join(options, ", "))
+}
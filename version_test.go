@@ -0,0 +1,57 @@
+package gokzg4844_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	gokzg4844 "github.com/RiemaLabs/go-kzg-4844"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	version := gokzg4844.Version()
+	require.True(t, strings.HasPrefix(version, "v"), version)
+}
+
+func TestFeatures(t *testing.T) {
+	require.Equal(t, gokzg4844.Features{CellsSupported: true, MonomialCommitments: true}, ctx.Features())
+
+	withoutMonomial, err := gokzg4844.NewContext4096Secure(gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.Features{}, withoutMonomial.Features())
+
+	precomputed, err := gokzg4844.NewContext4096Secure(gokzg4844.WithPrecomputedSRS(8), gokzg4844.WithoutMonomialSRS())
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.Features{PrecomputedSRS: true}, precomputed.Features())
+
+	// The cell methods are only available for blobs of 4096 scalars
+	small, err := gokzg4844.NewInsecureContextWithSecret(fr.NewElement(1337), 16)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.Features{MonomialCommitments: true}, small.Features())
+
+	require.NoError(t, precomputed.Close())
+	require.Equal(t, gokzg4844.Features{}, precomputed.Features())
+}
+
+func TestContextString(t *testing.T) {
+	fingerprint := ctx.SetupFingerprint()
+	summary := ctx.String()
+	require.Contains(t, summary, gokzg4844.Version())
+	require.Contains(t, summary, "0x"+hex.EncodeToString(fingerprint[:]))
+	require.Contains(t, summary, "domain size: 4096")
+	require.Contains(t, summary, "features: [cells, monomial commitments]")
+	require.Contains(t, summary, "options: []")
+
+	withOptions, err := gokzg4844.NewContext4096Secure(gokzg4844.WithPrecomputedSRS(8), gokzg4844.WithSerialMode(),
+		gokzg4844.WithRejectInfinityProofs(), gokzg4844.WithCommitmentCache(16))
+	require.NoError(t, err)
+	summary = withOptions.String()
+	require.Contains(t, summary, "go routines: 1")
+	require.Contains(t, summary, "precomputed SRS (8 bits)")
+	require.Contains(t, summary, "options: [commitment cache (16 entries), reject infinity proofs]")
+
+	require.NoError(t, withOptions.Close())
+	require.Equal(t, "gokzg4844.Context{closed}", withOptions.String())
+}
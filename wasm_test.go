@@ -0,0 +1,66 @@
+//go:build wasm_check
+
+package gokzg4844
+
+// The tests in this file build the library for js/wasm and run the unit tests under wasip1, where GOMAXPROCS is 1
+// and a panic in a go routine aborts the module. They call the go command, so they are opt-in:
+//
+//	make wasm-check
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// wasmUnitTestPackages are the packages whose unit tests are run under wasip1.
+var wasmUnitTestPackages = []string{".", "./internal/...", "./pkg/kzg"}
+
+// wasiRuntimes are the runtimes supported by go_wasip1_wasm_exec, in order of preference.
+var wasiRuntimes = []string{"wasmtime", "wazero", "wasmedge"}
+
+// runGo runs the go command with the arguments, adding env to the environment, and fails the test if it fails.
+func runGo(t *testing.T, env []string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "go %s\n%s", strings.Join(args, " "), output)
+}
+
+func TestJSWasmBuild(t *testing.T) {
+	env := []string{"GOOS=js", "GOARCH=wasm"}
+	runGo(t, env, "build", "./...")
+	runGo(t, env, "build", "-tags", "gokzg_serial", "./...")
+	// vet also type checks the tests
+	runGo(t, env, "vet", "./...")
+}
+
+func TestWasip1UnitTests(t *testing.T) {
+	var wasiRuntime string
+	for _, name := range wasiRuntimes {
+		if _, err := exec.LookPath(name); err == nil {
+			wasiRuntime = name
+			break
+		}
+	}
+	if wasiRuntime == "" {
+		t.Skipf("none of %s is installed", strings.Join(wasiRuntimes, ", "))
+	}
+
+	// go test finds go_wasip1_wasm_exec in the PATH, which is in lib/wasm since Go 1.21 and in misc/wasm before
+	goroot, err := exec.Command("go", "env", "GOROOT").Output()
+	require.NoError(t, err)
+	path := os.Getenv("PATH")
+	for _, dir := range []string{"lib", "misc"} {
+		path = filepath.Join(strings.TrimSpace(string(goroot)), dir, "wasm") + string(os.PathListSeparator) + path
+	}
+
+	env := []string{"GOOS=wasip1", "GOARCH=wasm", "GOWASIRUNTIME=" + wasiRuntime, "PATH=" + path}
+	args := append([]string{"test", "-short", "-timeout", "0"}, wasmUnitTestPackages...)
+	runGo(t, env, args...)
+}